@@ -87,9 +87,21 @@ Generate config.toml content
 [server]
 transports = {{ .Values.server.transports | toJson }}
 log_level = "{{ .Values.server.logLevel }}"
+shutdown_grace_period = {{ .Values.server.shutdownGracePeriod | default "20s" | quote }}
+
+[server.health]
+probe_interval = {{ .Values.server.health.probeInterval | default "30s" | quote }}
+probe_timeout = {{ .Values.server.health.probeTimeout | default "5s" | quote }}
 
 [server.http]
 address = "{{ .Values.server.http.address }}"
+read_timeout = {{ .Values.server.http.readTimeout | default "15s" | quote }}
+write_timeout = {{ .Values.server.http.writeTimeout | default "0s" | quote }}
+idle_timeout = {{ .Values.server.http.idleTimeout | default "60s" | quote }}
+sse_keep_alive = {{ .Values.server.http.sseKeepAlive | default "15s" | quote }}
+session_idle_timeout = {{ .Values.server.http.sessionIdleTimeout | default "30m" | quote }}
+stateless = {{ .Values.server.http.stateless | default false }}
+legacy_sse = {{ .Values.server.http.legacySSE | default false }}
 
 [server.http.oauth]
 enabled = {{ .Values.server.http.oauth.enabled }}
@@ -111,12 +123,87 @@ scopes = {{ .Values.server.http.oauth.scopes | toJson }}
 {{- if .Values.server.http.oauth.redirectURL }}
 redirect_url = "{{ .Values.server.http.oauth.redirectURL }}"
 {{- end }}
+{{- if .Values.server.http.oauth.audience }}
+audience = {{ .Values.server.http.oauth.audience | quote }}
+{{- end }}
+{{- if .Values.server.http.oauth.resourceURL }}
+resource_url = {{ .Values.server.http.oauth.resourceURL | quote }}
+{{- end }}
+{{- with .Values.server.http.oauth.authorizationServers }}
+authorization_servers = {{ toJson . }}
+{{- end }}
+{{- with .Values.server.http.oauth.serviceAccounts }}
+service_accounts = {{ toJson . }}
+{{- end }}
+{{- range .Values.server.http.oauth.toolScopes }}
+
+[[server.http.oauth.tool_scopes]]
+scope = {{ .scope | quote }}
+{{- with .tools }}
+tools = {{ toJson . }}
+{{- end }}
+{{- with .toolsets }}
+toolsets = {{ toJson . }}
+{{- end }}
+{{- with .classes }}
+classes = {{ toJson . }}
+{{- end }}
+{{- end }}
+{{- with .Values.server.http.oauth.apiKeys }}
+{{- if .enabled }}
+
+[server.http.oauth.api_keys]
+enabled = true
+{{- if .secretName }}
+secret_name = {{ .secretName | quote }}
+secret_namespace = {{ .secretNamespace | default $.Release.Namespace | quote }}
+{{- end }}
+refresh_interval = {{ .refreshInterval | default "1m" | quote }}
+{{- range .keys }}
+
+[[server.http.oauth.api_keys.keys]]
+name = {{ .name | quote }}
+hash = {{ .hash | quote }}
+{{- if .username }}
+username = {{ .username | quote }}
+{{- end }}
+{{- with .groups }}
+groups = {{ toJson . }}
+{{- end }}
+{{- with .scopes }}
+scopes = {{ toJson . }}
+{{- end }}
+{{- with .tools }}
+tools = {{ toJson . }}
+{{- end }}
+{{- if .expiresAt }}
+expires_at = {{ .expiresAt }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
 
 [server.http.cors]
 enabled = {{ .Values.server.http.cors.enabled }}
 allowed_origins = {{ .Values.server.http.cors.allowedOrigins | toJson }}
 allowed_methods = {{ .Values.server.http.cors.allowedMethods | toJson }}
 allowed_headers = {{ .Values.server.http.cors.allowedHeaders | toJson }}
+{{- with .Values.server.http.tls }}
+{{- if .enabled }}
+
+[server.http.tls]
+enabled = true
+cert_file = {{ .certFile | quote }}
+key_file = {{ .keyFile | quote }}
+min_version = {{ .minVersion | quote }}
+
+[server.http.tls.client_auth]
+mode = {{ .clientAuth.mode | quote }}
+ca_file = {{ .clientAuth.caFile | quote }}
+username_from = {{ .clientAuth.usernameFrom | quote }}
+groups_from = {{ .clientAuth.groupsFrom | quote }}
+{{- end }}
+{{- end }}
 
 
 [kubernetes]
@@ -144,6 +231,64 @@ require_rbac = {{ .Values.security.requireRBAC }}
 validate_token = {{ .Values.security.validateToken }}
 rbac_cache_ttl = {{ .Values.security.rbacCacheTTL }}
 
+[security.confirmation]
+enabled = {{ .Values.security.confirmation.enabled }}
+fallback = "{{ .Values.security.confirmation.fallback }}"
+timeout = "{{ .Values.security.confirmation.timeout }}"
+{{- range .Values.security.confirmation.rules }}
+
+[[security.confirmation.rules]]
+{{- if .tools }}
+tools = {{ .tools | toJson }}
+{{- end }}
+{{- if .namespaces }}
+namespaces = {{ .namespaces | toJson }}
+{{- end }}
+{{- if .kinds }}
+kinds = {{ .kinds | toJson }}
+{{- end }}
+{{- end }}
+
+[security.redaction]
+mode = "{{ .Values.security.redaction.mode }}"
+patterns = {{ .Values.security.redaction.patterns | toJson }}
+keys = {{ .Values.security.redaction.keys | toJson }}
+
+[security.redaction.reveal]
+enabled = {{ .Values.security.redaction.reveal.enabled }}
+groups = {{ .Values.security.redaction.reveal.groups | toJson }}
+
+[security.access]
+enabled = {{ .Values.security.access.enabled }}
+{{- range .Values.security.access.rules }}
+
+[[security.access.rules]]
+{{- range $key, $field := dict "users" .users "groups" .groups "toolsets" .toolsets "tools" .tools "contexts" .contexts "namespaces" .namespaces }}
+{{- if $field }}
+{{ $key }} = {{ $field | toJson }}
+{{- end }}
+{{- end }}
+{{- if .readOnly }}
+read_only = true
+{{- end }}
+{{- end }}
+
+[security.admission]
+enabled = {{ .Values.security.admission.enabled }}
+failure_policy = "{{ .Values.security.admission.failurePolicy }}"
+{{- range .Values.security.admission.rules }}
+
+[[security.admission.rules]]
+{{- if .tools }}
+tools = {{ .tools | toJson }}
+{{- end }}
+{{- range $key, $field := dict "name" .name "expression" .expression "action" .action "message" .message "message_expression" .messageExpression }}
+{{- if $field }}
+{{ $key }} = {{ $field | quote }}
+{{- end }}
+{{- end }}
+{{- end }}
+
 [helm]
 storage_driver = "{{ .Values.helm.storageDriver }}"
 default_namespace = "{{ .Values.helm.defaultNamespace }}"
@@ -174,5 +319,55 @@ cert_file = "{{ .Values.kiali.tls.certFile }}"
 key_file = "{{ .Values.kiali.tls.keyFile }}"
 {{- end }}
 insecure_skip_verify = {{ .Values.kiali.tls.insecureSkipVerify }}
+
+[audit]
+enabled = {{ .Values.audit.enabled }}
+stdout = {{ .Values.audit.stdout }}
+
+[audit.file]
+{{- if .Values.audit.file.path }}
+path = "{{ .Values.audit.file.path }}"
+{{- end }}
+max_size_mb = {{ .Values.audit.file.maxSizeMB }}
+max_backups = {{ .Values.audit.file.maxBackups }}
+
+[audit.webhook]
+{{- if .Values.audit.webhook.url }}
+url = "{{ .Values.audit.webhook.url }}"
+{{- end }}
+timeout = "{{ .Values.audit.webhook.timeout }}"
+{{- with .Values.audit.webhook.headers }}
+
+[audit.webhook.headers]
+{{- range $name, $value := . }}
+{{ $name | quote }} = {{ $value | quote }}
+{{- end }}
+{{- end }}
+
+[rate_limit]
+enabled = {{ .Values.rateLimit.enabled }}
+{{- range $table, $limit := dict "global" .Values.rateLimit.global "per_identity" .Values.rateLimit.perIdentity "per_context" .Values.rateLimit.perContext "http" .Values.rateLimit.http }}
+
+{{ include "kube-mcp.rateLimit" (list (printf "rate_limit.%s" $table) $limit) }}
+{{- end }}
+{{- range $name, $limit := .Values.rateLimit.contexts }}
+
+{{ include "kube-mcp.rateLimit" (list (printf "rate_limit.contexts.%s" ($name | quote)) $limit) }}
+{{- end }}
+{{- range $name, $limit := .Values.rateLimit.tools }}
+
+{{ include "kube-mcp.rateLimit" (list (printf "rate_limit.tools.%s" ($name | quote)) $limit) }}
+{{- end }}
+{{- end }}
+
+{{/*
+Render a rate limit table from a list of the table name and the limit values
+*/}}
+{{- define "kube-mcp.rateLimit" -}}
+{{- $limit := index . 1 -}}
+[{{ index . 0 }}]
+rps = {{ $limit.rps | default 0 }}
+burst = {{ $limit.burst | default 0 }}
+max_concurrent = {{ $limit.maxConcurrent | default 0 }}
 {{- end }}
 
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wrkode/kube-mcp/pkg/http"
+)
+
+const apiKeyUsage = `Usage: kube-mcp apikey generate
+
+Generate an API key. Give the key to the client and put its hash in
+server.http.oauth.api_keys.
+`
+
+// runAPIKey runs the "apikey" subcommand and returns the exit code.
+func runAPIKey(args []string) int {
+	if len(args) != 1 || args[0] != "generate" {
+		fmt.Fprint(os.Stderr, apiKeyUsage)
+		return 2
+	}
+
+	key, hash, err := http.GenerateAPIKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FAILED: %v\n", err)
+		return 1
+	}
+	fmt.Printf("key:  %s\nhash: %s\n", key, hash)
+	return 0
+}
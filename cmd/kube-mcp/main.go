@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	nethttp "net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/wrkode/kube-mcp/pkg/audit"
 	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/health"
 	"github.com/wrkode/kube-mcp/pkg/http"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	"github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/ratelimit"
+	"github.com/wrkode/kube-mcp/pkg/security"
 	"github.com/wrkode/kube-mcp/pkg/toolsets/autoscaling"
 	"github.com/wrkode/kube-mcp/pkg/toolsets/backup"
 	"github.com/wrkode/kube-mcp/pkg/toolsets/capi"
@@ -44,6 +50,13 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAudit(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		os.Exit(runAPIKey(os.Args[2:]))
+	}
+
 	flag.Parse()
 
 	if *versionFlag {
@@ -61,14 +74,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Setup hot reload with callback to apply runtime-reloadable settings
-	if err := config.SetupReload(cfgLoader, func(cfg *config.Config) error {
-		// Apply runtime-reloadable settings here
-		// For now, we'll just update the logger level if needed
-		// More sophisticated reload logic can be added later
-		return nil
-	}); err != nil {
-		log.Printf("Warning: Failed to setup hot reload: %v", err)
+	// Build rate limits from [rate_limit] settings
+	limiter, err := ratelimit.NewLimiter(&cfg.RateLimit)
+	if err != nil {
+		log.Fatalf("Failed to create rate limiter: %v", err)
+	}
+
+	// Build security policy from [security] settings
+	securityPolicy, err := security.NewPolicy(&cfg.Security)
+	if err != nil {
+		log.Fatalf("Failed to create security policy: %v", err)
 	}
 
 	// Create Kubernetes client factory
@@ -77,6 +92,7 @@ func main() {
 		cfg.Kubernetes.Burst,
 		cfg.Kubernetes.Timeout.Duration(),
 	)
+	factory.SetDeniedGVKs(securityPolicy.DeniedGVKs())
 
 	// Create Kubernetes provider
 	provider, err := kubernetes.NewProvider(
@@ -89,6 +105,69 @@ func main() {
 		log.Fatalf("Failed to create Kubernetes provider: %v", err)
 	}
 
+	// Run Kubernetes calls as the authenticated caller if configured
+	credentialMode, err := kubernetes.ParseCredentialMode(cfg.Kubernetes.CredentialMode)
+	if err != nil {
+		log.Fatalf("Invalid kubernetes.credential_mode: %v", err)
+	}
+	if credentialMode != kubernetes.CredentialModeServer {
+		if usesTransport(cfg, *transport, "stdio") {
+			log.Fatalf("kubernetes.credential_mode %q cannot be used with the stdio transport, whose callers are not authenticated", credentialMode)
+		}
+		if usesTransport(cfg, *transport, "http") {
+			// Every HTTP caller must be authenticated, by a bearer token or,
+			// for impersonation, by a required client certificate
+			tlsCfg := cfg.Server.HTTP.TLS
+			clientCerts := tlsCfg.Enabled && tlsCfg.ClientAuth.Mode != "" && tlsCfg.ClientAuth.Mode != "none"
+			requireCerts := tlsCfg.Enabled && tlsCfg.ClientAuth.Mode == "require"
+			switch {
+			case credentialMode == kubernetes.CredentialModePassthrough && clientCerts:
+				log.Fatalf("kubernetes.credential_mode %q cannot be used with server.http.tls.client_auth, because certificate callers have no token to pass through; use %q", credentialMode, kubernetes.CredentialModeImpersonate)
+			case credentialMode == kubernetes.CredentialModePassthrough && cfg.Server.HTTP.OAuth.APIKeys.Enabled:
+				log.Fatalf("kubernetes.credential_mode %q cannot be used with server.http.oauth.api_keys, because API keys are not Kubernetes credentials; use %q", credentialMode, kubernetes.CredentialModeImpersonate)
+			case credentialMode == kubernetes.CredentialModeImpersonate && requireCerts:
+			case !cfg.Server.HTTP.OAuth.Enabled:
+				log.Fatalf("kubernetes.credential_mode %q requires server.http.oauth.enabled for the HTTP transport", credentialMode)
+			}
+		}
+		provider = kubernetes.NewCallerProvider(
+			provider,
+			factory,
+			credentialMode,
+			cfg.Kubernetes.ClientCacheSize,
+			cfg.Kubernetes.ClientCacheTTL.Duration(),
+		)
+		log.Printf("Kubernetes calls from authenticated callers use %s credentials", credentialMode)
+	}
+
+	// Restrict callers to the contexts, namespaces and tools of their access rules
+	access, err := security.NewAccess(&cfg.Security.Access, provider)
+	if err != nil {
+		log.Fatalf("Failed to create access policy: %v", err)
+	}
+	admission, err := security.NewAdmission(&cfg.Security.Admission, provider)
+	if err != nil {
+		log.Fatalf("Failed to create admission policy: %v", err)
+	}
+	scopes, err := security.NewScopes(cfg.Server.HTTP.OAuth.ToolScopes)
+	if err != nil {
+		log.Fatalf("Failed to create tool scope policy: %v", err)
+	}
+
+	// Setup hot reload with callback to apply runtime-reloadable settings
+	if err := config.SetupReload(cfgLoader, func(cfg *config.Config) error {
+		// Apply runtime-reloadable settings here
+		// More sophisticated reload logic can be added later
+		return errors.Join(
+			limiter.Update(&cfg.RateLimit),
+			access.Update(&cfg.Security.Access),
+			scopes.Update(cfg.Server.HTTP.OAuth.ToolScopes),
+			admission.Update(&cfg.Security.Admission),
+		)
+	}); err != nil {
+		log.Printf("Warning: Failed to setup hot reload: %v", err)
+	}
+
 	// Get default client set for CRD discovery
 	defaultClientSet, err := provider.GetClientSet("")
 	if err != nil {
@@ -97,8 +176,16 @@ func main() {
 
 	// Create CRD discovery
 	crdDiscovery := kubernetes.NewCRDDiscovery(defaultClientSet, 5*time.Minute)
-	if err := crdDiscovery.DiscoverCRDs(ctx); err != nil {
-		log.Printf("Warning: Failed to discover CRDs: %v", err)
+	discoveryErr := crdDiscovery.DiscoverCRDs(ctx)
+	if discoveryErr != nil {
+		log.Printf("Warning: Failed to discover CRDs: %v", discoveryErr)
+	}
+
+	// Track readiness and probe each context's API server for the health endpoints
+	healthChecker := health.NewChecker(provider, &cfg.Server.Health)
+	healthChecker.SetCRDDiscovery(crdDiscovery, discoveryErr)
+	if usesTransport(cfg, *transport, "http") {
+		go healthChecker.Run(ctx)
 	}
 
 	// Initialize observability
@@ -108,15 +195,93 @@ func main() {
 	}
 	obsLogger := observability.NewLogger(logLevel, false) // JSON format can be configurable
 	obsMetrics := observability.NewMetrics(nil)           // Use default registry
+	limiter.SetMetrics(obsMetrics)
 
 	// Create MCP server
 	mcpServer := mcp.NewServer(name, version, cfg.Server.NormalizeToolNames)
+	obsMetrics.RegisterActiveSessions(mcpServer.SessionCount)
+
+	// Record every tool invocation in the audit log, including calls that
+	// later middleware rejects
+	if cfg.Audit.Stdout && usesTransport(cfg, *transport, "stdio") {
+		log.Fatalf("audit.stdout cannot be used with the stdio transport")
+	}
+	auditor, err := audit.NewFromConfig(&cfg.Audit)
+	if err != nil {
+		log.Fatalf("Failed to create audit log: %v", err)
+	}
+	defer auditor.Close()
+	mcpServer.UseToolMiddleware(auditor.Middleware())
+
+	// Enforce security modes before any toolset registers its tools
+	mcpServer.AddToolFilter(securityPolicy.ToolFilter())
+	mcpServer.UseToolMiddleware(securityPolicy.Middleware())
+
+	// List and allow only the tools, contexts and namespaces of each caller's access rules
+	mcpServer.AddToolListFilter(access.ToolListFilter())
+	mcpServer.UseToolMiddleware(access.Middleware())
+
+	// List and allow only the tools the caller's OAuth token has the scopes for
+	mcpServer.AddToolListFilter(scopes.ToolListFilter())
+	mcpServer.UseToolMiddleware(scopes.Middleware())
+
+	// Throttle tool calls before they ask for confirmation or reach the cluster
+	mcpServer.UseToolMiddleware(limiter.Middleware())
+
+	// Deny, confirm or audit calls matching CEL admission rules
+	mcpServer.UseToolMiddleware(admission.Middleware())
+
+	// Strip, project, tabulate and cut list and get results, after redaction
+	mcpServer.UseToolMiddleware(mcp.NewOutputShaper(cfg.Output.MaxBytes).Middleware())
+
+	// Mask Secret data and credentials in tool output unless revealed
+	redactor, err := security.NewRedactor(&cfg.Security.Redaction)
+	if err != nil {
+		log.Fatalf("Failed to create redaction policy: %v", err)
+	}
+	mcpServer.UseToolMiddleware(redactor.Middleware())
+
+	// Ask the user to confirm destructive, rule-matched and reveal calls via elicitation
+	confirmation, err := security.NewConfirmation(&cfg.Security.Confirmation, provider)
+	if err != nil {
+		log.Fatalf("Failed to create confirmation policy: %v", err)
+	}
+	mcpServer.UseToolMiddleware(confirmation.Middleware())
+	if cfg.Security.Confirmation.Enabled && cfg.Server.HTTP.Stateless && usesTransport(cfg, *transport, "http") {
+		log.Printf("Warning: stateless HTTP sessions cannot ask for confirmation; HTTP calls that need it are decided by security.confirmation.fallback (%s)", cfg.Security.Confirmation.Fallback)
+	}
+
+	// Check callers' permissions in the cluster of the context each call
+	// targets, with one cache for all toolsets
+	var rbacAuthorizer kubernetes.RBACAuthorizer
+	if cfg.Security.RequireRBAC {
+		rbacAuthorizer = kubernetes.NewRBACAuthorizer(provider, cfg.Security.RBACCacheTTL)
+	}
 
 	// Register toolsets with observability
-	if err := registerToolsets(mcpServer, provider, crdDiscovery, cfg, obsLogger, obsMetrics, defaultClientSet); err != nil {
+	if err := registerToolsets(mcpServer, provider, crdDiscovery, cfg, obsLogger, obsMetrics, rbacAuthorizer, healthChecker); err != nil {
 		log.Fatalf("Failed to register toolsets: %v", err)
 	}
 
+	// Expose Kubernetes objects, pod logs and Helm releases as MCP resources,
+	// and complete prompt arguments and resource template variables
+	checkResource := func(ctx context.Context, toolName string, target mcp.Target) error {
+		return errors.Join(scopes.CheckResource(ctx, toolName, target), access.CheckResource(ctx, toolName, target))
+	}
+	resourceProvider := mcp.NewResourceProvider(provider)
+	resourceProvider.SetRedactor(redactor.Redact)
+	resourceProvider.SetAccessCheck(checkResource)
+	completer := mcp.NewCompleter(provider, mcp.DefaultCompletionCacheTTL)
+	completer.SetAccessCheck(checkResource)
+	completer.SetContextCheck(func(ctx context.Context, toolName, contextName string) error {
+		return errors.Join(scopes.CheckResource(ctx, toolName, mcp.Target{Context: contextName}), access.CheckContext(ctx, toolName, contextName))
+	})
+	resourceProvider.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
+	completer.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
+	mcpServer.RegisterResources(resourceProvider)
+	mcpServer.RegisterCompleter(completer)
+	healthChecker.SetRegistered()
+
 	// Determine transport
 	transports := cfg.Server.Transports
 	if *transport != "" {
@@ -124,7 +289,8 @@ func main() {
 	}
 
 	// Start transports with observability
-	if err := startTransports(ctx, mcpServer, cfg, transports, obsLogger, obsMetrics, defaultClientSet); err != nil {
+	httpServers, err := startTransports(ctx, mcpServer, cfg, transports, obsLogger, obsMetrics, defaultClientSet, limiter, healthChecker)
+	if err != nil {
 		log.Fatalf("Failed to start transports: %v", err)
 	}
 
@@ -133,10 +299,32 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
-	log.Println("Shutting down...")
+	// Report not ready and let in-flight requests finish within the grace period
+	log.Printf("Shutting down, waiting up to %s for in-flight requests...", cfg.Server.ShutdownGracePeriod.Duration())
+	healthChecker.SetDraining()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownGracePeriod.Duration())
+	defer cancelShutdown()
+	for _, httpServer := range httpServers {
+		if err := httpServer.Stop(shutdownCtx); err != nil {
+			log.Printf("HTTP server shutdown: %v", err)
+		}
+	}
 	cancel()
 }
 
+// usesTransport reports whether the named transport will be started.
+func usesTransport(cfg *config.Config, transportOverride, name string) bool {
+	if transportOverride != "" {
+		return transportOverride == name
+	}
+	for _, t := range cfg.Server.Transports {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
 // registerToolsets registers all toolsets with the MCP server.
 func registerToolsets(
 	mcpServer *mcp.Server,
@@ -145,41 +333,50 @@ func registerToolsets(
 	cfg *config.Config,
 	logger *observability.Logger,
 	metrics *observability.Metrics,
-	defaultClientSet *kubernetes.ClientSet,
+	rbacAuthorizer kubernetes.RBACAuthorizer,
+	healthChecker *health.Checker,
 ) error {
 	// Config toolset (always enabled)
 	cfgToolset := configToolset.NewToolset(provider)
 	if err := mcpServer.RegisterToolset(cfgToolset); err != nil {
 		return fmt.Errorf("failed to register config toolset: %w", err)
 	}
+	healthChecker.AddToolset(cfgToolset.Name(), true)
 
 	// Core toolset (always enabled)
 	coreToolset := core.NewToolset(provider)
 	coreToolset.SetObservability(logger, metrics)
-
-	// Setup RBAC authorizer for core toolset
-	if cfg.Security.RequireRBAC {
-		rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-		coreToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
+	if err := coreToolset.SetExecConfig(&cfg.Toolsets.Core.Exec); err != nil {
+		return fmt.Errorf("invalid toolsets.core.exec: %w", err)
 	}
+	coreToolset.SetPortForwardConfig(&cfg.Toolsets.Core.PortForward, cfg.Server.HTTP.Stateless)
+	coreToolset.SetStreamConfig(&cfg.Toolsets.Core.Stream)
+	coreToolset.SetLogsConfig(&cfg.Toolsets.Core.Logs)
+
+	coreToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 
 	if err := mcpServer.RegisterToolset(coreToolset); err != nil {
 		return fmt.Errorf("failed to register core toolset: %w", err)
 	}
+	healthChecker.AddToolset(coreToolset.Name(), true)
 
 	// Helm toolset (always enabled)
 	helmSettings := cli.New()
 	helmToolset := helm.NewToolset(provider, helmSettings)
 	helmToolset.SetObservability(logger, metrics)
+	helmToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 	if err := mcpServer.RegisterToolset(helmToolset); err != nil {
 		return fmt.Errorf("failed to register helm toolset: %w", err)
 	}
+	healthChecker.AddToolset(helmToolset.Name(), true)
 
 	// KubeVirt toolset (conditional)
 	if cfg.KubeVirt.Enabled {
 		kubevirtToolset := kubevirt.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(kubevirtToolset.Name(), kubevirtToolset.IsEnabled(), kubevirt.VirtualMachineGVK)
 		if kubevirtToolset.IsEnabled() {
 			kubevirtToolset.SetObservability(logger, metrics)
+			kubevirtToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(kubevirtToolset); err != nil {
 				return fmt.Errorf("failed to register kubevirt toolset: %w", err)
 			}
@@ -193,8 +390,10 @@ func registerToolsets(
 		if err != nil {
 			return fmt.Errorf("failed to create kiali toolset: %w", err)
 		}
+		healthChecker.AddToolset(kialiToolset.Name(), kialiToolset.IsEnabled())
 		if kialiToolset.IsEnabled() {
 			kialiToolset.SetObservability(logger, metrics)
+			kialiToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(kialiToolset); err != nil {
 				return fmt.Errorf("failed to register kiali toolset: %w", err)
 			}
@@ -205,12 +404,10 @@ func registerToolsets(
 	// GitOps toolset (conditional)
 	if cfg.Toolsets.GitOps.Enabled {
 		gitopsToolset := gitops.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(gitopsToolset.Name(), gitopsToolset.IsEnabled(), gitops.KustomizationGVK, gitops.HelmReleaseGVK, gitops.ApplicationGVK)
 		if gitopsToolset.IsEnabled() {
 			gitopsToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				gitopsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			gitopsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(gitopsToolset); err != nil {
 				return fmt.Errorf("failed to register gitops toolset: %w", err)
 			}
@@ -221,8 +418,10 @@ func registerToolsets(
 	// Policy toolset (conditional)
 	if cfg.Toolsets.Policy.Enabled {
 		policyToolset := policy.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(policyToolset.Name(), policyToolset.IsEnabled(), policy.KyvernoClusterPolicyGVK, policy.KyvernoPolicyGVK, policy.GatekeeperConstraintTemplateGVK)
 		if policyToolset.IsEnabled() {
 			policyToolset.SetObservability(logger, metrics)
+			policyToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(policyToolset); err != nil {
 				return fmt.Errorf("failed to register policy toolset: %w", err)
 			}
@@ -233,12 +432,10 @@ func registerToolsets(
 	// CAPI toolset (conditional)
 	if cfg.Toolsets.CAPI.Enabled {
 		capiToolset := capi.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(capiToolset.Name(), capiToolset.IsEnabled(), capi.ClusterGVK, capi.MachineGVK, capi.MachineDeploymentGVK)
 		if capiToolset.IsEnabled() {
 			capiToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				capiToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			capiToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(capiToolset); err != nil {
 				return fmt.Errorf("failed to register capi toolset: %w", err)
 			}
@@ -249,12 +446,10 @@ func registerToolsets(
 	// Rollouts toolset (conditional)
 	if cfg.Toolsets.Rollouts.Enabled {
 		rolloutsToolset := rollouts.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(rolloutsToolset.Name(), rolloutsToolset.IsEnabled(), rollouts.RolloutGVK, rollouts.CanaryGVK)
 		if rolloutsToolset.IsEnabled() {
 			rolloutsToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				rolloutsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			rolloutsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(rolloutsToolset); err != nil {
 				return fmt.Errorf("failed to register rollouts toolset: %w", err)
 			}
@@ -265,12 +460,10 @@ func registerToolsets(
 	// Certs toolset (conditional)
 	if cfg.Toolsets.Certs.Enabled {
 		certsToolset := certs.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(certsToolset.Name(), certsToolset.IsEnabled(), certs.CertificateGVK, certs.IssuerGVK, certs.ClusterIssuerGVK)
 		if certsToolset.IsEnabled() {
 			certsToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				certsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			certsToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(certsToolset); err != nil {
 				return fmt.Errorf("failed to register certs toolset: %w", err)
 			}
@@ -281,12 +474,10 @@ func registerToolsets(
 	// Autoscaling toolset (conditional)
 	if cfg.Toolsets.Autoscaling.Enabled {
 		autoscalingToolset := autoscaling.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(autoscalingToolset.Name(), autoscalingToolset.IsEnabled(), autoscaling.ScaledObjectGVK, autoscaling.ScaledJobGVK)
 		if autoscalingToolset.IsEnabled() {
 			autoscalingToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				autoscalingToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			autoscalingToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(autoscalingToolset); err != nil {
 				return fmt.Errorf("failed to register autoscaling toolset: %w", err)
 			}
@@ -297,12 +488,10 @@ func registerToolsets(
 	// Backup toolset (conditional)
 	if cfg.Toolsets.Backup.Enabled {
 		backupToolset := backup.NewToolset(provider, crdDiscovery)
+		healthChecker.AddToolset(backupToolset.Name(), backupToolset.IsEnabled(), backup.BackupGVK, backup.RestoreGVK, backup.ScheduleGVK)
 		if backupToolset.IsEnabled() {
 			backupToolset.SetObservability(logger, metrics)
-			if cfg.Security.RequireRBAC {
-				rbacAuthorizer := kubernetes.NewRBACAuthorizer(defaultClientSet, cfg.Security.RBACCacheTTL)
-				backupToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
-			}
+			backupToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(backupToolset); err != nil {
 				return fmt.Errorf("failed to register backup toolset: %w", err)
 			}
@@ -313,8 +502,10 @@ func registerToolsets(
 	// Network toolset (conditional)
 	if cfg.Toolsets.Net.Enabled {
 		netToolset := net.NewToolset(provider, crdDiscovery, cfg.Toolsets.Net)
+		healthChecker.AddToolset(netToolset.Name(), netToolset.IsEnabled(), net.CiliumNetworkPolicyGVK, net.CiliumClusterwideNetworkPolicyGVK)
 		if netToolset.IsEnabled() {
 			netToolset.SetObservability(logger, metrics)
+			netToolset.SetRBACAuthorizer(rbacAuthorizer, cfg.Security.RequireRBAC)
 			if err := mcpServer.RegisterToolset(netToolset); err != nil {
 				return fmt.Errorf("failed to register net toolset: %w", err)
 			}
@@ -334,7 +525,10 @@ func startTransports(
 	logger *observability.Logger,
 	metrics *observability.Metrics,
 	defaultClientSet *kubernetes.ClientSet,
-) error {
+	limiter *ratelimit.Limiter,
+	healthChecker *health.Checker,
+) ([]*http.Server, error) {
+	var httpServers []*http.Server
 	for _, transportName := range transports {
 		switch transportName {
 		case "stdio":
@@ -346,21 +540,27 @@ func startTransports(
 			}()
 
 		case "http":
-			log.Printf("Starting HTTP transport on %s...", cfg.Server.HTTP.Address)
-			httpServer, err := http.NewServer(mcpServer, &cfg.Server.HTTP, logger, metrics, defaultClientSet, &cfg.Security)
+			if cfg.Server.HTTP.TLS.Enabled {
+				log.Printf("Starting HTTPS transport on %s...", cfg.Server.HTTP.Address)
+			} else {
+				log.Printf("Starting HTTP transport on %s...", cfg.Server.HTTP.Address)
+			}
+			httpServer, err := http.NewServer(mcpServer, &cfg.Server.HTTP, logger, metrics, defaultClientSet, &cfg.Security, limiter)
 			if err != nil {
-				return fmt.Errorf("failed to create HTTP server: %w", err)
+				return nil, fmt.Errorf("failed to create HTTP server: %w", err)
 			}
+			httpServer.SetHealthChecker(healthChecker)
 			go func() {
-				if err := httpServer.Start(); err != nil {
+				if err := httpServer.Start(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
 					log.Printf("HTTP server error: %v", err)
 				}
 			}()
+			httpServers = append(httpServers, httpServer)
 
 		default:
-			return fmt.Errorf("unknown transport: %s", transportName)
+			return nil, fmt.Errorf("unknown transport: %s", transportName)
 		}
 	}
 
-	return nil
+	return httpServers, nil
 }
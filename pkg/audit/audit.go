@@ -0,0 +1,397 @@
+// Package audit records every tool invocation in a hash-chained, append-only
+// audit log, separate from the general log stream.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Outcomes of a tool invocation.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	OutcomeDenied  = "denied"
+	OutcomePanic   = "panic"
+)
+
+// maxErrorLength bounds the error text recorded for failed calls.
+const maxErrorLength = 1024
+
+// Entry is one audit record. Entries are chained: PrevHash is the Hash of the
+// previous entry, and Hash covers the entry's serialized form including
+// PrevHash, so changing, removing or reordering entries breaks the chain.
+type Entry struct {
+	Seq          uint64                    `json:"seq"`
+	Time         time.Time                 `json:"time"`
+	Caller       *Caller                   `json:"caller,omitempty"`
+	SessionID    string                    `json:"session_id,omitempty"`
+	Tool         string                    `json:"tool"`
+	Arguments    map[string]any            `json:"arguments,omitempty"`
+	Target       mcpHelpers.Target         `json:"target"`
+	RBAC         []kubernetes.RBACDecision `json:"rbac,omitempty"`
+	Admission    []AdmissionDecision       `json:"admission,omitempty"`
+	Outcome      string                    `json:"outcome"`
+	Error        string                    `json:"error,omitempty"`
+	DurationMS   int64                     `json:"duration_ms"`
+	ResultDigest string                    `json:"result_digest,omitempty"`
+	PrevHash     string                    `json:"prev_hash"`
+	Hash         string                    `json:"hash,omitempty"`
+}
+
+// Caller is the authenticated caller of a tool. It is absent for callers
+// without an identity, such as the stdio transport.
+type Caller struct {
+	Username string   `json:"username"`
+	UID      string   `json:"uid,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// AdmissionDecision is an admission rule that matched a call, including
+// deny rules that rejected it.
+type AdmissionDecision struct {
+	Rule    string `json:"rule"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// recorder collects what a call's middleware and handler report while the
+// call runs, for its entry.
+type recorder struct {
+	mu    sync.Mutex
+	entry *Entry
+}
+
+type recorderKey struct{}
+
+// RecordAdmissionDecisions records admission decisions in the audit entry of
+// the call ctx belongs to. It does nothing if the call is not audited.
+func RecordAdmissionDecisions(ctx context.Context, decisions ...AdmissionDecision) {
+	rec, ok := ctx.Value(recorderKey{}).(*recorder)
+	if !ok {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.entry.Admission = append(rec.entry.Admission, decisions...)
+}
+
+// Auditor appends entries to its sinks. A nil *Auditor is valid and records
+// nothing.
+type Auditor struct {
+	sinks []Sink
+	now   func() time.Time
+
+	mu       sync.Mutex
+	seq      uint64
+	prevHash string
+}
+
+// NewAuditor creates an auditor writing to the given sinks. If a sink keeps
+// earlier entries (see Resumer), the chain continues from its last entry.
+func NewAuditor(sinks ...Sink) (*Auditor, error) {
+	a := &Auditor{sinks: sinks, now: time.Now}
+	for _, sink := range sinks {
+		resumer, ok := sink.(Resumer)
+		if !ok {
+			continue
+		}
+		line, err := resumer.LastLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read last audit entry: %w", err)
+		}
+		if len(line) == 0 {
+			continue
+		}
+		var last Entry
+		if err := json.Unmarshal(line, &last); err != nil {
+			return nil, fmt.Errorf("failed to parse last audit entry: %w", err)
+		}
+		a.seq, a.prevHash = last.Seq, last.Hash
+		break
+	}
+	return a, nil
+}
+
+// NewFromConfig creates an auditor with the sinks enabled in cfg. It returns
+// nil if auditing is disabled.
+func NewFromConfig(cfg *config.AuditConfig) (*Auditor, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	var sinks []Sink
+	closeAll := func() {
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
+	}
+	if cfg.File.Path != "" {
+		sink, err := NewFileSink(cfg.File.Path, cfg.File.MaxSizeMB, cfg.File.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Stdout {
+		sinks = append(sinks, NewWriterSink(os.Stdout))
+	}
+	if cfg.Webhook.URL != "" {
+		sinks = append(sinks, NewWebhookSink(cfg.Webhook.URL, cfg.Webhook.Headers, cfg.Webhook.Timeout.Duration()))
+	}
+	if len(sinks) == 0 {
+		return nil, fmt.Errorf("audit log is enabled but no sink is configured")
+	}
+
+	auditor, err := NewAuditor(sinks...)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return auditor, nil
+}
+
+// Close closes all sinks.
+func (a *Auditor) Close() error {
+	if a == nil {
+		return nil
+	}
+	var errs []error
+	for _, sink := range a.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Middleware returns tool middleware that records every call. It must be
+// added before any other middleware, so calls they reject are recorded too.
+// On a nil auditor it passes calls through.
+func (a *Auditor) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		if a == nil {
+			return next
+		}
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (result *mcp.CallToolResult, err error) {
+			start := a.now()
+			entry := &Entry{
+				Time:   start.UTC(),
+				Caller: callerFromContext(ctx),
+				Tool:   call.Name,
+				Target: mcpHelpers.TargetOf(call.Name, call.Arguments),
+			}
+			entry.Arguments = Redact(entry.Target, call.Arguments)
+			if call.Request != nil && call.Request.Session != nil {
+				entry.SessionID = call.Request.Session.ID()
+			}
+
+			rec := &recorder{entry: entry}
+			ctx = context.WithValue(ctx, recorderKey{}, rec)
+			ctx = kubernetes.WithRBACObserver(ctx, func(decision kubernetes.RBACDecision) {
+				rec.mu.Lock()
+				defer rec.mu.Unlock()
+				entry.RBAC = append(entry.RBAC, decision)
+			})
+
+			defer func() {
+				rec.mu.Lock()
+				defer rec.mu.Unlock()
+				entry.DurationMS = a.now().Sub(start).Milliseconds()
+				if r := recover(); r != nil {
+					entry.Outcome = OutcomePanic
+					entry.Error = truncate(fmt.Sprint(r))
+					a.record(entry)
+					panic(r)
+				}
+				entry.Outcome, entry.Error = outcome(entry.RBAC, result, err)
+				entry.ResultDigest = digest(result)
+				a.record(entry)
+			}()
+
+			return next(ctx, call)
+		}
+	}
+}
+
+// record chains the entry and writes it to every sink. Sink errors are
+// logged rather than failing the call.
+func (a *Auditor) record(entry *Entry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.Seq = a.seq + 1
+	entry.PrevHash = a.prevHash
+	entry.Hash = ""
+	line, hash, err := encode(entry)
+	if err != nil {
+		log.Printf("audit: failed to encode entry for %s: %v", entry.Tool, err)
+		return
+	}
+	a.seq, a.prevHash = entry.Seq, hash
+
+	for _, sink := range a.sinks {
+		if err := sink.Write(line); err != nil {
+			log.Printf("audit: failed to write entry %d: %v", entry.Seq, err)
+		}
+	}
+}
+
+// callerFromContext returns the authenticated caller in ctx, if any.
+func callerFromContext(ctx context.Context) *Caller {
+	identity := auth.IdentityFromContext(ctx)
+	if identity == nil {
+		return nil
+	}
+	return &Caller{Username: identity.Username, UID: identity.UID, Groups: identity.Groups}
+}
+
+// deniedErrors are the error types of calls rejected by policy middleware
+// before they reached a toolset.
+var deniedErrors = map[string]bool{
+	"FeatureDisabled":      true,
+	"AccessDenied":         true,
+	"InsufficientScope":    true,
+	"RateLimited":          true,
+	"AdmissionDenied":      true,
+	"ConfirmationRequired": true,
+}
+
+// outcome classifies a finished call. Error results of a call rejected by
+// policy, or after a denied RBAC check, are reported as denied.
+func outcome(decisions []kubernetes.RBACDecision, result *mcp.CallToolResult, err error) (string, string) {
+	if err != nil {
+		return OutcomeError, truncate(err.Error())
+	}
+	if result == nil || !result.IsError {
+		return OutcomeSuccess, ""
+	}
+
+	var text string
+	for _, content := range result.Content {
+		if t, ok := content.(*mcp.TextContent); ok {
+			text = t.Text
+			break
+		}
+	}
+	var body struct {
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(text), &body) == nil && deniedErrors[body.Error.Type] {
+		return OutcomeDenied, truncate(text)
+	}
+	for _, decision := range decisions {
+		if !decision.Allowed {
+			return OutcomeDenied, truncate(text)
+		}
+	}
+	return OutcomeError, truncate(text)
+}
+
+// digest returns the SHA-256 of the serialized result, so an entry can be
+// matched with a result without storing it.
+func digest(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return s[:maxErrorLength] + "..."
+}
+
+// sensitiveKeys are substrings of argument names whose values are redacted.
+// pods_exec stdin is included, since commands are often fed credentials.
+var sensitiveKeys = []string{"password", "passwd", "token", "credential", "apikey", "api_key", "secret", "key", "stdin"}
+
+// Redact returns a copy of the arguments with sensitive values replaced by a
+// fingerprint: values of arguments whose names look like credentials, and
+// Secret data and JSON patch values when the call targets a Secret, in nested
+// objects and lists alike. The fingerprint lets auditors tell whether two
+// calls used the same value without revealing it.
+func Redact(target mcpHelpers.Target, args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	return redactMap(args, target.Kind == "Secret")
+}
+
+func redactMap(m map[string]any, secret bool) map[string]any {
+	redacted := make(map[string]any, len(m))
+	for key, value := range m {
+		switch {
+		case isSensitive(key):
+			redacted[key] = fingerprint(value)
+		case secret && (key == "data" || key == "string_data" || key == "stringData"):
+			if values, ok := value.(map[string]any); ok {
+				masked := make(map[string]any, len(values))
+				for k, v := range values {
+					masked[k] = fingerprint(v)
+				}
+				redacted[key] = masked
+			} else {
+				redacted[key] = fingerprint(value)
+			}
+		case secret && key == "value":
+			// The value of a JSON patch operation, e.g. on /data/password
+			redacted[key] = fingerprint(value)
+		default:
+			redacted[key] = redactValue(value, secret)
+		}
+	}
+	return redacted
+}
+
+func redactValue(value any, secret bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactMap(v, secret)
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item, secret)
+		}
+		return redacted
+	}
+	return value
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func fingerprint(value any) string {
+	data, _ := json.Marshal(value)
+	sum := sha256.Sum256(data)
+	return "<redacted sha256:" + hex.EncodeToString(sum[:6]) + ">"
+}
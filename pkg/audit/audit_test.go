@@ -0,0 +1,351 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// memorySink keeps written lines in memory.
+type memorySink struct {
+	lines [][]byte
+}
+
+func (s *memorySink) Write(line []byte) error {
+	s.lines = append(s.lines, bytes.Clone(line))
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) entries() []Entry {
+	var entries []Entry
+	for _, line := range s.lines {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// clientSetProvider returns a fixed client set for every context.
+type clientSetProvider struct {
+	clientSet *kubernetes.ClientSet
+}
+
+func (p *clientSetProvider) GetClientSet(string) (*kubernetes.ClientSet, error) {
+	return p.clientSet, nil
+}
+func (p *clientSetProvider) ListContexts() ([]string, error)    { return nil, nil }
+func (p *clientSetProvider) GetCurrentContext() (string, error) { return "", nil }
+
+// AuditTestSuite tests audit entries, the hash chain and the file sink.
+type AuditTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+// authorizer returns an RBAC authorizer that allows only alice.
+func (s *AuditTestSuite) authorizer() kubernetes.RBACAuthorizer {
+	typed := fake.NewSimpleClientset()
+	typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == "alice"
+		return true, review, nil
+	})
+	return kubernetes.NewRBACAuthorizer(&clientSetProvider{clientSet: &kubernetes.ClientSet{Typed: typed}}, 60)
+}
+
+// call runs a call of tool through the auditor's middleware to handler.
+func (s *AuditTestSuite) call(ctx context.Context, auditor *Auditor, tool string, args map[string]any, handler mcpHelpers.ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	return auditor.Middleware()(handler)(ctx, &mcpHelpers.ToolCall{Name: tool, Arguments: args})
+}
+
+// TestMiddleware tests the recorded caller, target, redaction, RBAC decisions and outcome.
+func (s *AuditTestSuite) TestMiddleware() {
+	sink := &memorySink{}
+	auditor, err := NewAuditor(sink)
+	s.Require().NoError(err)
+
+	authorizer := s.authorizer()
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	handler := func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		allowed, err := authorizer.Allowed(ctx, auth.IdentityFromContext(ctx), "", "update", gvr, "default")
+		if err != nil || !allowed {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "forbidden"}}}, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "updated"}}}, nil
+	}
+
+	args := map[string]any{
+		"name":      "db",
+		"namespace": "default",
+		"data":      map[string]any{"password": "hunter2"},
+		"options":   map[string]any{"api_token": "abc"},
+	}
+	for _, user := range []string{"alice", "bob"} {
+		ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: user, Groups: []string{"dev"}})
+		_, err = s.call(ctx, auditor, "secrets_set_data", args, handler)
+		s.Require().NoError(err)
+	}
+
+	entries := sink.entries()
+	s.Require().Len(entries, 2)
+
+	allowed := entries[0]
+	s.Equal(uint64(1), allowed.Seq)
+	s.Equal("secrets_set_data", allowed.Tool)
+	s.Equal(&Caller{Username: "alice", Groups: []string{"dev"}}, allowed.Caller)
+	s.Equal("Secret", allowed.Target.Kind)
+	s.Equal("db", allowed.Target.Name)
+	s.Equal("default", allowed.Target.Namespace)
+	s.Equal(OutcomeSuccess, allowed.Outcome)
+	s.True(strings.HasPrefix(allowed.ResultDigest, "sha256:"))
+	s.Require().Len(allowed.RBAC, 1)
+	s.Equal(kubernetes.RBACDecision{Verb: "update", Resource: "secrets", Namespace: "default", Allowed: true}, allowed.RBAC[0])
+
+	data := allowed.Arguments["data"].(map[string]any)
+	s.True(strings.HasPrefix(data["password"].(string), "<redacted sha256:"))
+	options := allowed.Arguments["options"].(map[string]any)
+	s.True(strings.HasPrefix(options["api_token"].(string), "<redacted sha256:"))
+	s.Equal("db", allowed.Arguments["name"])
+	s.NotContains(string(sink.lines[0]), "hunter2")
+
+	denied := entries[1]
+	s.Equal(OutcomeDenied, denied.Outcome)
+	s.Equal("forbidden", denied.Error)
+	s.Equal(allowed.Hash, denied.PrevHash)
+	s.Equal(data["password"], denied.Arguments["data"].(map[string]any)["password"], "Fingerprints should be stable")
+}
+
+// TestRedact tests redaction in lists, of JSON patches on Secrets and of
+// exec stdin.
+func (s *AuditTestSuite) TestRedact() {
+	redacted := func(value any) bool {
+		text, ok := value.(string)
+		return ok && strings.HasPrefix(text, "<redacted sha256:")
+	}
+	secret := mcpHelpers.Target{Version: "v1", Kind: "Secret", Name: "db"}
+
+	args := Redact(secret, map[string]any{
+		"patch_type": "json",
+		"patch_data": []any{
+			map[string]any{"op": "replace", "path": "/data/password", "value": "aHVudGVyMg=="},
+		},
+	})
+	op := args["patch_data"].([]any)[0].(map[string]any)
+	s.Equal("/data/password", op["path"])
+	s.True(redacted(op["value"]))
+
+	args = Redact(mcpHelpers.Target{Group: "apps", Version: "v1", Kind: "Deployment"}, map[string]any{
+		"patch_data": []any{
+			map[string]any{"op": "replace", "path": "/spec/replicas", "value": 3.0},
+			map[string]any{"op": "add", "path": "/spec/template/spec/containers/0/env/0", "value": map[string]any{"name": "DB", "secretKeyRef": "db"}},
+		},
+	})
+	ops := args["patch_data"].([]any)
+	s.Equal(3.0, ops[0].(map[string]any)["value"], "Values are only redacted on Secrets")
+	s.True(redacted(ops[1].(map[string]any)["value"].(map[string]any)["secretKeyRef"]), "Lists should be searched for sensitive keys")
+
+	args = Redact(mcpHelpers.Target{Version: "v1", Kind: "Pod", Name: "web"}, map[string]any{
+		"command": []any{"psql"},
+		"stdin":   "\\password hunter2",
+		"ssh_key": "-----BEGIN",
+	})
+	s.Equal([]any{"psql"}, args["command"])
+	s.True(redacted(args["stdin"]))
+	s.True(redacted(args["ssh_key"]))
+}
+
+// TestErrorAndPanic tests the outcome of failed and panicking handlers.
+func (s *AuditTestSuite) TestErrorAndPanic() {
+	sink := &memorySink{}
+	auditor, err := NewAuditor(sink)
+	s.Require().NoError(err)
+
+	_, err = s.call(context.Background(), auditor, "pods_get", map[string]any{"name": "web"}, func(context.Context, *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("boom")
+	})
+	s.Error(err)
+
+	s.Panics(func() {
+		_, _ = s.call(context.Background(), auditor, "pods_get", nil, func(context.Context, *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			panic("nil map")
+		})
+	})
+
+	entries := sink.entries()
+	s.Require().Len(entries, 2)
+	s.Equal(OutcomeError, entries[0].Outcome)
+	s.Equal("boom", entries[0].Error)
+	s.Equal("Pod", entries[0].Target.Kind)
+	s.Nil(entries[0].Caller)
+	s.Equal(OutcomePanic, entries[1].Outcome)
+	s.Equal("nil map", entries[1].Error)
+}
+
+// TestRejected tests that calls rejected by later middleware are recorded as
+// denied, with the admission rules they matched.
+func (s *AuditTestSuite) TestRejected() {
+	sink := &memorySink{}
+	auditor, err := NewAuditor(sink)
+	s.Require().NoError(err)
+
+	_, err = s.call(context.Background(), auditor, "pods_delete", map[string]any{"name": "web"}, func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		RecordAdmissionDecisions(ctx, AdmissionDecision{Rule: "no-deletes", Action: "deny", Message: "Deletes are not allowed"})
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{
+			&mcp.TextContent{Text: `{"error":{"type":"AdmissionDenied","message":"Deletes are not allowed"}}`},
+		}}, nil
+	})
+	s.Require().NoError(err)
+
+	entries := sink.entries()
+	s.Require().Len(entries, 1)
+	s.Equal(OutcomeDenied, entries[0].Outcome)
+	s.Equal([]AdmissionDecision{{Rule: "no-deletes", Action: "deny", Message: "Deletes are not allowed"}}, entries[0].Admission)
+	s.Empty(entries[0].RBAC)
+}
+
+// TestNilAuditor tests that a nil auditor passes calls through.
+func (s *AuditTestSuite) TestNilAuditor() {
+	var auditor *Auditor
+	called := false
+	_, err := s.call(context.Background(), auditor, "pods_get", nil, func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		RecordAdmissionDecisions(ctx, AdmissionDecision{Rule: "any", Action: "audit"})
+		called = true
+		return nil, nil
+	})
+	s.NoError(err)
+	s.True(called)
+	s.NoError(auditor.Close())
+}
+
+// record writes n entries through the auditor.
+func (s *AuditTestSuite) record(auditor *Auditor, n int) {
+	handler := func(context.Context, *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	for i := 0; i < n; i++ {
+		_, err := s.call(context.Background(), auditor, "pods_list", map[string]any{"namespace": fmt.Sprintf("ns-%d", i)}, handler)
+		s.Require().NoError(err)
+	}
+}
+
+// TestVerify tests that the verifier accepts an intact chain and detects tampering.
+func (s *AuditTestSuite) TestVerify() {
+	sink := &memorySink{}
+	auditor, err := NewAuditor(sink)
+	s.Require().NoError(err)
+	s.record(auditor, 3)
+
+	verify := func(lines [][]byte) (*Verifier, error) {
+		verifier := &Verifier{}
+		return verifier, verifier.Verify(bytes.NewReader(bytes.Join(lines, nil)), "audit.log")
+	}
+
+	verifier, err := verify(sink.lines)
+	s.Require().NoError(err)
+	s.Equal(3, verifier.Entries)
+	s.Equal(uint64(1), verifier.FirstSeq)
+	s.Equal(uint64(3), verifier.LastSeq)
+
+	modified := [][]byte{sink.lines[0], bytes.Replace(sink.lines[1], []byte("ns-1"), []byte("ns-9"), 1), sink.lines[2]}
+	_, err = verify(modified)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "audit.log:2: entry 2: hash mismatch")
+
+	_, err = verify([][]byte{sink.lines[0], sink.lines[2]})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "expected sequence 2")
+
+	// A suffix of the chain verifies on its own, e.g. after rotation
+	verifier, err = verify(sink.lines[1:])
+	s.Require().NoError(err)
+	s.Equal(uint64(2), verifier.FirstSeq)
+}
+
+// TestFileSinkResume tests that a new auditor continues the chain of an existing file.
+func (s *AuditTestSuite) TestFileSinkResume() {
+	path := filepath.Join(s.dir, "audit", "audit.log")
+	for i := 0; i < 2; i++ {
+		sink, err := NewFileSink(path, 100, 3)
+		s.Require().NoError(err)
+		auditor, err := NewAuditor(sink)
+		s.Require().NoError(err)
+		s.record(auditor, 2)
+		s.Require().NoError(auditor.Close())
+	}
+
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+	verifier := &Verifier{}
+	s.Require().NoError(verifier.Verify(file, path))
+	s.Equal(4, verifier.Entries)
+
+	info, err := os.Stat(path)
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
+
+// TestFileSinkRotation tests that rotated files keep one chain across files.
+func (s *AuditTestSuite) TestFileSinkRotation() {
+	path := filepath.Join(s.dir, "audit.log")
+	sink, err := NewFileSink(path, 1, 2)
+	s.Require().NoError(err)
+	sink.maxSize = 1024
+	auditor, err := NewAuditor(sink)
+	s.Require().NoError(err)
+	s.record(auditor, 20)
+	s.Require().NoError(auditor.Close())
+
+	_, err = os.Stat(path + ".3")
+	s.True(os.IsNotExist(err), "Only max_backups rotated files should be kept")
+
+	verifier := &Verifier{}
+	for _, name := range []string{path + ".2", path + ".1", path} {
+		file, err := os.Open(name)
+		s.Require().NoError(err)
+		s.Require().NoError(verifier.Verify(file, name))
+		file.Close()
+	}
+	s.Equal(uint64(20), verifier.LastSeq)
+	s.Greater(verifier.FirstSeq, uint64(1))
+
+	// A new auditor resumes from the newest file
+	sink, err = NewFileSink(path, 1, 2)
+	s.Require().NoError(err)
+	auditor, err = NewAuditor(sink)
+	s.Require().NoError(err)
+	s.Equal(uint64(20), auditor.seq)
+	s.Equal(verifier.LastHash, auditor.prevHash)
+	s.Require().NoError(auditor.Close())
+}
+
+// TestAuditTestSuite runs the audit test suite.
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
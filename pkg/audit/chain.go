@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// encode serializes an entry as one JSON line and returns it with its hash.
+// The hash is the SHA-256 of the entry serialized without its hash field; the
+// hash field is then appended as the last field, so verification can recover
+// the exact hashed bytes from the line.
+func encode(entry *Entry) ([]byte, string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(entry); err != nil {
+		return nil, "", err
+	}
+	body := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	line := make([]byte, 0, len(body)+len(hash)+12)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, `,"hash":"`...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+	return line, hash, nil
+}
+
+// Verifier checks the hash chain of audit log files. Files must be verified
+// in order, oldest first; the chain continues across files.
+type Verifier struct {
+	// Entries is the number of entries verified so far.
+	Entries int
+
+	// FirstSeq and LastSeq are the sequence numbers of the first and last
+	// verified entries.
+	FirstSeq uint64
+	LastSeq  uint64
+
+	// LastHash is the hash of the last verified entry, the head of the chain.
+	LastHash string
+}
+
+// Verify checks every entry read from r, and that the first entry continues
+// the chain verified so far. source names r in errors.
+func (v *Verifier) Verify(r io.Reader, source string) error {
+	reader := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if verr := v.verifyLine(bytes.TrimRight(line, "\r\n")); verr != nil {
+				return fmt.Errorf("%s:%d: %w", source, lineNo, verr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+}
+
+// verifyLine checks one entry against its own hash and the previous entry.
+func (v *Verifier) verifyLine(line []byte) error {
+	var entry Entry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return fmt.Errorf("invalid entry: %w", err)
+	}
+
+	suffix := []byte(`,"hash":"` + entry.Hash + `"}`)
+	if entry.Hash == "" || !bytes.HasSuffix(line, suffix) {
+		return fmt.Errorf("entry %d: hash is missing or not the last field", entry.Seq)
+	}
+	body := append(bytes.Clone(line[:len(line)-len(suffix)]), '}')
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != entry.Hash {
+		return fmt.Errorf("entry %d: hash mismatch, the entry was modified", entry.Seq)
+	}
+
+	if v.Entries > 0 {
+		if entry.Seq != v.LastSeq+1 {
+			return fmt.Errorf("entry %d: expected sequence %d, entries are missing or reordered", entry.Seq, v.LastSeq+1)
+		}
+		if entry.PrevHash != v.LastHash {
+			return fmt.Errorf("entry %d: previous hash does not match entry %d", entry.Seq, v.LastSeq)
+		}
+	} else {
+		v.FirstSeq = entry.Seq
+	}
+
+	v.Entries++
+	v.LastSeq = entry.Seq
+	v.LastHash = entry.Hash
+	return nil
+}
@@ -0,0 +1,282 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sink receives serialized audit entries, one JSON line per entry.
+type Sink interface {
+	Write(line []byte) error
+	Close() error
+}
+
+// Resumer is implemented by sinks that keep earlier entries, so a restarted
+// server can continue the hash chain instead of starting a new one.
+type Resumer interface {
+	// LastLine returns the most recent entry, or nil if there is none.
+	LastLine() ([]byte, error)
+}
+
+// FileSink appends entries to a JSON-lines file and rotates it by size.
+// Rotated files are named <path>.1 (newest) to <path>.<maxBackups> (oldest).
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens (or creates) the audit file. A maxSizeMB of 0 disables
+// rotation.
+func NewFileSink(path string, maxSizeMB, maxBackups int) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	s := &FileSink{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+// Write appends a line, rotating the file first if it would exceed the maximum size.
+func (s *FileSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// rotate shifts <path>.N to <path>.N+1, dropping the oldest, and starts a new file.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	if s.maxBackups > 0 {
+		_ = os.Remove(s.backup(s.maxBackups))
+		for i := s.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(s.backup(i), s.backup(i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate audit log: %w", err)
+			}
+		}
+		if err := os.Rename(s.path, s.backup(1)); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return s.open()
+}
+
+func (s *FileSink) backup(n int) string {
+	return fmt.Sprintf("%s.%d", s.path, n)
+}
+
+// LastLine returns the last entry of the current file, or of the newest
+// backup if the current file is empty.
+func (s *FileSink) LastLine() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range []string{s.path, s.backup(1)} {
+		line, err := lastLine(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(line) > 0 {
+			return line, nil
+		}
+	}
+	return nil, nil
+}
+
+// lastLine reads the last non-empty line of a file, scanning backwards so
+// large files are not read in full.
+func lastLine(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 64 * 1024
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := int64(chunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if offset == 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// WriterSink writes entries to an io.Writer such as stdout.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write writes a line.
+func (s *WriterSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(line)
+	return err
+}
+
+// Close does nothing; the writer is owned by the caller.
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// webhookQueueSize bounds the entries waiting to be delivered.
+const webhookQueueSize = 1024
+
+// WebhookSink POSTs each entry as a JSON document to an HTTP endpoint.
+// Entries are delivered in order by a background goroutine, so a slow
+// endpoint does not slow down tool calls; entries that cannot be queued or
+// delivered are logged and dropped, which the hash chain makes visible to the
+// receiver as a gap.
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	queue chan []byte
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewWebhookSink creates a sink posting to url with the given extra headers.
+func NewWebhookSink(url string, headers map[string]string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	s := &WebhookSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+		queue:   make(chan []byte, webhookQueueSize),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write queues a line for delivery.
+func (s *WebhookSink) Write(line []byte) error {
+	select {
+	case s.queue <- bytes.Clone(line):
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue is full")
+	}
+}
+
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	for line := range s.queue {
+		if err := s.post(line); err != nil {
+			log.Printf("audit: failed to deliver entry to webhook: %v", err)
+		}
+	}
+}
+
+func (s *WebhookSink) post(line []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close delivers the queued entries and stops the sink.
+func (s *WebhookSink) Close() error {
+	s.once.Do(func() { close(s.queue) })
+	<-s.done
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package auth carries the authenticated caller of a request from the transport
+// layer to tool handlers.
+package auth
+
+import (
+	"context"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// Identity is a verified caller.
+type Identity struct {
+	// Username is the Kubernetes-facing user name.
+	Username string
+
+	// UID is a stable unique identifier for the user, if known.
+	UID string
+
+	// Groups the user belongs to.
+	Groups []string
+
+	// Extra holds additional attributes (e.g. from TokenReview or token claims).
+	Extra map[string][]string
+}
+
+type contextKey int
+
+const (
+	identityKey contextKey = iota
+	bearerTokenKey
+	scopesKey
+	allowedToolsKey
+)
+
+// WithIdentity returns a context carrying the identity.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	if identity == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// IdentityFromContext returns the identity stored in ctx, or nil if none.
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey).(*Identity)
+	return identity
+}
+
+// WithBearerToken returns a context carrying the caller's bearer token.
+func WithBearerToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, bearerTokenKey, token)
+}
+
+// BearerTokenFromContext returns the bearer token stored in ctx, or "" if none.
+func BearerTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(bearerTokenKey).(string)
+	return token
+}
+
+// WithScopes returns a context carrying the scopes granted to the caller's token.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// ScopesFromContext returns the scopes granted to the caller's token, and
+// whether the caller is held to tool scope rules at all.
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(scopesKey).([]string)
+	return scopes, ok
+}
+
+// WithAllowedTools returns a context restricting the caller to tools matching
+// the glob patterns, e.g. the tool allowlist of an API key.
+func WithAllowedTools(ctx context.Context, tools []string) context.Context {
+	return context.WithValue(ctx, allowedToolsKey, tools)
+}
+
+// AllowedToolsFromContext returns the tool patterns the caller is restricted
+// to, and whether the caller is restricted at all.
+func AllowedToolsFromContext(ctx context.Context) ([]string, bool) {
+	tools, ok := ctx.Value(allowedToolsKey).([]string)
+	return tools, ok
+}
+
+// Keys used in sdkauth.TokenInfo.Extra. The SDK copies TokenInfo from the HTTP
+// request into each tool call, which is how identities cross the transport.
+const (
+	tokenInfoIdentityKey = "kube-mcp/identity"
+	tokenInfoTokenKey    = "kube-mcp/token"
+	tokenInfoToolsKey    = "kube-mcp/tools"
+	tokenInfoUnscopedKey = "kube-mcp/unscoped"
+)
+
+// NewTokenInfo builds SDK token info carrying the identity, bearer token and
+// the scopes the token grants. A token without scopes grants none.
+func NewTokenInfo(identity *Identity, token string, scopes []string, expiration time.Time) *sdkauth.TokenInfo {
+	extra := map[string]any{
+		tokenInfoTokenKey: token,
+	}
+	if identity != nil {
+		extra[tokenInfoIdentityKey] = identity
+	}
+	return &sdkauth.TokenInfo{
+		Scopes:     scopes,
+		Expiration: expiration,
+		Extra:      extra,
+	}
+}
+
+// RestrictTokenTools restricts the caller of SDK token info to tools matching
+// the glob patterns. An empty list leaves the caller unrestricted.
+func RestrictTokenTools(info *sdkauth.TokenInfo, tools []string) *sdkauth.TokenInfo {
+	if len(tools) > 0 {
+		info.Extra[tokenInfoToolsKey] = tools
+	}
+	return info
+}
+
+// UnscopedTokenInfo marks SDK token info of a credential that cannot carry
+// scopes, such as a ServiceAccount token, an API key without scopes or a
+// client certificate. Its caller is exempt from tool scope rules.
+func UnscopedTokenInfo(info *sdkauth.TokenInfo) *sdkauth.TokenInfo {
+	info.Extra[tokenInfoUnscopedKey] = true
+	return info
+}
+
+// ContextWithTokenInfo copies the identity, bearer token, scopes and tool
+// restriction from SDK token info into ctx.
+func ContextWithTokenInfo(ctx context.Context, info *sdkauth.TokenInfo) context.Context {
+	if info == nil || info.Extra == nil {
+		return ctx
+	}
+	if identity, ok := info.Extra[tokenInfoIdentityKey].(*Identity); ok {
+		ctx = WithIdentity(ctx, identity)
+	}
+	if token, ok := info.Extra[tokenInfoTokenKey].(string); ok && token != "" {
+		ctx = WithBearerToken(ctx, token)
+	}
+	// Every token is held to its scopes unless its credential type has none
+	if unscoped, _ := info.Extra[tokenInfoUnscopedKey].(bool); !unscoped {
+		ctx = WithScopes(ctx, info.Scopes)
+	}
+	if tools, ok := info.Extra[tokenInfoToolsKey].([]string); ok {
+		ctx = WithAllowedTools(ctx, tools)
+	}
+	return ctx
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -53,6 +54,61 @@ burst = 200
 	s.Equal([]string{"stdio"}, cfg.Server.Transports, "Transports should match")
 	s.Equal(float32(100), cfg.Kubernetes.QPS, "QPS should match")
 	s.Equal(200, cfg.Kubernetes.Burst, "Burst should match")
+	s.Equal(20*time.Second, cfg.Server.ShutdownGracePeriod.Duration(), "Shutdown grace period should default to 20s")
+	s.Equal(30*time.Second, cfg.Server.Health.ProbeInterval.Duration(), "Probe interval should default to 30s")
+	s.Zero(cfg.Server.HTTP.WriteTimeout.Duration(), "Write timeout should default to no limit")
+	s.Equal(15*time.Second, cfg.Server.HTTP.SSEKeepAlive.Duration(), "SSE keep-alive should default to 15s")
+	s.Equal(30*time.Minute, cfg.Server.HTTP.SessionIdleTimeout.Duration(), "Session idle timeout should default to 30m")
+	s.False(cfg.Server.HTTP.Stateless, "Sessions should be stateful by default")
+}
+
+// TestLoadHTTPStreaming tests loading HTTP timeouts and session settings.
+func (s *ConfigTestSuite) TestLoadHTTPStreaming() {
+	baseConfig := `
+[server.http]
+write_timeout = "1m"
+sse_keep_alive = "-1s"
+session_idle_timeout = "2h"
+stateless = true
+legacy_sse = true
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err)
+	s.Equal(time.Minute, cfg.Server.HTTP.WriteTimeout.Duration())
+	s.Equal(15*time.Second, cfg.Server.HTTP.ReadTimeout.Duration())
+	s.Equal(60*time.Second, cfg.Server.HTTP.IdleTimeout.Duration())
+	s.Negative(cfg.Server.HTTP.SSEKeepAlive.Duration())
+	s.Equal(2*time.Hour, cfg.Server.HTTP.SessionIdleTimeout.Duration())
+	s.True(cfg.Server.HTTP.Stateless)
+	s.True(cfg.Server.HTTP.LegacySSE)
+}
+
+// TestLoadExec tests pods_exec limits and rules.
+func (s *ConfigTestSuite) TestLoadExec() {
+	baseConfig := `
+[toolsets.core.exec]
+max_timeout = "10m"
+
+[[toolsets.core.exec.rules]]
+namespaces = ["prod-*"]
+allow = ["ls", "cat"]
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err)
+	exec := cfg.Toolsets.Core.Exec
+	s.Equal(30*time.Second, exec.Timeout.Duration())
+	s.Equal(10*time.Minute, exec.MaxTimeout.Duration())
+	s.Equal(1<<20, exec.MaxOutputBytes)
+	s.Equal(1<<20, exec.MaxStdinBytes)
+	s.Require().Len(exec.Rules, 1)
+	s.Equal([]string{"prod-*"}, exec.Rules[0].Namespaces)
+	s.Equal([]string{"ls", "cat"}, exec.Rules[0].Allow)
 }
 
 // TestLoadWithDropIn tests loading with drop-in files.
@@ -140,6 +196,280 @@ func (s *ConfigTestSuite) TestMissingConfigFile() {
 }
 
 // TestConfigTestSuite runs the config test suite.
+// TestLoadConfirmation tests loading confirmation settings and their defaults.
+func (s *ConfigTestSuite) TestLoadConfirmation() {
+	baseConfig := `
+[security.confirmation]
+enabled = true
+
+[[security.confirmation.rules]]
+namespaces = ["kube-system"]
+
+[[security.confirmation.rules]]
+tools = ["resources_apply"]
+kinds = ["Namespace", "ClusterRoleBinding.rbac.authorization.k8s.io"]
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	confirmation := cfg.Security.Confirmation
+	s.True(confirmation.Enabled)
+	s.Equal("deny", confirmation.Fallback, "Fallback should default to deny")
+	s.Equal(5*time.Minute, confirmation.Timeout.Duration(), "Timeout should default to 5m")
+	s.Require().Len(confirmation.Rules, 2)
+	s.Equal([]string{"kube-system"}, confirmation.Rules[0].Namespaces)
+	s.Equal([]string{"resources_apply"}, confirmation.Rules[1].Tools)
+	s.Equal([]string{"Namespace", "ClusterRoleBinding.rbac.authorization.k8s.io"}, confirmation.Rules[1].Kinds)
+}
+
+func (s *ConfigTestSuite) TestLoadAudit() {
+	baseConfig := `
+[audit]
+enabled = true
+
+[audit.file]
+path = "/var/log/kube-mcp/audit.log"
+
+[audit.webhook]
+url = "https://siem.example.com/ingest"
+headers = { Authorization = "Bearer abc" }
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.True(cfg.Audit.Enabled)
+	s.Equal("/var/log/kube-mcp/audit.log", cfg.Audit.File.Path)
+	s.Equal(100, cfg.Audit.File.MaxSizeMB, "MaxSizeMB should default to 100")
+	s.Equal(10, cfg.Audit.File.MaxBackups, "MaxBackups should default to 10")
+	s.False(cfg.Audit.Stdout)
+	s.Equal("https://siem.example.com/ingest", cfg.Audit.Webhook.URL)
+	s.Equal(map[string]string{"Authorization": "Bearer abc"}, cfg.Audit.Webhook.Headers)
+	s.Equal(5*time.Second, cfg.Audit.Webhook.Timeout.Duration(), "Timeout should default to 5s")
+}
+
+// TestLoadRedaction tests loading redaction settings and their defaults.
+func (s *ConfigTestSuite) TestLoadRedaction() {
+	cfg, err := NewLoader("/nonexistent/config.toml", "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.Equal("mask", cfg.Security.Redaction.Mode, "Mode should default to mask")
+	s.False(cfg.Security.Redaction.Reveal.Enabled, "Reveal should be disabled by default")
+
+	baseConfig := `
+[security.redaction]
+keys = ["license"]
+
+[security.redaction.reveal]
+enabled = true
+groups = ["sre"]
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err = NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.Equal("mask", cfg.Security.Redaction.Mode)
+	s.Equal([]string{"license"}, cfg.Security.Redaction.Keys)
+	s.True(cfg.Security.Redaction.Reveal.Enabled)
+	s.Equal([]string{"sre"}, cfg.Security.Redaction.Reveal.Groups)
+}
+
+// TestLoadRateLimit tests loading rate limits, including per-tool and per-context tables.
+func (s *ConfigTestSuite) TestLoadRateLimit() {
+	baseConfig := `
+[rate_limit]
+enabled = true
+
+[rate_limit.per_identity]
+rps = 5
+max_concurrent = 4
+
+[rate_limit.contexts.prod]
+rps = 10
+
+[rate_limit.tools."autoscaling.hpa_explain"]
+rps = 0.2
+burst = 2
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.True(cfg.RateLimit.Enabled)
+	s.Equal(RateLimit{RPS: 5, MaxConcurrent: 4}, cfg.RateLimit.PerIdentity)
+	s.Equal(RateLimit{RPS: 10}, cfg.RateLimit.Contexts["prod"])
+	s.Equal(RateLimit{RPS: 0.2, Burst: 2}, cfg.RateLimit.Tools["autoscaling.hpa_explain"])
+	s.Equal(RateLimit{}, cfg.RateLimit.Global)
+}
+
+// TestLoadAccess tests loading per-identity access rules.
+func (s *ConfigTestSuite) TestLoadAccess() {
+	baseConfig := `
+[security.access]
+enabled = true
+
+[[security.access.rules]]
+groups = ["platform"]
+
+[[security.access.rules]]
+groups = ["team-a"]
+toolsets = ["core", "helm"]
+read_only = true
+contexts = ["staging-*"]
+namespaces = ["team-a-*"]
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.True(cfg.Security.Access.Enabled)
+	s.Equal([]AccessRule{
+		{Groups: []string{"platform"}},
+		{
+			Groups:     []string{"team-a"},
+			Toolsets:   []string{"core", "helm"},
+			ReadOnly:   true,
+			Contexts:   []string{"staging-*"},
+			Namespaces: []string{"team-a-*"},
+		},
+	}, cfg.Security.Access.Rules)
+}
+
+// TestLoadOAuthToolScopes tests loading the resource URI and tool scope rules.
+func (s *ConfigTestSuite) TestLoadOAuthToolScopes() {
+	baseConfig := `
+[server.http.oauth]
+enabled = true
+resource_url = "https://kube-mcp.example.com/mcp"
+authorization_servers = ["https://auth.example.com"]
+
+[[server.http.oauth.tool_scopes]]
+scope = "kube:read"
+
+[[server.http.oauth.tool_scopes]]
+scope = "kube:write"
+classes = ["write", "destructive"]
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.Equal("https://kube-mcp.example.com/mcp", cfg.Server.HTTP.OAuth.ResourceURL)
+	s.Equal([]string{"https://auth.example.com"}, cfg.Server.HTTP.OAuth.AuthorizationServers)
+	s.Equal([]ToolScopeRule{
+		{Scope: "kube:read"},
+		{Scope: "kube:write", Classes: []string{"write", "destructive"}},
+	}, cfg.Server.HTTP.OAuth.ToolScopes)
+}
+
+// TestLoadAPIKeys tests loading API keys and their defaults.
+func (s *ConfigTestSuite) TestLoadAPIKeys() {
+	baseConfig := `
+[server.http.oauth]
+enabled = true
+provider = "apikey"
+
+[server.http.oauth.api_keys]
+enabled = true
+secret_name = "kube-mcp-api-keys"
+secret_namespace = "kube-mcp"
+
+[[server.http.oauth.api_keys.keys]]
+name = "ci"
+hash = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+groups = ["ci"]
+tools = ["pods_*"]
+expires_at = 2030-01-01T00:00:00Z
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	apiKeys := cfg.Server.HTTP.OAuth.APIKeys
+	s.True(apiKeys.Enabled)
+	s.Equal("kube-mcp-api-keys", apiKeys.SecretName)
+	s.Equal(time.Minute, apiKeys.RefreshInterval.Duration())
+	s.Require().Len(apiKeys.Keys, 1)
+	s.Equal("ci", apiKeys.Keys[0].Name)
+	s.Equal([]string{"pods_*"}, apiKeys.Keys[0].Tools)
+	s.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), apiKeys.Keys[0].ExpiresAt.UTC())
+}
+
+// TestLoadHTTPTLS tests loading HTTP TLS settings and their defaults.
+func (s *ConfigTestSuite) TestLoadHTTPTLS() {
+	baseConfig := `
+[server.http.tls]
+enabled = true
+cert_file = "/etc/kube-mcp/tls/tls.crt"
+key_file = "/etc/kube-mcp/tls/tls.key"
+
+[server.http.tls.client_auth]
+mode = "require"
+ca_file = "/etc/kube-mcp/tls/ca.crt"
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	s.Equal(HTTPTLSConfig{
+		Enabled:    true,
+		CertFile:   "/etc/kube-mcp/tls/tls.crt",
+		KeyFile:    "/etc/kube-mcp/tls/tls.key",
+		MinVersion: "1.2",
+		ClientAuth: ClientAuthConfig{
+			Mode:         "require",
+			CAFile:       "/etc/kube-mcp/tls/ca.crt",
+			UsernameFrom: "cn",
+			GroupsFrom:   "o",
+		},
+	}, cfg.Server.HTTP.TLS)
+}
+
+// TestLoadAdmission tests loading admission rules and their defaults.
+func (s *ConfigTestSuite) TestLoadAdmission() {
+	baseConfig := `
+[security.admission]
+enabled = true
+
+[[security.admission.rules]]
+name = "no-scale-to-zero"
+tools = ["resources_scale"]
+expression = 'request.arguments.replicas == 0'
+message = "Scaling to zero is not allowed"
+
+[[security.admission.rules]]
+name = "exec"
+tools = ["pods_exec"]
+expression = "true"
+action = "audit"
+`
+	basePath := filepath.Join(s.tempDir, "config.toml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(baseConfig), 0644))
+
+	cfg, err := NewLoader(basePath, "").Load()
+	s.Require().NoError(err, "Failed to load config")
+	admission := cfg.Security.Admission
+	s.True(admission.Enabled)
+	s.Equal("deny", admission.FailurePolicy, "Failure policy should default to deny")
+	s.Require().Len(admission.Rules, 2)
+	s.Equal(AdmissionRule{
+		Name:       "no-scale-to-zero",
+		Tools:      []string{"resources_scale"},
+		Expression: "request.arguments.replicas == 0",
+		Action:     "deny",
+		Message:    "Scaling to zero is not allowed",
+	}, admission.Rules[0], "Action should default to deny")
+	s.Equal("audit", admission.Rules[1].Action)
+}
+
 func TestConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(ConfigTestSuite))
 }
@@ -13,16 +13,37 @@ func (l *Loader) applyDefaults(cfg *Config) {
 	if cfg.Server.LogLevel == "" {
 		cfg.Server.LogLevel = "info"
 	}
+	if cfg.Server.ShutdownGracePeriod == 0 {
+		cfg.Server.ShutdownGracePeriod = Duration(20 * time.Second)
+	}
+	if cfg.Server.Health.ProbeInterval == 0 {
+		cfg.Server.Health.ProbeInterval = Duration(30 * time.Second)
+	}
+	if cfg.Server.Health.ProbeTimeout == 0 {
+		cfg.Server.Health.ProbeTimeout = Duration(5 * time.Second)
+	}
 
 	// HTTP defaults
 	if cfg.Server.HTTP.Address == "" {
 		cfg.Server.HTTP.Address = "0.0.0.0:8080"
 	}
+	if cfg.Server.HTTP.ReadTimeout == 0 {
+		cfg.Server.HTTP.ReadTimeout = Duration(15 * time.Second)
+	}
+	if cfg.Server.HTTP.IdleTimeout == 0 {
+		cfg.Server.HTTP.IdleTimeout = Duration(60 * time.Second)
+	}
+	if cfg.Server.HTTP.SSEKeepAlive == 0 {
+		cfg.Server.HTTP.SSEKeepAlive = Duration(15 * time.Second)
+	}
+	if cfg.Server.HTTP.SessionIdleTimeout == 0 {
+		cfg.Server.HTTP.SessionIdleTimeout = Duration(30 * time.Minute)
+	}
 	if len(cfg.Server.HTTP.CORS.AllowedMethods) == 0 {
-		cfg.Server.HTTP.CORS.AllowedMethods = []string{"GET", "POST", "OPTIONS"}
+		cfg.Server.HTTP.CORS.AllowedMethods = []string{"GET", "POST", "DELETE", "OPTIONS"}
 	}
 	if len(cfg.Server.HTTP.CORS.AllowedHeaders) == 0 {
-		cfg.Server.HTTP.CORS.AllowedHeaders = []string{"Content-Type", "Authorization"}
+		cfg.Server.HTTP.CORS.AllowedHeaders = []string{"Content-Type", "Authorization", "Mcp-Session-Id", "Mcp-Protocol-Version", "Last-Event-ID"}
 	}
 	if len(cfg.Server.HTTP.OAuth.Scopes) == 0 {
 		cfg.Server.HTTP.OAuth.Scopes = []string{"openid", "profile"}
@@ -30,6 +51,27 @@ func (l *Loader) applyDefaults(cfg *Config) {
 	if cfg.Server.HTTP.OAuth.Provider == "" {
 		cfg.Server.HTTP.OAuth.Provider = "oidc"
 	}
+	if cfg.Server.HTTP.OAuth.UsernameClaim == "" {
+		cfg.Server.HTTP.OAuth.UsernameClaim = "sub"
+	}
+	if cfg.Server.HTTP.OAuth.GroupsClaim == "" {
+		cfg.Server.HTTP.OAuth.GroupsClaim = "groups"
+	}
+	if cfg.Server.HTTP.OAuth.APIKeys.RefreshInterval == 0 {
+		cfg.Server.HTTP.OAuth.APIKeys.RefreshInterval = Duration(time.Minute)
+	}
+	if cfg.Server.HTTP.TLS.MinVersion == "" {
+		cfg.Server.HTTP.TLS.MinVersion = "1.2"
+	}
+	if cfg.Server.HTTP.TLS.ClientAuth.Mode == "" {
+		cfg.Server.HTTP.TLS.ClientAuth.Mode = "none"
+	}
+	if cfg.Server.HTTP.TLS.ClientAuth.UsernameFrom == "" {
+		cfg.Server.HTTP.TLS.ClientAuth.UsernameFrom = "cn"
+	}
+	if cfg.Server.HTTP.TLS.ClientAuth.GroupsFrom == "" {
+		cfg.Server.HTTP.TLS.ClientAuth.GroupsFrom = "o"
+	}
 
 	// Kubernetes defaults
 	if cfg.Kubernetes.Provider == "" {
@@ -47,12 +89,39 @@ func (l *Loader) applyDefaults(cfg *Config) {
 	if cfg.Kubernetes.Timeout == 0 {
 		cfg.Kubernetes.Timeout = Duration(30 * time.Second)
 	}
+	if cfg.Kubernetes.CredentialMode == "" {
+		cfg.Kubernetes.CredentialMode = "server"
+	}
+	if cfg.Kubernetes.ClientCacheSize == 0 {
+		cfg.Kubernetes.ClientCacheSize = 100
+	}
+	if cfg.Kubernetes.ClientCacheTTL == 0 {
+		cfg.Kubernetes.ClientCacheTTL = Duration(10 * time.Minute)
+	}
 
 	// Security defaults
 	if cfg.Security.RequireRBAC {
 		cfg.Security.RequireRBAC = true
 	}
 
+	if cfg.Security.Confirmation.Fallback == "" {
+		cfg.Security.Confirmation.Fallback = "deny"
+	}
+	if cfg.Security.Confirmation.Timeout == 0 {
+		cfg.Security.Confirmation.Timeout = Duration(5 * time.Minute)
+	}
+	if cfg.Security.Redaction.Mode == "" {
+		cfg.Security.Redaction.Mode = "mask"
+	}
+	if cfg.Security.Admission.FailurePolicy == "" {
+		cfg.Security.Admission.FailurePolicy = "deny"
+	}
+	for i := range cfg.Security.Admission.Rules {
+		if cfg.Security.Admission.Rules[i].Action == "" {
+			cfg.Security.Admission.Rules[i].Action = "deny"
+		}
+	}
+
 	// Helm defaults
 	if cfg.Helm.StorageDriver == "" {
 		cfg.Helm.StorageDriver = "secret"
@@ -74,8 +143,62 @@ func (l *Loader) applyDefaults(cfg *Config) {
 		cfg.Kiali.Timeout = Duration(30 * time.Second)
 	}
 
+	// Core defaults
+	if cfg.Toolsets.Core.Exec.Timeout == 0 {
+		cfg.Toolsets.Core.Exec.Timeout = Duration(30 * time.Second)
+	}
+	if cfg.Toolsets.Core.Exec.MaxTimeout == 0 {
+		cfg.Toolsets.Core.Exec.MaxTimeout = Duration(5 * time.Minute)
+	}
+	if cfg.Toolsets.Core.Exec.MaxOutputBytes == 0 {
+		cfg.Toolsets.Core.Exec.MaxOutputBytes = 1 << 20
+	}
+	if cfg.Toolsets.Core.Exec.MaxStdinBytes == 0 {
+		cfg.Toolsets.Core.Exec.MaxStdinBytes = 1 << 20
+	}
+	if len(cfg.Toolsets.Core.PortForward.Addresses) == 0 {
+		cfg.Toolsets.Core.PortForward.Addresses = []string{"localhost"}
+	}
+	if cfg.Toolsets.Core.PortForward.IdleTimeout == 0 {
+		cfg.Toolsets.Core.PortForward.IdleTimeout = Duration(30 * time.Minute)
+	}
+	if cfg.Toolsets.Core.PortForward.MaxSessions == 0 {
+		cfg.Toolsets.Core.PortForward.MaxSessions = 10
+	}
+	if cfg.Toolsets.Core.Stream.DefaultDuration == 0 {
+		cfg.Toolsets.Core.Stream.DefaultDuration = Duration(30 * time.Second)
+	}
+	if cfg.Toolsets.Core.Stream.MaxDuration == 0 {
+		cfg.Toolsets.Core.Stream.MaxDuration = Duration(30 * time.Minute)
+	}
+	if cfg.Toolsets.Core.Stream.MaxItems == 0 {
+		cfg.Toolsets.Core.Stream.MaxItems = 10000
+	}
+	if cfg.Toolsets.Core.Logs.Concurrency == 0 {
+		cfg.Toolsets.Core.Logs.Concurrency = 5
+	}
+	if cfg.Toolsets.Core.Logs.MaxBytes == 0 {
+		cfg.Toolsets.Core.Logs.MaxBytes = 1 << 20
+	}
+
 	// Network defaults
 	if cfg.Toolsets.Net.HubbleTimeout == 0 {
 		cfg.Toolsets.Net.HubbleTimeout = Duration(10 * time.Second)
 	}
+
+	// Output defaults
+	if cfg.Output.MaxBytes == 0 {
+		cfg.Output.MaxBytes = 128 << 10
+	}
+
+	// Audit defaults
+	if cfg.Audit.File.MaxSizeMB == 0 {
+		cfg.Audit.File.MaxSizeMB = 100
+	}
+	if cfg.Audit.File.MaxBackups == 0 {
+		cfg.Audit.File.MaxBackups = 10
+	}
+	if cfg.Audit.Webhook.Timeout == 0 {
+		cfg.Audit.Webhook.Timeout = Duration(5 * time.Second)
+	}
 }
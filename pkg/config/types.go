@@ -37,6 +37,9 @@ type Config struct {
 	KubeVirt   KubeVirtConfig   `toml:"kubevirt"`
 	Kiali      KialiConfig      `toml:"kiali"`
 	Toolsets   ToolsetsConfig   `toml:"toolsets"`
+	Audit      AuditConfig      `toml:"audit"`
+	RateLimit  RateLimitConfig  `toml:"rate_limit"`
+	Output     OutputConfig     `toml:"output"`
 }
 
 // ServerConfig contains server-level configuration.
@@ -53,6 +56,21 @@ type ServerConfig struct {
 	// Normalize tool names by replacing dots with underscores (for n8n compatibility)
 	// When enabled, "autoscaling.hpa_explain" becomes "autoscaling_hpa_explain"
 	NormalizeToolNames bool `toml:"normalize_tool_names"`
+
+	// How long shutdown waits for in-flight HTTP requests before closing them
+	ShutdownGracePeriod Duration `toml:"shutdown_grace_period" default:"20s"`
+
+	// Kubernetes API server probes behind the health endpoints
+	Health HealthConfig `toml:"health"`
+}
+
+// HealthConfig configures the API server probes behind /readyz and /health.
+type HealthConfig struct {
+	// How often each context's API server is probed
+	ProbeInterval Duration `toml:"probe_interval" default:"30s"`
+
+	// Timeout of a single probe
+	ProbeTimeout Duration `toml:"probe_timeout" default:"5s"`
 }
 
 // HTTPConfig contains HTTP transport configuration.
@@ -65,6 +83,35 @@ type HTTPConfig struct {
 
 	// CORS configuration
 	CORS CORSConfig `toml:"cors"`
+
+	// TLS termination and client certificate authentication
+	TLS HTTPTLSConfig `toml:"tls"`
+
+	// Maximum time to read a request, including the body
+	ReadTimeout Duration `toml:"read_timeout" default:"15s"`
+
+	// Maximum time to write a response, or zero (the default) for no limit,
+	// since tool calls have their own timeouts. Event streams, such as
+	// watches, followed logs and progress of long tool calls, are exempt.
+	WriteTimeout Duration `toml:"write_timeout"`
+
+	// How long an idle keep-alive connection is kept open
+	IdleTimeout Duration `toml:"idle_timeout" default:"60s"`
+
+	// Interval of keep-alive comments on open event streams, so proxies and
+	// load balancers do not close them; negative disables them
+	SSEKeepAlive Duration `toml:"sse_keep_alive" default:"15s"`
+
+	// How long a session may stay idle before it is closed; negative keeps
+	// idle sessions open
+	SessionIdleTimeout Duration `toml:"session_idle_timeout" default:"30m"`
+
+	// Serve without sessions, so any replica can handle any request. Server
+	// to client requests, such as confirmation prompts, are not possible.
+	Stateless bool `toml:"stateless" default:"false"`
+
+	// Serve the legacy HTTP+SSE transport at /sse for older clients
+	LegacySSE bool `toml:"legacy_sse" default:"false"`
 }
 
 // OAuth2Config contains OAuth2/OIDC configuration.
@@ -72,7 +119,8 @@ type OAuth2Config struct {
 	// Enable OAuth2/OIDC
 	Enabled bool `toml:"enabled" default:"false"`
 
-	// Provider: "oidc", "oauth2"
+	// Provider: "oidc", "oauth2", "serviceaccount" (projected ServiceAccount
+	// tokens checked with TokenReview) or "apikey" (API keys only)
 	Provider string `toml:"provider" default:"oidc"`
 
 	// Issuer URL
@@ -89,6 +137,95 @@ type OAuth2Config struct {
 
 	// Redirect URL
 	RedirectURL string `toml:"redirect_url"`
+
+	// Expected token audience (defaults to the client ID)
+	Audience string `toml:"audience"`
+
+	// Claim used as the caller's username
+	UsernameClaim string `toml:"username_claim" default:"sub"`
+
+	// Claim holding the caller's groups (string or list of strings)
+	GroupsClaim string `toml:"groups_claim" default:"groups"`
+
+	// RFC 7662 token introspection endpoint for the "oauth2" provider
+	// (defaults to <issuer_url>/introspect)
+	IntrospectionURL string `toml:"introspection_url"`
+
+	// Canonical URI of the MCP endpoint, e.g. https://kube-mcp.example.com/mcp.
+	// Published in the protected resource metadata and, unless audience is
+	// set, required in the token audience (derived from requests if empty)
+	ResourceURL string `toml:"resource_url"`
+
+	// Authorization servers published in the protected resource metadata
+	// (defaults to issuer_url)
+	AuthorizationServers []string `toml:"authorization_servers"`
+
+	// Scopes bearer tokens need to list and call tools
+	ToolScopes []ToolScopeRule `toml:"tool_scopes"`
+
+	// ServiceAccounts the "serviceaccount" provider accepts, as
+	// "namespace/name" glob patterns (all if empty)
+	ServiceAccounts []string `toml:"service_accounts"`
+
+	// Static API keys, accepted alongside the provider's tokens
+	APIKeys APIKeysConfig `toml:"api_keys"`
+}
+
+// APIKeysConfig configures static API keys for clients such as CI bots. Keys
+// are stored as SHA-256 hashes, in the configuration or in a Kubernetes Secret.
+type APIKeysConfig struct {
+	// Accept API keys
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Keys configured inline
+	Keys []APIKey `toml:"keys"`
+
+	// Secret holding more keys as TOML in its "keys.toml" entry
+	SecretName      string `toml:"secret_name"`
+	SecretNamespace string `toml:"secret_namespace"`
+
+	// How often the Secret is reread
+	RefreshInterval Duration `toml:"refresh_interval" default:"1m"`
+}
+
+// APIKey is an API key bound to an identity.
+type APIKey struct {
+	// Name of the key, used in the default username
+	Name string `toml:"name"`
+
+	// SHA-256 hash of the key, as "sha256:<hex>"
+	Hash string `toml:"hash"`
+
+	// Username of the identity (defaults to "apikey:<name>")
+	Username string `toml:"username"`
+
+	// Groups of the identity
+	Groups []string `toml:"groups"`
+
+	// OAuth scopes granted to the key, checked against tool_scopes
+	Scopes []string `toml:"scopes"`
+
+	// Tool name glob patterns the key may use (all tools if empty)
+	Tools []string `toml:"tools"`
+
+	// Time after which the key is rejected (never if unset)
+	ExpiresAt time.Time `toml:"expires_at"`
+}
+
+// ToolScopeRule requires a scope for the tools it matches. A tool needs the
+// scopes of every matching rule.
+type ToolScopeRule struct {
+	// Required scope, e.g. "kube:write"
+	Scope string `toml:"scope"`
+
+	// Tool name glob patterns (all tools if empty)
+	Tools []string `toml:"tools"`
+
+	// Toolsets (all toolsets if empty)
+	Toolsets []string `toml:"toolsets"`
+
+	// Tool classes: "read", "write", "destructive" (all classes if empty)
+	Classes []string `toml:"classes"`
 }
 
 // CORSConfig contains CORS configuration.
@@ -100,10 +237,45 @@ type CORSConfig struct {
 	AllowedOrigins []string `toml:"allowed_origins"`
 
 	// Allowed methods
-	AllowedMethods []string `toml:"allowed_methods" default:"[\"GET\", \"POST\", \"OPTIONS\"]"`
+	AllowedMethods []string `toml:"allowed_methods" default:"[\"GET\", \"POST\", \"DELETE\", \"OPTIONS\"]"`
 
 	// Allowed headers
-	AllowedHeaders []string `toml:"allowed_headers" default:"[\"Content-Type\", \"Authorization\"]"`
+	AllowedHeaders []string `toml:"allowed_headers" default:"[\"Content-Type\", \"Authorization\", \"Mcp-Session-Id\", \"Mcp-Protocol-Version\", \"Last-Event-ID\"]"`
+}
+
+// HTTPTLSConfig configures TLS on the HTTP transport. The certificate, key
+// and client CA bundle are reloaded when the files change.
+type HTTPTLSConfig struct {
+	// Serve HTTPS instead of HTTP
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Server certificate path (PEM, may include intermediates)
+	CertFile string `toml:"cert_file"`
+
+	// Server private key path (PEM)
+	KeyFile string `toml:"key_file"`
+
+	// Minimum TLS version: "1.2" or "1.3"
+	MinVersion string `toml:"min_version" default:"1.2"`
+
+	// Client certificate authentication
+	ClientAuth ClientAuthConfig `toml:"client_auth"`
+}
+
+// ClientAuthConfig configures client certificate authentication and how a
+// verified certificate is mapped to an identity.
+type ClientAuthConfig struct {
+	// "none", "optional" (verify certificates that are sent) or "require"
+	Mode string `toml:"mode" default:"none"`
+
+	// CA bundle path used to verify client certificates
+	CAFile string `toml:"ca_file"`
+
+	// Certificate field the username is taken from: "cn", "email", "dns" or "uri"
+	UsernameFrom string `toml:"username_from" default:"cn"`
+
+	// Certificate field the groups are taken from: "o", "ou" or "none"
+	GroupsFrom string `toml:"groups_from" default:"o"`
 }
 
 // KubernetesConfig contains Kubernetes client configuration.
@@ -128,6 +300,17 @@ type KubernetesConfig struct {
 
 	// Timeout for Kubernetes API calls
 	Timeout Duration `toml:"timeout" default:"30s"`
+
+	// Credentials used for calls made on behalf of authenticated HTTP callers:
+	// "server" (server's own credentials), "passthrough" (caller's bearer token),
+	// "impersonate" (server credentials impersonating the caller's verified identity)
+	CredentialMode string `toml:"credential_mode" default:"server"`
+
+	// Maximum number of per-caller client sets kept in the cache
+	ClientCacheSize int `toml:"client_cache_size" default:"100"`
+
+	// Per-caller client sets unused for this long are evicted
+	ClientCacheTTL Duration `toml:"client_cache_ttl" default:"10m"`
 }
 
 // SecurityConfig contains security-related configuration.
@@ -150,6 +333,231 @@ type SecurityConfig struct {
 
 	// RBAC cache TTL in seconds
 	RBACCacheTTL int `toml:"rbac_cache_ttl" default:"5"`
+
+	// Human confirmation of risky tool calls via MCP elicitation
+	Confirmation ConfirmationConfig `toml:"confirmation"`
+
+	// Masking of credentials in tool output
+	Redaction RedactionConfig `toml:"redaction"`
+
+	// Per-identity access to contexts, namespaces, toolsets and tools
+	Access AccessConfig `toml:"access"`
+
+	// CEL rules evaluated before every tool call
+	Admission AdmissionConfig `toml:"admission"`
+}
+
+// ConfirmationConfig controls which tool calls need explicit user
+// confirmation through an MCP elicitation request.
+type ConfirmationConfig struct {
+	// Require confirmation for destructive tools and calls matching Rules
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Decision when the client does not support elicitation: "deny" or "allow"
+	Fallback string `toml:"fallback" default:"deny"`
+
+	// How long to wait for the user's answer
+	Timeout Duration `toml:"timeout" default:"5m"`
+
+	// Additional calls that need confirmation even if the tool is not destructive
+	Rules []ConfirmationRule `toml:"rules"`
+}
+
+// ConfirmationRule matches tool calls that need confirmation. Every non-empty
+// field must match; within a field, any listed value matches.
+type ConfirmationRule struct {
+	// Tool names (original, non-normalized names)
+	Tools []string `toml:"tools"`
+
+	// Namespaces the call targets
+	Namespaces []string `toml:"namespaces"`
+
+	// Kinds the call targets, as "Kind" or "Kind.group"
+	Kinds []string `toml:"kinds"`
+}
+
+// RedactionConfig controls masking of Secret data and credentials in tool
+// output and resource contents.
+type RedactionConfig struct {
+	// "mask" hides Secret data and detected credentials; "off" returns output unchanged
+	Mode string `toml:"mode" default:"mask"`
+
+	// Additional regular expressions whose matches are masked
+	Patterns []string `toml:"patterns"`
+
+	// Additional field names whose values are masked (case-insensitive substrings)
+	Keys []string `toml:"keys"`
+
+	// Explicit reveal of unmasked output
+	Reveal RevealConfig `toml:"reveal"`
+}
+
+// RevealConfig controls the reveal argument, which returns tool output unmasked.
+type RevealConfig struct {
+	// Allow calls with reveal: true
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Groups allowed to reveal; empty allows every caller
+	Groups []string `toml:"groups"`
+}
+
+// AccessConfig restricts which contexts, namespaces, toolsets and tools each
+// caller may use. A caller may do what any rule matching it allows.
+type AccessConfig struct {
+	// Enforce the rules; callers matching no rule may use nothing
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Rules granting access
+	Rules []AccessRule `toml:"rules"`
+}
+
+// AccessRule grants the matching callers the use of tools within contexts and
+// namespaces. Empty lists match everything.
+type AccessRule struct {
+	// Usernames the rule applies to
+	Users []string `toml:"users"`
+
+	// Groups the rule applies to; with Users empty too, every caller,
+	// including unauthenticated ones
+	Groups []string `toml:"groups"`
+
+	// Toolsets whose tools are allowed, e.g. "core"
+	Toolsets []string `toml:"toolsets"`
+
+	// Tools allowed in addition to Toolsets, as glob patterns, e.g. "rollouts.*"
+	Tools []string `toml:"tools"`
+
+	// Allow only read-only tools
+	ReadOnly bool `toml:"read_only"`
+
+	// Contexts the tools may be used against, as glob patterns
+	Contexts []string `toml:"contexts"`
+
+	// Namespaces the tools may be used in, as glob patterns; if set,
+	// cluster-wide calls are not allowed
+	Namespaces []string `toml:"namespaces"`
+}
+
+// AdmissionConfig holds CEL rules that are evaluated against every tool call
+// before it runs.
+type AdmissionConfig struct {
+	// Evaluate the rules
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Decision when a deny or confirm rule cannot be evaluated: "deny" or "ignore"
+	FailurePolicy string `toml:"failure_policy" default:"deny"`
+
+	// Rules, evaluated in order
+	Rules []AdmissionRule `toml:"rules"`
+}
+
+// AdmissionRule is a CEL expression and the action taken when it evaluates
+// to true.
+type AdmissionRule struct {
+	// Name identifying the rule in denials and audit entries
+	Name string `toml:"name"`
+
+	// Tools the rule applies to, as glob patterns; empty applies to every tool
+	Tools []string `toml:"tools"`
+
+	// CEL expression over request, object and namespaceObject; the rule
+	// matches when it evaluates to true
+	Expression string `toml:"expression"`
+
+	// Action when the rule matches: "deny", "confirm" or "audit"
+	Action string `toml:"action" default:"deny"`
+
+	// Message explaining the action to the caller
+	Message string `toml:"message"`
+
+	// CEL expression returning the message, used instead of Message
+	MessageExpression string `toml:"message_expression"`
+}
+
+// AuditConfig contains audit log configuration. Every tool invocation is
+// written to all configured sinks as one hash-chained JSON line.
+type AuditConfig struct {
+	// Enable the audit log
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// Rotating JSON-lines file sink
+	File AuditFileConfig `toml:"file"`
+
+	// Write entries to stdout (not allowed with the stdio transport)
+	Stdout bool `toml:"stdout" default:"false"`
+
+	// HTTP webhook sink
+	Webhook AuditWebhookConfig `toml:"webhook"`
+}
+
+// AuditFileConfig configures the audit log file sink.
+type AuditFileConfig struct {
+	// Path of the audit log file; the sink is disabled if empty
+	Path string `toml:"path"`
+
+	// Rotate the file when it reaches this size in megabytes
+	MaxSizeMB int `toml:"max_size_mb" default:"100"`
+
+	// Number of rotated files to keep
+	MaxBackups int `toml:"max_backups" default:"10"`
+}
+
+// AuditWebhookConfig configures the audit log webhook sink.
+type AuditWebhookConfig struct {
+	// URL entries are POSTed to; the sink is disabled if empty
+	URL string `toml:"url"`
+
+	// Extra HTTP headers, e.g. for authentication
+	Headers map[string]string `toml:"headers"`
+
+	// Request timeout
+	Timeout Duration `toml:"timeout" default:"5s"`
+}
+
+// RateLimitConfig limits the rate and concurrency of tool calls and HTTP
+// requests. Each limit applies separately; a call must pass all of them.
+type RateLimitConfig struct {
+	// Enable rate limiting
+	Enabled bool `toml:"enabled" default:"false"`
+
+	// All tool calls combined
+	Global RateLimit `toml:"global"`
+
+	// Tool calls of each caller
+	PerIdentity RateLimit `toml:"per_identity"`
+
+	// Tool calls against each Kubernetes context
+	PerContext RateLimit `toml:"per_context"`
+
+	// Limits for individual contexts, replacing PerContext, keyed by context name
+	Contexts map[string]RateLimit `toml:"contexts"`
+
+	// Calls of each caller to individual tools, keyed by tool name
+	Tools map[string]RateLimit `toml:"tools"`
+
+	// Requests of each client to the HTTP MCP endpoint
+	HTTP RateLimit `toml:"http"`
+}
+
+// RateLimit is a token bucket and a concurrency limit. Zero values disable
+// the respective limit.
+type RateLimit struct {
+	// Sustained requests per second
+	RPS float64 `toml:"rps"`
+
+	// Requests allowed in a burst above RPS (default: RPS rounded up)
+	Burst int `toml:"burst"`
+
+	// Requests allowed to run at the same time
+	MaxConcurrent int `toml:"max_concurrent"`
+}
+
+// OutputConfig shapes the results of list and get tools.
+type OutputConfig struct {
+	// Most bytes a list result may have; longer lists are cut and return a
+	// cursor to continue from. Calls may ask for less with max_bytes, which
+	// also limits single objects.
+	MaxBytes int `toml:"max_bytes" default:"131072"`
 }
 
 // HelmConfig contains Helm-specific configuration.
@@ -208,6 +616,7 @@ type TLSConfig struct {
 
 // ToolsetsConfig contains configuration for optional toolsets.
 type ToolsetsConfig struct {
+	Core        CoreConfig        `toml:"core"`
 	GitOps      GitOpsConfig      `toml:"gitops"`
 	Policy      PolicyConfig      `toml:"policy"`
 	CAPI        CAPIConfig        `toml:"capi"`
@@ -218,6 +627,89 @@ type ToolsetsConfig struct {
 	Net         NetConfig         `toml:"net"`
 }
 
+// CoreConfig contains Core toolset configuration.
+type CoreConfig struct {
+	// Command execution in pods
+	Exec ExecConfig `toml:"exec"`
+
+	// Port-forward sessions
+	PortForward PortForwardConfig `toml:"port_forward"`
+
+	// Log following and watches
+	Stream StreamConfig `toml:"stream"`
+
+	// Log aggregation across pods
+	Logs LogsConfig `toml:"logs"`
+}
+
+// ExecConfig limits commands pods_exec runs in containers.
+type ExecConfig struct {
+	// Timeout of a command unless the call sets a shorter or longer one
+	Timeout Duration `toml:"timeout" default:"30s"`
+
+	// Longest timeout a call may set
+	MaxTimeout Duration `toml:"max_timeout" default:"5m"`
+
+	// Bytes of stdout and of stderr returned; the rest is dropped
+	MaxOutputBytes int `toml:"max_output_bytes" default:"1048576"`
+
+	// Largest stdin content a call may send
+	MaxStdinBytes int `toml:"max_stdin_bytes" default:"1048576"`
+
+	// Commands allowed and denied by namespace. The first rule matching the
+	// pod's namespace applies; without one, any command may run.
+	Rules []ExecRule `toml:"rules"`
+}
+
+// ExecRule allows and denies commands in a set of namespaces. Commands are
+// matched by their executable, as given or by base name.
+type ExecRule struct {
+	// Namespace glob patterns (empty matches all)
+	Namespaces []string `toml:"namespaces"`
+
+	// Executable glob patterns that may run (empty allows all not denied)
+	Allow []string `toml:"allow"`
+
+	// Executable glob patterns that may not run
+	Deny []string `toml:"deny"`
+}
+
+// PortForwardConfig configures the port-forward sessions pods_port_forward
+// opens.
+type PortForwardConfig struct {
+	// Local addresses forwarded ports listen on
+	Addresses []string `toml:"addresses" default:"[\"localhost\"]"`
+
+	// Time without traffic after which a session is closed (negative keeps
+	// idle sessions open)
+	IdleTimeout Duration `toml:"idle_timeout" default:"30m"`
+
+	// Most sessions open at a time
+	MaxSessions int `toml:"max_sessions" default:"10"`
+}
+
+// StreamConfig limits the streams of pods_logs with follow and of
+// resources_watch.
+type StreamConfig struct {
+	// How long a stream runs unless the call sets a duration
+	DefaultDuration Duration `toml:"default_duration" default:"30s"`
+
+	// Longest duration a call may set
+	MaxDuration Duration `toml:"max_duration" default:"30m"`
+
+	// Most log lines or events a stream delivers
+	MaxItems int `toml:"max_items" default:"10000"`
+}
+
+// LogsConfig limits pods_logs_aggregate.
+type LogsConfig struct {
+	// Containers whose logs are fetched at the same time
+	Concurrency int `toml:"concurrency" default:"5"`
+
+	// Bytes of merged logs returned; the oldest lines beyond are dropped
+	MaxBytes int `toml:"max_bytes" default:"1048576"`
+}
+
 // GitOpsConfig contains GitOps toolset configuration.
 type GitOpsConfig struct {
 	// Enable GitOps toolset (auto-detected if CRDs exist)
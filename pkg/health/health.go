@@ -0,0 +1,277 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Overall health statuses.
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+// ContextStatus is the result of probing a Kubernetes context's API server.
+type ContextStatus struct {
+	Name      string    `json:"name"`
+	Reachable bool      `json:"reachable"`
+	LatencyMS int64     `json:"latency_ms"`
+	Version   string    `json:"version,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CRDStatus reports whether a CRD a toolset depends on was discovered.
+type CRDStatus struct {
+	GVK   string `json:"gvk"`
+	Found bool   `json:"found"`
+}
+
+// ToolsetStatus reports whether a toolset was registered and the CRDs it
+// depends on.
+type ToolsetStatus struct {
+	Name       string      `json:"name"`
+	Registered bool        `json:"registered"`
+	CRDs       []CRDStatus `json:"crds,omitempty"`
+}
+
+// Report is the server's health: readiness, each context's API server and
+// each toolset's CRD discovery status.
+type Report struct {
+	Status            string          `json:"status"`
+	Ready             bool            `json:"ready"`
+	Reason            string          `json:"reason,omitempty"`
+	Contexts          []ContextStatus `json:"contexts"`
+	Toolsets          []ToolsetStatus `json:"toolsets"`
+	CRDDiscoveryError string          `json:"crd_discovery_error,omitempty"`
+}
+
+// toolset is a registered toolset and the CRDs it depends on.
+type toolset struct {
+	name       string
+	registered bool
+	crds       []schema.GroupVersionKind
+}
+
+// Checker tracks whether the server is ready to serve and probes the API
+// server of each Kubernetes context in the background.
+type Checker struct {
+	provider kubernetes.ClientProvider
+	interval time.Duration
+	timeout  time.Duration
+
+	mu           sync.RWMutex
+	contexts     []ContextStatus
+	probed       bool
+	registered   bool
+	draining     bool
+	toolsets     []toolset
+	discovery    *kubernetes.CRDDiscovery
+	discoveryErr error
+}
+
+// NewChecker creates a checker probing the contexts of provider.
+func NewChecker(provider kubernetes.ClientProvider, cfg *config.HealthConfig) *Checker {
+	return &Checker{
+		provider: provider,
+		interval: cfg.ProbeInterval.Duration(),
+		timeout:  cfg.ProbeTimeout.Duration(),
+	}
+}
+
+// SetCRDDiscovery sets the CRD discovery toolset CRDs are looked up in, and
+// the error of the initial discovery, if any.
+func (c *Checker) SetCRDDiscovery(discovery *kubernetes.CRDDiscovery, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.discovery = discovery
+	c.discoveryErr = err
+}
+
+// AddToolset records a toolset enabled in the configuration, whether it was
+// registered, and the CRDs it depends on.
+func (c *Checker) AddToolset(name string, registered bool, crds ...schema.GroupVersionKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.toolsets = append(c.toolsets, toolset{name: name, registered: registered, crds: crds})
+}
+
+// SetRegistered marks toolset registration as complete.
+func (c *Checker) SetRegistered() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registered = true
+}
+
+// SetDraining marks the server as shutting down, so it is no longer ready.
+func (c *Checker) SetDraining() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+}
+
+// Run probes the API servers now and then every probe interval until ctx is
+// done.
+func (c *Checker) Run(ctx context.Context) {
+	c.Probe(ctx)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Probe(ctx)
+		}
+	}
+}
+
+// Probe probes the API server of every context concurrently.
+func (c *Checker) Probe(ctx context.Context) {
+	names, err := c.provider.ListContexts()
+	if err != nil || len(names) == 0 {
+		status := ContextStatus{Error: "no Kubernetes contexts", CheckedAt: time.Now()}
+		if err != nil {
+			status.Error = fmt.Sprintf("failed to list contexts: %v", err)
+		}
+		c.setContexts([]ContextStatus{status})
+		return
+	}
+	sort.Strings(names)
+
+	statuses := make([]ContextStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = c.probeContext(ctx, name)
+		}()
+	}
+	wg.Wait()
+	c.setContexts(statuses)
+}
+
+func (c *Checker) setContexts(statuses []ContextStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.contexts = statuses
+	c.probed = true
+}
+
+// probeContext requests the API server version of a context, giving up after
+// the probe timeout.
+func (c *Checker) probeContext(ctx context.Context, name string) ContextStatus {
+	status := ContextStatus{Name: name, CheckedAt: time.Now()}
+	clientSet, err := c.provider.GetClientSet(name)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	type result struct {
+		version string
+		err     error
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		info, err := clientSet.Discovery.ServerVersion()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{version: info.GitVersion}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	select {
+	case r := <-done:
+		status.LatencyMS = time.Since(start).Milliseconds()
+		if r.err != nil {
+			status.Error = r.err.Error()
+			return status
+		}
+		status.Reachable = true
+		status.Version = r.version
+	case <-ctx.Done():
+		status.LatencyMS = time.Since(start).Milliseconds()
+		status.Error = fmt.Sprintf("API server did not respond within %s", c.timeout)
+	}
+	return status
+}
+
+// Ready reports whether the server is ready to serve: toolsets are
+// registered, the last probe reached at least one API server, and the server
+// is not shutting down. If not, it returns the reason.
+func (c *Checker) Ready() (bool, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready()
+}
+
+func (c *Checker) ready() (bool, string) {
+	switch {
+	case c.draining:
+		return false, "shutting down"
+	case !c.registered:
+		return false, "toolsets are not registered yet"
+	case !c.probed:
+		return false, "API servers have not been probed yet"
+	}
+	for _, status := range c.contexts {
+		if status.Reachable {
+			return true, ""
+		}
+	}
+	return false, "no Kubernetes API server is reachable"
+}
+
+// Report returns the server's health.
+func (c *Checker) Report() *Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report := &Report{
+		Contexts: append([]ContextStatus{}, c.contexts...),
+		Toolsets: make([]ToolsetStatus, 0, len(c.toolsets)),
+	}
+	report.Ready, report.Reason = c.ready()
+
+	reachable := 0
+	for _, status := range c.contexts {
+		if status.Reachable {
+			reachable++
+		}
+	}
+	switch {
+	case reachable == 0:
+		report.Status = StatusUnhealthy
+	case reachable < len(c.contexts):
+		report.Status = StatusDegraded
+	default:
+		report.Status = StatusHealthy
+	}
+
+	for _, ts := range c.toolsets {
+		status := ToolsetStatus{Name: ts.name, Registered: ts.registered}
+		for _, gvk := range ts.crds {
+			found := c.discovery != nil && c.discovery.HasCRD(gvk)
+			status.CRDs = append(status.CRDs, CRDStatus{GVK: kubernetes.GVK{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}.String(), Found: found})
+		}
+		report.Toolsets = append(report.Toolsets, status)
+	}
+	if c.discoveryErr != nil {
+		report.CRDDiscoveryError = c.discoveryErr.Error()
+	}
+	return report
+}
@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// slowDiscovery is a discovery client whose API server does not respond.
+type slowDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	release chan struct{}
+}
+
+func (d *slowDiscovery) ServerVersion() (*version.Info, error) {
+	<-d.release
+	return nil, errors.New("released")
+}
+
+// testProvider serves a client set per context.
+type testProvider struct {
+	clientSets map[string]*kubernetes.ClientSet
+}
+
+func (p *testProvider) GetClientSet(name string) (*kubernetes.ClientSet, error) {
+	clientSet, ok := p.clientSets[name]
+	if !ok || clientSet == nil {
+		return nil, errors.New("no credentials for context " + name)
+	}
+	return clientSet, nil
+}
+
+func (p *testProvider) ListContexts() ([]string, error) {
+	names := make([]string, 0, len(p.clientSets))
+	for name := range p.clientSets {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (p *testProvider) GetCurrentContext() (string, error) {
+	return "", nil
+}
+
+// HealthTestSuite tests readiness and the health report.
+type HealthTestSuite struct {
+	suite.Suite
+	provider *testProvider
+	cfg      *config.HealthConfig
+}
+
+func (s *HealthTestSuite) SetupTest() {
+	s.provider = &testProvider{clientSets: map[string]*kubernetes.ClientSet{
+		"prod": s.clientSet("v1.31.2", nil),
+	}}
+	s.cfg = &config.HealthConfig{
+		ProbeInterval: config.Duration(time.Minute),
+		ProbeTimeout:  config.Duration(100 * time.Millisecond),
+	}
+}
+
+// clientSet returns a client set whose API server reports gitVersion, or
+// fails with err.
+func (s *HealthTestSuite) clientSet(gitVersion string, err error) *kubernetes.ClientSet {
+	client := fake.NewSimpleClientset()
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+	if err != nil {
+		discovery.PrependReactor("get", "version", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, err
+		})
+	}
+	return &kubernetes.ClientSet{Typed: client, Discovery: discovery}
+}
+
+// TestReadiness tests that the server is ready only after registration and a
+// successful probe, and not while draining.
+func (s *HealthTestSuite) TestReadiness() {
+	checker := NewChecker(s.provider, s.cfg)
+
+	ready, reason := checker.Ready()
+	s.False(ready)
+	s.Contains(reason, "registered")
+
+	checker.SetRegistered()
+	ready, reason = checker.Ready()
+	s.False(ready)
+	s.Contains(reason, "probed")
+
+	checker.Probe(context.Background())
+	ready, _ = checker.Ready()
+	s.True(ready)
+
+	checker.SetDraining()
+	ready, reason = checker.Ready()
+	s.False(ready)
+	s.Equal("shutting down", reason)
+}
+
+// TestReport tests per-context probe results and the overall status.
+func (s *HealthTestSuite) TestReport() {
+	release := make(chan struct{})
+	defer close(release)
+	s.provider.clientSets["dev"] = s.clientSet("", errors.New("connection refused"))
+	s.provider.clientSets["lab"] = &kubernetes.ClientSet{Discovery: &slowDiscovery{release: release}}
+	s.provider.clientSets["old"] = nil
+
+	checker := NewChecker(s.provider, s.cfg)
+	checker.SetRegistered()
+	checker.Probe(context.Background())
+
+	report := checker.Report()
+	s.True(report.Ready)
+	s.Equal(StatusDegraded, report.Status)
+	s.Require().Len(report.Contexts, 4)
+
+	byName := make(map[string]ContextStatus)
+	for _, status := range report.Contexts {
+		byName[status.Name] = status
+	}
+	s.True(byName["prod"].Reachable)
+	s.Equal("v1.31.2", byName["prod"].Version)
+	s.False(byName["dev"].Reachable)
+	s.Contains(byName["dev"].Error, "connection refused")
+	s.False(byName["lab"].Reachable)
+	s.Contains(byName["lab"].Error, "did not respond")
+	s.False(byName["old"].Reachable)
+	s.Contains(byName["old"].Error, "no credentials")
+
+	// Without any reachable API server the server is not ready
+	s.provider.clientSets = map[string]*kubernetes.ClientSet{"dev": s.provider.clientSets["dev"]}
+	checker.Probe(context.Background())
+	report = checker.Report()
+	s.False(report.Ready)
+	s.Equal(StatusUnhealthy, report.Status)
+	s.Contains(report.Reason, "reachable")
+}
+
+// TestToolsets tests reporting the CRDs each toolset depends on.
+func (s *HealthTestSuite) TestToolsets() {
+	checker := NewChecker(s.provider, s.cfg)
+	checker.SetCRDDiscovery(kubernetes.NewCRDDiscovery(s.provider.clientSets["prod"], time.Minute), errors.New("discovery failed"))
+	checker.AddToolset("core", true)
+	checker.AddToolset("gitops", false, schema.GroupVersionKind{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "GitRepository"})
+
+	report := checker.Report()
+	s.Equal("discovery failed", report.CRDDiscoveryError)
+	s.Require().Len(report.Toolsets, 2)
+	s.Equal(ToolsetStatus{Name: "core", Registered: true}, report.Toolsets[0])
+	s.Equal(ToolsetStatus{Name: "gitops", CRDs: []CRDStatus{{GVK: "source.toolkit.fluxcd.io/v1/GitRepository"}}}, report.Toolsets[1])
+}
+
+// TestHealthTestSuite runs the health test suite.
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIKeyPrefix starts every generated API key, so API keys can be told apart
+// from other bearer tokens.
+const APIKeyPrefix = "kmcp_"
+
+// apiKeyHashPrefix starts the configured hash of an API key.
+const apiKeyHashPrefix = "sha256:"
+
+// apiKeysSecretKey is the Secret entry holding API keys.
+const apiKeysSecretKey = "keys.toml"
+
+// GenerateAPIKey returns a new random API key and its hash for the
+// configuration.
+func GenerateAPIKey() (key, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the "sha256:<hex>" hash of an API key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return apiKeyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// APIKeyVerifier verifies static API keys configured inline or in a
+// Kubernetes Secret. The Secret is reread every refresh interval, so keys can
+// be rotated by adding the new key, moving clients over and removing the old
+// one.
+type APIKeyVerifier struct {
+	keys            map[string]*config.APIKey
+	clientSet       *kubernetes.ClientSet
+	secretName      string
+	secretNamespace string
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu         sync.Mutex
+	secretKeys map[string]*config.APIKey
+	secretErr  error
+	loadedAt   time.Time
+}
+
+// NewAPIKeyVerifier creates a verifier for the configured API keys.
+func NewAPIKeyVerifier(cfg *config.APIKeysConfig, clientSet *kubernetes.ClientSet) (*APIKeyVerifier, error) {
+	keys, err := indexAPIKeys(cfg.Keys, "server.http.oauth.api_keys.keys")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SecretName != "" {
+		if cfg.SecretNamespace == "" {
+			return nil, errors.New("server.http.oauth.api_keys.secret_namespace is required with secret_name")
+		}
+		if clientSet == nil {
+			return nil, errors.New("a Kubernetes client is required to read API keys from a Secret")
+		}
+	}
+
+	return &APIKeyVerifier{
+		keys:            keys,
+		clientSet:       clientSet,
+		secretName:      cfg.SecretName,
+		secretNamespace: cfg.SecretNamespace,
+		refreshInterval: cfg.RefreshInterval.Duration(),
+		now:             time.Now,
+	}, nil
+}
+
+// indexAPIKeys validates API keys and indexes them by hash.
+func indexAPIKeys(keys []config.APIKey, source string) (map[string]*config.APIKey, error) {
+	index := make(map[string]*config.APIKey, len(keys))
+	for i := range keys {
+		key := &keys[i]
+		if key.Name == "" {
+			return nil, fmt.Errorf("invalid %s[%d]: name is required", source, i)
+		}
+		hash, ok := strings.CutPrefix(strings.ToLower(key.Hash), apiKeyHashPrefix)
+		if _, err := hex.DecodeString(hash); !ok || err != nil || len(hash) != 2*sha256.Size {
+			return nil, fmt.Errorf("invalid %s[%d] hash: must be \"sha256:\" followed by 64 hex digits", source, i)
+		}
+		if _, ok := index[hash]; ok {
+			return nil, fmt.Errorf("invalid %s[%d]: duplicate hash", source, i)
+		}
+		for _, pattern := range key.Tools {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid %s[%d] tool pattern %q: %w", source, i, pattern, err)
+			}
+		}
+		index[hash] = key
+	}
+	return index, nil
+}
+
+// Verify returns the configured API key matching key, if it has not expired.
+func (v *APIKeyVerifier) Verify(ctx context.Context, key string) (*config.APIKey, error) {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+
+	apiKey, ok := v.keys[hash]
+	if !ok && v.secretName != "" {
+		secretKeys, err := v.loadSecretKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		apiKey, ok = secretKeys[hash]
+	}
+	if !ok {
+		return nil, errors.New("unknown API key")
+	}
+	if !apiKey.ExpiresAt.IsZero() && !v.now().Before(apiKey.ExpiresAt) {
+		return nil, fmt.Errorf("API key %s expired at %s", apiKey.Name, apiKey.ExpiresAt.Format(time.RFC3339))
+	}
+	return apiKey, nil
+}
+
+// loadSecretKeys returns the keys in the Secret, rereading it once the
+// refresh interval has passed. If the Secret cannot be reread, the keys read
+// before are kept.
+func (v *APIKeyVerifier) loadSecretKeys(ctx context.Context) (map[string]*config.APIKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.loadedAt.IsZero() && v.now().Sub(v.loadedAt) < v.refreshInterval {
+		return v.secretKeys, v.secretErr
+	}
+	v.loadedAt = v.now()
+
+	keys, err := v.readSecret(ctx)
+	if err != nil {
+		if v.secretKeys == nil {
+			v.secretErr = err
+		}
+		return v.secretKeys, v.secretErr
+	}
+	v.secretKeys, v.secretErr = keys, nil
+	return keys, nil
+}
+
+// readSecret reads and validates the keys in the Secret.
+func (v *APIKeyVerifier) readSecret(ctx context.Context) (map[string]*config.APIKey, error) {
+	source := fmt.Sprintf("Secret %s/%s", v.secretNamespace, v.secretName)
+	secret, err := v.clientSet.Typed.CoreV1().Secrets(v.secretNamespace).Get(ctx, v.secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys from %s: %w", source, err)
+	}
+	var file struct {
+		Keys []config.APIKey `toml:"keys"`
+	}
+	if err := toml.Unmarshal(secret.Data[apiKeysSecretKey], &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s in %s: %w", apiKeysSecretKey, source, err)
+	}
+	return indexAPIKeys(file.Keys, source+" keys")
+}
+
+// apiKeyIdentity returns the identity an API key is bound to.
+func apiKeyIdentity(key *config.APIKey) *auth.Identity {
+	username := key.Username
+	if username == "" {
+		username = "apikey:" + key.Name
+	}
+	return &auth.Identity{Username: username, Groups: key.Groups}
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// APIKeyTestSuite tests API key and ServiceAccount token authentication.
+type APIKeyTestSuite struct {
+	suite.Suite
+	key    string
+	hash   string
+	client *fake.Clientset
+}
+
+func (s *APIKeyTestSuite) SetupTest() {
+	var err error
+	s.key, s.hash, err = GenerateAPIKey()
+	s.Require().NoError(err)
+	s.client = fake.NewSimpleClientset()
+}
+
+func (s *APIKeyTestSuite) clientSet() *kubernetes.ClientSet {
+	return &kubernetes.ClientSet{Typed: s.client}
+}
+
+// serve authenticates a request with token and returns the response code and
+// the request context seen by the handler.
+func (s *APIKeyTestSuite) serve(middleware *OAuthMiddleware, token string) (int, context.Context) {
+	var ctx context.Context
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, ctx
+}
+
+// TestInlineKeys tests verifying keys, expiry and key validation.
+func (s *APIKeyTestSuite) TestInlineKeys() {
+	expired, expiredHash, err := GenerateAPIKey()
+	s.Require().NoError(err)
+	verifier, err := NewAPIKeyVerifier(&config.APIKeysConfig{
+		Enabled: true,
+		Keys: []config.APIKey{
+			{Name: "ci", Hash: s.hash},
+			{Name: "old", Hash: expiredHash, ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}, nil)
+	s.Require().NoError(err)
+
+	key, err := verifier.Verify(context.Background(), s.key)
+	s.Require().NoError(err)
+	s.Equal("ci", key.Name)
+	s.Equal("apikey:ci", apiKeyIdentity(key).Username)
+
+	_, err = verifier.Verify(context.Background(), expired)
+	s.ErrorContains(err, "expired")
+	_, err = verifier.Verify(context.Background(), APIKeyPrefix+"unknown")
+	s.Error(err)
+
+	for _, keys := range [][]config.APIKey{
+		{{Hash: s.hash}},
+		{{Name: "ci", Hash: "md5:abc"}},
+		{{Name: "ci", Hash: strings.TrimSuffix(s.hash, "0") + "z"}},
+		{{Name: "ci", Hash: s.hash}, {Name: "again", Hash: s.hash}},
+		{{Name: "ci", Hash: s.hash, Tools: []string{"["}}},
+	} {
+		_, err := NewAPIKeyVerifier(&config.APIKeysConfig{Enabled: true, Keys: keys}, nil)
+		s.Error(err, "%+v", keys)
+	}
+	_, err = NewAPIKeyVerifier(&config.APIKeysConfig{Enabled: true, SecretName: "keys", SecretNamespace: "kube-mcp"}, nil)
+	s.Error(err, "Reading keys from a Secret requires a Kubernetes client")
+}
+
+// TestSecretKeys tests that keys are read from the Secret and reread after
+// the refresh interval, keeping the previous keys if it cannot be read.
+func (s *APIKeyTestSuite) TestSecretKeys() {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-keys", Namespace: "kube-mcp"},
+		Data: map[string][]byte{
+			apiKeysSecretKey: []byte("[[keys]]\nname = \"ci\"\nhash = \"" + s.hash + "\"\n"),
+		},
+	}
+	_, err := s.client.CoreV1().Secrets("kube-mcp").Create(context.Background(), secret, metav1.CreateOptions{})
+	s.Require().NoError(err)
+
+	verifier, err := NewAPIKeyVerifier(&config.APIKeysConfig{
+		Enabled:         true,
+		SecretName:      "api-keys",
+		SecretNamespace: "kube-mcp",
+		RefreshInterval: config.Duration(time.Minute),
+	}, s.clientSet())
+	s.Require().NoError(err)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	key, err := verifier.Verify(context.Background(), s.key)
+	s.Require().NoError(err)
+	s.Equal("ci", key.Name)
+
+	// Rotate: replace the key in the Secret
+	rotated, rotatedHash, err := GenerateAPIKey()
+	s.Require().NoError(err)
+	secret.Data[apiKeysSecretKey] = []byte("[[keys]]\nname = \"ci-2\"\nhash = \"" + rotatedHash + "\"\n")
+	_, err = s.client.CoreV1().Secrets("kube-mcp").Update(context.Background(), secret, metav1.UpdateOptions{})
+	s.Require().NoError(err)
+
+	_, err = verifier.Verify(context.Background(), rotated)
+	s.Error(err, "The Secret should not be reread before the refresh interval")
+
+	now = now.Add(time.Minute)
+	_, err = verifier.Verify(context.Background(), rotated)
+	s.NoError(err)
+	_, err = verifier.Verify(context.Background(), s.key)
+	s.Error(err, "The replaced key should be rejected")
+
+	s.Require().NoError(s.client.CoreV1().Secrets("kube-mcp").Delete(context.Background(), "api-keys", metav1.DeleteOptions{}))
+	now = now.Add(time.Minute)
+	_, err = verifier.Verify(context.Background(), rotated)
+	s.NoError(err, "Keys should be kept when the Secret cannot be read")
+}
+
+// TestMiddleware tests that API keys authenticate as their identity,
+// restricted to their tools.
+func (s *APIKeyTestSuite) TestMiddleware() {
+	middleware, err := NewOAuthMiddleware(&config.OAuth2Config{
+		Enabled:  true,
+		Provider: "apikey",
+		APIKeys: config.APIKeysConfig{
+			Enabled: true,
+			Keys: []config.APIKey{{
+				Name:     "ci",
+				Hash:     s.hash,
+				Username: "ci-bot",
+				Groups:   []string{"ci"},
+				Tools:    []string{"pods_*"},
+			}},
+		},
+	}, nil, &config.SecurityConfig{ValidateToken: true})
+	s.Require().NoError(err)
+
+	code, ctx := s.serve(middleware, s.key)
+	s.Require().Equal(http.StatusOK, code)
+	identity := auth.IdentityFromContext(ctx)
+	s.Require().NotNil(identity)
+	s.Equal("ci-bot", identity.Username)
+	s.Equal([]string{"ci"}, identity.Groups)
+	tools, restricted := auth.AllowedToolsFromContext(ctx)
+	s.True(restricted)
+	s.Equal([]string{"pods_*"}, tools)
+	_, scoped := auth.ScopesFromContext(ctx)
+	s.False(scoped, "Keys without scopes should not be checked against tool scopes")
+
+	code, _ = s.serve(middleware, APIKeyPrefix+"unknown")
+	s.Equal(http.StatusUnauthorized, code)
+
+	_, err = NewOAuthMiddleware(&config.OAuth2Config{Enabled: true, Provider: "apikey"}, nil, nil)
+	s.Error(err, "The apikey provider requires API keys")
+}
+
+// TestServiceAccountProvider tests that ServiceAccount tokens are reviewed
+// for the configured audience and checked against the allowed ServiceAccounts.
+func (s *APIKeyTestSuite) TestServiceAccountProvider() {
+	var audiences []string
+	s.client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		audiences = review.Spec.Audiences
+		review.Status = authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			Audiences:     review.Spec.Audiences,
+			User:          authenticationv1.UserInfo{Username: review.Spec.Token},
+		}
+		if review.Spec.Token == "wrong-audience" {
+			review.Status.Audiences = nil
+		}
+		return true, review, nil
+	})
+
+	cfg := &config.OAuth2Config{
+		Enabled:         true,
+		Provider:        "serviceaccount",
+		ResourceURL:     "https://kube-mcp.example.com/mcp",
+		ServiceAccounts: []string{"ci/*"},
+	}
+	middleware, err := NewOAuthMiddleware(cfg, s.clientSet(), &config.SecurityConfig{ValidateToken: true})
+	s.Require().NoError(err)
+
+	code, ctx := s.serve(middleware, "system:serviceaccount:ci:deployer")
+	s.Require().Equal(http.StatusOK, code)
+	s.Equal([]string{cfg.ResourceURL}, audiences)
+	s.Equal("system:serviceaccount:ci:deployer", auth.IdentityFromContext(ctx).Username)
+	_, scoped := auth.ScopesFromContext(ctx)
+	s.False(scoped)
+
+	for _, token := range []string{"system:serviceaccount:default:app", "alice", "wrong-audience"} {
+		code, _ := s.serve(middleware, token)
+		s.Equal(http.StatusUnauthorized, code, token)
+	}
+
+	cfg.ResourceURL = ""
+	_, err = NewOAuthMiddleware(cfg, s.clientSet(), nil)
+	s.Error(err, "The serviceaccount provider requires an audience")
+}
+
+// TestAPIKeyTestSuite runs the API key test suite.
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
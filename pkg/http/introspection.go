@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+)
+
+// OAuth2Verifier verifies opaque OAuth2 access tokens using RFC 7662 token
+// introspection.
+type OAuth2Verifier struct {
+	introspectionURL string
+	clientID         string
+	clientSecret     string
+	issuer           string
+	audience         string
+	claims           claimMapping
+	client           *http.Client
+	now              func() time.Time
+}
+
+// NewOAuth2Verifier creates a new OAuth2 verifier.
+func NewOAuth2Verifier(cfg *config.OAuth2Config) (*OAuth2Verifier, error) {
+	introspectionURL := cfg.IntrospectionURL
+	if introspectionURL == "" {
+		if cfg.IssuerURL == "" {
+			return nil, fmt.Errorf("introspection_url or issuer_url is required for the oauth2 provider")
+		}
+		introspectionURL = strings.TrimSuffix(cfg.IssuerURL, "/") + "/introspect"
+	}
+
+	return &OAuth2Verifier{
+		introspectionURL: introspectionURL,
+		clientID:         cfg.ClientID,
+		clientSecret:     cfg.ClientSecret,
+		issuer:           cfg.IssuerURL,
+		audience:         tokenAudience(cfg),
+		claims:           newClaimMapping(cfg),
+		client:           &http.Client{Timeout: 10 * time.Second},
+		now:              time.Now,
+	}, nil
+}
+
+// VerifyToken introspects the token and maps the response to an identity.
+// The token must be active and unexpired, and must match the configured issuer
+// and audience when the response reports them.
+func (v *OAuth2Verifier) VerifyToken(ctx context.Context, token string) (*auth.Identity, error) {
+	identity, _, err := v.VerifyTokenScopes(ctx, token)
+	return identity, err
+}
+
+// VerifyTokenScopes verifies the token like VerifyToken and also returns the
+// scopes it grants.
+func (v *OAuth2Verifier) VerifyTokenScopes(ctx context.Context, token string) (*auth.Identity, []string, error) {
+	claims, err := v.introspect(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if active, _ := claims["active"].(bool); !active {
+		return nil, nil, fmt.Errorf("token is not active")
+	}
+	if exp, ok := claims["exp"].(float64); ok && v.now().After(time.Unix(int64(exp), 0)) {
+		return nil, nil, fmt.Errorf("token is expired")
+	}
+	if iss, ok := claims["iss"].(string); ok && v.issuer != "" && iss != v.issuer {
+		return nil, nil, fmt.Errorf("token issued by %q, expected %q", iss, v.issuer)
+	}
+	if v.audience != "" && !hasAudience(claims["aud"], v.audience) {
+		return nil, nil, fmt.Errorf("token audience does not include %q", v.audience)
+	}
+
+	identity, err := v.claims.identity(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	return identity, tokenScopes(claims), nil
+}
+
+// introspect calls the introspection endpoint and returns the decoded response.
+func (v *OAuth2Verifier) introspect(ctx context.Context, token string) (map[string]any, error) {
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.introspectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if v.clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(v.clientID), url.QueryEscape(v.clientSecret))
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read introspection response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned %s", resp.Status)
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+	return claims, nil
+}
+
+// hasAudience reports whether an "aud" value (a string or a list of strings)
+// contains the audience.
+func hasAudience(aud any, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,9 +2,15 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
 
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	authenticationv1 "k8s.io/api/authentication/v1"
 )
 
 // KubernetesTokenVerifier verifies Bearer tokens using Kubernetes TokenReview API.
@@ -19,12 +25,91 @@ func NewKubernetesTokenVerifier(clientSet *kubernetes.ClientSet) *KubernetesToke
 	}
 }
 
-// VerifyToken verifies a Bearer token using TokenReview.
-func (v *KubernetesTokenVerifier) VerifyToken(ctx context.Context, token string) error {
+// VerifyToken verifies a Bearer token using TokenReview and returns the
+// authenticated user as an identity.
+func (v *KubernetesTokenVerifier) VerifyToken(ctx context.Context, token string) (*auth.Identity, error) {
 	reviewer := kubernetes.NewTokenReviewer(v.clientSet)
-	_, err := reviewer.ValidateToken(ctx, token)
+	user, err := reviewer.ValidateToken(ctx, token)
 	if err != nil {
-		return fmt.Errorf("token validation failed: %w", err)
+		return nil, fmt.Errorf("token validation failed: %w", err)
 	}
-	return nil
+	return identityFromUserInfo(user), nil
+}
+
+// serviceAccountUsernamePrefix starts the usernames of ServiceAccounts.
+const serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+// ServiceAccountVerifier verifies projected ServiceAccount tokens issued for
+// kube-mcp's audience using the TokenReview API.
+type ServiceAccountVerifier struct {
+	reviewer        *kubernetes.TokenReviewer
+	serviceAccounts []string
+}
+
+// NewServiceAccountVerifier creates a verifier for ServiceAccount tokens with
+// the configured audience, accepting the ServiceAccounts matching the
+// "namespace/name" patterns, or any ServiceAccount if there are none.
+func NewServiceAccountVerifier(cfg *config.OAuth2Config, clientSet *kubernetes.ClientSet) (*ServiceAccountVerifier, error) {
+	audience := tokenAudience(cfg)
+	if audience == "" {
+		return nil, errors.New("audience or resource_url is required for the serviceaccount provider")
+	}
+	if clientSet == nil {
+		return nil, errors.New("a Kubernetes client is required for the serviceaccount provider")
+	}
+	for _, pattern := range cfg.ServiceAccounts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid server.http.oauth.service_accounts pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &ServiceAccountVerifier{
+		reviewer:        kubernetes.NewTokenReviewer(clientSet, audience),
+		serviceAccounts: cfg.ServiceAccounts,
+	}, nil
+}
+
+// VerifyToken verifies a ServiceAccount token and returns the ServiceAccount
+// as an identity.
+func (v *ServiceAccountVerifier) VerifyToken(ctx context.Context, token string) (*auth.Identity, error) {
+	user, err := v.reviewer.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("token validation failed: %w", err)
+	}
+
+	name, ok := strings.CutPrefix(user.Username, serviceAccountUsernamePrefix)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a ServiceAccount", user.Username)
+	}
+	name = strings.Replace(name, ":", "/", 1)
+	if len(v.serviceAccounts) > 0 && !matchesAnyPattern(v.serviceAccounts, name) {
+		return nil, fmt.Errorf("ServiceAccount %s is not allowed", name)
+	}
+	return identityFromUserInfo(user), nil
+}
+
+// matchesAnyPattern reports whether name matches any of the glob patterns.
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// identityFromUserInfo converts a TokenReview user to an identity.
+func identityFromUserInfo(user *authenticationv1.UserInfo) *auth.Identity {
+	identity := &auth.Identity{
+		Username: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+	}
+	if len(user.Extra) > 0 {
+		identity.Extra = make(map[string][]string, len(user.Extra))
+		for k, v := range user.Extra {
+			identity.Extra[k] = []string(v)
+		}
+	}
+	return identity
 }
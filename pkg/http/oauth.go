@@ -5,25 +5,46 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/wrkode/kube-mcp/pkg/auth"
 	"github.com/wrkode/kube-mcp/pkg/config"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
-	"golang.org/x/oauth2"
 )
 
-// OAuthMiddleware provides OAuth2/OIDC authentication middleware.
+// OAuthMiddleware provides bearer token authentication middleware for
+// OAuth2/OIDC tokens, ServiceAccount tokens and API keys.
 type OAuthMiddleware struct {
 	config        *config.OAuth2Config
 	verifier      TokenVerifier
+	apiKeys       *APIKeyVerifier
 	k8sVerifier   *KubernetesTokenVerifier
 	validateToken bool
 }
 
 // TokenVerifier verifies OAuth tokens.
 type TokenVerifier interface {
-	VerifyToken(ctx context.Context, token string) error
+	// VerifyToken verifies the token and returns the caller's identity.
+	// A nil identity with a nil error means the token is valid but carries no identity.
+	VerifyToken(ctx context.Context, token string) (*auth.Identity, error)
 }
 
+// ScopedTokenVerifier is a TokenVerifier that also reports the scopes granted
+// to the token.
+type ScopedTokenVerifier interface {
+	TokenVerifier
+
+	// VerifyTokenScopes verifies the token like VerifyToken and also returns
+	// its scopes.
+	VerifyTokenScopes(ctx context.Context, token string) (*auth.Identity, []string, error)
+}
+
+// tokenInfoLifetime bounds how long verified token info is trusted when the
+// verifier cannot report the token's own expiry. Token info is request-scoped,
+// so this only needs to cover a single request.
+const tokenInfoLifetime = 5 * time.Minute
+
 // NewOAuthMiddleware creates a new OAuth middleware.
 func NewOAuthMiddleware(cfg *config.OAuth2Config, clientSet *kubernetes.ClientSet, securityCfg *config.SecurityConfig) (*OAuthMiddleware, error) {
 	var verifier TokenVerifier
@@ -40,15 +61,33 @@ func NewOAuthMiddleware(cfg *config.OAuth2Config, clientSet *kubernetes.ClientSe
 		if err != nil {
 			return nil, fmt.Errorf("failed to create OAuth2 verifier: %w", err)
 		}
+	case "serviceaccount":
+		verifier, err = NewServiceAccountVerifier(cfg, clientSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ServiceAccount token verifier: %w", err)
+		}
+	case "apikey":
+		if !cfg.APIKeys.Enabled {
+			return nil, fmt.Errorf("the apikey provider requires server.http.oauth.api_keys.enabled")
+		}
 	default:
 		return nil, fmt.Errorf("unsupported OAuth provider: %s", cfg.Provider)
 	}
 
+	var apiKeys *APIKeyVerifier
+	if cfg.APIKeys.Enabled {
+		apiKeys, err = NewAPIKeyVerifier(&cfg.APIKeys, clientSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create API key verifier: %w", err)
+		}
+	}
+
+	// ServiceAccount tokens are already reviewed by the verifier
 	var k8sVerifier *KubernetesTokenVerifier
 	validateToken := true
 	if securityCfg != nil {
 		validateToken = securityCfg.ValidateToken
-		if validateToken && clientSet != nil {
+		if validateToken && clientSet != nil && cfg.Provider != "serviceaccount" {
 			k8sVerifier = NewKubernetesTokenVerifier(clientSet)
 		}
 	}
@@ -56,100 +95,87 @@ func NewOAuthMiddleware(cfg *config.OAuth2Config, clientSet *kubernetes.ClientSe
 	return &OAuthMiddleware{
 		config:        cfg,
 		verifier:      verifier,
+		apiKeys:       apiKeys,
 		k8sVerifier:   k8sVerifier,
 		validateToken: validateToken,
 	}, nil
 }
 
 // Middleware returns an HTTP middleware function for OAuth authentication.
+// The verified identity, bearer token and scopes are attached to the request
+// context and to the MCP token info, so tool handlers can act as the caller.
+// Requests without a valid token get a 401 challenge pointing to the
+// protected resource metadata.
 func (m *OAuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
-			return
-		}
+		withCaller := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			ctx := auth.ContextWithTokenInfo(r.Context(), sdkauth.TokenInfoFromContext(r.Context()))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+		challenged := &challengeWriter{ResponseWriter: w, challenge: m.challenge(r)}
+		sdkauth.RequireBearerToken(m.verifyToken, nil)(withCaller).ServeHTTP(challenged, r)
+	})
+}
 
-		// Parse Bearer token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
-			return
-		}
+// verifyToken implements sdkauth.TokenVerifier.
+func (m *OAuthMiddleware) verifyToken(ctx context.Context, token string, _ *http.Request) (*sdkauth.TokenInfo, error) {
+	// API keys are told apart by their prefix, unless they are the only
+	// tokens accepted
+	if m.apiKeys != nil && (m.verifier == nil || strings.HasPrefix(token, APIKeyPrefix)) {
+		return m.verifyAPIKey(ctx, token)
+	}
 
-		token := parts[1]
+	var identity *auth.Identity
+	var scopes []string
 
-		// Verify token with OAuth provider (if configured)
-		if m.verifier != nil {
-			if err := m.verifier.VerifyToken(r.Context(), token); err != nil {
-				http.Error(w, fmt.Sprintf("Token verification failed: %v", err), http.StatusUnauthorized)
-				return
-			}
+	// Verify token with OAuth provider (if configured)
+	if m.verifier != nil {
+		var err error
+		if scoped, ok := m.verifier.(ScopedTokenVerifier); ok {
+			identity, scopes, err = scoped.VerifyTokenScopes(ctx, token)
+		} else {
+			identity, err = m.verifier.VerifyToken(ctx, token)
 		}
-
-		// Validate token with Kubernetes TokenReview if enabled
-		if m.validateToken && m.k8sVerifier != nil {
-			if err := m.k8sVerifier.VerifyToken(r.Context(), token); err != nil {
-				http.Error(w, fmt.Sprintf("Kubernetes token validation failed: %v", err), http.StatusUnauthorized)
-				return
-			}
+		if err != nil {
+			return nil, fmt.Errorf("%w: token verification failed: %v", sdkauth.ErrInvalidToken, err)
 		}
+	}
 
-		// Store token in context for use by tool handlers
-		ctx := context.WithValue(r.Context(), "bearer_token", token)
-		r = r.WithContext(ctx)
-
-		// Token is valid, proceed
-		next.ServeHTTP(w, r)
-	})
-}
-
-// OIDCVerifier verifies OIDC tokens.
-type OIDCVerifier struct {
-	provider *oauth2.Config
-}
-
-// NewOIDCVerifier creates a new OIDC verifier.
-func NewOIDCVerifier(cfg *config.OAuth2Config) (*OIDCVerifier, error) {
-	// For OIDC, we would typically use a library like github.com/coreos/go-oidc
-	// For now, this is a placeholder implementation
-	return &OIDCVerifier{}, nil
-}
-
-// VerifyToken verifies an OIDC token.
-func (v *OIDCVerifier) VerifyToken(ctx context.Context, token string) error {
-	// TODO: Implement OIDC token verification
-	// This would use the OIDC provider to verify the token
-	return nil
-}
+	// Validate token with Kubernetes TokenReview if enabled; its identity is
+	// authoritative for Kubernetes calls.
+	if m.validateToken && m.k8sVerifier != nil {
+		id, err := m.k8sVerifier.VerifyToken(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: kubernetes token validation failed: %v", sdkauth.ErrInvalidToken, err)
+		}
+		identity = id
+	}
 
-// OAuth2Verifier verifies OAuth2 tokens.
-type OAuth2Verifier struct {
-	config *oauth2.Config
+	info := auth.NewTokenInfo(identity, token, scopes, time.Now().Add(tokenInfoLifetime))
+	// OAuth and OIDC tokens are held to their scopes, and grant none without
+	// a scope claim; ServiceAccount and other Kubernetes tokens have no scopes
+	if _, scoped := m.verifier.(ScopedTokenVerifier); !scoped {
+		return auth.UnscopedTokenInfo(info), nil
+	}
+	return info, nil
 }
 
-// NewOAuth2Verifier creates a new OAuth2 verifier.
-func NewOAuth2Verifier(cfg *config.OAuth2Config) (*OAuth2Verifier, error) {
-	oauth2Config := &oauth2.Config{
-		ClientID:     cfg.ClientID,
-		ClientSecret: cfg.ClientSecret,
-		Scopes:       cfg.Scopes,
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  cfg.IssuerURL + "/auth",
-			TokenURL: cfg.IssuerURL + "/token",
-		},
-		RedirectURL: cfg.RedirectURL,
+// verifyAPIKey verifies an API key and returns token info for its identity,
+// restricted to its tools.
+func (m *OAuthMiddleware) verifyAPIKey(ctx context.Context, token string) (*sdkauth.TokenInfo, error) {
+	key, err := m.apiKeys.Verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: API key verification failed: %v", sdkauth.ErrInvalidToken, err)
 	}
 
-	return &OAuth2Verifier{
-		config: oauth2Config,
-	}, nil
-}
-
-// VerifyToken verifies an OAuth2 token.
-func (v *OAuth2Verifier) VerifyToken(ctx context.Context, token string) error {
-	// For OAuth2, we would verify the token with the provider
-	// This is a placeholder implementation
-	return nil
+	expiration := time.Now().Add(tokenInfoLifetime)
+	if !key.ExpiresAt.IsZero() && key.ExpiresAt.Before(expiration) {
+		expiration = key.ExpiresAt
+	}
+	info := auth.RestrictTokenTools(auth.NewTokenInfo(apiKeyIdentity(key), token, key.Scopes, expiration), key.Tools)
+	// Keys configured without scopes are limited by their tools instead
+	if len(key.Scopes) == 0 {
+		return auth.UnscopedTokenInfo(info), nil
+	}
+	return info, nil
 }
@@ -0,0 +1,379 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/oauthex"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpServer "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// testIssuer is a local OIDC issuer serving discovery, JWKS and introspection.
+type testIssuer struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	keys   map[string]*rsa.PrivateKey
+	active map[string]map[string]any
+}
+
+func newTestIssuer() *testIssuer {
+	i := &testIssuer{
+		keys:   make(map[string]*rsa.PrivateKey),
+		active: make(map[string]map[string]any),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                i.server.URL,
+			"jwks_uri":                              i.server.URL + "/jwks",
+			"authorization_endpoint":                i.server.URL + "/auth",
+			"token_endpoint":                        i.server.URL + "/token",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		var keys []map[string]string
+		for kid, key := range i.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	mux.HandleFunc("/introspect", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "kube-mcp" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		resp, ok := i.active[r.PostFormValue("token")]
+		if !ok {
+			resp = map[string]any{"active": false}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	i.server = httptest.NewServer(mux)
+	return i
+}
+
+// rotateKey publishes a new signing key under kid.
+func (i *testIssuer) rotateKey(kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys[kid] = key
+}
+
+// sign returns an RS256 JWT over claims signed with the key kid.
+func (i *testIssuer) sign(kid string, claims map[string]any) string {
+	i.mu.Lock()
+	key := i.keys[kid]
+	i.mu.Unlock()
+	return signJWT(key, kid, claims)
+}
+
+func signJWT(key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// OAuthTestSuite tests OIDC and OAuth2 token verification.
+type OAuthTestSuite struct {
+	suite.Suite
+	issuer *testIssuer
+	cfg    *config.OAuth2Config
+}
+
+// SetupTest sets up the test.
+func (s *OAuthTestSuite) SetupTest() {
+	s.issuer = newTestIssuer()
+	s.issuer.rotateKey("key-1")
+	s.cfg = &config.OAuth2Config{
+		Enabled:      true,
+		Provider:     "oidc",
+		IssuerURL:    s.issuer.server.URL,
+		ClientID:     "kube-mcp",
+		ClientSecret: "secret",
+	}
+}
+
+// TearDownTest tears down the test.
+func (s *OAuthTestSuite) TearDownTest() {
+	s.issuer.server.Close()
+}
+
+func (s *OAuthTestSuite) claims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss":    s.issuer.server.URL,
+		"aud":    "kube-mcp",
+		"sub":    "user-123",
+		"email":  "alice@example.com",
+		"groups": []string{"dev", "ops"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+// TestOIDCValidToken tests that a valid token yields the mapped identity.
+func (s *OAuthTestSuite) TestOIDCValidToken() {
+	s.cfg.UsernameClaim = "email"
+	verifier, err := NewOIDCVerifier(s.cfg)
+	s.Require().NoError(err)
+
+	identity, err := verifier.VerifyToken(context.Background(), s.issuer.sign("key-1", s.claims(nil)))
+	s.Require().NoError(err)
+	s.Equal("alice@example.com", identity.Username)
+	s.Equal("user-123", identity.UID)
+	s.Equal([]string{"dev", "ops"}, identity.Groups)
+}
+
+// TestOIDCRejectsInvalidTokens tests signature, issuer, audience and expiry checks.
+func (s *OAuthTestSuite) TestOIDCRejectsInvalidTokens() {
+	verifier, err := NewOIDCVerifier(s.cfg)
+	s.Require().NoError(err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	tests := map[string]string{
+		"wrong signature": signJWT(otherKey, "key-1", s.claims(nil)),
+		"wrong issuer":    s.issuer.sign("key-1", s.claims(map[string]any{"iss": "https://evil.example.com"})),
+		"wrong audience":  s.issuer.sign("key-1", s.claims(map[string]any{"aud": "someone-else"})),
+		"expired":         s.issuer.sign("key-1", s.claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})),
+		"not a JWT":       "opaque-token",
+	}
+	for name, token := range tests {
+		_, err := verifier.VerifyToken(context.Background(), token)
+		s.Error(err, name)
+	}
+}
+
+// TestOIDCKeyRotation tests that tokens signed with a newly published key verify.
+func (s *OAuthTestSuite) TestOIDCKeyRotation() {
+	verifier, err := NewOIDCVerifier(s.cfg)
+	s.Require().NoError(err)
+
+	_, err = verifier.VerifyToken(context.Background(), s.issuer.sign("key-1", s.claims(nil)))
+	s.Require().NoError(err)
+
+	s.issuer.rotateKey("key-2")
+	identity, err := verifier.VerifyToken(context.Background(), s.issuer.sign("key-2", s.claims(nil)))
+	s.Require().NoError(err)
+	s.Equal("user-123", identity.Username)
+}
+
+// TestOIDCMissingUsernameClaim tests that tokens without the username claim are rejected.
+func (s *OAuthTestSuite) TestOIDCMissingUsernameClaim() {
+	s.cfg.UsernameClaim = "preferred_username"
+	verifier, err := NewOIDCVerifier(s.cfg)
+	s.Require().NoError(err)
+
+	_, err = verifier.VerifyToken(context.Background(), s.issuer.sign("key-1", s.claims(nil)))
+	s.Error(err)
+}
+
+// TestOAuth2Introspection tests RFC 7662 introspection.
+func (s *OAuthTestSuite) TestOAuth2Introspection() {
+	s.cfg.Provider = "oauth2"
+	s.cfg.Audience = "kube-mcp"
+	s.cfg.UsernameClaim = "username"
+	s.issuer.active["good"] = map[string]any{
+		"active":   true,
+		"username": "alice",
+		"sub":      "user-123",
+		"aud":      []string{"kube-mcp"},
+		"groups":   "dev",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}
+	s.issuer.active["other-audience"] = map[string]any{
+		"active":   true,
+		"username": "alice",
+		"aud":      "someone-else",
+	}
+	verifier, err := NewOAuth2Verifier(s.cfg)
+	s.Require().NoError(err)
+
+	identity, err := verifier.VerifyToken(context.Background(), "good")
+	s.Require().NoError(err)
+	s.Equal("alice", identity.Username)
+	s.Equal([]string{"dev"}, identity.Groups)
+
+	_, err = verifier.VerifyToken(context.Background(), "other-audience")
+	s.Error(err)
+
+	_, err = verifier.VerifyToken(context.Background(), "revoked")
+	s.Error(err)
+}
+
+// TestMiddlewareAttachesIdentity tests that the verified identity reaches the request context.
+func (s *OAuthTestSuite) TestMiddlewareAttachesIdentity() {
+	middleware, err := NewOAuthMiddleware(s.cfg, nil, &config.SecurityConfig{})
+	s.Require().NoError(err)
+
+	var got *auth.Identity
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = auth.IdentityFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+s.issuer.sign("key-1", s.claims(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Require().NotNil(got)
+	s.Equal("user-123", got.Username)
+
+	req = httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	s.Equal(http.StatusUnauthorized, rec.Code)
+}
+
+// TestTokenScopes tests that the token's scopes reach the request context.
+func (s *OAuthTestSuite) TestTokenScopes() {
+	middleware, err := NewOAuthMiddleware(s.cfg, nil, &config.SecurityConfig{})
+	s.Require().NoError(err)
+
+	var scopes []string
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scopes, _ = auth.ScopesFromContext(r.Context())
+	}))
+
+	for claims, want := range map[string]map[string]any{
+		"scope": {"scope": "kube:read kube:write"},
+		"scp":   {"scp": []string{"kube:read", "kube:write"}},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+s.issuer.sign("key-1", s.claims(want)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		s.Require().Equal(http.StatusOK, rec.Code, claims)
+		s.Equal([]string{"kube:read", "kube:write"}, scopes, claims)
+	}
+
+	// A token without scopes grants none, and is still held to scope rules
+	var scoped bool
+	handler = middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scopes, scoped = auth.ScopesFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+s.issuer.sign("key-1", s.claims(nil)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.True(scoped)
+	s.Empty(scopes)
+}
+
+// TestResourceAudience tests that tokens must be issued for the server's
+// resource URI when it is configured.
+func (s *OAuthTestSuite) TestResourceAudience() {
+	s.cfg.ResourceURL = "https://kube-mcp.example.com/mcp"
+	verifier, err := NewOIDCVerifier(s.cfg)
+	s.Require().NoError(err)
+
+	_, err = verifier.VerifyToken(context.Background(), s.issuer.sign("key-1", s.claims(nil)))
+	s.Error(err, "A token for the client ID should be rejected")
+
+	_, err = verifier.VerifyToken(context.Background(), s.issuer.sign("key-1", s.claims(map[string]any{
+		"aud": []string{"kube-mcp", s.cfg.ResourceURL},
+	})))
+	s.NoError(err)
+}
+
+// TestProtectedResourceMetadata tests that unauthenticated MCP requests are
+// challenged with a resource_metadata URL an MCP client can follow to the
+// authorization server.
+func (s *OAuthTestSuite) TestProtectedResourceMetadata() {
+	s.cfg.Scopes = []string{"openid"}
+	s.cfg.ToolScopes = []config.ToolScopeRule{
+		{Scope: "kube:read"},
+		{Scope: "kube:write", Classes: []string{"write", "destructive"}},
+	}
+	server, err := NewServer(mcpServer.NewServer("kube-mcp", "1.2.3", false), &config.HTTPConfig{OAuth: *s.cfg},
+		nil, nil, nil, &config.SecurityConfig{}, nil)
+	s.Require().NoError(err)
+	ts := httptest.NewTLSServer(server)
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/mcp", "application/json", strings.NewReader("{}"))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Contains(resp.Header.Get("WWW-Authenticate"), `resource_metadata="`+ts.URL+`/.well-known/oauth-protected-resource/mcp"`)
+
+	match := regexp.MustCompile(`resource_metadata="([^"]+)"`).FindStringSubmatch(resp.Header.Get("WWW-Authenticate"))
+	s.Require().Len(match, 2)
+	resp, err = ts.Client().Get(match[1])
+	s.Require().NoError(err)
+	var metadata oauthex.ProtectedResourceMetadata
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&metadata))
+	resp.Body.Close()
+	s.Equal(ts.URL+"/mcp", metadata.Resource, "The resource must match the URL the client used")
+	s.Equal([]string{s.issuer.server.URL}, metadata.AuthorizationServers)
+	s.Equal([]string{"openid", "kube:read", "kube:write"}, metadata.ScopesSupported)
+	s.Equal([]string{"header"}, metadata.BearerMethodsSupported)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader("{}"))
+	s.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	resp, err = ts.Client().Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Contains(resp.Header.Get("WWW-Authenticate"), `error="invalid_token"`)
+
+	resp, err = ts.Client().Get(ts.URL + "/.well-known/mcp")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var info map[string]any
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&info))
+	s.Equal("kube-mcp", info["name"])
+	s.Equal("1.2.3", info["version"])
+	s.Equal(ts.URL+"/.well-known/oauth-protected-resource/mcp", info["protected_resource_metadata"])
+}
+
+// TestOAuthTestSuite runs the OAuth test suite.
+func TestOAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(OAuthTestSuite))
+}
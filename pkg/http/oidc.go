@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+)
+
+// OIDCVerifier verifies OIDC JWTs against the issuer's published signing keys.
+// Provider discovery happens on first use, so the server can start while the
+// issuer is unreachable. Signing keys are cached and refetched when a token
+// references an unknown key ID, which picks up key rotation.
+type OIDCVerifier struct {
+	issuerURL string
+	audience  string
+	claims    claimMapping
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCVerifier creates a new OIDC verifier.
+func NewOIDCVerifier(cfg *config.OAuth2Config) (*OIDCVerifier, error) {
+	if cfg.IssuerURL == "" {
+		return nil, fmt.Errorf("issuer_url is required for the oidc provider")
+	}
+	audience := tokenAudience(cfg)
+	if audience == "" {
+		audience = cfg.ClientID
+	}
+	if audience == "" {
+		return nil, fmt.Errorf("audience, resource_url or client_id is required for the oidc provider")
+	}
+
+	return &OIDCVerifier{
+		issuerURL: cfg.IssuerURL,
+		audience:  audience,
+		claims:    newClaimMapping(cfg),
+	}, nil
+}
+
+// VerifyToken verifies the token's signature, issuer, audience and expiry and
+// maps its claims to an identity.
+func (v *OIDCVerifier) VerifyToken(ctx context.Context, token string) (*auth.Identity, error) {
+	identity, _, err := v.VerifyTokenScopes(ctx, token)
+	return identity, err
+}
+
+// VerifyTokenScopes verifies the token like VerifyToken and also returns the
+// scopes it grants.
+func (v *OIDCVerifier) VerifyTokenScopes(ctx context.Context, token string) (*auth.Identity, []string, error) {
+	verifier, err := v.getVerifier(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode token claims: %w", err)
+	}
+	identity, err := v.claims.identity(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	return identity, tokenScopes(claims), nil
+}
+
+// getVerifier discovers the provider on first use. Failed discovery is retried
+// on the next request.
+func (v *OIDCVerifier) getVerifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, v.issuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	v.verifier = provider.Verifier(&oidc.Config{ClientID: v.audience})
+	return v.verifier, nil
+}
+
+// claimMapping maps token claims to an identity.
+type claimMapping struct {
+	usernameClaim string
+	groupsClaim   string
+}
+
+func newClaimMapping(cfg *config.OAuth2Config) claimMapping {
+	m := claimMapping{
+		usernameClaim: cfg.UsernameClaim,
+		groupsClaim:   cfg.GroupsClaim,
+	}
+	if m.usernameClaim == "" {
+		m.usernameClaim = "sub"
+	}
+	if m.groupsClaim == "" {
+		m.groupsClaim = "groups"
+	}
+	return m
+}
+
+// identity builds an identity from claims. The username claim is required;
+// the groups claim may be missing, a string, or a list of strings.
+func (m claimMapping) identity(claims map[string]any) (*auth.Identity, error) {
+	username, _ := claims[m.usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("token has no %q claim", m.usernameClaim)
+	}
+
+	identity := &auth.Identity{Username: username}
+	if sub, ok := claims["sub"].(string); ok {
+		identity.UID = sub
+	}
+
+	switch groups := claims[m.groupsClaim].(type) {
+	case nil:
+	case string:
+		identity.Groups = []string{groups}
+	case []any:
+		for _, g := range groups {
+			s, ok := g.(string)
+			if !ok {
+				return nil, fmt.Errorf("claim %q must contain only strings", m.groupsClaim)
+			}
+			identity.Groups = append(identity.Groups, s)
+		}
+	default:
+		return nil, fmt.Errorf("claim %q must be a string or a list of strings", m.groupsClaim)
+	}
+
+	return identity, nil
+}
+
+// tokenAudience returns the audience tokens must be issued for: the
+// configured audience, or else the server's resource URI.
+func tokenAudience(cfg *config.OAuth2Config) string {
+	if cfg.Audience != "" {
+		return cfg.Audience
+	}
+	return cfg.ResourceURL
+}
+
+// tokenScopes returns the scopes in a token's "scope" claim (space-separated,
+// as in RFC 9068 and RFC 7662) or "scp" claim (a string or a list, as issued
+// by some providers). It is never nil, so tokens without scopes are checked
+// against the tool scopes too.
+func tokenScopes(claims map[string]any) []string {
+	for _, claim := range []string{"scope", "scp"} {
+		switch value := claims[claim].(type) {
+		case string:
+			return strings.Fields(value)
+		case []any:
+			scopes := []string{}
+			for _, v := range value {
+				if scope, ok := v.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+			return scopes
+		}
+	}
+	return []string{}
+}
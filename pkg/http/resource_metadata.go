@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/oauthex"
+	"github.com/wrkode/kube-mcp/pkg/config"
+)
+
+// protectedResourceMetadataPath is the RFC 9728 well-known path of the
+// protected resource metadata.
+const protectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// mcpPath is the path of the MCP endpoint.
+const mcpPath = "/mcp"
+
+// resourceURL returns the canonical URI of the MCP endpoint: resource_url if
+// configured, or else one derived from the request's scheme and host.
+func resourceURL(cfg *config.OAuth2Config, r *http.Request) string {
+	if cfg.ResourceURL != "" {
+		return cfg.ResourceURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	} else if proto := r.Header.Get("X-Forwarded-Proto"); proto == "https" {
+		scheme = proto
+	}
+	host := r.Host
+	if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host + mcpPath
+}
+
+// resourceMetadataURL returns where the metadata of a resource is published:
+// the well-known path inserted before the resource's path (RFC 9728 §3.1).
+func resourceMetadataURL(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return ""
+	}
+	u.Path = protectedResourceMetadataPath + strings.TrimSuffix(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+// protectedResourceMetadata builds the metadata MCP clients use to find the
+// authorization server and the scopes to request.
+func protectedResourceMetadata(cfg *config.OAuth2Config, r *http.Request) *oauthex.ProtectedResourceMetadata {
+	servers := cfg.AuthorizationServers
+	if len(servers) == 0 && cfg.IssuerURL != "" {
+		servers = []string{cfg.IssuerURL}
+	}
+
+	scopes := slices.Clone(cfg.Scopes)
+	for _, rule := range cfg.ToolScopes {
+		if rule.Scope != "" && !slices.Contains(scopes, rule.Scope) {
+			scopes = append(scopes, rule.Scope)
+		}
+	}
+
+	return &oauthex.ProtectedResourceMetadata{
+		Resource:               resourceURL(cfg, r),
+		AuthorizationServers:   servers,
+		ScopesSupported:        scopes,
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           "kube-mcp",
+	}
+}
+
+// resourceMetadataHandler serves the protected resource metadata.
+func (m *OAuthMiddleware) resourceMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(protectedResourceMetadata(m.config, r))
+}
+
+// challengeWriter adds a WWW-Authenticate challenge pointing to the protected
+// resource metadata to 401 responses.
+type challengeWriter struct {
+	http.ResponseWriter
+	challenge string
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *challengeWriter) WriteHeader(code int) {
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", w.challenge)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher, so event streams reach the client.
+func (w *challengeWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *challengeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// challenge returns the RFC 6750 challenge for a request without a valid
+// token, with the RFC 9728 resource_metadata parameter.
+func (m *OAuthMiddleware) challenge(r *http.Request) string {
+	params := []string{`realm="kube-mcp"`}
+	if r.Header.Get("Authorization") != "" {
+		params = append(params, `error="invalid_token"`)
+	}
+	if metadataURL := resourceMetadataURL(resourceURL(m.config, r)); metadataURL != "" {
+		params = append(params, `resource_metadata="`+metadataURL+`"`)
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
@@ -2,18 +2,24 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	"time"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/health"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpServer "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/ratelimit"
 )
 
 // Server provides HTTP transport for MCP.
@@ -22,17 +28,23 @@ type Server struct {
 	httpServer *http.Server
 	config     *config.HTTPConfig
 	oauth      *OAuthMiddleware
+	certAuth   *clientCertAuth
+	limiter    *ratelimit.Limiter
+	health     *health.Checker
+	sessions   *sessionTracker
 	logger     *observability.Logger
 	metrics    *observability.Metrics
 }
 
-// NewServer creates a new HTTP server for MCP.
-func NewServer(mcpServer *mcpServer.Server, cfg *config.HTTPConfig, logger *observability.Logger, metrics *observability.Metrics, clientSet *kubernetes.ClientSet, securityCfg *config.SecurityConfig) (*Server, error) {
+// NewServer creates a new HTTP server for MCP. limiter may be nil.
+func NewServer(mcpServer *mcpServer.Server, cfg *config.HTTPConfig, logger *observability.Logger, metrics *observability.Metrics, clientSet *kubernetes.ClientSet, securityCfg *config.SecurityConfig, limiter *ratelimit.Limiter) (*Server, error) {
 	s := &Server{
 		mcpServer: mcpServer,
 		config:    cfg,
+		limiter:   limiter,
 		logger:    logger,
 		metrics:   metrics,
+		sessions:  newSessionTracker(mcpServer.GetSDKServer(), metrics),
 	}
 
 	// Setup OAuth middleware if enabled
@@ -44,6 +56,22 @@ func NewServer(mcpServer *mcpServer.Server, cfg *config.HTTPConfig, logger *obse
 		}
 	}
 
+	// Setup TLS, with client certificate authentication if enabled
+	var tlsConfig *tls.Config
+	if cfg.TLS.Enabled {
+		var err error
+		tlsConfig, err = newTLSConfig(&cfg.TLS, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		if cfg.TLS.ClientAuth.Mode != "" && cfg.TLS.ClientAuth.Mode != "none" {
+			s.certAuth, err = newClientCertAuth(&cfg.TLS.ClientAuth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to configure client certificate authentication: %w", err)
+			}
+		}
+	}
+
 	// Setup router
 	router := mux.NewRouter()
 	s.setupRoutes(router)
@@ -57,9 +85,10 @@ func NewServer(mcpServer *mcpServer.Server, cfg *config.HTTPConfig, logger *obse
 	s.httpServer = &http.Server{
 		Addr:         cfg.Address,
 		Handler:      rootHandler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.ReadTimeout.Duration(),
+		WriteTimeout: cfg.WriteTimeout.Duration(),
+		IdleTimeout:  cfg.IdleTimeout.Duration(),
+		TLSConfig:    tlsConfig,
 	}
 
 	return s, nil
@@ -72,16 +101,19 @@ func (s *Server) setupRoutes(router *mux.Router) {
 		router.Use(s.corsMiddleware)
 	}
 
-	// OAuth middleware for protected routes
-	var mcpHandler http.Handler = s.mcpHandler()
-	if s.oauth != nil {
-		mcpHandler = s.oauth.Middleware(mcpHandler)
-	}
+	// MCP endpoint: POST for messages, GET for the stream of server messages
+	// and DELETE to close a session
+	keepAlive := s.config.SSEKeepAlive.Duration()
+	router.Handle(mcpPath, s.protect(streamMiddleware(keepAlive, s.sessions.onHeader, s.sessions.Middleware(s.mcpHandler())))).Methods("GET", "POST", "DELETE", "OPTIONS")
 
-	// MCP endpoint
-	router.Handle("/mcp", mcpHandler).Methods("POST", "OPTIONS")
+	// Legacy HTTP+SSE endpoint for older clients
+	if s.config.LegacySSE {
+		router.Handle(ssePath, s.protect(streamMiddleware(keepAlive, nil, newSSEHandler(s.mcpServer.GetSDKServer(), s.metrics)))).Methods("GET", "POST", "OPTIONS")
+	}
 
-	// Health check endpoint
+	// Health check endpoints
+	router.HandleFunc("/livez", s.livezHandler).Methods("GET")
+	router.HandleFunc("/readyz", s.readyzHandler).Methods("GET")
 	router.HandleFunc("/health", s.healthHandler).Methods("GET")
 
 	// Prometheus metrics endpoint
@@ -91,34 +123,108 @@ func (s *Server) setupRoutes(router *mux.Router) {
 
 	// Well-known endpoints
 	router.HandleFunc("/.well-known/mcp", s.wellKnownHandler).Methods("GET")
+
+	// OAuth 2.0 protected resource metadata (RFC 9728), at the root and at
+	// the path derived from the MCP endpoint's resource URI
+	if s.oauth != nil {
+		router.HandleFunc(protectedResourceMetadataPath, s.oauth.resourceMetadataHandler).Methods("GET")
+		router.HandleFunc(protectedResourceMetadataPath+mcpPath, s.oauth.resourceMetadataHandler).Methods("GET")
+		if u, err := url.Parse(s.oauth.config.ResourceURL); err == nil && u.Path != "" && u.Path != mcpPath {
+			router.HandleFunc(protectedResourceMetadataPath+strings.TrimSuffix(u.Path, "/"), s.oauth.resourceMetadataHandler).Methods("GET")
+		}
+	}
+}
+
+// protect wraps an MCP endpoint with authentication and rate limiting.
+func (s *Server) protect(handler http.Handler) http.Handler {
+	// Rate limiting per client, inside authentication so clients are identified by user
+	if s.limiter != nil {
+		handler = s.limiter.HTTPMiddleware(handler)
+	}
+
+	// OAuth middleware for protected routes
+	authenticated := handler
+	if s.oauth != nil {
+		authenticated = s.oauth.Middleware(handler)
+	}
+
+	// Requests with a verified client certificate and no bearer token are
+	// authenticated by the certificate instead
+	if s.certAuth != nil {
+		authenticated = s.certAuth.Middleware(handler, authenticated)
+	}
+	return authenticated
 }
 
 // mcpHandler creates the MCP HTTP handler.
 func (s *Server) mcpHandler() http.Handler {
+	opts := &mcp.StreamableHTTPOptions{Stateless: s.config.Stateless}
+	if timeout := s.config.SessionIdleTimeout.Duration(); timeout > 0 {
+		opts.SessionTimeout = timeout
+	}
 	return mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
 		return s.mcpServer.GetSDKServer()
-	}, &mcp.StreamableHTTPOptions{})
+	}, opts)
+}
+
+// SetHealthChecker sets the checker behind the readiness and health
+// endpoints. Without one, the server is always reported ready.
+func (s *Server) SetHealthChecker(checker *health.Checker) {
+	s.health = checker
+}
+
+// livezHandler reports that the process is serving requests.
+func (s *Server) livezHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
-// healthHandler handles health check requests.
+// readyzHandler reports whether the server is ready to serve MCP requests.
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if s.health != nil {
+		if ready, reason := s.health.Ready(); !ready {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready", "reason": reason})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// healthHandler reports the health of each Kubernetes context and toolset,
+// with 503 when the server is not ready.
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if s.health == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"status": health.StatusHealthy})
+		return
+	}
+	report := s.health.Report()
+	code := http.StatusOK
+	if !report.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+// writeJSON writes v as a JSON response with the status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"status": "healthy",
-	})
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
 }
 
 // wellKnownHandler handles .well-known/mcp requests.
 func (s *Server) wellKnownHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	// Get implementation info - we store it in our wrapper
-	json.NewEncoder(w).Encode(map[string]any{
-		"name":     "kube-mcp",
-		"version":  "1.0.0",
-		"endpoint": "/mcp",
-	})
+	impl := s.mcpServer.Implementation()
+	info := map[string]any{
+		"name":     impl.Name,
+		"version":  impl.Version,
+		"endpoint": mcpPath,
+	}
+	if s.oauth != nil {
+		info["protected_resource_metadata"] = resourceMetadataURL(resourceURL(s.oauth.config, r))
+	}
+	json.NewEncoder(w).Encode(info)
 }
 
 // corsMiddleware handles CORS.
@@ -132,6 +238,7 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Methods", joinStrings(s.config.CORS.AllowedMethods, ","))
 		w.Header().Set("Access-Control-Allow-Headers", joinStrings(s.config.CORS.AllowedHeaders, ","))
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Expose-Headers", sessionIDHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -173,14 +280,39 @@ func joinStrings(strs []string, sep string) string {
 	return result
 }
 
-// Start starts the HTTP server.
+// Start starts the HTTP server, with TLS if enabled.
 func (s *Server) Start() error {
-	return s.httpServer.ListenAndServe()
+	addr := s.httpServer.Addr
+	if addr == "" {
+		addr = ":http"
+		if s.httpServer.TLSConfig != nil {
+			addr = ":https"
+		}
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves HTTP, or HTTPS if TLS is enabled, on the listener.
+func (s *Server) Serve(listener net.Listener) error {
+	if s.httpServer.TLSConfig != nil {
+		return s.httpServer.ServeTLS(listener, "", "")
+	}
+	return s.httpServer.Serve(listener)
 }
 
-// Stop stops the HTTP server.
+// Stop stops accepting connections and waits for in-flight requests to
+// finish until ctx is done, then closes the connections still open, such as
+// long-lived MCP streams.
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+	if ctx.Err() != nil {
+		return errors.Join(err, s.httpServer.Close())
+	}
+	return err
 }
 
 // ServeHTTP implements http.Handler for compatibility.
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/health"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpServer "github.com/wrkode/kube-mcp/pkg/mcp"
+	"github.com/wrkode/kube-mcp/pkg/observability"
+)
+
+// noContextsProvider is a provider without Kubernetes contexts.
+type noContextsProvider struct{}
+
+func (noContextsProvider) GetClientSet(string) (*kubernetes.ClientSet, error) { return nil, nil }
+func (noContextsProvider) ListContexts() ([]string, error)                    { return nil, nil }
+func (noContextsProvider) GetCurrentContext() (string, error)                 { return "", nil }
+
+// userTransport authenticates requests as a user, in tests.
+type userTransport struct {
+	user string
+}
+
+func (t userTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("X-Test-User", t.user)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// ServerTestSuite tests the health endpoints, graceful shutdown, streaming
+// and sessions.
+type ServerTestSuite struct {
+	suite.Suite
+	server *Server
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	var err error
+	s.server, err = NewServer(mcpServer.NewServer("test", "0.0.0", false), &config.HTTPConfig{}, nil, nil, nil, &config.SecurityConfig{}, nil)
+	s.Require().NoError(err)
+}
+
+// get requests path and decodes the JSON response.
+func (s *ServerTestSuite) get(baseURL, path string) (int, map[string]any) {
+	resp, err := http.Get(baseURL + path)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]any
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+// serve serves the server on a local port and returns its URL.
+func (s *ServerTestSuite) serve() string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	go func() { _ = s.server.Serve(listener) }()
+	server := s.server
+	s.T().Cleanup(func() {
+		// Connections the client dialed but never used hold up shutdown
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_ = server.Stop(ctx)
+	})
+	return "http://" + listener.Addr().String()
+}
+
+// TestHealthEndpoints tests liveness, readiness and the health report.
+func (s *ServerTestSuite) TestHealthEndpoints() {
+	checker := health.NewChecker(noContextsProvider{}, &config.HealthConfig{ProbeTimeout: config.Duration(time.Second)})
+	s.server.SetHealthChecker(checker)
+	url := s.serve()
+
+	code, body := s.get(url, "/livez")
+	s.Equal(http.StatusOK, code)
+	s.Equal("ok", body["status"])
+
+	code, body = s.get(url, "/readyz")
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.Equal("not ready", body["status"])
+
+	checker.SetRegistered()
+	checker.Probe(context.Background())
+	code, body = s.get(url, "/health")
+	s.Equal(http.StatusServiceUnavailable, code)
+	s.Equal(health.StatusUnhealthy, body["status"])
+	s.Contains(body["contexts"].([]any)[0].(map[string]any)["error"], "no Kubernetes contexts")
+}
+
+// TestStopWaitsForRequests tests that Stop lets in-flight requests finish,
+// and closes them once the grace period is over.
+func (s *ServerTestSuite) TestStopWaitsForRequests() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.server.httpServer.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+	url := s.serve()
+
+	result := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			result <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		result <- string(body)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.server.Stop(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	s.NoError(<-stopped)
+	s.Equal("done", <-result)
+
+	// A request still running after the grace period is cut off
+	s.SetupTest()
+	s.server.httpServer.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	url = s.serve()
+	go func() {
+		_, err := http.Get(url)
+		result <- err.Error()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Error(s.server.Stop(ctx))
+	s.NotEmpty(<-result)
+}
+
+// newServer replaces the server with one using cfg and metrics, whose
+// callers are authenticated by the X-Test-User header.
+func (s *ServerTestSuite) newServer(cfg *config.HTTPConfig, metrics *observability.Metrics) {
+	var err error
+	s.server, err = NewServer(mcpServer.NewServer("test", "0.0.0", false), cfg, observability.NewLogger(observability.LogLevelError, true), metrics, nil, &config.SecurityConfig{}, nil)
+	s.Require().NoError(err)
+	handler := s.server.httpServer.Handler
+	s.server.httpServer.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get("X-Test-User"); user != "" {
+			r = r.WithContext(auth.WithIdentity(r.Context(), &auth.Identity{Username: user}))
+		}
+		handler.ServeHTTP(w, r)
+	})
+	mcp.AddTool(s.server.mcpServer.GetSDKServer(), &mcp.Tool{Name: "whoami"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		name := "anonymous"
+		if identity := auth.IdentityFromContext(ctx); identity != nil {
+			name = identity.Username
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: name}}}, nil, nil
+	})
+}
+
+// connect opens an MCP session as user over transport, "streamable" or "sse".
+func (s *ServerTestSuite) connect(url, transport, user string) *mcp.ClientSession {
+	httpClient := &http.Client{Transport: userTransport{user: user}}
+	var t mcp.Transport = &mcp.StreamableClientTransport{Endpoint: url + mcpPath, HTTPClient: httpClient}
+	if transport == "sse" {
+		t = &mcp.SSEClientTransport{Endpoint: url + ssePath, HTTPClient: httpClient}
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, nil).Connect(context.Background(), t, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+// post posts an MCP ping to url as user and returns the status code.
+func (s *ServerTestSuite) post(url, user string, header http.Header) int {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	s.Require().NoError(err)
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := (&http.Client{Transport: userTransport{user: user}}).Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestEventStreamOutlivesWriteTimeout tests that event streams are exempt
+// from the write timeout and carry keep-alive comments.
+func (s *ServerTestSuite) TestEventStreamOutlivesWriteTimeout() {
+	s.server.httpServer.WriteTimeout = 100 * time.Millisecond
+	s.server.httpServer.Handler = streamMiddleware(50*time.Millisecond, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		_, _ = io.WriteString(w, "data: last\n\n")
+	}))
+	url := s.serve()
+
+	resp, err := http.Get(url)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(body), "data: first\n\n"))
+	s.Contains(string(body), keepAliveComment)
+	s.Contains(string(body), "data: last\n\n")
+}
+
+// TestSessions tests that Streamable HTTP sessions are bound to the caller
+// that opened them, expire when idle and are counted in metrics.
+func (s *ServerTestSuite) TestSessions() {
+	registry := prometheus.NewRegistry()
+	metrics := observability.NewMetrics(registry)
+	s.newServer(&config.HTTPConfig{SessionIdleTimeout: config.Duration(300 * time.Millisecond)}, metrics)
+	metrics.RegisterActiveSessions(s.server.mcpServer.SessionCount)
+	url := s.serve()
+
+	session := s.connect(url, "streamable", "alice")
+	s.Require().NotEmpty(session.ID())
+	s.Equal(1, s.server.mcpServer.SessionCount())
+
+	header := http.Header{sessionIDHeader: {session.ID()}}
+	s.Equal(http.StatusOK, s.post(url+mcpPath, "alice", header))
+	s.Equal(http.StatusNotFound, s.post(url+mcpPath, "bob", header))
+	s.Equal(http.StatusNotFound, s.post(url+mcpPath, "", header))
+
+	s.NoError(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP kube_mcp_sessions_active Number of open MCP sessions
+# TYPE kube_mcp_sessions_active gauge
+kube_mcp_sessions_active 1
+# HELP kube_mcp_sessions_total Total number of MCP sessions opened over HTTP
+# TYPE kube_mcp_sessions_total counter
+kube_mcp_sessions_total{transport="streamable"} 1
+`), "kube_mcp_sessions_active", "kube_mcp_sessions_total"))
+
+	// Idle sessions are closed
+	s.Eventually(func() bool {
+		return s.server.mcpServer.SessionCount() == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	s.Equal(http.StatusNotFound, s.post(url+mcpPath, "alice", header))
+}
+
+// TestLegacySSE tests that legacy SSE sessions run as the caller that opened
+// them and accept messages only from that caller.
+func (s *ServerTestSuite) TestLegacySSE() {
+	s.newServer(&config.HTTPConfig{LegacySSE: true}, nil)
+	url := s.serve()
+
+	session := s.connect(url, "sse", "alice")
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+	s.Require().NoError(err)
+	s.Equal("alice", result.Content[0].(*mcp.TextContent).Text)
+
+	// The endpoint of a session is announced on its stream
+	req, err := http.NewRequest(http.MethodGet, url+ssePath, nil)
+	s.Require().NoError(err)
+	resp, err := (&http.Client{Transport: userTransport{user: "alice"}}).Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	var endpoint string
+	for endpoint == "" && scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			endpoint = data
+		}
+	}
+	s.Require().Contains(endpoint, "sessionid=")
+
+	s.Equal(http.StatusNotFound, s.post(url+endpoint, "bob", http.Header{}))
+	s.Equal(http.StatusAccepted, s.post(url+endpoint, "alice", http.Header{}))
+}
+
+// TestServerTestSuite runs the server test suite.
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"sync"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/observability"
+)
+
+// sessionIDHeader carries the Streamable HTTP session ID.
+const sessionIDHeader = "Mcp-Session-Id"
+
+// ssePath is the path of the legacy HTTP+SSE endpoint.
+const ssePath = "/sse"
+
+// callerName returns the username of the authenticated caller of r, or ""
+// if it is anonymous.
+func callerName(r *http.Request) string {
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil {
+		return identity.Username
+	}
+	return ""
+}
+
+// sessionTracker binds each Streamable HTTP session to the caller that
+// opened it, so a session ID is useless to other callers, and counts the
+// sessions opened.
+type sessionTracker struct {
+	server  *mcp.Server
+	metrics *observability.Metrics
+
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// newSessionTracker creates a tracker for sessions of server. metrics may be
+// nil.
+func newSessionTracker(server *mcp.Server, metrics *observability.Metrics) *sessionTracker {
+	return &sessionTracker{
+		server:  server,
+		metrics: metrics,
+		owners:  make(map[string]string),
+	}
+}
+
+// Middleware rejects requests for a session opened by another caller as if
+// the session did not exist.
+func (t *sessionTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get(sessionIDHeader); id != "" {
+			t.mu.Lock()
+			owner, ok := t.owners[id]
+			t.mu.Unlock()
+			if ok && owner != callerName(r) {
+				http.Error(w, "session not found", http.StatusNotFound)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// onHeader records the session a response to an initialize request assigns.
+func (t *sessionTracker) onHeader(r *http.Request, header http.Header) {
+	id := header.Get(sessionIDHeader)
+	if id == "" || r.Header.Get(sessionIDHeader) != "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune()
+	t.owners[id] = callerName(r)
+	if t.metrics != nil {
+		t.metrics.RecordSession("streamable")
+	}
+}
+
+// prune forgets sessions that were closed, by the client or for being idle.
+func (t *sessionTracker) prune() {
+	open := make(map[string]bool, len(t.owners))
+	for session := range t.server.Sessions() {
+		open[session.ID()] = true
+	}
+	for id := range t.owners {
+		if !open[id] {
+			delete(t.owners, id)
+		}
+	}
+}
+
+// sseHandler serves the legacy HTTP+SSE transport: a GET opens a session and
+// streams its messages, and the client POSTs its messages to the endpoint
+// announced on the stream. Unlike the SDK's SSEHandler, it runs the session
+// as the caller that opened it, until the caller's token expires, and
+// accepts messages only from that caller.
+type sseHandler struct {
+	server  *mcp.Server
+	metrics *observability.Metrics
+
+	mu       sync.Mutex
+	sessions map[string]*sseSession
+}
+
+// sseSession is an open legacy SSE session.
+type sseSession struct {
+	transport *mcp.SSEServerTransport
+	owner     string
+}
+
+// newSSEHandler creates a legacy SSE handler for server. metrics may be nil.
+func newSSEHandler(server *mcp.Server, metrics *observability.Metrics) *sseHandler {
+	return &sseHandler{
+		server:   server,
+		metrics:  metrics,
+		sessions: make(map[string]*sseSession),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.mu.Lock()
+		session := h.sessions[r.URL.Query().Get("sessionid")]
+		h.mu.Unlock()
+		if session == nil || session.owner != callerName(r) {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		session.transport.ServeHTTP(w, r)
+	case http.MethodGet:
+		h.serveStream(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveStream opens a session and streams its messages until the client
+// disconnects, the session is closed or the caller's token expires.
+func (h *sseHandler) serveStream(w http.ResponseWriter, r *http.Request) {
+	id := rand.Text()
+	endpoint, err := r.URL.Parse("?sessionid=" + id)
+	if err != nil {
+		http.Error(w, "failed to create session endpoint", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	transport := &mcp.SSEServerTransport{Endpoint: endpoint.RequestURI(), Response: w}
+	h.mu.Lock()
+	h.sessions[id] = &sseSession{transport: transport, owner: callerName(r)}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, id)
+		h.mu.Unlock()
+	}()
+
+	// Requests of the session are handled in contexts derived from this one,
+	// which carries the caller's identity
+	ctx := r.Context()
+	if info := sdkauth.TokenInfoFromContext(ctx); info != nil && !info.Expiration.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, info.Expiration)
+		defer cancel()
+	}
+
+	session, err := h.server.Connect(ctx, transport, nil)
+	if err != nil {
+		http.Error(w, "failed to connect session", http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+	if h.metrics != nil {
+		h.metrics.RecordSession("sse")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		_ = session.Wait()
+		close(closed)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-closed:
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keepAliveComment is an SSE comment, ignored by clients.
+const keepAliveComment = ": keep-alive\n\n"
+
+// streamMiddleware lets event stream responses outlive the server's write
+// timeout and writes keep-alive comments on them every interval, so idle
+// streams are not closed by proxies. A non-positive interval disables
+// keep-alive comments. onHeader, if set, is called with the response headers
+// before they are written.
+func streamMiddleware(interval time.Duration, onHeader func(r *http.Request, header http.Header), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &streamWriter{ResponseWriter: w, interval: interval}
+		if onHeader != nil {
+			sw.onHeader = func(header http.Header) { onHeader(r, header) }
+		}
+		defer sw.stop()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// streamWriter serializes writes to a response so keep-alive comments never
+// split an event.
+type streamWriter struct {
+	http.ResponseWriter
+	interval time.Duration
+	onHeader func(http.Header)
+
+	mu          sync.Mutex
+	wroteHeader bool
+	done        chan struct{}
+	stopped     chan struct{}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *streamWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(code)
+}
+
+func (w *streamWriter) writeHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.onHeader != nil {
+			w.onHeader(w.Header())
+		}
+		if code == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+			_ = http.NewResponseController(w.ResponseWriter).SetWriteDeadline(time.Time{})
+			if w.interval > 0 {
+				w.done = make(chan struct{})
+				w.stopped = make(chan struct{})
+				go w.keepAlive()
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *streamWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		w.writeHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher.
+func (w *streamWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		w.writeHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *streamWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// keepAlive writes a keep-alive comment every interval until the response
+// ends or a write fails.
+func (w *streamWriter) keepAlive() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			_, err := io.WriteString(w.ResponseWriter, keepAliveComment)
+			if err == nil {
+				err = http.NewResponseController(w.ResponseWriter).Flush()
+			}
+			w.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// stop stops keep-alive comments before the handler returns.
+func (w *streamWriter) stop() {
+	w.mu.Lock()
+	done := w.done
+	w.mu.Unlock()
+	if done != nil {
+		close(done)
+		<-w.stopped
+	}
+}
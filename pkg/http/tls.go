@@ -0,0 +1,276 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/observability"
+)
+
+// tlsReloadInterval is how often the certificate files are checked for changes.
+const tlsReloadInterval = 10 * time.Second
+
+// newTLSConfig builds the TLS configuration of the HTTP server. The
+// certificate, key and client CA bundle are served by a reloader, so renewed
+// files are picked up without a restart.
+func newTLSConfig(cfg *config.HTTPTLSConfig, logger *observability.Logger) (*tls.Config, error) {
+	var minVersion uint16
+	switch cfg.MinVersion {
+	case "", "1.2":
+		minVersion = tls.VersionTLS12
+	case "1.3":
+		minVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("invalid server.http.tls.min_version %q: must be \"1.2\" or \"1.3\"", cfg.MinVersion)
+	}
+
+	var clientAuth tls.ClientAuthType
+	switch cfg.ClientAuth.Mode {
+	case "", "none":
+		clientAuth = tls.NoClientCert
+	case "optional":
+		clientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid server.http.tls.client_auth.mode %q: must be \"none\", \"optional\" or \"require\"", cfg.ClientAuth.Mode)
+	}
+
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("server.http.tls.cert_file and server.http.tls.key_file are required when TLS is enabled")
+	}
+	var caFile string
+	if clientAuth != tls.NoClientCert {
+		if cfg.ClientAuth.CAFile == "" {
+			return nil, fmt.Errorf("server.http.tls.client_auth.ca_file is required when client_auth.mode is %s", cfg.ClientAuth.Mode)
+		}
+		caFile = cfg.ClientAuth.CAFile
+	}
+
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile, caFile, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := reloader.current()
+			return &tls.Config{
+				MinVersion:   minVersion,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+	return tlsConfig, nil
+}
+
+// certReloader serves the server certificate and client CA bundle from disk,
+// reloading them when the files change, e.g. when cert-manager renews a
+// mounted Secret.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	logger   *observability.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// newCertReloader loads the files. caFile may be empty.
+func newCertReloader(certFile, keyFile, caFile string, logger *observability.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		logger:   logger,
+		now:      time.Now,
+		modTimes: make(map[string]time.Time),
+	}
+	r.lastCheck = r.now()
+	r.changed()
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads the certificate, key and CA bundle.
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.caFile != "" {
+		data, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in client CA bundle %s", r.caFile)
+		}
+	}
+
+	r.cert, r.clientCAs = &cert, clientCAs
+	return nil
+}
+
+// current returns the certificate and client CA pool, reloading them first if
+// a file changed since the last check. If the new files cannot be loaded, the
+// previous ones are kept and loading is retried at the next check.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastCheck) >= tlsReloadInterval {
+		r.lastCheck = now
+		if r.changed() {
+			if err := r.load(); err != nil {
+				clear(r.modTimes)
+				if r.logger != nil {
+					r.logger.Warn(context.Background(), "Failed to reload TLS certificates, keeping the previous ones", "error", err)
+				}
+			} else if r.logger != nil {
+				r.logger.Info(context.Background(), "Reloaded TLS certificates", "cert_file", r.certFile)
+			}
+		}
+	}
+	return r.cert, r.clientCAs
+}
+
+// changed reports whether a file's modification time changed since it was
+// last recorded, and records the new times.
+func (r *certReloader) changed() bool {
+	changed := false
+	for _, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[path]) {
+			r.modTimes[path] = info.ModTime()
+			changed = true
+		}
+	}
+	return changed
+}
+
+// clientCertAuth authenticates requests by their verified client certificate.
+type clientCertAuth struct {
+	usernameFrom string
+	groupsFrom   string
+}
+
+// newClientCertAuth creates client certificate authentication from the
+// client_auth settings.
+func newClientCertAuth(cfg *config.ClientAuthConfig) (*clientCertAuth, error) {
+	switch cfg.UsernameFrom {
+	case "", "cn", "email", "dns", "uri":
+	default:
+		return nil, fmt.Errorf("invalid server.http.tls.client_auth.username_from %q: must be \"cn\", \"email\", \"dns\" or \"uri\"", cfg.UsernameFrom)
+	}
+	switch cfg.GroupsFrom {
+	case "", "o", "ou", "none":
+	default:
+		return nil, fmt.Errorf("invalid server.http.tls.client_auth.groups_from %q: must be \"o\", \"ou\" or \"none\"", cfg.GroupsFrom)
+	}
+	return &clientCertAuth{usernameFrom: cfg.UsernameFrom, groupsFrom: cfg.GroupsFrom}, nil
+}
+
+// Middleware authenticates requests that present a verified client
+// certificate and no bearer token as the certificate's identity, and passes
+// them to next. Other requests are passed to fallback, which may require a
+// bearer token.
+func (c *clientCertAuth) Middleware(next, fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || r.Header.Get("Authorization") != "" {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		cert := r.TLS.VerifiedChains[0][0]
+		identity, err := c.identity(cert)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r = withTokenInfo(w, r, auth.UnscopedTokenInfo(auth.NewTokenInfo(identity, "", nil, cert.NotAfter)))
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+}
+
+// identity maps a verified client certificate to an identity: by default the
+// subject's common name is the username and its organizations are the
+// groups, as in Kubernetes client certificate authentication.
+func (c *clientCertAuth) identity(cert *x509.Certificate) (*auth.Identity, error) {
+	var username string
+	switch c.usernameFrom {
+	case "", "cn":
+		username = cert.Subject.CommonName
+	case "email":
+		if len(cert.EmailAddresses) > 0 {
+			username = cert.EmailAddresses[0]
+		}
+	case "dns":
+		if len(cert.DNSNames) > 0 {
+			username = cert.DNSNames[0]
+		}
+	case "uri":
+		if len(cert.URIs) > 0 {
+			username = cert.URIs[0].String()
+		}
+	}
+	if username == "" {
+		return nil, fmt.Errorf("client certificate has no %s to use as username", c.usernameFrom)
+	}
+
+	var groups []string
+	switch c.groupsFrom {
+	case "", "o":
+		groups = cert.Subject.Organization
+	case "ou":
+		groups = cert.Subject.OrganizationalUnit
+	}
+	return &auth.Identity{Username: username, Groups: groups}, nil
+}
+
+// withTokenInfo returns r with info stored where the SDK looks for the caller
+// of MCP requests. The SDK only stores token info through RequireBearerToken,
+// so a copy of the request passes through it with a placeholder token; r's
+// own headers are unchanged.
+func withTokenInfo(w http.ResponseWriter, r *http.Request, info *sdkauth.TokenInfo) *http.Request {
+	out := r
+	probe := r.Clone(r.Context())
+	probe.Header.Set("Authorization", "Bearer client-certificate")
+	verify := func(context.Context, string, *http.Request) (*sdkauth.TokenInfo, error) {
+		return info, nil
+	}
+	sdkauth.RequireBearerToken(verify, nil)(http.HandlerFunc(func(_ http.ResponseWriter, authenticated *http.Request) {
+		out = r.WithContext(authenticated.Context())
+	})).ServeHTTP(w, probe)
+	return out
+}
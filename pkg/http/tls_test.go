@@ -0,0 +1,366 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpServer "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// testCA issues certificates for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for the template, signed by the CA.
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// serverCert returns a template for a localhost server certificate.
+func serverCert(serial int64) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+}
+
+// clientCert returns a template for a client certificate.
+func clientCert(subject pkix.Name) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(100),
+		Subject:      subject,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+}
+
+// whoamiToolset has a tool that returns the caller's identity.
+type whoamiToolset struct{}
+
+func (w *whoamiToolset) Name() string { return "whoami" }
+
+func (w *whoamiToolset) Tools() []*mcp.Tool {
+	return []*mcp.Tool{mcpServer.NewTool("whoami", "Return the caller").WithReadOnly().Build()}
+}
+
+func (w *whoamiToolset) RegisterTools(server *mcp.Server) error {
+	mcpServer.AddTool(server, w.Tools()[0], func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		result, err := mcpServer.NewJSONResult(auth.IdentityFromContext(ctx))
+		return result, nil, err
+	})
+	return nil
+}
+
+// TLSTestSuite tests HTTPS serving, certificate reloading and client
+// certificate authentication.
+type TLSTestSuite struct {
+	suite.Suite
+	dir string
+	ca  *testCA
+	cfg *config.HTTPConfig
+}
+
+// SetupTest writes a CA bundle and a server certificate.
+func (s *TLSTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.ca = newTestCA(s.T())
+	s.writeFile("ca.crt", s.ca.pem)
+	s.writeServerCert(1)
+	s.cfg = &config.HTTPConfig{
+		TLS: config.HTTPTLSConfig{
+			Enabled:    true,
+			CertFile:   filepath.Join(s.dir, "tls.crt"),
+			KeyFile:    filepath.Join(s.dir, "tls.key"),
+			MinVersion: "1.2",
+			ClientAuth: config.ClientAuthConfig{
+				Mode:         "none",
+				CAFile:       filepath.Join(s.dir, "ca.crt"),
+				UsernameFrom: "cn",
+				GroupsFrom:   "o",
+			},
+		},
+	}
+}
+
+func (s *TLSTestSuite) writeFile(name string, data []byte) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, name), data, 0o600))
+}
+
+func (s *TLSTestSuite) writeServerCert(serial int64) {
+	cert, key := s.ca.issue(s.T(), serverCert(serial))
+	s.writeFile("tls.crt", cert)
+	s.writeFile("tls.key", key)
+}
+
+// serve starts the server and returns its base URL.
+func (s *TLSTestSuite) serve() string {
+	server := mcpServer.NewServer("test", "0.0.0", false)
+	s.Require().NoError(server.RegisterToolset(&whoamiToolset{}))
+
+	httpServer, err := NewServer(server, s.cfg, nil, nil, nil, &config.SecurityConfig{}, nil)
+	s.Require().NoError(err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	go func() { _ = httpServer.Serve(listener) }()
+	s.T().Cleanup(func() { _ = httpServer.Stop(context.Background()) })
+	return "https://" + listener.Addr().String()
+}
+
+// client returns an HTTP client trusting the CA, presenting the client
+// certificate if one is given.
+func (s *TLSTestSuite) client(certPEM, keyPEM []byte) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(s.ca.pem)
+	tlsConfig := &tls.Config{RootCAs: pool}
+	if certPEM != nil {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		s.Require().NoError(err)
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+// whoami calls the whoami tool and returns the identity it saw.
+func (s *TLSTestSuite) whoami(baseURL string, client *http.Client) (*auth.Identity, error) {
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, nil)
+	session, err := mcpClient.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   baseURL + "/mcp",
+		HTTPClient: client,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+	if err != nil {
+		return nil, err
+	}
+	var identity *auth.Identity
+	s.Require().NoError(json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &identity))
+	return identity, nil
+}
+
+// TestServeTLS tests that the server serves HTTPS and that anonymous callers
+// have no identity without client authentication.
+func (s *TLSTestSuite) TestServeTLS() {
+	baseURL := s.serve()
+
+	resp, err := s.client(nil, nil).Get(baseURL + "/health")
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(uint16(tls.VersionTLS13), resp.TLS.Version)
+
+	resp, err = http.Get("http://" + resp.Request.URL.Host + "/health")
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode, "Plain HTTP should be rejected")
+
+	identity, err := s.whoami(baseURL, s.client(nil, nil))
+	s.Require().NoError(err)
+	s.Nil(identity)
+}
+
+// TestReload tests that renewed certificate files are served without a
+// restart, and that invalid files keep the previous certificate.
+func (s *TLSTestSuite) TestReload() {
+	reloader, err := newCertReloader(s.cfg.TLS.CertFile, s.cfg.TLS.KeyFile, "", nil)
+	s.Require().NoError(err)
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+	reloader.lastCheck = now
+
+	serial := func() int64 {
+		cert, _ := reloader.current()
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		s.Require().NoError(err)
+		return parsed.SerialNumber.Int64()
+	}
+	touch := func(offset time.Duration) {
+		for _, name := range []string{"tls.crt", "tls.key"} {
+			s.Require().NoError(os.Chtimes(filepath.Join(s.dir, name), now.Add(offset), now.Add(offset)))
+		}
+	}
+	s.Equal(int64(1), serial())
+
+	s.writeServerCert(2)
+	touch(time.Minute)
+	s.Equal(int64(1), serial(), "Files should not be checked before the interval")
+	now = now.Add(tlsReloadInterval)
+	s.Equal(int64(2), serial())
+
+	s.writeFile("tls.key", []byte("garbage"))
+	touch(2 * time.Minute)
+	now = now.Add(tlsReloadInterval)
+	s.Equal(int64(2), serial(), "An invalid key should keep the previous certificate")
+
+	s.writeServerCert(3)
+	now = now.Add(tlsReloadInterval)
+	s.Equal(int64(3), serial(), "Loading should be retried after a failure")
+}
+
+// TestRequireClientCert tests that callers are authenticated as their client
+// certificate's subject, and that callers without one are rejected.
+func (s *TLSTestSuite) TestRequireClientCert() {
+	s.cfg.TLS.ClientAuth.Mode = "require"
+	baseURL := s.serve()
+
+	certPEM, keyPEM := s.ca.issue(s.T(), clientCert(pkix.Name{CommonName: "alice", Organization: []string{"platform", "oncall"}}))
+	identity, err := s.whoami(baseURL, s.client(certPEM, keyPEM))
+	s.Require().NoError(err)
+	s.Require().NotNil(identity)
+	s.Equal("alice", identity.Username)
+	s.ElementsMatch([]string{"platform", "oncall"}, identity.Groups)
+
+	_, err = s.client(nil, nil).Get(baseURL + "/health")
+	s.Error(err, "Callers without a certificate should be rejected")
+
+	other := newTestCA(s.T())
+	certPEM, keyPEM = other.issue(s.T(), clientCert(pkix.Name{CommonName: "mallory"}))
+	_, err = s.client(certPEM, keyPEM).Get(baseURL + "/health")
+	s.Error(err, "Certificates from another CA should be rejected")
+}
+
+// TestOptionalClientCert tests that callers without a certificate are let
+// through anonymously when client certificates are optional.
+func (s *TLSTestSuite) TestOptionalClientCert() {
+	s.cfg.TLS.ClientAuth.Mode = "optional"
+	baseURL := s.serve()
+
+	identity, err := s.whoami(baseURL, s.client(nil, nil))
+	s.Require().NoError(err)
+	s.Nil(identity)
+
+	certPEM, keyPEM := s.ca.issue(s.T(), clientCert(pkix.Name{CommonName: "bob"}))
+	identity, err = s.whoami(baseURL, s.client(certPEM, keyPEM))
+	s.Require().NoError(err)
+	s.Require().NotNil(identity)
+	s.Equal("bob", identity.Username)
+}
+
+// TestIdentityMapping tests mapping certificate fields to usernames and groups.
+func (s *TLSTestSuite) TestIdentityMapping() {
+	spiffe, _ := url.Parse("spiffe://cluster.local/ns/agents/sa/planner")
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "alice",
+			Organization:       []string{"platform"},
+			OrganizationalUnit: []string{"sre"},
+		},
+		EmailAddresses: []string{"alice@example.com"},
+		DNSNames:       []string{"agent.example.com"},
+		URIs:           []*url.URL{spiffe},
+	}
+
+	for _, tc := range []struct {
+		usernameFrom, groupsFrom string
+		username                 string
+		groups                   []string
+	}{
+		{"cn", "o", "alice", []string{"platform"}},
+		{"email", "ou", "alice@example.com", []string{"sre"}},
+		{"dns", "none", "agent.example.com", nil},
+		{"uri", "o", "spiffe://cluster.local/ns/agents/sa/planner", []string{"platform"}},
+	} {
+		certAuth, err := newClientCertAuth(&config.ClientAuthConfig{UsernameFrom: tc.usernameFrom, GroupsFrom: tc.groupsFrom})
+		s.Require().NoError(err)
+		identity, err := certAuth.identity(cert)
+		s.Require().NoError(err)
+		s.Equal(tc.username, identity.Username)
+		s.Equal(tc.groups, identity.Groups)
+	}
+
+	certAuth, err := newClientCertAuth(&config.ClientAuthConfig{UsernameFrom: "email"})
+	s.Require().NoError(err)
+	_, err = certAuth.identity(&x509.Certificate{Subject: pkix.Name{CommonName: "alice"}})
+	s.Error(err, "A certificate without the username field should be rejected")
+
+	_, err = newClientCertAuth(&config.ClientAuthConfig{UsernameFrom: "serial"})
+	s.Error(err)
+}
+
+// TestInvalidConfig tests that invalid TLS settings are rejected at startup.
+func (s *TLSTestSuite) TestInvalidConfig() {
+	for _, mutate := range []func(*config.HTTPTLSConfig){
+		func(c *config.HTTPTLSConfig) { c.MinVersion = "1.1" },
+		func(c *config.HTTPTLSConfig) { c.CertFile = "" },
+		func(c *config.HTTPTLSConfig) { c.KeyFile = filepath.Join(s.dir, "missing.key") },
+		func(c *config.HTTPTLSConfig) { c.ClientAuth.Mode = "sometimes" },
+		func(c *config.HTTPTLSConfig) { c.ClientAuth.Mode = "require"; c.ClientAuth.CAFile = "" },
+	} {
+		cfg := s.cfg.TLS
+		mutate(&cfg)
+		_, err := newTLSConfig(&cfg, nil)
+		s.Error(err)
+	}
+}
+
+// TestTLSTestSuite runs the TLS test suite.
+func TestTLSTestSuite(t *testing.T) {
+	suite.Run(t, new(TLSTestSuite))
+}
@@ -3,6 +3,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,12 +13,16 @@ import (
 // TokenReviewer validates Bearer tokens using Kubernetes TokenReview API.
 type TokenReviewer struct {
 	clientSet *ClientSet
+	audiences []string
 }
 
-// NewTokenReviewer creates a new TokenReviewer.
-func NewTokenReviewer(clientSet *ClientSet) *TokenReviewer {
+// NewTokenReviewer creates a new TokenReviewer. If audiences are given,
+// tokens must be issued for one of them, e.g. projected ServiceAccount tokens
+// requested for kube-mcp; otherwise the API server's audiences apply.
+func NewTokenReviewer(clientSet *ClientSet, audiences ...string) *TokenReviewer {
 	return &TokenReviewer{
 		clientSet: clientSet,
+		audiences: audiences,
 	}
 }
 
@@ -26,7 +31,8 @@ func NewTokenReviewer(clientSet *ClientSet) *TokenReviewer {
 func (t *TokenReviewer) ValidateToken(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
 	tr := &authenticationv1.TokenReview{
 		Spec: authenticationv1.TokenReviewSpec{
-			Token: token,
+			Token:     token,
+			Audiences: t.audiences,
 		},
 	}
 
@@ -39,6 +45,14 @@ func (t *TokenReviewer) ValidateToken(ctx context.Context, token string) (*authe
 		return nil, fmt.Errorf("token is not authenticated: %s", result.Status.Error)
 	}
 
+	// The API server reports the requested audiences the token is valid for;
+	// an authenticator that ignores audiences reports none
+	if len(t.audiences) > 0 && !slices.ContainsFunc(result.Status.Audiences, func(aud string) bool {
+		return slices.Contains(t.audiences, aud)
+	}) {
+		return nil, fmt.Errorf("token is not valid for audiences %v", t.audiences)
+	}
+
 	return &result.Status.User, nil
 }
 
@@ -47,26 +61,37 @@ type CredentialSelector struct {
 	// BearerToken is the Bearer token to use if provided.
 	BearerToken string
 
+	// Impersonate, if set, makes requests on behalf of another user using the
+	// original credentials.
+	Impersonate *rest.ImpersonationConfig
+
 	// UseServiceAccount indicates whether to use service account credentials.
 	UseServiceAccount bool
 }
 
 // SelectCredentials selects the appropriate REST config based on credentials.
-// If BearerToken is provided, it takes precedence.
-// Otherwise, uses the original config (which may have service account credentials).
+// If BearerToken is provided, it takes precedence and all other credentials
+// (client certificates, exec plugins, basic auth) are dropped so the API server
+// authenticates the token's subject. Otherwise, if Impersonate is set, the
+// original credentials are kept and impersonation headers are added.
+// The returned config never carries the original's transport wrappers; the
+// ClientFactory applies its own when building clients from it.
 func SelectCredentials(originalConfig *rest.Config, selector *CredentialSelector) *rest.Config {
-	if selector == nil {
+	if selector == nil || selector.UseServiceAccount {
 		return originalConfig
 	}
 
-	// Create a copy of the config
-	config := *originalConfig
-
-	// If Bearer token is provided, use it
-	if selector.BearerToken != "" {
+	switch {
+	case selector.BearerToken != "":
+		config := rest.AnonymousClientConfig(originalConfig)
 		config.BearerToken = selector.BearerToken
-		config.BearerTokenFile = "" // Clear BearerTokenFile if token is provided
+		return config
+	case selector.Impersonate != nil:
+		config := rest.CopyConfig(originalConfig)
+		config.WrapTransport = nil
+		config.Impersonate = *selector.Impersonate
+		return config
+	default:
+		return originalConfig
 	}
-
-	return &config
 }
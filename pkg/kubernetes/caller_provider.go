@@ -0,0 +1,263 @@
+package kubernetes
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"k8s.io/client-go/rest"
+)
+
+// CredentialMode selects whose credentials are used for Kubernetes API calls.
+type CredentialMode string
+
+const (
+	// CredentialModeServer uses the server's own kubeconfig or service account.
+	CredentialModeServer CredentialMode = "server"
+
+	// CredentialModePassthrough uses the caller's bearer token.
+	CredentialModePassthrough CredentialMode = "passthrough"
+
+	// CredentialModeImpersonate uses the server's credentials and impersonates
+	// the caller's verified identity.
+	CredentialModeImpersonate CredentialMode = "impersonate"
+)
+
+// ParseCredentialMode validates a credential mode string. An empty string
+// selects CredentialModeServer.
+func ParseCredentialMode(s string) (CredentialMode, error) {
+	switch CredentialMode(s) {
+	case "", CredentialModeServer:
+		return CredentialModeServer, nil
+	case CredentialModePassthrough, CredentialModeImpersonate:
+		return CredentialMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown credential mode: %s (must be server, passthrough or impersonate)", s)
+	}
+}
+
+// RequestClientProvider is implemented by providers that can return clients
+// scoped to the caller of the current request.
+type RequestClientProvider interface {
+	// GetClientSetForRequest returns a ClientSet for the given Kubernetes context
+	// that acts as the caller found in ctx.
+	GetClientSetForRequest(ctx context.Context, name string) (*ClientSet, error)
+}
+
+// ClientSetForRequest returns a ClientSet for the named Kubernetes context,
+// scoped to the request's caller when the provider supports it.
+func ClientSetForRequest(ctx context.Context, provider ClientProvider, name string) (*ClientSet, error) {
+	if rp, ok := provider.(RequestClientProvider); ok {
+		return rp.GetClientSetForRequest(ctx, name)
+	}
+	return provider.GetClientSet(name)
+}
+
+// CallerProvider wraps a ClientProvider so that Kubernetes calls run as the
+// authenticated caller instead of the server.
+// Requests without a caller fail rather than fall back to the server's
+// credentials.
+type CallerProvider struct {
+	ClientProvider
+
+	factory *ClientFactory
+	mode    CredentialMode
+	cache   *clientSetCache
+}
+
+// NewCallerProvider creates a caller-scoped provider. Per-caller client sets
+// are cached up to cacheSize entries and evicted after ttl without use.
+func NewCallerProvider(base ClientProvider, factory *ClientFactory, mode CredentialMode, cacheSize int, ttl time.Duration) *CallerProvider {
+	return &CallerProvider{
+		ClientProvider: base,
+		factory:        factory,
+		mode:           mode,
+		cache:          newClientSetCache(cacheSize, ttl),
+	}
+}
+
+// Mode returns the provider's credential mode.
+func (p *CallerProvider) Mode() CredentialMode {
+	return p.mode
+}
+
+// GetClientSetForRequest implements RequestClientProvider.
+func (p *CallerProvider) GetClientSetForRequest(ctx context.Context, name string) (*ClientSet, error) {
+	base, err := p.ClientProvider.GetClientSet(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var selector *CredentialSelector
+	var key string
+	switch p.mode {
+	case CredentialModePassthrough:
+		token := auth.BearerTokenFromContext(ctx)
+		if token == "" {
+			return nil, fmt.Errorf("credential mode %s requires a caller bearer token", p.mode)
+		}
+		selector = &CredentialSelector{BearerToken: token}
+		key = "token:" + hashString(token)
+	case CredentialModeImpersonate:
+		identity := auth.IdentityFromContext(ctx)
+		if identity == nil || identity.Username == "" {
+			return nil, fmt.Errorf("credential mode %s requires an authenticated caller", p.mode)
+		}
+		// The UID is not impersonated, which would need the impersonate verb
+		// on uids as well
+		selector = &CredentialSelector{Impersonate: &rest.ImpersonationConfig{
+			UserName: identity.Username,
+			Groups:   identity.Groups,
+			Extra:    identity.Extra,
+		}}
+		key = "impersonate:" + identityKey(identity)
+	default:
+		return base, nil
+	}
+
+	key = name + "|" + key
+	return p.cache.getOrCreate(key, func() (*ClientSet, error) {
+		clientSet, err := p.factory.CreateClientSet(SelectCredentials(base.Config, selector))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client set for caller: %w", err)
+		}
+		return clientSet, nil
+	})
+}
+
+// hashString returns a hex SHA-256 digest, so raw tokens are never used as map keys.
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// CallerKey returns a stable key for the caller found in ctx, for caches that
+// must not share results between callers. It returns "" when ctx carries no
+// caller, i.e. when requests run with the server's credentials.
+func CallerKey(ctx context.Context) string {
+	if identity := auth.IdentityFromContext(ctx); identity != nil && identity.Username != "" {
+		return "identity:" + identityKey(identity)
+	}
+	if token := auth.BearerTokenFromContext(ctx); token != "" {
+		return "token:" + hashString(token)
+	}
+	return ""
+}
+
+// identityKey builds a stable cache key for an identity.
+func identityKey(identity *auth.Identity) string {
+	groups := append([]string(nil), identity.Groups...)
+	sort.Strings(groups)
+
+	extraKeys := make([]string, 0, len(identity.Extra))
+	for k := range identity.Extra {
+		extraKeys = append(extraKeys, k)
+	}
+	sort.Strings(extraKeys)
+
+	var b strings.Builder
+	b.WriteString(identity.Username)
+	b.WriteString("\x00")
+	b.WriteString(identity.UID)
+	b.WriteString("\x00")
+	b.WriteString(strings.Join(groups, ","))
+	for _, k := range extraKeys {
+		b.WriteString("\x00")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(strings.Join(identity.Extra[k], ","))
+	}
+	return hashString(b.String())
+}
+
+// clientSetCache is a size-bounded LRU cache of client sets with idle expiry.
+type clientSetCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	lru     *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type clientSetCacheEntry struct {
+	key      string
+	value    *ClientSet
+	lastUsed time.Time
+}
+
+func newClientSetCache(maxSize int, ttl time.Duration) *clientSetCache {
+	if maxSize <= 0 {
+		maxSize = 100
+	}
+	return &clientSetCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// getOrCreate returns the cached client set for key, creating it if needed.
+func (c *clientSetCache) getOrCreate(key string, create func() (*ClientSet, error)) (*ClientSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*clientSetCacheEntry)
+		entry.lastUsed = now
+		c.lru.MoveToFront(elem)
+		return entry.value, nil
+	}
+
+	clientSet, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[key] = c.lru.PushFront(&clientSetCacheEntry{key: key, value: clientSet, lastUsed: now})
+	for c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+	return clientSet, nil
+}
+
+// evictExpired drops entries idle for longer than the TTL.
+func (c *clientSetCache) evictExpired(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	for elem := c.lru.Back(); elem != nil; {
+		entry := elem.Value.(*clientSetCacheEntry)
+		if now.Sub(entry.lastUsed) < c.ttl {
+			return
+		}
+		prev := elem.Prev()
+		c.remove(elem)
+		elem = prev
+	}
+}
+
+func (c *clientSetCache) remove(elem *list.Element) {
+	entry := elem.Value.(*clientSetCacheEntry)
+	delete(c.entries, entry.key)
+	c.lru.Remove(elem)
+}
+
+// len returns the number of cached entries.
+func (c *clientSetCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"k8s.io/client-go/rest"
+)
+
+// staticProvider returns a fixed client set for every context.
+type staticProvider struct {
+	clientSet *ClientSet
+}
+
+func (p *staticProvider) GetClientSet(ctx string) (*ClientSet, error) { return p.clientSet, nil }
+func (p *staticProvider) ListContexts() ([]string, error)             { return []string{"test"}, nil }
+func (p *staticProvider) GetCurrentContext() (string, error)          { return "test", nil }
+
+// CallerProviderTestSuite tests per-caller credential selection and caching.
+type CallerProviderTestSuite struct {
+	suite.Suite
+	factory *ClientFactory
+	base    *staticProvider
+}
+
+// SetupTest sets up the test.
+func (s *CallerProviderTestSuite) SetupTest() {
+	s.factory = NewClientFactory(100, 200, 0)
+	clientSet, err := s.factory.CreateClientSet(&rest.Config{
+		Host:        "https://127.0.0.1:6443",
+		BearerToken: "server-token",
+	})
+	s.Require().NoError(err)
+	s.base = &staticProvider{clientSet: clientSet}
+}
+
+// TestParseCredentialMode tests credential mode parsing.
+func (s *CallerProviderTestSuite) TestParseCredentialMode() {
+	mode, err := ParseCredentialMode("")
+	s.Require().NoError(err)
+	s.Equal(CredentialModeServer, mode)
+
+	mode, err = ParseCredentialMode("impersonate")
+	s.Require().NoError(err)
+	s.Equal(CredentialModeImpersonate, mode)
+
+	_, err = ParseCredentialMode("bogus")
+	s.Error(err)
+}
+
+// TestSelectCredentialsToken tests that a bearer token replaces all server credentials.
+func (s *CallerProviderTestSuite) TestSelectCredentialsToken() {
+	original := &rest.Config{
+		Host:        "https://127.0.0.1:6443",
+		BearerToken: "server-token",
+		TLSClientConfig: rest.TLSClientConfig{
+			CertData: []byte("server-cert"),
+			KeyData:  []byte("server-key"),
+		},
+	}
+	cfg := SelectCredentials(original, &CredentialSelector{BearerToken: "caller-token"})
+	s.Equal("caller-token", cfg.BearerToken)
+	s.Empty(cfg.CertData, "Client certificates must not be sent with the caller's token")
+	s.Empty(cfg.KeyData)
+	s.Equal(original.Host, cfg.Host)
+}
+
+// TestSelectCredentialsImpersonate tests impersonation config.
+func (s *CallerProviderTestSuite) TestSelectCredentialsImpersonate() {
+	cfg := SelectCredentials(s.base.clientSet.Config, &CredentialSelector{
+		Impersonate: &rest.ImpersonationConfig{UserName: "alice", Groups: []string{"dev"}},
+	})
+	s.Equal("server-token", cfg.BearerToken, "Impersonation keeps server credentials")
+	s.Equal("alice", cfg.Impersonate.UserName)
+	s.Equal([]string{"dev"}, cfg.Impersonate.Groups)
+	s.Empty(s.base.clientSet.Config.Impersonate.UserName, "Original config must not be modified")
+}
+
+// TestPassthrough tests that callers get their own cached client sets.
+func (s *CallerProviderTestSuite) TestPassthrough() {
+	provider := NewCallerProvider(s.base, s.factory, CredentialModePassthrough, 10, time.Minute)
+
+	_, err := provider.GetClientSetForRequest(context.Background(), "")
+	s.ErrorContains(err, "requires a caller bearer token", "Requests without a caller must not use server credentials")
+
+	ctx := auth.WithBearerToken(context.Background(), "alice-token")
+	alice, err := provider.GetClientSetForRequest(ctx, "")
+	s.Require().NoError(err)
+	s.NotSame(s.base.clientSet, alice)
+	s.Equal("alice-token", alice.Config.BearerToken)
+
+	again, err := ClientSetForRequest(ctx, provider, "")
+	s.Require().NoError(err)
+	s.Same(alice, again, "Client sets should be cached per token")
+
+	bob, err := provider.GetClientSetForRequest(auth.WithBearerToken(context.Background(), "bob-token"), "")
+	s.Require().NoError(err)
+	s.NotSame(alice, bob)
+	s.Equal(2, provider.cache.len())
+}
+
+// TestImpersonate tests impersonation of the verified identity.
+func (s *CallerProviderTestSuite) TestImpersonate() {
+	provider := NewCallerProvider(s.base, s.factory, CredentialModeImpersonate, 10, time.Minute)
+
+	_, err := provider.GetClientSetForRequest(context.Background(), "")
+	s.ErrorContains(err, "requires an authenticated caller")
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: "alice", UID: "1234", Groups: []string{"dev", "ops"}})
+	clientSet, err := provider.GetClientSetForRequest(ctx, "")
+	s.Require().NoError(err)
+	s.Equal("alice", clientSet.Config.Impersonate.UserName)
+	s.Empty(clientSet.Config.Impersonate.UID)
+	s.Equal([]string{"dev", "ops"}, clientSet.Config.Impersonate.Groups)
+
+	// Group order must not produce a separate cache entry
+	ctx = auth.WithIdentity(context.Background(), &auth.Identity{Username: "alice", UID: "1234", Groups: []string{"ops", "dev"}})
+	again, err := provider.GetClientSetForRequest(ctx, "")
+	s.Require().NoError(err)
+	s.Same(clientSet, again)
+}
+
+// TestCacheEviction tests LRU and idle eviction.
+func (s *CallerProviderTestSuite) TestCacheEviction() {
+	cache := newClientSetCache(2, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	create := func() (*ClientSet, error) { return &ClientSet{}, nil }
+	first, _ := cache.getOrCreate("a", create)
+	_, _ = cache.getOrCreate("b", create)
+	_, _ = cache.getOrCreate("a", create) // a is now most recently used
+	_, _ = cache.getOrCreate("c", create) // evicts b
+	s.Equal(2, cache.len())
+
+	again, _ := cache.getOrCreate("a", create)
+	s.Same(first, again, "Recently used entries should survive LRU eviction")
+
+	now = now.Add(2 * time.Minute)
+	_, _ = cache.getOrCreate("d", create)
+	s.Equal(1, cache.len(), "Idle entries should be evicted after the TTL")
+}
+
+// TestCallerProviderTestSuite runs the caller provider test suite.
+func TestCallerProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(CallerProviderTestSuite))
+}
@@ -3,6 +3,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -98,11 +99,10 @@ func (d *CRDDiscovery) ListCRDs() []schema.GroupVersionKind {
 
 	gvks := make([]schema.GroupVersionKind, 0, len(d.cache))
 	for key := range d.cache {
-		// Parse key format: "group/version/kind"
-		var gvk schema.GroupVersionKind
-		_, err := fmt.Sscanf(key, "%s/%s/%s", &gvk.Group, &gvk.Version, &gvk.Kind)
-		if err == nil {
-			gvks = append(gvks, gvk)
+		// Parse key format: "group/version/kind" (group is empty for the core API)
+		parts := strings.SplitN(key, "/", 3)
+		if len(parts) == 3 {
+			gvks = append(gvks, schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]})
 		}
 	}
 
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,9 +20,10 @@ import (
 
 // ClientFactory creates Kubernetes clients from a REST config.
 type ClientFactory struct {
-	qps     float32
-	burst   int
-	timeout time.Duration
+	qps        float32
+	burst      int
+	timeout    time.Duration
+	deniedGVKs *DeniedGVKs
 }
 
 // NewClientFactory creates a new client factory with the given settings.
@@ -33,6 +35,12 @@ func NewClientFactory(qps float32, burst int, timeout time.Duration) *ClientFact
 	}
 }
 
+// SetDeniedGVKs configures kinds that client sets created by this factory
+// must refuse to access. It must be called before any client set is created.
+func (f *ClientFactory) SetDeniedGVKs(denied *DeniedGVKs) {
+	f.deniedGVKs = denied
+}
+
 // CreateClientSet creates a ClientSet from a REST config.
 func (f *ClientFactory) CreateClientSet(config *rest.Config) (*ClientSet, error) {
 	// Apply QPS and burst settings
@@ -40,6 +48,24 @@ func (f *ClientFactory) CreateClientSet(config *rest.Config) (*ClientSet, error)
 	config.Burst = f.burst
 	config.Timeout = f.timeout
 
+	// Create discovery client
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	// Create REST mapper
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	// Guard all resource requests against denied GVKs. Discovery stays on the
+	// unwrapped config so the mapper can resolve kinds without recursion.
+	if !f.deniedGVKs.IsEmpty() {
+		config = rest.CopyConfig(config)
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &deniedGVKRoundTripper{denied: f.deniedGVKs, mapper: mapper, next: rt}
+		})
+	}
+
 	// Create typed client
 	typedClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -52,15 +78,6 @@ func (f *ClientFactory) CreateClientSet(config *rest.Config) (*ClientSet, error)
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	// Create discovery client
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
-	}
-
-	// Create REST mapper
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
-
 	// Create metrics client (may fail if metrics server is not available)
 	metricsClient, _ := metricsclientset.NewForConfig(config)
 
@@ -100,7 +117,7 @@ func expandKubeconfigPath(path string) string {
 func (f *ClientFactory) CreateKubeconfigClientSet(kubeconfigPath, context string) (*ClientSet, error) {
 	// Expand ~ in kubeconfig path
 	expandedPath := expandKubeconfigPath(kubeconfigPath)
-	
+
 	// Use clientcmd to load kubeconfig
 	loadingRules := &clientcmd.ClientConfigLoadingRules{
 		ExplicitPath: expandedPath,
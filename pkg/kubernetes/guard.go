@@ -0,0 +1,184 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeniedGVKs is a set of GroupVersionKinds that must never be accessed.
+// Matching is done on group and kind so that a denied kind cannot be reached
+// through another served version.
+type DeniedGVKs struct {
+	kinds     map[schema.GroupKind]struct{}
+	resources map[schema.GroupResource]struct{}
+}
+
+// NewDeniedGVKs parses a list of GVK strings (see ParseGVK) into a deny set.
+func NewDeniedGVKs(entries []string) (*DeniedGVKs, error) {
+	d := &DeniedGVKs{
+		kinds:     make(map[schema.GroupKind]struct{}),
+		resources: make(map[schema.GroupResource]struct{}),
+	}
+	for _, entry := range entries {
+		gvk, err := ParseGVK(entry)
+		if err != nil {
+			return nil, err
+		}
+		d.kinds[schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}] = struct{}{}
+
+		plural, singular := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+		d.resources[plural.GroupResource()] = struct{}{}
+		d.resources[singular.GroupResource()] = struct{}{}
+	}
+	return d, nil
+}
+
+// IsEmpty reports whether the deny set contains no entries.
+func (d *DeniedGVKs) IsEmpty() bool {
+	return d == nil || len(d.kinds) == 0
+}
+
+// Denies reports whether the given group and kind are denied.
+// Kind comparison is case-insensitive to match the API server's handling of
+// user-supplied kinds in the resources_* tools.
+func (d *DeniedGVKs) Denies(group, kind string) bool {
+	if d.IsEmpty() {
+		return false
+	}
+	if _, ok := d.kinds[schema.GroupKind{Group: group, Kind: kind}]; ok {
+		return true
+	}
+	for gk := range d.kinds {
+		if gk.Group == group && strings.EqualFold(gk.Kind, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeniesResource reports whether the given group and resource name are those
+// of a denied kind. Resource names are guessed from the kind, as kubectl does,
+// so that requests are rejected even when the kind cannot be looked up.
+func (d *DeniedGVKs) DeniesResource(group, resource string) bool {
+	if d.IsEmpty() {
+		return false
+	}
+	_, ok := d.resources[schema.GroupResource{Group: group, Resource: strings.ToLower(resource)}]
+	return ok
+}
+
+// deniedGVKRoundTripper rejects API requests that target a denied kind before
+// they leave the process. It sits underneath the typed and dynamic clients so
+// every toolset is covered, including those that address CRDs dynamically.
+type deniedGVKRoundTripper struct {
+	denied *DeniedGVKs
+	mapper meta.RESTMapper
+	next   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *deniedGVKRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	gvr, ok := parseResourcePath(req.URL.Path)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	if rt.denied.DeniesResource(gvr.Group, gvr.Resource) {
+		return forbiddenResponse(req, gvr, fmt.Sprintf("%s is denied by kube-mcp security.denied_gvks", gvr.GroupResource())), nil
+	}
+
+	gvk, err := rt.mapper.KindFor(gvr)
+	if err != nil {
+		// The resource may have been added since discovery was cached.
+		// Resources whose kind still cannot be told are rejected, since
+		// they could be a denied kind under another name.
+		if resettable, ok := rt.mapper.(meta.ResettableRESTMapper); ok {
+			resettable.Reset()
+			gvk, err = rt.mapper.KindFor(gvr)
+		}
+		if err != nil {
+			return forbiddenResponse(req, gvr, fmt.Sprintf("the kind of %s cannot be determined to check it against kube-mcp security.denied_gvks: %v", gvr.String(), err)), nil
+		}
+	}
+
+	if rt.denied.Denies(gvk.Group, gvk.Kind) {
+		return forbiddenResponse(req, gvr, fmt.Sprintf("%s is denied by kube-mcp security.denied_gvks", gvk.String())), nil
+	}
+
+	return rt.next.RoundTrip(req)
+}
+
+// parseResourcePath extracts the GroupVersionResource from a Kubernetes API path.
+// Discovery and non-resource paths return false.
+func parseResourcePath(path string) (schema.GroupVersionResource, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	var gvr schema.GroupVersionResource
+	var rest []string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		gvr.Version = parts[1]
+		rest = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		gvr.Group = parts[1]
+		gvr.Version = parts[2]
+		rest = parts[3:]
+	default:
+		return gvr, false
+	}
+
+	// Legacy watch paths: /api/v1/watch/...
+	if rest[0] == "watch" {
+		rest = rest[1:]
+		if len(rest) == 0 {
+			return gvr, false
+		}
+	}
+
+	// Namespaced resources: namespaces/{ns}/{resource}/...
+	// A bare namespaces or namespaces/{name}[/{subresource}] targets Namespace itself.
+	if rest[0] == "namespaces" && len(rest) >= 3 && rest[2] != "status" && rest[2] != "finalize" {
+		gvr.Resource = rest[2]
+	} else {
+		gvr.Resource = rest[0]
+	}
+
+	return gvr, gvr.Resource != ""
+}
+
+// forbiddenResponse builds a 403 response carrying a metav1.Status so client-go
+// surfaces it as a regular Forbidden StatusError.
+func forbiddenResponse(req *http.Request, gvr schema.GroupVersionResource, message string) *http.Response {
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   metav1.StatusReasonForbidden,
+		Details: &metav1.StatusDetails{
+			Group: gvr.Group,
+			Kind:  gvr.Resource,
+		},
+		Code: http.StatusForbidden,
+	}
+	body, _ := json.Marshal(status)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusForbidden, http.StatusText(http.StatusForbidden)),
+		StatusCode:    http.StatusForbidden,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GuardTestSuite tests denied GVK parsing and request path matching.
+type GuardTestSuite struct {
+	suite.Suite
+}
+
+// TestParseGVK tests GVK string parsing.
+func (s *GuardTestSuite) TestParseGVK() {
+	gvk, err := ParseGVK("rbac.authorization.k8s.io/v1/ClusterRole")
+	s.Require().NoError(err)
+	s.Equal(GVK{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, gvk)
+
+	gvk, err = ParseGVK("v1/Secret")
+	s.Require().NoError(err)
+	s.Equal(GVK{Version: "v1", Kind: "Secret"}, gvk)
+
+	for _, invalid := range []string{"", "Secret", "a/b/c/d", "apps//Deployment"} {
+		_, err := ParseGVK(invalid)
+		s.Error(err, "Expected %q to be rejected", invalid)
+	}
+}
+
+// TestDenies tests group/kind matching.
+func (s *GuardTestSuite) TestDenies() {
+	denied, err := NewDeniedGVKs([]string{"v1/Secret", "rbac.authorization.k8s.io/v1/ClusterRole"})
+	s.Require().NoError(err)
+
+	s.True(denied.Denies("", "Secret"))
+	s.True(denied.Denies("", "secret"), "Kind matching should be case-insensitive")
+	s.True(denied.Denies("rbac.authorization.k8s.io", "ClusterRole"))
+	s.False(denied.Denies("", "ClusterRole"), "Group must match")
+	s.False(denied.Denies("", "ConfigMap"))
+
+	s.True(denied.DeniesResource("", "secrets"))
+	s.True(denied.DeniesResource("", "secret"))
+	s.True(denied.DeniesResource("rbac.authorization.k8s.io", "clusterroles"))
+	s.False(denied.DeniesResource("", "configmaps"))
+
+	var empty *DeniedGVKs
+	s.True(empty.IsEmpty())
+	s.False(empty.Denies("", "Secret"))
+	s.False(empty.DeniesResource("", "secrets"))
+}
+
+// resettingMapper learns the widgets resource when it is reset, like a
+// discovery-backed mapper after a CRD is installed.
+type resettingMapper struct {
+	*meta.DefaultRESTMapper
+	resets int
+}
+
+func (m *resettingMapper) Reset() {
+	m.resets++
+	m.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+}
+
+// roundTripFunc is an http.RoundTripper backed by a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestRoundTrip tests that denied kinds are rejected by resource name and by
+// kind, and that resources whose kind cannot be told are rejected after the
+// mapper is reset.
+func (s *GuardTestSuite) TestRoundTrip() {
+	denied, err := NewDeniedGVKs([]string{"v1/Secret", "example.com/v1/Gadget"})
+	s.Require().NoError(err)
+	mapper := &resettingMapper{DefaultRESTMapper: meta.NewDefaultRESTMapper(nil)}
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.AddSpecific(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"},
+		schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "things"},
+		schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "thing"}, meta.RESTScopeNamespace)
+
+	rt := &deniedGVKRoundTripper{denied: denied, mapper: mapper, next: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})}
+	status := func(path string) int {
+		req, err := http.NewRequest(http.MethodGet, "https://api.example.com"+path, nil)
+		s.Require().NoError(err)
+		resp, err := rt.RoundTrip(req)
+		s.Require().NoError(err)
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusOK, status("/api/v1/namespaces/default/configmaps"))
+	s.Equal(http.StatusForbidden, status("/api/v1/namespaces/default/secrets/token"), "Denied by resource name without a mapping")
+	s.Equal(http.StatusForbidden, status("/apis/example.com/v1/namespaces/default/things"), "Denied by mapped kind")
+	s.Equal(0, mapper.resets)
+
+	s.Equal(http.StatusOK, status("/apis/example.com/v1/namespaces/default/widgets"))
+	s.Equal(1, mapper.resets, "Unknown resources should reset the mapper")
+	s.Equal(http.StatusForbidden, status("/apis/example.com/v1/namespaces/default/sprockets"), "Unknown resources should be denied")
+}
+
+// TestParseResourcePath tests extracting resources from API paths.
+func (s *GuardTestSuite) TestParseResourcePath() {
+	cases := map[string]schema.GroupVersionResource{
+		"/api/v1/pods":                                      {Version: "v1", Resource: "pods"},
+		"/api/v1/namespaces/default/secrets/foo":            {Version: "v1", Resource: "secrets"},
+		"/api/v1/namespaces/default/pods/foo/exec":          {Version: "v1", Resource: "pods"},
+		"/api/v1/namespaces":                                {Version: "v1", Resource: "namespaces"},
+		"/api/v1/namespaces/default":                        {Version: "v1", Resource: "namespaces"},
+		"/api/v1/namespaces/default/status":                 {Version: "v1", Resource: "namespaces"},
+		"/api/v1/watch/namespaces/default/configmaps":       {Version: "v1", Resource: "configmaps"},
+		"/apis/apps/v1/namespaces/default/deployments/web":  {Group: "apps", Version: "v1", Resource: "deployments"},
+		"/apis/rbac.authorization.k8s.io/v1/clusterroles/x": {Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+	}
+	for path, expected := range cases {
+		gvr, ok := parseResourcePath(path)
+		s.True(ok, "Expected %s to be a resource path", path)
+		s.Equal(expected, gvr, "Path %s", path)
+	}
+
+	for _, path := range []string{"/api", "/api/v1", "/apis", "/apis/apps/v1", "/version", "/healthz"} {
+		_, ok := parseResourcePath(path)
+		s.False(ok, "Expected %s to be a non-resource path", path)
+	}
+}
+
+// TestGuardTestSuite runs the guard test suite.
+func TestGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(GuardTestSuite))
+}
@@ -1,11 +1,14 @@
 package kubernetes
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/wrkode/kube-mcp/pkg/auth"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,93 +16,207 @@ import (
 
 // RBACAuthorizer provides RBAC authorization checking with caching.
 type RBACAuthorizer interface {
-	// Allowed checks if a user is allowed to perform an action.
-	Allowed(ctx context.Context, user string, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error)
+	// Allowed checks if a user is allowed to perform an action in the cluster
+	// of a Kubernetes context, the default one if contextName is empty.
+	// A nil identity checks the server's own permissions. A resource of the
+	// form resource/subresource, such as pods/exec, checks the subresource.
+	Allowed(ctx context.Context, identity *auth.Identity, contextName, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error)
+}
+
+// RBACDecision is the outcome of an RBAC check, as reported to RBAC observers.
+type RBACDecision struct {
+	Verb      string `json:"verb"`
+	Group     string `json:"group,omitempty"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Allowed   bool   `json:"allowed"`
+	Error     string `json:"error,omitempty"`
+}
+
+type rbacObserverKey struct{}
+
+// WithRBACObserver returns a context in which every decision of the RBAC
+// authorizer, cached or not, is reported to observe. It lets callers such as
+// the audit log see the checks a tool handler made.
+func WithRBACObserver(ctx context.Context, observe func(RBACDecision)) context.Context {
+	return context.WithValue(ctx, rbacObserverKey{}, observe)
+}
+
+// observeRBAC reports a decision to the observer in ctx, if any.
+func observeRBAC(ctx context.Context, verb string, gvr schema.GroupVersionResource, namespace string, allowed bool, err error) {
+	observe, ok := ctx.Value(rbacObserverKey{}).(func(RBACDecision))
+	if !ok {
+		return
+	}
+	decision := RBACDecision{
+		Verb:      verb,
+		Group:     gvr.Group,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Allowed:   allowed,
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	observe(decision)
 }
 
 // rbacCacheEntry represents a cached RBAC check result.
 type rbacCacheEntry struct {
+	key       string
 	allowed   bool
 	expiresAt time.Time
 }
 
-// rbacAuthorizerImpl implements RBACAuthorizer with caching.
+// rbacAuthorizerImpl implements RBACAuthorizer with caching. Entries are also
+// kept in the order they were cached, which with a single TTL is the order
+// they expire in, so expired entries are evicted without scanning the cache.
 type rbacAuthorizerImpl struct {
-	clientSet *ClientSet
-	cache     map[string]*rbacCacheEntry
-	mu        sync.RWMutex
-	ttl       time.Duration
+	provider ClientProvider
+	cache    map[string]*rbacCacheEntry
+	expiry   list.List // of *rbacCacheEntry, oldest first
+	mu       sync.RWMutex
+	ttl      time.Duration
 }
 
-// NewRBACAuthorizer creates a new RBAC authorizer with caching.
-func NewRBACAuthorizer(clientSet *ClientSet, ttlSeconds int) RBACAuthorizer {
+// NewRBACAuthorizer creates a new RBAC authorizer with caching. Access reviews
+// are sent with the server's credentials for each context, as provider gives
+// them; callers may not be allowed to create them.
+func NewRBACAuthorizer(provider ClientProvider, ttlSeconds int) RBACAuthorizer {
 	ttl := time.Duration(ttlSeconds) * time.Second
 	if ttl <= 0 {
 		ttl = 5 * time.Second // Default TTL
 	}
 
 	return &rbacAuthorizerImpl{
-		clientSet: clientSet,
-		cache:     make(map[string]*rbacCacheEntry),
-		ttl:       ttl,
+		provider: provider,
+		cache:    make(map[string]*rbacCacheEntry),
+		ttl:      ttl,
 	}
 }
 
 // cacheKey generates a cache key for an RBAC check.
-func cacheKey(user, verb string, gvr schema.GroupVersionResource, namespace string) string {
-	return fmt.Sprintf("%s:%s:%s:%s:%s", user, verb, gvr.Group, gvr.Resource, namespace)
+func cacheKey(contextName, user, verb string, gvr schema.GroupVersionResource, namespace string) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s:%s", contextName, user, verb, gvr.Group, gvr.Resource, namespace)
+}
+
+// subjectKey identifies the subject of an RBAC check in the cache. The server
+// itself (nil identity) uses the empty key.
+func subjectKey(identity *auth.Identity) string {
+	if identity == nil {
+		return ""
+	}
+	return identityKey(identity)
 }
 
 // Allowed checks if a user is allowed to perform an action.
-func (r *rbacAuthorizerImpl) Allowed(ctx context.Context, user string, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
-	key := cacheKey(user, verb, gvr, namespace)
+func (r *rbacAuthorizerImpl) Allowed(ctx context.Context, identity *auth.Identity, contextName, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	allowed, err := r.allowed(ctx, identity, contextName, verb, gvr, namespace)
+	observeRBAC(ctx, verb, gvr, namespace, allowed, err)
+	return allowed, err
+}
+
+// allowed answers an RBAC check from the cache, or checks and caches it.
+func (r *rbacAuthorizerImpl) allowed(ctx context.Context, identity *auth.Identity, contextName, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	key := cacheKey(contextName, subjectKey(identity), verb, gvr, namespace)
 
 	// Check cache
 	r.mu.RLock()
-	if entry, ok := r.cache[key]; ok {
-		if time.Now().Before(entry.expiresAt) {
-			allowed := entry.allowed
-			r.mu.RUnlock()
-			return allowed, nil
-		}
-		// Entry expired, remove it
-		delete(r.cache, key)
-	}
+	entry, ok := r.cache[key]
 	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.allowed, nil
+	}
 
 	// Perform actual RBAC check
-	allowed, err := r.checkRBAC(ctx, user, verb, gvr, namespace)
+	allowed, err := r.checkRBAC(ctx, identity, contextName, verb, gvr, namespace)
 	if err != nil {
 		return false, err
 	}
 
-	// Cache the result
+	// Cache the result, evicting expired entries so the cache holds only
+	// the checks of the last TTL
+	now := time.Now()
 	r.mu.Lock()
-	r.cache[key] = &rbacCacheEntry{
+	r.evictExpired(now)
+	entry = &rbacCacheEntry{
+		key:       key,
 		allowed:   allowed,
-		expiresAt: time.Now().Add(r.ttl),
+		expiresAt: now.Add(r.ttl),
 	}
+	r.cache[key] = entry
+	r.expiry.PushBack(entry)
 	r.mu.Unlock()
 
 	return allowed, nil
 }
 
-// checkRBAC performs the actual RBAC check using SelfSubjectAccessReview.
-func (r *rbacAuthorizerImpl) checkRBAC(ctx context.Context, user string, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
-	review := &authorizationv1.SelfSubjectAccessReview{
-		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace: namespace,
-				Verb:      verb,
-				Group:     gvr.Group,
-				Resource:  gvr.Resource,
+// evictExpired drops the entries that expired by now, oldest first. Entries
+// that were cached again since are only dropped from the expiry order.
+func (r *rbacAuthorizerImpl) evictExpired(now time.Time) {
+	for elem := r.expiry.Front(); elem != nil; elem = r.expiry.Front() {
+		entry := elem.Value.(*rbacCacheEntry)
+		if now.Before(entry.expiresAt) {
+			return
+		}
+		if r.cache[entry.key] == entry {
+			delete(r.cache, entry.key)
+		}
+		r.expiry.Remove(elem)
+	}
+}
+
+// checkRBAC performs the actual RBAC check. Callers with an identity are
+// checked with a SubjectAccessReview; otherwise a SelfSubjectAccessReview
+// checks the server's own credentials.
+func (r *rbacAuthorizerImpl) checkRBAC(ctx context.Context, identity *auth.Identity, contextName, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	clientSet, err := r.provider.GetClientSet(contextName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get client set: %w", err)
+	}
+
+	resource, subresource, _ := strings.Cut(gvr.Resource, "/")
+	attributes := &authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        verb,
+		Group:       gvr.Group,
+		Resource:    resource,
+		Subresource: subresource,
+	}
+
+	if identity == nil {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: attributes,
 			},
+		}
+
+		result, err := clientSet.Typed.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to create self subject access review: %w", err)
+		}
+
+		return result.Status.Allowed, nil
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+			User:               identity.Username,
+			UID:                identity.UID,
+			Groups:             identity.Groups,
 		},
 	}
+	if len(identity.Extra) > 0 {
+		review.Spec.Extra = make(map[string]authorizationv1.ExtraValue, len(identity.Extra))
+		for k, v := range identity.Extra {
+			review.Spec.Extra[k] = authorizationv1.ExtraValue(v)
+		}
+	}
 
-	result, err := r.clientSet.Typed.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	result, err := clientSet.Typed.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
 	if err != nil {
-		return false, fmt.Errorf("failed to create self subject access review: %w", err)
+		return false, fmt.Errorf("failed to create subject access review: %w", err)
 	}
 
 	return result.Status.Allowed, nil
@@ -110,4 +227,5 @@ func (r *rbacAuthorizerImpl) ClearCache() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.cache = make(map[string]*rbacCacheEntry)
+	r.expiry.Init()
 }
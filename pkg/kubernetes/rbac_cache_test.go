@@ -1,11 +1,18 @@
 package kubernetes
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 // RBACCacheTestSuite tests RBAC caching functionality.
@@ -35,17 +42,21 @@ func (s *RBACCacheTestSuite) TestCacheKeyGeneration() {
 	}
 	namespace := "default"
 
-	key1 := cacheKey(user, verb, gvr, namespace)
-	key2 := cacheKey(user, verb, gvr, namespace)
+	key1 := cacheKey("", user, verb, gvr, namespace)
+	key2 := cacheKey("", user, verb, gvr, namespace)
 	s.Equal(key1, key2, "Same inputs should generate same key")
 
 	// Different namespace should generate different key
-	key3 := cacheKey(user, verb, gvr, "other-ns")
+	key3 := cacheKey("", user, verb, gvr, "other-ns")
 	s.NotEqual(key1, key3, "Different namespace should generate different key")
 
 	// Different verb should generate different key
-	key4 := cacheKey(user, "list", gvr, namespace)
+	key4 := cacheKey("", user, "list", gvr, namespace)
 	s.NotEqual(key1, key4, "Different verb should generate different key")
+
+	// Different context should generate different key
+	key5 := cacheKey("prod", user, verb, gvr, namespace)
+	s.NotEqual(key1, key5, "Different context should generate different key")
 }
 
 // TestCacheExpiry tests cache expiry behavior.
@@ -126,6 +137,144 @@ func (s *RBACCacheTestSuite) TestTTLConfiguration() {
 	s.NotEqual(authorizer1.ttl, authorizer2.ttl, "Different TTLs should be different")
 }
 
+// TestSubjectAccessReview tests that callers are checked with a SubjectAccessReview
+// and cached per user and groups.
+func (s *RBACCacheTestSuite) TestSubjectAccessReview() {
+	typed := fake.NewSimpleClientset()
+	var reviews []*authorizationv1.SubjectAccessReview
+	selfReviews := 0
+	typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews = append(reviews, review)
+		review.Status.Allowed = review.Spec.User == "alice"
+		return true, review, nil
+	})
+	typed.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selfReviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+
+	authorizer := NewRBACAuthorizer(&staticProvider{clientSet: &ClientSet{Typed: typed}}, 60)
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	ctx := context.Background()
+
+	allowed, err := authorizer.Allowed(ctx, &auth.Identity{Username: "alice", Groups: []string{"dev", "ops"}}, "", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.True(allowed)
+	s.Require().Len(reviews, 1)
+	s.Equal("alice", reviews[0].Spec.User)
+	s.Equal([]string{"dev", "ops"}, reviews[0].Spec.Groups)
+	s.Equal("delete", reviews[0].Spec.ResourceAttributes.Verb)
+
+	// Same user and groups in a different order is served from the cache
+	_, err = authorizer.Allowed(ctx, &auth.Identity{Username: "alice", Groups: []string{"ops", "dev"}}, "", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.Len(reviews, 1)
+
+	// Different groups are checked separately
+	_, err = authorizer.Allowed(ctx, &auth.Identity{Username: "alice", Groups: []string{"dev"}}, "", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.Len(reviews, 2)
+
+	allowed, err = authorizer.Allowed(ctx, &auth.Identity{Username: "bob"}, "", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.False(allowed)
+
+	// Without a caller, the server's own identity is checked
+	allowed, err = authorizer.Allowed(ctx, nil, "", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.True(allowed)
+	s.Equal(1, selfReviews)
+}
+
+// contextProvider returns the client set of each context by name.
+type contextProvider map[string]*ClientSet
+
+func (p contextProvider) GetClientSet(name string) (*ClientSet, error) {
+	clientSet, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found", name)
+	}
+	return clientSet, nil
+}
+func (p contextProvider) ListContexts() ([]string, error)    { return nil, nil }
+func (p contextProvider) GetCurrentContext() (string, error) { return "", nil }
+
+// TestEviction tests that expired entries are evicted, oldest first, when
+// checks are cached.
+func (s *RBACCacheTestSuite) TestEviction() {
+	typed := fake.NewSimpleClientset()
+	typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	authorizer := NewRBACAuthorizer(&staticProvider{clientSet: &ClientSet{Typed: typed}}, 60).(*rbacAuthorizerImpl)
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	ctx := context.Background()
+
+	for _, user := range []string{"alice", "bob"} {
+		_, err := authorizer.Allowed(ctx, &auth.Identity{Username: user}, "", "get", gvr, "default")
+		s.Require().NoError(err)
+	}
+	s.Len(authorizer.cache, 2)
+
+	for _, entry := range authorizer.cache {
+		entry.expiresAt = time.Now().Add(-time.Second)
+	}
+	_, err := authorizer.Allowed(ctx, &auth.Identity{Username: "carol"}, "", "get", gvr, "default")
+	s.Require().NoError(err)
+	s.Len(authorizer.cache, 1, "Expired entries should be evicted")
+	s.Equal(1, authorizer.expiry.Len())
+
+	// An entry cached again outlives the expiry of its earlier entry
+	now := time.Now()
+	authorizer.ClearCache()
+	earlier := &rbacCacheEntry{key: "k", expiresAt: now.Add(-time.Second)}
+	later := &rbacCacheEntry{key: "k", allowed: true, expiresAt: now.Add(time.Minute)}
+	authorizer.cache["k"] = later
+	authorizer.expiry.PushBack(earlier)
+	authorizer.expiry.PushBack(later)
+	authorizer.evictExpired(now)
+	s.Same(later, authorizer.cache["k"])
+	s.Equal(1, authorizer.expiry.Len())
+}
+
+// TestContexts tests that checks are reviewed by the cluster of their context
+// and cached per context.
+func (s *RBACCacheTestSuite) TestContexts() {
+	reviews := map[string]int{}
+	clientSet := func(name string, allowed bool) *ClientSet {
+		typed := fake.NewSimpleClientset()
+		typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			reviews[name]++
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+			review.Status.Allowed = allowed
+			return true, review, nil
+		})
+		return &ClientSet{Typed: typed}
+	}
+	authorizer := NewRBACAuthorizer(contextProvider{"dev": clientSet("dev", true), "prod": clientSet("prod", false)}, 60)
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	alice := &auth.Identity{Username: "alice"}
+	ctx := context.Background()
+
+	allowed, err := authorizer.Allowed(ctx, alice, "dev", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.True(allowed)
+	allowed, err = authorizer.Allowed(ctx, alice, "prod", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.False(allowed, "Prod should not be answered from the cache of dev")
+	_, err = authorizer.Allowed(ctx, alice, "dev", "delete", gvr, "default")
+	s.Require().NoError(err)
+	s.Equal(map[string]int{"dev": 1, "prod": 1}, reviews)
+
+	_, err = authorizer.Allowed(ctx, alice, "staging", "delete", gvr, "default")
+	s.Error(err)
+}
+
 // TestRBACCacheTestSuite runs the RBAC cache test suite.
 func TestRBACCacheTestSuite(t *testing.T) {
 	suite.Run(t, new(RBACCacheTestSuite))
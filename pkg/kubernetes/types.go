@@ -1,6 +1,9 @@
 package kubernetes
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
@@ -60,11 +63,23 @@ func (g GVK) String() string {
 }
 
 // ParseGVK parses a GVK string into a GVK struct.
-func ParseGVK(s string) GVK {
-	// Simple parsing - format: group/version/kind or version/kind
-	// This is a placeholder implementation
-	// In production, use proper parsing logic
-	return GVK{}
+// Accepted formats are "group/version/kind" and "version/kind" (core group).
+func ParseGVK(s string) (GVK, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	for _, part := range parts {
+		if part == "" {
+			return GVK{}, fmt.Errorf("invalid GVK %q: empty segment", s)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return GVK{Version: parts[0], Kind: parts[1]}, nil
+	case 3:
+		return GVK{Group: parts[0], Version: parts[1], Kind: parts[2]}, nil
+	default:
+		return GVK{}, fmt.Errorf("invalid GVK %q: expected group/version/kind or version/kind", s)
+	}
 }
 
 // ToSchemaGVK converts to schema.GroupVersionKind.
@@ -7,32 +7,42 @@ import (
 // AddTool wraps the SDK's generic AddTool function to normalize tool names for n8n compatibility.
 // IMPORTANT: Toolsets should use this function instead of calling mcp.AddTool directly
 // from the SDK package. This ensures tool names are normalized when normalizeToolNames
-// is enabled in the configuration.
+// is enabled in the configuration, and that tool filters and middleware registered on
+// the Server (e.g. security policy enforcement) apply to every tool.
 //
 // When normalizeToolNames is enabled, dots in tool names are replaced with underscores.
 // For example: "autoscaling.hpa_explain" becomes "autoscaling_hpa_explain"
 //
+// The tool is registered with the definition the toolset publishes from Tools(),
+// so clients see its full input schema and annotations; the tool passed here only
+// identifies it by name.
+//
 // This is a generic wrapper that matches the SDK's AddTool signature.
 func AddTool[In, Out any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
-	// If server is wrapped by our Server, normalize the tool name
-	if srv, ok := getServerFromSDK(server); ok && srv.normalizeToolNames {
-		// Create a copy of the tool to avoid modifying the original
-		normalizedTool := *tool
-		originalName := tool.Name
-		normalizedTool.Name = srv.normalizeToolName(tool.Name)
+	srv, ok := getServerFromSDK(server)
+	if !ok {
+		// Not one of our servers, call SDK directly
+		mcp.AddTool(server, tool, handler)
+		return
+	}
+
+	def := srv.toolDefinition(tool)
+	if !srv.toolAllowed(def) {
+		return
+	}
+
+	// Create a copy of the definition to avoid modifying the original
+	registered := *def
+	if srv.normalizeToolNames {
+		registered.Name = srv.normalizeToolName(tool.Name)
 
 		// Store mapping for reverse lookup
-		if normalizedTool.Name != originalName {
-			srv.nameMapping[normalizedTool.Name] = originalName
+		if registered.Name != tool.Name {
+			srv.nameMapping[registered.Name] = tool.Name
 		}
-
-		// Register with normalized name
-		mcp.AddTool(server, &normalizedTool, handler)
-		return
 	}
 
-	// No normalization needed, call SDK directly
-	mcp.AddTool(server, tool, handler)
+	mcp.AddTool(server, &registered, wrapHandler(srv, def, handler))
 }
 
 // getServerFromSDK attempts to find our Server wrapper from the SDK server.
@@ -0,0 +1,456 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxCompletionValues is the most values a completion result may carry.
+const maxCompletionValues = 100
+
+// DefaultCompletionCacheTTL is how long completion values are cached.
+const DefaultCompletionCacheTTL = 30 * time.Second
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// Completer answers completion/complete requests for prompt arguments and
+// resource template variables. Kubernetes lookups are cached per context and
+// caller, so completing while typing does not hit the API server on every key.
+type Completer struct {
+	provider       kubernetes.ClientProvider
+	rbacAuthorizer kubernetes.RBACAuthorizer
+	requireRBAC    bool
+	checkAccess    func(ctx context.Context, toolName string, target Target) error
+	checkContext   func(ctx context.Context, toolName, contextName string) error
+	ttl            time.Duration
+	now            func() time.Time
+
+	mu        sync.Mutex
+	discovery map[string]*kubernetes.CRDDiscovery // context -> API discovery
+	cache     map[string]completionCacheEntry
+}
+
+// completionCacheEntry is a cached list of completion values.
+type completionCacheEntry struct {
+	values    []string
+	expiresAt time.Time
+}
+
+// NewCompleter creates a completer whose lookups are cached for ttl.
+// A non-positive ttl selects DefaultCompletionCacheTTL.
+func NewCompleter(provider kubernetes.ClientProvider, ttl time.Duration) *Completer {
+	if ttl <= 0 {
+		ttl = DefaultCompletionCacheTTL
+	}
+	return &Completer{
+		provider:  provider,
+		ttl:       ttl,
+		now:       time.Now,
+		discovery: make(map[string]*kubernetes.CRDDiscovery),
+		cache:     make(map[string]completionCacheEntry),
+	}
+}
+
+// SetRBACAuthorizer sets the RBAC authorizer for completion lookups.
+func (c *Completer) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
+	c.rbacAuthorizer = authorizer
+	c.requireRBAC = requireRBAC
+}
+
+// SetAccessCheck sets a function that decides whether the caller in ctx may
+// see the values of a lookup. Lookups are checked as a call to the tool that
+// reads the completed resource: resources_get, pods_logs or helm_releases_list.
+func (c *Completer) SetAccessCheck(check func(ctx context.Context, toolName string, target Target) error) {
+	c.checkAccess = check
+}
+
+// SetContextCheck sets a function that decides whether the caller in ctx may
+// see a context, and what is served in it, as for SetAccessCheck.
+func (c *Completer) SetContextCheck(check func(ctx context.Context, toolName, contextName string) error) {
+	c.checkContext = check
+}
+
+// RegisterCompleter enables argument completion for prompts and resource templates.
+func (s *Server) RegisterCompleter(c *Completer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completer = c
+}
+
+// complete handles completion/complete. Lookups that fail (e.g. because the
+// caller may not list a resource) complete to no values rather than an error.
+func (s *Server) complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	s.mu.RLock()
+	c := s.completer
+	s.mu.RUnlock()
+
+	params := req.Params
+	if c == nil || params.Ref == nil {
+		return completionResult(nil, ""), nil
+	}
+	if req.Extra != nil {
+		ctx = auth.ContextWithTokenInfo(ctx, req.Extra.TokenInfo)
+	}
+
+	args := map[string]string{}
+	if params.Context != nil && params.Context.Arguments != nil {
+		args = params.Context.Arguments
+	}
+
+	var values []string
+	var err error
+	switch params.Ref.Type {
+	case "ref/prompt":
+		s.mu.RLock()
+		prompt, ok := s.prompts[params.Ref.Name]
+		s.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown prompt %q", params.Ref.Name)
+		}
+		values, err = c.completePromptArgument(ctx, prompt, params.Argument.Name, args)
+	case "ref/resource":
+		values, err = c.completeTemplateVariable(ctx, params.Ref.URI, params.Argument.Name, args)
+	}
+	if err != nil {
+		values = nil
+	}
+	return completionResult(values, params.Argument.Value), nil
+}
+
+// completionResult returns the sorted values starting with prefix
+// (case-insensitively), truncated to maxCompletionValues.
+func completionResult(values []string, prefix string) *mcp.CompleteResult {
+	prefix = strings.ToLower(prefix)
+	matches := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			matches = append(matches, value)
+		}
+	}
+	sort.Strings(matches)
+
+	result := &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{Values: matches, Total: len(matches)},
+	}
+	if len(matches) > maxCompletionValues {
+		result.Completion.Values = matches[:maxCompletionValues]
+		result.Completion.HasMore = true
+	}
+	return result
+}
+
+// completePromptArgument completes a prompt argument.
+func (c *Completer) completePromptArgument(ctx context.Context, prompt *Prompt, name string, args map[string]string) ([]string, error) {
+	switch name {
+	case "context":
+		return c.contexts(ctx, "resources_get")
+	case "namespace":
+		return c.namespaces(ctx, "resources_get", args["context"])
+	}
+	if gvk, ok := prompt.ObjectKinds[name]; ok {
+		return c.objectNames(ctx, "resources_get", args["context"], gvk, args["namespace"])
+	}
+	return nil, nil
+}
+
+// completeTemplateVariable completes a resource template variable.
+func (c *Completer) completeTemplateVariable(ctx context.Context, template, name string, args map[string]string) ([]string, error) {
+	switch name {
+	case "context":
+		return c.contexts(ctx, resourceTool(template))
+	case "namespace":
+		return c.namespaces(ctx, resourceTool(template), args["context"])
+	}
+
+	switch template {
+	case PodLogURITemplate:
+		if name == "name" {
+			return c.objectNames(ctx, resourceTool(template), args["context"], schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, args["namespace"])
+		}
+	case HelmReleaseURITemplate:
+		if name == "name" {
+			return c.helmReleases(ctx, args["context"], args["namespace"])
+		}
+	case ObjectURITemplate, ClusterObjectURITemplate:
+		group := args["group"]
+		if group == "core" {
+			group = ""
+		}
+		switch name {
+		case "group":
+			return c.discovered(ctx, resourceTool(template), args["context"], func(gvk schema.GroupVersionKind) string {
+				if gvk.Group == "" {
+					return "core"
+				}
+				return gvk.Group
+			}, nil)
+		case "version":
+			return c.discovered(ctx, resourceTool(template), args["context"], func(gvk schema.GroupVersionKind) string {
+				return gvk.Version
+			}, func(gvk schema.GroupVersionKind) bool {
+				return args["group"] == "" || gvk.Group == group
+			})
+		case "kind":
+			return c.discovered(ctx, resourceTool(template), args["context"], func(gvk schema.GroupVersionKind) string {
+				return gvk.Kind
+			}, func(gvk schema.GroupVersionKind) bool {
+				return (args["group"] == "" || gvk.Group == group) && (args["version"] == "" || gvk.Version == args["version"])
+			})
+		case "name":
+			if args["version"] == "" || args["kind"] == "" {
+				return nil, nil
+			}
+			gvk := schema.GroupVersionKind{Group: group, Version: args["version"], Kind: args["kind"]}
+			return c.objectNames(ctx, resourceTool(template), args["context"], gvk, args["namespace"])
+		}
+	}
+	return nil, nil
+}
+
+// discovered returns the distinct values of key over the API kinds served in
+// a context that match filter.
+func (c *Completer) discovered(ctx context.Context, toolName, contextName string, key func(schema.GroupVersionKind) string, filter func(schema.GroupVersionKind) bool) ([]string, error) {
+	if err := c.contextAccess(ctx, toolName, contextName); err != nil {
+		return nil, err
+	}
+	discovery, err := c.discoveryFor(contextName)
+	if err != nil {
+		return nil, err
+	}
+	if err := discovery.DiscoverCRDs(ctx); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	values := []string{}
+	for _, gvk := range discovery.ListCRDs() {
+		if filter != nil && !filter(gvk) {
+			continue
+		}
+		if value := key(gvk); !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// discoveryFor returns the API discovery cache for a context. Discovery uses
+// the server's credentials, since the set of served APIs is the same for
+// every caller.
+func (c *Completer) discoveryFor(contextName string) (*kubernetes.CRDDiscovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if discovery, ok := c.discovery[contextName]; ok {
+		return discovery, nil
+	}
+	clientSet, err := c.provider.GetClientSet(contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client set: %w", err)
+	}
+	discovery := kubernetes.NewCRDDiscovery(clientSet, c.ttl)
+	c.discovery[contextName] = discovery
+	return discovery, nil
+}
+
+// contexts returns the contexts the caller may use.
+func (c *Completer) contexts(ctx context.Context, toolName string) ([]string, error) {
+	contexts, err := c.provider.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+	allowed := []string{}
+	for _, contextName := range contexts {
+		if c.contextAccess(ctx, toolName, contextName) == nil {
+			allowed = append(allowed, contextName)
+		}
+	}
+	return allowed, nil
+}
+
+// namespaces returns the namespaces the caller can list and may use in a
+// context.
+func (c *Completer) namespaces(ctx context.Context, toolName, contextName string) ([]string, error) {
+	if err := c.contextAccess(ctx, toolName, contextName); err != nil {
+		return nil, err
+	}
+	key := strings.Join([]string{kubernetes.CallerKey(ctx), contextName, "namespaces"}, "|")
+	namespaces, err := c.cached(key, func() ([]string, error) {
+		clientSet, err := kubernetes.ClientSetForRequest(ctx, c.provider, contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client set: %w", err)
+		}
+		if err := c.checkRBAC(ctx, contextName, "list", namespacesGVR, ""); err != nil {
+			return nil, err
+		}
+
+		list, err := clientSet.Typed.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, ns := range list.Items {
+			names = append(names, ns.Name)
+		}
+		return names, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := []string{}
+	for _, namespace := range namespaces {
+		if c.access(ctx, toolName, Target{Context: contextName, Namespace: namespace}) == nil {
+			allowed = append(allowed, namespace)
+		}
+	}
+	return allowed, nil
+}
+
+// objectNames returns the names of objects of a kind. An empty namespace
+// lists namespaced kinds across all namespaces.
+func (c *Completer) objectNames(ctx context.Context, toolName, contextName string, gvk schema.GroupVersionKind, namespace string) ([]string, error) {
+	target := Target{Context: contextName, Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Namespace: namespace}
+	if err := c.access(ctx, toolName, target); err != nil {
+		return nil, err
+	}
+	key := strings.Join([]string{kubernetes.CallerKey(ctx), contextName, gvk.String(), namespace}, "|")
+	return c.cached(key, func() ([]string, error) {
+		clientSet, err := kubernetes.ClientSetForRequest(ctx, c.provider, contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client set: %w", err)
+		}
+		mapping, err := clientSet.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map GVK to GVR: %w", err)
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			namespace = ""
+		}
+		gvr := mapping.Resource
+
+		if err := c.checkRBAC(ctx, contextName, "list", gvr, namespace); err != nil {
+			return nil, err
+		}
+
+		list, err := clientSet.Dynamic.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			if name := item.GetName(); !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	})
+}
+
+// helmReleases returns the names of Helm releases, found through the labels
+// of Helm's release storage Secrets.
+func (c *Completer) helmReleases(ctx context.Context, contextName, namespace string) ([]string, error) {
+	if err := c.access(ctx, resourceTool(HelmReleaseURITemplate), Target{Context: contextName, Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	key := strings.Join([]string{kubernetes.CallerKey(ctx), contextName, "helm", namespace}, "|")
+	return c.cached(key, func() ([]string, error) {
+		clientSet, err := kubernetes.ClientSetForRequest(ctx, c.provider, contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client set: %w", err)
+		}
+		if err := c.checkRBAC(ctx, contextName, "list", secretsGVR, namespace); err != nil {
+			return nil, err
+		}
+
+		selector := labels.SelectorFromSet(labels.Set{"owner": "helm"})
+		list, err := clientSet.Typed.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		names := []string{}
+		for _, secret := range list.Items {
+			if name := secret.Labels["name"]; name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	})
+}
+
+// cached returns the cached values for key, loading them on a miss. Errors are
+// not cached.
+func (c *Completer) cached(key string, load func() ([]string, error)) ([]string, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.values, nil
+	}
+
+	values, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.cache {
+		if !now.Before(e.expiresAt) {
+			delete(c.cache, k)
+		}
+	}
+	c.cache[key] = completionCacheEntry{values: values, expiresAt: now.Add(c.ttl)}
+	return values, nil
+}
+
+// access runs the access check, if any, for a lookup.
+func (c *Completer) access(ctx context.Context, toolName string, target Target) error {
+	if c.checkAccess == nil {
+		return nil
+	}
+	return c.checkAccess(ctx, toolName, target)
+}
+
+// contextAccess runs the context check, if any, for a lookup in a context.
+func (c *Completer) contextAccess(ctx context.Context, toolName, contextName string) error {
+	if c.checkContext == nil {
+		return nil
+	}
+	return c.checkContext(ctx, toolName, contextName)
+}
+
+// checkRBAC checks that the caller may perform verb on gvr in a context.
+func (c *Completer) checkRBAC(ctx context.Context, contextName, verb string, gvr schema.GroupVersionResource, namespace string) error {
+	if !c.requireRBAC || c.rbacAuthorizer == nil {
+		return nil
+	}
+
+	allowed, err := c.rbacAuthorizer.Allowed(ctx, auth.IdentityFromContext(ctx), contextName, verb, gvr, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to check RBAC: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace)
+	}
+	return nil
+}
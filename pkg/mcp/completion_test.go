@@ -0,0 +1,223 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// CompletionTestSuite tests argument completion.
+type CompletionTestSuite struct {
+	suite.Suite
+	session        *mcp.ClientSession
+	completer      *mcpHelpers.Completer
+	closeSessions  func()
+	namespaceLists int
+	mappings       int
+}
+
+// countingMapper counts the mappings it looks up.
+type countingMapper struct {
+	meta.RESTMapper
+	mappings *int
+}
+
+func (m *countingMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	*m.mappings++
+	return m.RESTMapper.RESTMapping(gk, versions...)
+}
+
+// preferredDiscovery serves a fixed list of preferred resources.
+type preferredDiscovery struct {
+	discovery.DiscoveryInterface
+	lists []*metav1.APIResourceList
+}
+
+func (d *preferredDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.lists, nil
+}
+
+func (s *CompletionTestSuite) SetupTest() {
+	typed := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "sh.helm.release.v1.web.v1", Namespace: "shop",
+			Labels: map[string]string{"owner": "helm", "name": "web"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "sh.helm.release.v1.web.v2", Namespace: "shop",
+			Labels: map[string]string{"owner": "helm", "name": "web"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "shop"}},
+	)
+	s.namespaceLists = 0
+	s.mappings = 0
+	typed.PrependReactor("list", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		s.namespaceLists++
+		return false, nil, nil
+	})
+
+	pod := func(namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"},
+		pod("shop", "web-0"), pod("shop", "web-1"), pod("shop", "worker-0"), pod("staging", "web-0"))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+	clientSet := &kubernetes.ClientSet{
+		Typed:   typed,
+		Dynamic: dynamic,
+		Discovery: &preferredDiscovery{typed.Discovery(), []*metav1.APIResourceList{
+			{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod"}, {Name: "nodes", Kind: "Node"}}},
+			{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}},
+			{GroupVersion: "velero.io/v1", APIResources: []metav1.APIResource{{Name: "restores", Kind: "Restore"}}},
+		}},
+		RESTMapper: &countingMapper{RESTMapper: mapper, mappings: &s.mappings},
+	}
+
+	provider := &clientSetProvider{clientSet: clientSet}
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	s.Require().NoError(server.RegisterToolset(core.NewToolset(provider)))
+	server.RegisterResources(mcpHelpers.NewResourceProvider(provider))
+	s.completer = mcpHelpers.NewCompleter(provider, 0)
+	server.RegisterCompleter(s.completer)
+
+	s.session, s.closeSessions = connect(&s.Suite, server)
+}
+
+func (s *CompletionTestSuite) TearDownTest() {
+	s.closeSessions()
+}
+
+func (s *CompletionTestSuite) complete(ref *mcp.CompleteReference, name, value string, args map[string]string) []string {
+	result, err := s.session.Complete(context.Background(), &mcp.CompleteParams{
+		Ref:      ref,
+		Argument: mcp.CompleteParamsArgument{Name: name, Value: value},
+		Context:  &mcp.CompleteContext{Arguments: args},
+	})
+	s.Require().NoError(err)
+	return result.Completion.Values
+}
+
+// TestPromptArguments tests completing contexts, namespaces and object names for prompts.
+func (s *CompletionTestSuite) TestPromptArguments() {
+	ref := &mcp.CompleteReference{Type: "ref/prompt", Name: "troubleshoot_crashlooping_pod"}
+
+	s.Equal([]string{"test"}, s.complete(ref, "context", "", nil))
+	s.Equal([]string{"shop", "staging"}, s.complete(ref, "namespace", "s", nil))
+	s.Equal([]string{"web-0", "web-1"}, s.complete(ref, "name", "WEB", map[string]string{"namespace": "shop"}))
+	s.Equal([]string{"web-0", "web-1", "worker-0"}, s.complete(ref, "name", "", map[string]string{"namespace": "shop"}))
+
+	_, err := s.session.Complete(context.Background(), &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "missing"},
+		Argument: mcp.CompleteParamsArgument{Name: "name"},
+	})
+	s.Error(err)
+}
+
+// TestTemplateVariables tests completing resource template variables from discovery.
+func (s *CompletionTestSuite) TestTemplateVariables() {
+	ref := &mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.ObjectURITemplate}
+
+	s.Equal([]string{"apps", "core", "velero.io"}, s.complete(ref, "group", "", nil))
+	s.Equal([]string{"v1"}, s.complete(ref, "version", "", map[string]string{"group": "apps"}))
+	s.Equal([]string{"Node", "Pod"}, s.complete(ref, "kind", "", map[string]string{"group": "core", "version": "v1"}))
+	s.Equal([]string{"web-0"}, s.complete(ref, "name", "w", map[string]string{
+		"group": "core", "version": "v1", "kind": "Pod", "namespace": "staging",
+	}))
+
+	helmRef := &mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.HelmReleaseURITemplate}
+	s.Equal([]string{"web"}, s.complete(helmRef, "name", "", map[string]string{"namespace": "shop"}))
+}
+
+// TestUnknownKind tests that failed lookups complete to no values.
+func (s *CompletionTestSuite) TestUnknownKind() {
+	ref := &mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.ObjectURITemplate}
+	s.Empty(s.complete(ref, "name", "", map[string]string{"group": "example.com", "version": "v1", "kind": "Widget"}))
+}
+
+// TestAccessCheck tests that lookups are checked as the tool that reads the
+// completed resource.
+func (s *CompletionTestSuite) TestAccessCheck() {
+	var checked []string
+	s.completer.SetAccessCheck(func(_ context.Context, toolName string, target mcpHelpers.Target) error {
+		checked = append(checked, toolName+" "+target.Kind+" "+target.Namespace)
+		if toolName == "helm_releases_list" {
+			return errors.New("access denied")
+		}
+		return nil
+	})
+
+	logRef := &mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.PodLogURITemplate}
+	s.Equal([]string{"web-0"}, s.complete(logRef, "name", "", map[string]string{"namespace": "staging"}))
+	helmRef := &mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.HelmReleaseURITemplate}
+	s.Empty(s.complete(helmRef, "name", "", map[string]string{"namespace": "shop"}))
+	s.Equal([]string{"pods_logs Pod staging", "helm_releases_list  shop"}, checked)
+}
+
+// TestContextCheck tests that contexts and namespaces are filtered through
+// the access checks.
+func (s *CompletionTestSuite) TestContextCheck() {
+	ref := &mcp.CompleteReference{Type: "ref/prompt", Name: "troubleshoot_crashlooping_pod"}
+	s.completer.SetAccessCheck(func(_ context.Context, _ string, target mcpHelpers.Target) error {
+		if target.Namespace == "staging" {
+			return errors.New("access denied")
+		}
+		return nil
+	})
+	s.Equal([]string{"default", "shop"}, s.complete(ref, "namespace", "", nil))
+
+	s.completer.SetContextCheck(func(context.Context, string, string) error {
+		return errors.New("access denied")
+	})
+	s.Empty(s.complete(ref, "context", "", nil))
+	s.Empty(s.complete(ref, "namespace", "", nil))
+	s.Empty(s.complete(&mcp.CompleteReference{Type: "ref/resource", URI: mcpHelpers.ObjectURITemplate}, "group", "", nil))
+}
+
+// TestCaching tests that lookups are cached while typing.
+func (s *CompletionTestSuite) TestCaching() {
+	ref := &mcp.CompleteReference{Type: "ref/prompt", Name: "troubleshoot_crashlooping_pod"}
+	for _, value := range []string{"", "s", "sh", "sho"} {
+		s.complete(ref, "namespace", value, nil)
+	}
+	s.Equal(1, s.namespaceLists)
+
+	// Cached object names are returned without mapping the kind again
+	for _, value := range []string{"", "w", "we"} {
+		s.complete(ref, "name", value, map[string]string{"namespace": "shop"})
+	}
+	s.Equal(1, s.mappings)
+}
+
+// TestCompletionTestSuite runs the completion test suite.
+func TestCompletionTestSuite(t *testing.T) {
+	suite.Run(t, new(CompletionTestSuite))
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+)
+
+// ToolCall describes a single tool invocation as seen by tool middleware.
+type ToolCall struct {
+	// Name is the original (non-normalized) tool name.
+	Name string
+
+	// Tool is the tool definition published by the owning toolset's Tools().
+	// It falls back to the registered tool if the toolset does not list it.
+	Tool *mcp.Tool
+
+	// Toolset is the name of the toolset that publishes the tool, if known.
+	Toolset string
+
+	// Class is the tool's classification derived from its annotations.
+	Class ToolClass
+
+	// Request is the raw SDK request. It may be nil when handlers are invoked directly.
+	Request *mcp.CallToolRequest
+
+	// Arguments is a decoded, read-only view of the call arguments.
+	Arguments map[string]any
+
+	// ConfirmationReasons are added by middleware that requires the user to
+	// confirm the call, such as admission rules with the confirm action.
+	ConfirmationReasons []string
+}
+
+// StringArg returns a string argument, or an empty string if it is absent or not a string.
+func (c *ToolCall) StringArg(name string) string {
+	if c.Arguments == nil {
+		return ""
+	}
+	s, _ := c.Arguments[name].(string)
+	return s
+}
+
+// ToolHandlerFunc handles a tool call after middleware has run.
+type ToolHandlerFunc func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, error)
+
+// ToolMiddleware wraps a ToolHandlerFunc. Middleware may short-circuit a call by
+// returning a result without invoking next.
+type ToolMiddleware func(next ToolHandlerFunc) ToolHandlerFunc
+
+// ToolFilter decides whether a tool is registered at all. Returning false hides
+// the tool from tools/list and makes it uncallable.
+type ToolFilter func(tool *mcp.Tool) bool
+
+// ToolListFilter decides whether a registered tool is listed in a tools/list
+// response. Unlike a ToolFilter, it runs on every request, with the caller's
+// identity in ctx, so the tools listed can differ between sessions.
+type ToolListFilter func(ctx context.Context, tool *mcp.Tool, toolset string) bool
+
+// UseToolMiddleware appends middleware applied to every tool call.
+// Middleware runs in the order it was added.
+func (s *Server) UseToolMiddleware(middleware ...ToolMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolMiddleware = append(s.toolMiddleware, middleware...)
+}
+
+// AddToolFilter adds a filter consulted when tools are registered.
+// Filters must be added before toolsets are registered.
+func (s *Server) AddToolFilter(filter ToolFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolFilters = append(s.toolFilters, filter)
+}
+
+// AddToolListFilter adds a filter applied to every tools/list response.
+func (s *Server) AddToolListFilter(filter ToolListFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolListFilters = append(s.toolListFilters, filter)
+}
+
+// filterToolList is SDK middleware that removes tools rejected by a
+// ToolListFilter from tools/list responses.
+func (s *Server) filterToolList(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if err != nil || method != "tools/list" {
+			return result, err
+		}
+		list, ok := result.(*mcp.ListToolsResult)
+		if !ok {
+			return result, err
+		}
+
+		s.mu.RLock()
+		filters := make([]ToolListFilter, len(s.toolListFilters))
+		copy(filters, s.toolListFilters)
+		s.mu.RUnlock()
+		if len(filters) == 0 {
+			return result, err
+		}
+
+		if extra := req.GetExtra(); extra != nil {
+			ctx = auth.ContextWithTokenInfo(ctx, extra.TokenInfo)
+		}
+		tools := make([]*mcp.Tool, 0, len(list.Tools))
+		for _, tool := range list.Tools {
+			name := s.GetOriginalToolName(tool.Name)
+			def, ok := s.registry.GetTool(name)
+			if !ok {
+				def = tool
+			}
+			listed := true
+			for _, filter := range filters {
+				if !filter(ctx, def, s.registry.ToolsetOf(name)) {
+					listed = false
+					break
+				}
+			}
+			if listed {
+				tools = append(tools, tool)
+			}
+		}
+		list.Tools = tools
+		return list, nil
+	}
+}
+
+// toolAllowed reports whether all registered filters accept the tool.
+func (s *Server) toolAllowed(tool *mcp.Tool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, filter := range s.toolFilters {
+		if !filter(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// toolDefinition returns the toolset-published definition for a tool, falling
+// back to the tool passed at registration time.
+func (s *Server) toolDefinition(tool *mcp.Tool) *mcp.Tool {
+	if def, ok := s.registry.GetTool(tool.Name); ok {
+		return def
+	}
+	return tool
+}
+
+// chain builds the middleware chain around the final handler.
+func (s *Server) chain(final ToolHandlerFunc) ToolHandlerFunc {
+	s.mu.RLock()
+	middleware := make([]ToolMiddleware, len(s.toolMiddleware))
+	copy(middleware, s.toolMiddleware)
+	s.mu.RUnlock()
+
+	handler := final
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// wrapHandler runs the server's tool middleware around a typed SDK handler.
+func wrapHandler[In, Out any](s *Server, def *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	class := ClassifyTool(def)
+	return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+		// Expose the authenticated HTTP caller (if any) to middleware and handlers
+		if req != nil && req.Extra != nil {
+			ctx = auth.ContextWithTokenInfo(ctx, req.Extra.TokenInfo)
+		}
+		ctx = ContextWithProgress(ctx, newProgress(req))
+
+		var out Out
+		final := func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, error) {
+			result, o, err := handler(ctx, req, in)
+			out = o
+			return result, err
+		}
+
+		call := &ToolCall{
+			Name:      def.Name,
+			Tool:      def,
+			Toolset:   s.registry.ToolsetOf(def.Name),
+			Class:     class,
+			Request:   req,
+			Arguments: decodeArguments(req),
+		}
+		result, err := s.chain(final)(ctx, call)
+		return result, out, err
+	}
+}
+
+// decodeArguments decodes raw call arguments into a map.
+func decodeArguments(req *mcp.CallToolRequest) map[string]any {
+	args := make(map[string]any)
+	if req == nil || req.Params == nil || len(req.Params.Arguments) == 0 {
+		return args
+	}
+	_ = json.Unmarshal(req.Params.Arguments, &args)
+	return args
+}
@@ -0,0 +1,511 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// lastAppliedAnnotation holds a copy of the applied manifest, which repeats
+// the object in every result.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// outputParameters are the parameters every list and get tool accepts.
+var outputParameters = map[string]map[string]any{
+	"fields": {
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"description": "Fields to return of each item, as paths (e.g. metadata.name, status.phase) or JSONPath (e.g. {.spec.containers[*].image})",
+	},
+	"output": {
+		"type":        "string",
+		"enum":        []string{"json", "table"},
+		"description": "Output format: json (default), or a table with the columns kubectl shows for the kind",
+	},
+	"max_bytes": {
+		"type":        "integer",
+		"description": "Most bytes to return (default: server setting for lists); longer lists are cut and return a next_cursor, and longer objects fail",
+	},
+	"cursor": {
+		"type":        "string",
+		"description": "next_cursor of a previous call with the same arguments, to continue a cut list",
+	},
+}
+
+// ShapesOutput reports whether the results of a tool are shaped by the
+// OutputShaper: read-only tools named *_list or *_get.
+func ShapesOutput(tool *mcp.Tool) bool {
+	if tool == nil || tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+		return false
+	}
+	name := tool.Name[strings.LastIndex(tool.Name, ".")+1:]
+	return strings.HasSuffix(name, "_list") || strings.HasSuffix(name, "_get")
+}
+
+type objectsKey struct{}
+
+// WantsObjects reports whether the call in ctx projects fields or asks for a
+// table, so list and get tools that summarise Kubernetes objects should
+// return the objects whole for the output layer to shape.
+func WantsObjects(ctx context.Context) bool {
+	wants, _ := ctx.Value(objectsKey{}).(bool)
+	return wants
+}
+
+// OutputShaper shapes the JSON results of list and get tools in every
+// toolset: it strips managedFields and the last-applied annotation, projects
+// fields, renders tables and cuts results to a maximum size, with a cursor to
+// continue from.
+type OutputShaper struct {
+	maxBytes int
+}
+
+// NewOutputShaper creates an output shaper returning at most maxBytes per
+// call unless the call asks for less.
+func NewOutputShaper(maxBytes int) *OutputShaper {
+	return &OutputShaper{maxBytes: maxBytes}
+}
+
+// Middleware shapes the results of the tools ShapesOutput selects, and strips
+// the metadata of objects any tool streams.
+func (o *OutputShaper) Middleware() ToolMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, call *ToolCall) (*mcp.CallToolResult, error) {
+			ctx = ContextWithStreamFilter(ctx, stripItem)
+			if !ShapesOutput(call.Tool) {
+				return next(ctx, call)
+			}
+			shape, err := o.shapeOf(call.Tool, call.Arguments)
+			if err != nil {
+				return NewErrorResult(err), nil
+			}
+			if len(shape.fields) > 0 || shape.table {
+				ctx = context.WithValue(ctx, objectsKey{}, true)
+			}
+
+			result, err := next(ctx, call)
+			if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+				return result, err
+			}
+			text, ok := result.Content[0].(*mcp.TextContent)
+			if !ok {
+				return result, nil
+			}
+			shaped, err := shape.apply(text.Text)
+			if err != nil {
+				return NewErrorResult(err), nil
+			}
+			text.Text = shaped
+			return result, nil
+		}
+	}
+}
+
+// outputShape is how a call asked for its result to be shaped.
+type outputShape struct {
+	listTool  bool
+	fields    []string
+	paths     []*jsonpath.JSONPath
+	table     bool
+	maxBytes  int
+	requested bool // the call set max_bytes
+	offset    int
+	argsHash  string
+	version   string // of the list the cursor cut, then of the list shaped
+}
+
+// shapeOf returns the shape the output arguments of a call of tool ask for.
+func (o *OutputShaper) shapeOf(tool *mcp.Tool, args map[string]any) (*outputShape, error) {
+	shape := &outputShape{
+		listTool: strings.HasSuffix(tool.Name, "_list"),
+		maxBytes: o.maxBytes,
+		argsHash: argumentsHash(args),
+	}
+
+	if fields, ok := args["fields"].([]any); ok {
+		for _, field := range fields {
+			path, ok := field.(string)
+			if !ok || path == "" {
+				return nil, fmt.Errorf("fields must be non-empty strings")
+			}
+			parsed := jsonpath.New(path).AllowMissingKeys(true)
+			if err := parsed.Parse(jsonPathTemplate(path)); err != nil {
+				return nil, fmt.Errorf("invalid field %q: %w", path, err)
+			}
+			shape.fields = append(shape.fields, path)
+			shape.paths = append(shape.paths, parsed)
+		}
+	}
+
+	switch output, _ := args["output"].(string); output {
+	case "", "json":
+	case "table":
+		shape.table = true
+	default:
+		return nil, fmt.Errorf("invalid output %q: must be json or table", output)
+	}
+
+	if maxBytes, ok := args["max_bytes"].(float64); ok {
+		switch {
+		case maxBytes <= 0:
+			return nil, fmt.Errorf("max_bytes must be positive")
+		case o.maxBytes > 0 && int(maxBytes) > o.maxBytes:
+			return nil, fmt.Errorf("max_bytes of %d exceeds the limit of %d", int(maxBytes), o.maxBytes)
+		}
+		shape.maxBytes = int(maxBytes)
+		shape.requested = true
+	}
+
+	if cursor, _ := args["cursor"].(string); cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Args != shape.argsHash {
+			return nil, fmt.Errorf("cursor belongs to a call with different arguments")
+		}
+		shape.offset = c.Offset
+		shape.version = c.Version
+	}
+	return shape, nil
+}
+
+// jsonPathTemplate turns a path such as metadata.name or $.metadata.name
+// into a JSONPath template; templates in braces are used as they are.
+func jsonPathTemplate(path string) string {
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	path = strings.TrimPrefix(path, "$")
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+	return "{" + path + "}"
+}
+
+// argumentsHash identifies the arguments of a call other than its cursor,
+// so a cursor is only used to continue the call it came from.
+func argumentsHash(args map[string]any) string {
+	rest := make(map[string]any, len(args))
+	for key, value := range args {
+		if key != "cursor" {
+			rest[key] = value
+		}
+	}
+	// Map keys are marshalled in sorted order, so the encoding is stable
+	data, _ := json.Marshal(rest)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// cursor is the decoded form of a next_cursor.
+type cursor struct {
+	Offset  int    `json:"o"`
+	Args    string `json:"a"`
+	Version string `json:"v"`
+}
+
+func (s *outputShape) encodeCursor(offset int) string {
+	data, _ := json.Marshal(cursor{Offset: offset, Args: s.argsHash, Version: s.version})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	var c cursor
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.Offset < 0 {
+		return cursor{}, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
+
+// listVersion identifies the items of a list, so a cursor only continues the
+// list it cut: the same objects, in the same order, at the same
+// resourceVersions. Items without metadata are identified by their namespace
+// and name fields, or else whole.
+func listVersion(items []any) string {
+	hash := sha256.New()
+	for _, item := range items {
+		var identity any = item
+		obj, _ := item.(map[string]any)
+		if metadata, ok := obj["metadata"].(map[string]any); ok {
+			identity = []any{metadata["uid"], metadata["namespace"], metadata["name"], metadata["resourceVersion"]}
+		} else if name, ok := obj["name"]; ok {
+			identity = []any{obj["namespace"], name}
+		}
+		data, _ := json.Marshal(identity)
+		hash.Write(append(data, '\n'))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// apply shapes a result. Results that are not JSON are returned unchanged.
+func (s *outputShape) apply(text string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return text, nil
+	}
+
+	// The items of a list are the array of a result, or the list field of
+	// the result of a list tool; anything else is a single item
+	var container map[string]any
+	key := ""
+	var items []any
+	list := true
+	switch v := value.(type) {
+	case []any:
+		items = v
+	case map[string]any:
+		container = v
+		if s.listTool {
+			key = listKey(v)
+		}
+		if key != "" {
+			items = v[key].([]any)
+		} else {
+			items, list = []any{v}, false
+		}
+	default:
+		return text, nil
+	}
+
+	if list {
+		version := listVersion(items)
+		if s.offset > 0 && s.version != version {
+			return "", fmt.Errorf("the list changed since the cursor was returned; call again without cursor")
+		}
+		s.version = version
+	}
+
+	stripped := false
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok && stripMetadata(obj) {
+			stripped = true
+		}
+	}
+	if !stripped && len(s.fields) == 0 && !s.table && s.offset == 0 && (s.maxBytes <= 0 || len(text) <= s.maxBytes) {
+		return text, nil
+	}
+
+	if s.offset > len(items) || (!list && s.offset > 0) {
+		return "", fmt.Errorf("cursor is past the end of the result")
+	}
+	// A single object cannot be cut, so only a limit the call set applies
+	if !list && !s.requested {
+		s.maxBytes = 0
+	}
+	items = items[s.offset:]
+	if len(s.fields) > 0 {
+		projected := make([]any, len(items))
+		for i, item := range items {
+			projected[i] = s.project(item)
+		}
+		items = projected
+	}
+
+	if s.table {
+		return s.renderTable(container, key, items)
+	}
+	if !list {
+		return s.fit(items[0])
+	}
+	return s.fitList(container, key, items)
+}
+
+// listKey returns the field of a list tool's result holding its items: the
+// items of a Kubernetes list, or else the only array of objects, which may be
+// empty. It returns "" if there is no such field.
+func listKey(obj map[string]any) string {
+	if _, ok := obj["items"].([]any); ok {
+		return "items"
+	}
+	key := ""
+	for k, v := range obj {
+		values, ok := v.([]any)
+		if !ok || !allObjects(values) {
+			continue
+		}
+		if key != "" {
+			return ""
+		}
+		key = k
+	}
+	return key
+}
+
+func allObjects(values []any) bool {
+	for _, value := range values {
+		if _, ok := value.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// stripMetadata removes managedFields and the last-applied annotation from
+// an object. It reports whether anything was removed.
+func stripMetadata(obj map[string]any) bool {
+	metadata, ok := obj["metadata"].(map[string]any)
+	if !ok {
+		return false
+	}
+	_, stripped := metadata["managedFields"]
+	delete(metadata, "managedFields")
+	if annotations, ok := metadata["annotations"].(map[string]any); ok {
+		if _, found := annotations[lastAppliedAnnotation]; found {
+			delete(annotations, lastAppliedAnnotation)
+			stripped = true
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return stripped
+}
+
+// stripItem strips the metadata of a streamed object, or of the object of a
+// watch event.
+func stripItem(item any) any {
+	if obj, ok := item.(map[string]any); ok {
+		stripMetadata(obj)
+		if object, ok := obj["object"].(map[string]any); ok {
+			stripMetadata(object)
+		}
+	}
+	return item
+}
+
+// project returns the selected fields of an item, by path. Fields with one
+// value map to it, fields with several to a list; missing fields are left
+// out.
+func (s *outputShape) project(item any) any {
+	projected := make(map[string]any, len(s.fields))
+	for i, path := range s.paths {
+		results, err := path.FindResults(item)
+		if err != nil {
+			continue
+		}
+		var values []any
+		for _, result := range results {
+			for _, value := range result {
+				values = append(values, jsonValue(value))
+			}
+		}
+		switch len(values) {
+		case 0:
+		case 1:
+			projected[s.fields[i]] = values[0]
+		default:
+			projected[s.fields[i]] = values
+		}
+	}
+	return projected
+}
+
+// jsonValue returns the value a JSONPath result holds.
+func jsonValue(value reflect.Value) any {
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// fit encodes a single item, which cannot be cut, failing if it exceeds the
+// max_bytes the call set.
+func (s *outputShape) fit(item any) (string, error) {
+	data, err := marshal(item)
+	if err != nil {
+		return "", err
+	}
+	if s.maxBytes > 0 && len(data) > s.maxBytes {
+		return "", fmt.Errorf("result of %d bytes exceeds max_bytes of %d; select fewer fields with fields", len(data), s.maxBytes)
+	}
+	return string(data), nil
+}
+
+// fitList encodes the items of a list in their container, cutting them to
+// max_bytes and adding a next_cursor if they do not fit. A cut top-level
+// array is returned in the items field of an object.
+func (s *outputShape) fitList(container map[string]any, key string, items []any) (string, error) {
+	if key == "" {
+		key = "items"
+	}
+	encode := func(n int) ([]byte, error) {
+		if container == nil && n == len(items) {
+			return marshal(items)
+		}
+		result := make(map[string]any, len(container)+3)
+		for k, v := range container {
+			result[k] = v
+		}
+		result[key] = items[:n]
+		if n < len(items) {
+			result["truncated"] = true
+			result["next_cursor"] = s.encodeCursor(s.offset + n)
+		}
+		return marshal(result)
+	}
+
+	data, err := encode(len(items))
+	if err != nil || s.maxBytes <= 0 || len(data) <= s.maxBytes {
+		return string(data), err
+	}
+
+	// Keep the items whose encodings fit, then drop more while the
+	// container and cursor push the result over the limit
+	n, size := 0, 0
+	for ; n < len(items); n++ {
+		encoded, err := marshal(items[n])
+		if err != nil {
+			return "", err
+		}
+		if size += len(encoded) + 1; size > s.maxBytes {
+			break
+		}
+	}
+	for ; n > 0; n-- {
+		if data, err = encode(n); err != nil || len(data) <= s.maxBytes {
+			return string(data), err
+		}
+	}
+	return "", fmt.Errorf("an item exceeds max_bytes of %d; select fewer fields with fields", s.maxBytes)
+}
+
+// marshal encodes a value as JSON without escaping HTML characters.
+func marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// OutputTestSuite tests stripping, projection, tables and size limits of list
+// and get results.
+type OutputTestSuite struct {
+	suite.Suite
+	list *mcp.Tool
+	get  *mcp.Tool
+}
+
+func (s *OutputTestSuite) SetupTest() {
+	s.list = mcpHelpers.NewTool("pods_list", "List pods").WithReadOnly().Build()
+	s.get = mcpHelpers.NewTool("pods_get", "Get a pod").WithReadOnly().Build()
+}
+
+// pod returns a pod as the API serves it, with managedFields and the
+// last-applied annotation.
+func pod(name, namespace, phase string, restarts int) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"name":              name,
+			"namespace":         namespace,
+			"creationTimestamp": time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			"managedFields":     []any{map[string]any{"manager": "kubectl"}},
+			"annotations": map[string]any{
+				"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Pod"}`,
+			},
+		},
+		"spec": map[string]any{
+			"containers": []any{map[string]any{"name": "app", "image": "nginx:1.27"}, map[string]any{"name": "proxy", "image": "envoy:1.31"}},
+		},
+		"status": map[string]any{
+			"phase": phase,
+			"containerStatuses": []any{
+				map[string]any{"name": "app", "ready": true, "restartCount": restarts},
+				map[string]any{"name": "proxy", "ready": phase == "Running", "restartCount": 0},
+			},
+		},
+	}
+}
+
+// call runs a call of tool with args through the shaper, whose handler
+// returns result and records whether objects were asked for.
+func (s *OutputTestSuite) call(shaper *mcpHelpers.OutputShaper, tool *mcp.Tool, args map[string]any, result any) (string, bool, bool) {
+	wantsObjects := false
+	handler := shaper.Middleware()(func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		wantsObjects = mcpHelpers.WantsObjects(ctx)
+		return mcpHelpers.NewJSONResult(result)
+	})
+	res, err := handler(context.Background(), &mcpHelpers.ToolCall{Name: tool.Name, Tool: tool, Arguments: args})
+	s.Require().NoError(err)
+	return res.Content[0].(*mcp.TextContent).Text, res.IsError, wantsObjects
+}
+
+// TestShapesOutput tests which tools are shaped.
+func (s *OutputTestSuite) TestShapesOutput() {
+	s.True(mcpHelpers.ShapesOutput(s.list))
+	s.True(mcpHelpers.ShapesOutput(mcpHelpers.NewTool("certs.certificate_get", "").WithReadOnly().Build()))
+	s.False(mcpHelpers.ShapesOutput(mcpHelpers.NewTool("pods_logs", "").WithReadOnly().Build()))
+	s.False(mcpHelpers.ShapesOutput(mcpHelpers.NewTool("fake_list", "").WithDestructive().Build()))
+}
+
+// TestStrip tests that managedFields and the last-applied annotation are
+// removed, and that other results pass unchanged.
+func (s *OutputTestSuite) TestStrip() {
+	shaper := mcpHelpers.NewOutputShaper(0)
+	text, isError, wantsObjects := s.call(shaper, s.get, nil, pod("web", "shop", "Running", 0))
+	s.False(isError)
+	s.False(wantsObjects)
+	s.NotContains(text, "managedFields")
+	s.NotContains(text, "last-applied-configuration")
+	s.NotContains(text, "annotations")
+	s.Contains(text, `"image":"nginx:1.27"`)
+
+	summary := map[string]any{"pods": []any{map[string]any{"name": "web", "status": "Running"}}}
+	text, _, _ = s.call(shaper, s.list, nil, summary)
+	s.JSONEq(`{"pods":[{"name":"web","status":"Running"}]}`, text)
+}
+
+// TestFields tests projection by path and by JSONPath.
+func (s *OutputTestSuite) TestFields() {
+	shaper := mcpHelpers.NewOutputShaper(0)
+	args := map[string]any{"fields": []any{"metadata.name", "{.spec.containers[*].image}", "status.reason"}}
+	text, isError, wantsObjects := s.call(shaper, s.list, args, map[string]any{
+		"pods":     []any{pod("web", "shop", "Running", 0)},
+		"continue": "abc",
+	})
+	s.Require().False(isError, text)
+	s.True(wantsObjects)
+	s.JSONEq(`{"pods":[{"metadata.name":"web","{.spec.containers[*].image}":["nginx:1.27","envoy:1.31"]}],"continue":"abc"}`, text)
+
+	text, isError, _ = s.call(shaper, s.get, map[string]any{"fields": []any{"status.phase"}}, pod("web", "shop", "Running", 0))
+	s.Require().False(isError, text)
+	s.JSONEq(`{"status.phase":"Running"}`, text)
+
+	// Arrays in the result of a get are fields, not a list
+	text, isError, _ = s.call(shaper, s.get, map[string]any{"fields": []any{"name"}}, map[string]any{"name": "web", "events": []any{}})
+	s.Require().False(isError, text)
+	s.JSONEq(`{"name":"web"}`, text)
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"fields": []any{"name"}}, map[string]any{
+		"items":    []any{map[string]any{"name": "web", "phase": "Running"}},
+		"warnings": []any{},
+	})
+	s.Require().False(isError, text)
+	s.JSONEq(`{"items":[{"name":"web"}],"warnings":[]}`, text)
+
+	text, isError, _ = s.call(shaper, s.get, map[string]any{"fields": []any{"{.spec["}}, pod("web", "shop", "Running", 0))
+	s.True(isError)
+	s.Contains(text, "invalid field")
+}
+
+// TestTable tests kubectl-like columns per kind, selected fields as columns
+// and summaries without a kind.
+func (s *OutputTestSuite) TestTable() {
+	shaper := mcpHelpers.NewOutputShaper(0)
+	args := map[string]any{"output": "table"}
+	text, isError, wantsObjects := s.call(shaper, s.list, args, map[string]any{
+		"pods": []any{pod("web", "shop", "Running", 3), pod("worker", "jobs", "Pending", 0)},
+	})
+	s.Require().False(isError, text)
+	s.True(wantsObjects)
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	s.Require().Len(lines, 3)
+	s.Equal([]string{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE"}, strings.Fields(lines[0]))
+	s.Equal([]string{"shop", "web", "2/2", "Running", "3", "2h"}, strings.Fields(lines[1]))
+	s.Equal([]string{"jobs", "worker", "1/2", "Pending", "0", "2h"}, strings.Fields(lines[2]))
+
+	args["fields"] = []any{"metadata.name", "status.phase"}
+	text, _, _ = s.call(shaper, s.list, args, map[string]any{
+		"pods":     []any{pod("web", "shop", "Running", 0)},
+		"continue": "abc",
+	})
+	s.Equal([]string{"metadata.name", "status.phase", "web", "Running", "continue:", "abc"}, strings.Fields(text))
+
+	text, _, _ = s.call(shaper, mcpHelpers.NewTool("config_contexts_list", "").WithReadOnly().Build(),
+		map[string]any{"output": "table"}, map[string]any{
+			"contexts": []any{map[string]any{"name": "prod", "current": true, "cluster": "eu-1"}},
+		})
+	s.Equal([]string{"NAME", "CLUSTER", "CURRENT", "prod", "eu-1", "true"}, strings.Fields(text))
+
+	text, _, _ = s.call(shaper, s.list, map[string]any{"output": "table"}, map[string]any{"pods": []any{}})
+	s.Equal("No resources found.\n", text)
+}
+
+// TestMaxBytes tests that lists are cut with a cursor continuing them, and
+// that cursors only continue the call they came from.
+func (s *OutputTestSuite) TestMaxBytes() {
+	items := make([]any, 20)
+	for i := range items {
+		items[i] = map[string]any{"name": strings.Repeat("x", 40), "index": i}
+	}
+	result := map[string]any{"pods": items}
+	shaper := mcpHelpers.NewOutputShaper(1000)
+
+	text, isError, _ := s.call(shaper, s.list, map[string]any{"max_bytes": 400.0}, result)
+	s.Require().False(isError, text)
+	s.LessOrEqual(len(text), 400)
+	var page struct {
+		Pods       []map[string]any `json:"pods"`
+		Truncated  bool             `json:"truncated"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(text), &page))
+	s.True(page.Truncated)
+	s.NotEmpty(page.Pods)
+	first := len(page.Pods)
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"max_bytes": 400.0, "cursor": page.NextCursor}, result)
+	s.Require().False(isError, text)
+	page.Pods = nil
+	s.Require().NoError(json.Unmarshal([]byte(text), &page))
+	s.Equal(float64(first), page.Pods[0]["index"])
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"max_bytes": 300.0, "cursor": page.NextCursor}, result)
+	s.True(isError)
+	s.Contains(text, "different arguments")
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"max_bytes": 400.0, "cursor": page.NextCursor},
+		map[string]any{"pods": items[1:]})
+	s.True(isError)
+	s.Contains(text, "list changed")
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"output": "table", "max_bytes": 300.0}, result)
+	s.Require().False(isError, text)
+	s.LessOrEqual(len(text), 300)
+	s.Contains(text, "more; call again with cursor")
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"max_bytes": 2000.0}, result)
+	s.True(isError)
+	s.Contains(text, "exceeds the limit of 1000")
+
+	text, isError, _ = s.call(shaper, s.get, map[string]any{"max_bytes": 50.0}, pod("web", "shop", "Running", 0))
+	s.True(isError)
+	s.Contains(text, "select fewer fields")
+
+	// The server's limit cuts lists, but does not fail single objects
+	text, isError, _ = s.call(mcpHelpers.NewOutputShaper(100), s.get, nil, pod("web", "shop", "Running", 0))
+	s.Require().False(isError, text)
+	s.Greater(len(text), 100)
+	s.Contains(text, `"image":"nginx:1.27"`)
+
+	text, isError, _ = s.call(shaper, s.list, map[string]any{"cursor": "nope"}, result)
+	s.True(isError)
+	s.Contains(text, "invalid cursor")
+}
+
+func TestOutputTestSuite(t *testing.T) {
+	suite.Run(t, new(OutputTestSuite))
+}
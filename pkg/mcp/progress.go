@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultWaitTimeout is how long mutations with wait=true wait by default.
+const DefaultWaitTimeout = 5 * time.Minute
+
+// Progress reports the progress of a tool call to the client with
+// notifications/progress. Reports are only sent when the call carries a
+// progress token; otherwise they are dropped, so handlers can report
+// unconditionally.
+//
+// Cancellation needs no helper: the SDK cancels the handler's context when
+// the client sends notifications/cancelled, so long-running handlers only
+// need to honour ctx.
+type Progress struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu       sync.Mutex
+	progress float64
+}
+
+type progressKey struct{}
+
+// newProgress returns a reporter for the tool call.
+func newProgress(req *mcp.CallToolRequest) *Progress {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return &Progress{}
+	}
+	return &Progress{session: req.Session, token: req.Params.GetProgressToken()}
+}
+
+// ContextWithProgress returns a context carrying the progress reporter.
+func ContextWithProgress(ctx context.Context, p *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// ProgressFromContext returns the progress reporter of the tool call in ctx.
+// It never returns nil; without a reporter, reports are dropped.
+func ProgressFromContext(ctx context.Context) *Progress {
+	if p, ok := ctx.Value(progressKey{}).(*Progress); ok && p != nil {
+		return p
+	}
+	return &Progress{}
+}
+
+// Report sends a progress notification. A total of 0 means the total is
+// unknown. Progress must increase, so reports that do not are dropped.
+func (p *Progress) Report(ctx context.Context, progress, total float64, message string) {
+	if p.session == nil || p.token == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if progress <= p.progress {
+		p.mu.Unlock()
+		return
+	}
+	p.progress = progress
+	p.mu.Unlock()
+
+	// Progress is best effort; a failed notification must not fail the call
+	_ = p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
+// WaitFor calls check every interval until it reports done, timeout expires
+// or ctx is cancelled, reporting elapsed seconds and check's message as
+// progress. A non-positive timeout selects DefaultWaitTimeout.
+func WaitFor(ctx context.Context, interval, timeout time.Duration, check func(ctx context.Context) (done bool, message string, err error)) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	progress := ProgressFromContext(ctx)
+	start := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, message, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		progress.Report(ctx, time.Since(start).Seconds(), timeout.Seconds(), message)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait cancelled: %w", ctx.Err())
+		case <-deadline.C:
+			return fmt.Errorf("timed out after %s waiting: %s", timeout, message)
+		case <-ticker.C:
+		}
+	}
+}
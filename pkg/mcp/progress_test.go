@@ -0,0 +1,246 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// ProgressTestSuite tests progress reporting and cancellation of tool calls.
+type ProgressTestSuite struct {
+	suite.Suite
+	mu            sync.Mutex
+	notifications []*mcp.ProgressNotificationParams
+}
+
+func (s *ProgressTestSuite) SetupTest() {
+	s.mu.Lock()
+	s.notifications = nil
+	s.mu.Unlock()
+}
+
+// connect connects a client that records progress notifications.
+func (s *ProgressTestSuite) connect(server *mcpHelpers.Server) (*mcp.ClientSession, func()) {
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.notifications = append(s.notifications, req.Params)
+		},
+	})
+	session, err := client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+
+	return session, func() {
+		session.Close()
+		serverSession.Close()
+	}
+}
+
+func (s *ProgressTestSuite) received() []*mcp.ProgressNotificationParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*mcp.ProgressNotificationParams(nil), s.notifications...)
+}
+
+// newServer registers a tool that waits for three polls.
+func (s *ProgressTestSuite) newServer() *mcpHelpers.Server {
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	mcpHelpers.AddTool(server.GetSDKServer(), &mcp.Tool{Name: "wait", Description: "Wait for three polls"},
+		func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+			polls := 0
+			err := mcpHelpers.WaitFor(ctx, time.Millisecond, time.Minute, func(context.Context) (bool, string, error) {
+				polls++
+				return polls == 3, "polling", nil
+			})
+			if err != nil {
+				return mcpHelpers.NewErrorResult(err), nil, nil
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil, nil
+		})
+	return server
+}
+
+// TestProgressReported tests that a call with a progress token receives progress notifications.
+func (s *ProgressTestSuite) TestProgressReported() {
+	session, closeSessions := s.connect(s.newServer())
+	defer closeSessions()
+
+	// SetProgressToken only works on existing metadata
+	params := &mcp.CallToolParams{Meta: mcp.Meta{}, Name: "wait", Arguments: map[string]any{}}
+	params.SetProgressToken("token-1")
+	result, err := session.CallTool(context.Background(), params)
+	s.Require().NoError(err)
+	s.Require().False(result.IsError)
+
+	s.Require().Eventually(func() bool { return len(s.received()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	last := 0.0
+	for _, notification := range s.received() {
+		s.Equal("token-1", notification.ProgressToken)
+		s.Equal(time.Minute.Seconds(), notification.Total)
+		s.Equal("polling", notification.Message)
+		s.Greater(notification.Progress, last, "progress must increase")
+		last = notification.Progress
+	}
+}
+
+// TestProgressWithoutToken tests that calls without a progress token receive no notifications.
+func (s *ProgressTestSuite) TestProgressWithoutToken() {
+	session, closeSessions := s.connect(s.newServer())
+	defer closeSessions()
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "wait", Arguments: map[string]any{}})
+	s.Require().NoError(err)
+	s.Require().False(result.IsError)
+	s.Empty(s.received())
+}
+
+// TestWaitFor tests WaitFor's completion, error, timeout and cancellation.
+func (s *ProgressTestSuite) TestWaitFor() {
+	ctx := context.Background()
+
+	s.NoError(mcpHelpers.WaitFor(ctx, time.Millisecond, time.Second, func(context.Context) (bool, string, error) {
+		return true, "", nil
+	}))
+
+	checkErr := errors.New("boom")
+	s.ErrorIs(mcpHelpers.WaitFor(ctx, time.Millisecond, time.Second, func(context.Context) (bool, string, error) {
+		return false, "", checkErr
+	}), checkErr)
+
+	err := mcpHelpers.WaitFor(ctx, time.Millisecond, 20*time.Millisecond, func(context.Context) (bool, string, error) {
+		return false, "0/3 ready", nil
+	})
+	s.Require().Error(err)
+	s.Contains(err.Error(), "timed out")
+	s.Contains(err.Error(), "0/3 ready")
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	polls := 0
+	err = mcpHelpers.WaitFor(cancelCtx, time.Millisecond, time.Minute, func(context.Context) (bool, string, error) {
+		polls++
+		if polls == 2 {
+			cancel()
+		}
+		return false, "", nil
+	})
+	s.ErrorIs(err, context.Canceled)
+}
+
+// newStreamServer registers a tool that streams the numbers from 1 to count
+// within limits.
+func (s *ProgressTestSuite) newStreamServer(count int, limits mcpHelpers.StreamLimits) *mcpHelpers.Server {
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	mcpHelpers.AddTool(server.GetSDKServer(), &mcp.Tool{Name: "count", Description: "Stream numbers"},
+		func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+			summary, err := mcpHelpers.Stream(ctx, limits, func(ctx context.Context, emit func(item any) bool) error {
+				for i := 1; i <= count; i++ {
+					if !emit(strconv.Itoa(i)) {
+						return nil
+					}
+				}
+				<-ctx.Done()
+				return nil
+			})
+			if err != nil {
+				return mcpHelpers.NewErrorResult(err), nil, nil
+			}
+			result, err := mcpHelpers.NewJSONResult(summary)
+			return result, nil, err
+		})
+	return server
+}
+
+// callStream calls the count tool, with a progress token if token is set.
+func (s *ProgressTestSuite) callStream(server *mcpHelpers.Server, token bool) mcpHelpers.StreamSummary {
+	session, closeSessions := s.connect(server)
+	defer closeSessions()
+
+	params := &mcp.CallToolParams{Meta: mcp.Meta{}, Name: "count", Arguments: map[string]any{}}
+	if token {
+		params.SetProgressToken("stream-1")
+	}
+	result, err := session.CallTool(context.Background(), params)
+	s.Require().NoError(err)
+	s.Require().False(result.IsError)
+	var summary mcpHelpers.StreamSummary
+	s.Require().NoError(json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &summary))
+	return summary
+}
+
+// TestStream tests that streamed items arrive as progress notifications in
+// order, and stop at the item limit.
+func (s *ProgressTestSuite) TestStream() {
+	summary := s.callStream(s.newStreamServer(500, mcpHelpers.StreamLimits{Duration: time.Minute, MaxItems: 300}), true)
+	s.True(summary.Streamed)
+	s.Equal(300, summary.Items)
+	s.Equal(mcpHelpers.StreamMaxItems, summary.Reason)
+	s.Empty(summary.Collected)
+
+	var lines []string
+	s.Require().Eventually(func() bool {
+		lines = nil
+		for _, notification := range s.received() {
+			s.Equal(300.0, notification.Total)
+			lines = append(lines, strings.Split(notification.Message, "\n")...)
+		}
+		return len(lines) == 300
+	}, 5*time.Second, 10*time.Millisecond)
+	for i, line := range lines {
+		s.Equal(strconv.Itoa(i+1), line)
+	}
+}
+
+// TestStreamWithoutToken tests that items are collected for calls without a
+// progress token, and that streams stop at their duration.
+func (s *ProgressTestSuite) TestStreamWithoutToken() {
+	summary := s.callStream(s.newStreamServer(3, mcpHelpers.StreamLimits{Duration: 50 * time.Millisecond}), false)
+	s.False(summary.Streamed)
+	s.Equal(3, summary.Items)
+	s.Equal([]any{"1", "2", "3"}, summary.Collected)
+	s.Equal(mcpHelpers.StreamMaxDuration, summary.Reason)
+	s.Empty(s.received())
+}
+
+// TestStreamCancelled tests that cancelling the call stops the producer.
+func (s *ProgressTestSuite) TestStreamCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := mcpHelpers.Stream(ctx, mcpHelpers.StreamLimits{}, func(ctx context.Context, emit func(item any) bool) error {
+		defer close(stopped)
+		for emit("line") {
+		}
+		return nil
+	})
+	s.ErrorIs(err, context.Canceled)
+	s.Require().Eventually(func() bool {
+		select {
+		case <-stopped:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+}
+
+func TestProgressTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressTestSuite))
+}
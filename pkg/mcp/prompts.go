@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PromptProvider is implemented by toolsets that contribute MCP prompts.
+type PromptProvider interface {
+	// Prompts returns all prompts provided by this toolset.
+	Prompts() []*Prompt
+}
+
+// Prompt is a prompt contributed by a toolset.
+// Messages reference tools by their original names in backticks (e.g.
+// `policy.violations_list`); the server rewrites them to the names clients see.
+type Prompt struct {
+	// Prompt is the definition published in prompts/list.
+	Prompt *mcp.Prompt
+
+	// ObjectKinds maps arguments that name a Kubernetes object to the object's
+	// kind, so the argument can be completed.
+	ObjectKinds map[string]schema.GroupVersionKind
+
+	// Tools lists the tools the prompt relies on. The prompt is only
+	// registered when all of them are registered and allowed.
+	Tools []string
+
+	// Render returns the user messages for the given arguments.
+	// Required arguments are validated before Render is called.
+	Render func(args map[string]string) []string
+}
+
+// PromptBuilder helps build prompts with consistent patterns.
+type PromptBuilder struct {
+	prompt *Prompt
+}
+
+// NewPrompt creates a new prompt builder.
+func NewPrompt(name, title, description string) *PromptBuilder {
+	return &PromptBuilder{
+		prompt: &Prompt{
+			Prompt: &mcp.Prompt{
+				Name:        name,
+				Title:       title,
+				Description: description,
+			},
+		},
+	}
+}
+
+// WithArgument adds an argument to the prompt.
+func (b *PromptBuilder) WithArgument(name, description string, required bool) *PromptBuilder {
+	b.prompt.Prompt.Arguments = append(b.prompt.Prompt.Arguments, &mcp.PromptArgument{
+		Name:        name,
+		Description: description,
+		Required:    required,
+	})
+	return b
+}
+
+// WithObjectArgument adds an argument naming an object of the given kind.
+// Objects are looked up in the namespace given by the "namespace" argument.
+func (b *PromptBuilder) WithObjectArgument(name, description string, gvk schema.GroupVersionKind, required bool) *PromptBuilder {
+	if b.prompt.ObjectKinds == nil {
+		b.prompt.ObjectKinds = make(map[string]schema.GroupVersionKind)
+	}
+	b.prompt.ObjectKinds[name] = gvk
+	return b.WithArgument(name, description, required)
+}
+
+// WithContextArgument adds the optional Kubernetes context argument.
+func (b *PromptBuilder) WithContextArgument() *PromptBuilder {
+	return b.WithArgument("context", "Kubernetes context name (default: current context)", false)
+}
+
+// WithTools sets the tools the prompt relies on.
+func (b *PromptBuilder) WithTools(tools ...string) *PromptBuilder {
+	b.prompt.Tools = tools
+	return b
+}
+
+// WithRender sets the function that renders the prompt's messages.
+func (b *PromptBuilder) WithRender(render func(args map[string]string) []string) *PromptBuilder {
+	b.prompt.Render = render
+	return b
+}
+
+// Build returns the built prompt.
+func (b *PromptBuilder) Build() *Prompt {
+	return b.prompt
+}
+
+// ContextClause describes the Kubernetes context for prompt text, e.g.
+// " in context prod", or an empty string for the current context.
+func ContextClause(args map[string]string) string {
+	if args["context"] == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in context %s", args["context"])
+}
+
+// registerPrompts registers the prompts of a toolset implementing PromptProvider.
+// Prompts relying on tools that are not registered or are hidden by tool
+// filters are skipped.
+func (s *Server) registerPrompts(toolset Toolset) {
+	provider, ok := toolset.(PromptProvider)
+	if !ok {
+		return
+	}
+
+	for _, prompt := range provider.Prompts() {
+		if !s.promptToolsAvailable(prompt) {
+			continue
+		}
+		s.mu.Lock()
+		s.prompts[prompt.Prompt.Name] = prompt
+		s.mu.Unlock()
+		s.sdkServer.AddPrompt(prompt.Prompt, s.promptHandler(prompt))
+	}
+}
+
+// promptToolsAvailable reports whether every tool a prompt relies on is registered and allowed.
+func (s *Server) promptToolsAvailable(prompt *Prompt) bool {
+	for _, name := range prompt.Tools {
+		tool, ok := s.registry.GetTool(name)
+		if !ok || !s.toolAllowed(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// promptHandler validates arguments and renders a prompt.
+func (s *Server) promptHandler(prompt *Prompt) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		if args == nil {
+			args = map[string]string{}
+		}
+		for _, arg := range prompt.Prompt.Arguments {
+			if arg.Required && args[arg.Name] == "" {
+				return nil, fmt.Errorf("missing required argument %q", arg.Name)
+			}
+		}
+
+		result := &mcp.GetPromptResult{
+			Description: prompt.Prompt.Description,
+			Messages:    []*mcp.PromptMessage{},
+		}
+		for _, text := range prompt.Render(args) {
+			result.Messages = append(result.Messages, &mcp.PromptMessage{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: s.rewriteToolNames(text)},
+			})
+		}
+		return result, nil
+	}
+}
+
+// rewriteToolNames replaces backticked tool names with their normalized names
+// when tool name normalization is enabled.
+func (s *Server) rewriteToolNames(text string) string {
+	if !s.normalizeToolNames {
+		return text
+	}
+	for _, tool := range s.registry.ListTools() {
+		if strings.Contains(tool.Name, ".") {
+			text = strings.ReplaceAll(text, "`"+tool.Name+"`", "`"+strings.ReplaceAll(tool.Name, ".", "_")+"`")
+		}
+	}
+	return text
+}
@@ -0,0 +1,115 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// PromptsTestSuite tests the prompts contributed by toolsets.
+type PromptsTestSuite struct {
+	suite.Suite
+}
+
+// newServer registers all toolsets on a new server.
+func (s *PromptsTestSuite) newServer(normalize bool, filters ...mcpHelpers.ToolFilter) *mcpHelpers.Server {
+	server := mcpHelpers.NewServer("test", "1.0.0", normalize)
+	for _, filter := range filters {
+		server.AddToolFilter(filter)
+	}
+	for _, toolset := range allToolsets(&s.Suite) {
+		s.Require().NoError(server.RegisterToolset(toolset))
+	}
+	return server
+}
+
+func (s *PromptsTestSuite) listPrompts(session *mcp.ClientSession) map[string]*mcp.Prompt {
+	prompts := make(map[string]*mcp.Prompt)
+	for prompt, err := range session.Prompts(context.Background(), nil) {
+		s.Require().NoError(err)
+		prompts[prompt.Name] = prompt
+	}
+	return prompts
+}
+
+// TestPromptsListed tests that every toolset's prompts are listed with their arguments.
+func (s *PromptsTestSuite) TestPromptsListed() {
+	session, closeSessions := connect(&s.Suite, s.newServer(false))
+	defer closeSessions()
+
+	prompts := s.listPrompts(session)
+	s.Len(prompts, 5)
+	for _, name := range []string{
+		"troubleshoot_crashlooping_pod",
+		"explain_deployment_not_progressing",
+		"audit_namespace_policies",
+		"plan_machinedeployment_scale",
+		"investigate_failed_restore",
+	} {
+		prompt, ok := prompts[name]
+		if !s.True(ok, "prompt %s not listed", name) {
+			continue
+		}
+		s.NotEmpty(prompt.Description, name)
+		s.Equal("context", prompt.Arguments[0].Name, name)
+		s.False(prompt.Arguments[0].Required, name)
+	}
+}
+
+// TestGetPrompt tests rendering a prompt and validating its arguments.
+func (s *PromptsTestSuite) TestGetPrompt() {
+	ctx := context.Background()
+	session, closeSessions := connect(&s.Suite, s.newServer(false))
+	defer closeSessions()
+
+	result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "troubleshoot_crashlooping_pod",
+		Arguments: map[string]string{"context": "prod", "namespace": "shop", "name": "web-0"},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(result.Messages, 2)
+	s.Equal(mcp.Role("user"), result.Messages[0].Role)
+	s.Contains(result.Messages[0].Content.(*mcp.TextContent).Text, "Pod shop/web-0 in context prod")
+	s.Contains(result.Messages[1].Content.(*mcp.TextContent).Text, "`pods_logs` with previous=true")
+
+	_, err = session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "troubleshoot_crashlooping_pod",
+		Arguments: map[string]string{"namespace": "shop"},
+	})
+	s.ErrorContains(err, `missing required argument "name"`)
+}
+
+// TestPromptToolNamesNormalized tests that tool references follow tool name normalization.
+func (s *PromptsTestSuite) TestPromptToolNamesNormalized() {
+	session, closeSessions := connect(&s.Suite, s.newServer(true))
+	defer closeSessions()
+
+	result, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "audit_namespace_policies",
+		Arguments: map[string]string{"namespace": "shop"},
+	})
+	s.Require().NoError(err)
+	text := result.Messages[1].Content.(*mcp.TextContent).Text
+	s.Contains(text, "`policy_violations_list`")
+	s.NotContains(text, "policy.violations_list")
+}
+
+// TestPromptsRequireTools tests that prompts relying on hidden tools are not registered.
+func (s *PromptsTestSuite) TestPromptsRequireTools() {
+	session, closeSessions := connect(&s.Suite, s.newServer(false, func(tool *mcp.Tool) bool {
+		return tool.Name != "pods_logs"
+	}))
+	defer closeSessions()
+
+	prompts := s.listPrompts(session)
+	s.NotContains(prompts, "troubleshoot_crashlooping_pod")
+	s.Contains(prompts, "explain_deployment_not_progressing")
+}
+
+// TestPromptsTestSuite runs the prompts test suite.
+func TestPromptsTestSuite(t *testing.T) {
+	suite.Run(t, new(PromptsTestSuite))
+}
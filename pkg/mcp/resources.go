@@ -0,0 +1,573 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	"github.com/yosida95/uritemplate/v3"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// Resource URI templates. An empty context selects the current context, and
+// the core API group may be written as "core" or left empty.
+const (
+	ObjectURITemplate        = "k8s://{context}/{group}/{version}/{kind}/{namespace}/{name}"
+	ClusterObjectURITemplate = "k8s://{context}/{group}/{version}/{kind}/{name}"
+	PodLogURITemplate        = "k8s://{context}/core/v1/Pod/{namespace}/{name}/log{?container,tail_lines}"
+	HelmReleaseURITemplate   = "helm://{context}/{namespace}/{name}"
+)
+
+// watchRetryInterval is how long a subscription waits before re-establishing
+// a failed watch.
+const watchRetryInterval = 5 * time.Second
+
+var (
+	podsGVR    = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	podLogsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods/log"}
+	secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+// resourceTemplates lists the templates in match order. The pod log template
+// comes first so that log URIs are never read as objects.
+var resourceTemplates = []*mcp.ResourceTemplate{
+	{
+		Name:        "pod-logs",
+		Title:       "Pod logs",
+		Description: "Logs of a pod container. Subscriptions notify when the pod changes (e.g. a container restarts).",
+		URITemplate: PodLogURITemplate,
+		MIMEType:    "text/plain",
+	},
+	{
+		Name:        "kubernetes-object",
+		Title:       "Kubernetes object",
+		Description: "A namespaced Kubernetes object as JSON, e.g. k8s://prod/apps/v1/Deployment/default/web",
+		URITemplate: ObjectURITemplate,
+		MIMEType:    "application/json",
+	},
+	{
+		Name:        "kubernetes-cluster-object",
+		Title:       "Kubernetes cluster-scoped object",
+		Description: "A cluster-scoped Kubernetes object as JSON, e.g. k8s://prod/core/v1/Node/worker-1",
+		URITemplate: ClusterObjectURITemplate,
+		MIMEType:    "application/json",
+	},
+	{
+		Name:        "helm-release",
+		Title:       "Helm release manifest",
+		Description: "The rendered manifest of the latest revision of a Helm release",
+		URITemplate: HelmReleaseURITemplate,
+		MIMEType:    "application/yaml",
+	},
+}
+
+// compiledTemplates holds the parsed form of each resource template.
+var compiledTemplates = func() map[string]*uritemplate.Template {
+	compiled := make(map[string]*uritemplate.Template, len(resourceTemplates))
+	for _, rt := range resourceTemplates {
+		compiled[rt.URITemplate] = uritemplate.MustNew(rt.URITemplate)
+	}
+	return compiled
+}()
+
+// resourceRef is a parsed resource URI.
+type resourceRef struct {
+	template  string
+	context   string
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	container string
+	tailLines *int64
+}
+
+// parseResourceURI matches uri against the resource templates.
+func parseResourceURI(uri string) (*resourceRef, error) {
+	for _, rt := range resourceTemplates {
+		values := compiledTemplates[rt.URITemplate].Match(uri)
+		if values == nil {
+			continue
+		}
+
+		ref := &resourceRef{
+			template:  rt.URITemplate,
+			context:   values.Get("context").String(),
+			namespace: values.Get("namespace").String(),
+			name:      values.Get("name").String(),
+		}
+		if ref.name == "" {
+			return nil, fmt.Errorf("resource URI %s has no name", uri)
+		}
+
+		switch rt.URITemplate {
+		case PodLogURITemplate:
+			ref.gvk = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+			ref.container = values.Get("container").String()
+			if tail := values.Get("tail_lines").String(); tail != "" {
+				n, err := strconv.ParseInt(tail, 10, 64)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid tail_lines %q", tail)
+				}
+				ref.tailLines = &n
+			}
+		case ObjectURITemplate, ClusterObjectURITemplate:
+			group := values.Get("group").String()
+			if group == "core" {
+				group = ""
+			}
+			ref.gvk = schema.GroupVersionKind{
+				Group:   group,
+				Version: values.Get("version").String(),
+				Kind:    values.Get("kind").String(),
+			}
+		}
+		return ref, nil
+	}
+	return nil, fmt.Errorf("unsupported resource URI %s", uri)
+}
+
+// ResourceProvider exposes Kubernetes objects, pod logs and Helm releases as
+// MCP resources. Subscriptions are backed by Kubernetes watches, one per
+// subscribed URI, and send notifications/resources/updated on every change.
+type ResourceProvider struct {
+	provider       kubernetes.ClientProvider
+	rbacAuthorizer kubernetes.RBACAuthorizer
+	requireRBAC    bool
+
+	redact      func(text string) string
+	checkAccess func(ctx context.Context, toolName string, target Target) error
+
+	server  *mcp.Server
+	mu      sync.Mutex
+	watches map[string]*resourceWatch
+}
+
+// resourceWatch is the watch behind a subscribed URI.
+type resourceWatch struct {
+	cancel   context.CancelFunc
+	sessions map[*mcp.ServerSession]struct{}
+}
+
+// NewResourceProvider creates a new resource provider.
+func NewResourceProvider(provider kubernetes.ClientProvider) *ResourceProvider {
+	return &ResourceProvider{
+		provider: provider,
+		watches:  make(map[string]*resourceWatch),
+	}
+}
+
+// SetRBACAuthorizer sets the RBAC authorizer for the provider.
+func (p *ResourceProvider) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
+	p.rbacAuthorizer = authorizer
+	p.requireRBAC = requireRBAC
+}
+
+// SetRedactor sets a function applied to resource contents before they are
+// returned, e.g. to mask Secret data.
+func (p *ResourceProvider) SetRedactor(redact func(text string) string) {
+	p.redact = redact
+}
+
+// SetAccessCheck sets a function that decides whether the caller in ctx may
+// read or subscribe to a resource. Resources are checked as a call to the tool
+// that returns the same data: resources_get, pods_logs or helm_releases_list.
+func (p *ResourceProvider) SetAccessCheck(check func(ctx context.Context, toolName string, target Target) error) {
+	p.checkAccess = check
+}
+
+// RegisterResources registers the provider's resource templates with the server
+// and routes resource subscriptions to it.
+func (s *Server) RegisterResources(p *ResourceProvider) {
+	s.mu.Lock()
+	s.resources = p
+	s.mu.Unlock()
+
+	p.server = s.sdkServer
+	for _, rt := range resourceTemplates {
+		s.sdkServer.AddResourceTemplate(rt, p.read)
+	}
+}
+
+// subscribeResource handles resources/subscribe.
+func (s *Server) subscribeResource(ctx context.Context, req *mcp.SubscribeRequest) error {
+	s.mu.RLock()
+	p := s.resources
+	s.mu.RUnlock()
+	if p == nil {
+		return fmt.Errorf("resource subscriptions are not supported")
+	}
+	return p.subscribe(ctx, req)
+}
+
+// unsubscribeResource handles resources/unsubscribe.
+func (s *Server) unsubscribeResource(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	s.mu.RLock()
+	p := s.resources
+	s.mu.RUnlock()
+	if p != nil {
+		p.unsubscribe(req.Params.URI, req.Session)
+	}
+	return nil
+}
+
+// read handles resources/read for all templates.
+func (p *ResourceProvider) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	if req.Extra != nil {
+		ctx = auth.ContextWithTokenInfo(ctx, req.Extra.TokenInfo)
+	}
+
+	uri := req.Params.URI
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.access(ctx, ref); err != nil {
+		return nil, err
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, p.provider, ref.context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client set: %w", err)
+	}
+
+	var text string
+	switch ref.template {
+	case PodLogURITemplate:
+		text, err = p.readPodLogs(ctx, clientSet, ref)
+	case HelmReleaseURITemplate:
+		text, err = p.readHelmRelease(ctx, clientSet, ref)
+	default:
+		text, err = p.readObject(ctx, clientSet, ref)
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) || errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return nil, err
+	}
+	if p.redact != nil {
+		text = p.redact(text)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, Text: text}},
+	}, nil
+}
+
+// access runs the access check, if any, for a resource.
+func (p *ResourceProvider) access(ctx context.Context, ref *resourceRef) error {
+	if p.checkAccess == nil {
+		return nil
+	}
+
+	return p.checkAccess(ctx, resourceTool(ref.template), Target{
+		Context:   ref.context,
+		Group:     ref.gvk.Group,
+		Version:   ref.gvk.Version,
+		Kind:      ref.gvk.Kind,
+		Namespace: ref.namespace,
+		Name:      ref.name,
+	})
+}
+
+// resourceTool returns the read-only tool that returns the same data as the
+// resources of a template, which access to them is checked as.
+func resourceTool(template string) string {
+	switch template {
+	case PodLogURITemplate:
+		return "pods_logs"
+	case HelmReleaseURITemplate:
+		return "helm_releases_list"
+	}
+	return "resources_get"
+}
+
+// readObject returns an object as indented JSON, without managed fields.
+func (p *ResourceProvider) readObject(ctx context.Context, clientSet *kubernetes.ClientSet, ref *resourceRef) (string, error) {
+	gvr, err := p.objectGVR(clientSet, ref)
+	if err != nil {
+		return "", err
+	}
+	if err := p.checkRBAC(ctx, ref.context, "get", gvr, ref.namespace); err != nil {
+		return "", err
+	}
+
+	obj, err := clientSet.Dynamic.Resource(gvr).Namespace(ref.namespace).Get(ctx, ref.name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	obj.SetManagedFields(nil)
+
+	data, err := json.MarshalIndent(obj.Object, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal object: %w", err)
+	}
+	return string(data), nil
+}
+
+// readPodLogs returns a pod container's logs.
+func (p *ResourceProvider) readPodLogs(ctx context.Context, clientSet *kubernetes.ClientSet, ref *resourceRef) (string, error) {
+	if err := p.checkRBAC(ctx, ref.context, "get", podLogsGVR, ref.namespace); err != nil {
+		return "", err
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container: ref.container,
+		TailLines: ref.tailLines,
+	}
+	data, err := clientSet.Typed.CoreV1().Pods(ref.namespace).GetLogs(ref.name, opts).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// readHelmRelease returns the manifest of a release's latest revision.
+func (p *ResourceProvider) readHelmRelease(ctx context.Context, clientSet *kubernetes.ClientSet, ref *resourceRef) (string, error) {
+	if err := p.checkRBAC(ctx, ref.context, "list", secretsGVR, ref.namespace); err != nil {
+		return "", err
+	}
+
+	releases := storage.Init(driver.NewSecrets(clientSet.Typed.CoreV1().Secrets(ref.namespace)))
+	rel, err := releases.Last(ref.name)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// objectGVR maps an object reference to its resource, checking that the URI
+// template matches the kind's scope.
+func (p *ResourceProvider) objectGVR(clientSet *kubernetes.ClientSet, ref *resourceRef) (schema.GroupVersionResource, error) {
+	mapping, err := clientSet.RESTMapper.RESTMapping(ref.gvk.GroupKind(), ref.gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to map GVK to GVR: %w", err)
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced && ref.template == ClusterObjectURITemplate {
+		return schema.GroupVersionResource{}, fmt.Errorf("%s is namespaced; use %s", ref.gvk.Kind, ObjectURITemplate)
+	}
+	if !namespaced && ref.template == ObjectURITemplate {
+		return schema.GroupVersionResource{}, fmt.Errorf("%s is cluster-scoped; use %s", ref.gvk.Kind, ClusterObjectURITemplate)
+	}
+	return mapping.Resource, nil
+}
+
+// watchTarget returns the collection and list options to watch for changes to ref.
+func (p *ResourceProvider) watchTarget(clientSet *kubernetes.ClientSet, ref *resourceRef) (schema.GroupVersionResource, metav1.ListOptions, error) {
+	switch ref.template {
+	case HelmReleaseURITemplate:
+		selector := labels.SelectorFromSet(labels.Set{"owner": "helm", "name": ref.name})
+		return secretsGVR, metav1.ListOptions{LabelSelector: selector.String()}, nil
+	case PodLogURITemplate:
+		return podsGVR, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", ref.name).String()}, nil
+	default:
+		gvr, err := p.objectGVR(clientSet, ref)
+		return gvr, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", ref.name).String()}, err
+	}
+}
+
+// subscribe starts watching the resource behind the URI, or joins the existing
+// watch. Every subscriber lists the resource with its own credentials first, so
+// a subscription is only accepted for callers allowed to see it.
+func (p *ResourceProvider) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	if req.Extra != nil {
+		ctx = auth.ContextWithTokenInfo(ctx, req.Extra.TokenInfo)
+	}
+
+	uri := req.Params.URI
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+	if err := p.access(ctx, ref); err != nil {
+		return err
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, p.provider, ref.context)
+	if err != nil {
+		return fmt.Errorf("failed to get client set: %w", err)
+	}
+	gvr, opts, err := p.watchTarget(clientSet, ref)
+	if err != nil {
+		return err
+	}
+	if err := p.checkRBAC(ctx, ref.context, "watch", gvr, ref.namespace); err != nil {
+		return err
+	}
+
+	client := clientSet.Dynamic.Resource(gvr).Namespace(ref.namespace)
+	list, err := client.List(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if w, ok := p.watches[uri]; ok {
+		w.sessions[req.Session] = struct{}{}
+		return nil
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	p.watches[uri] = &resourceWatch{
+		cancel:   cancel,
+		sessions: map[*mcp.ServerSession]struct{}{req.Session: {}},
+	}
+	go p.watch(watchCtx, uri, client, opts, list.GetResourceVersion())
+	return nil
+}
+
+// unsubscribe removes a session from a URI's watch, stopping the watch when
+// no sessions remain.
+func (p *ResourceProvider) unsubscribe(uri string, session *mcp.ServerSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.watches[uri]
+	if !ok {
+		return
+	}
+	delete(w.sessions, session)
+	if len(w.sessions) == 0 {
+		w.cancel()
+		delete(p.watches, uri)
+	}
+}
+
+// watch follows changes to a resource until the subscription ends, re-establishing
+// the watch when the API server closes it.
+func (p *ResourceProvider) watch(ctx context.Context, uri string, client dynamic.ResourceInterface, opts metav1.ListOptions, resourceVersion string) {
+	for ctx.Err() == nil {
+		watchOpts := opts
+		watchOpts.ResourceVersion = resourceVersion
+		watchOpts.AllowWatchBookmarks = true
+		watcher, err := client.Watch(ctx, watchOpts)
+		if err != nil {
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				resourceVersion = p.relist(ctx, client, opts)
+			} else {
+				p.waitRetry(ctx)
+			}
+			continue
+		}
+
+		var expired bool
+		resourceVersion, expired = p.consume(ctx, uri, watcher, resourceVersion)
+		if expired {
+			resourceVersion = p.relist(ctx, client, opts)
+		}
+	}
+}
+
+// relist returns the current resource version, so that an expired watch
+// resumes from the current state instead of replaying it.
+func (p *ResourceProvider) relist(ctx context.Context, client dynamic.ResourceInterface, opts metav1.ListOptions) string {
+	for ctx.Err() == nil {
+		list, err := client.List(ctx, opts)
+		if err == nil {
+			return list.GetResourceVersion()
+		}
+		p.waitRetry(ctx)
+	}
+	return ""
+}
+
+// consume forwards watch events as resource updates. It returns the last seen
+// resource version and whether the watch reported an error.
+func (p *ResourceProvider) consume(ctx context.Context, uri string, watcher watch.Interface, resourceVersion string) (string, bool) {
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, false
+			}
+			if event.Type == watch.Error {
+				return resourceVersion, true
+			}
+			if obj, err := meta.Accessor(event.Object); err == nil {
+				resourceVersion = obj.GetResourceVersion()
+			}
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			p.notify(ctx, uri)
+		}
+	}
+}
+
+// notify sends notifications/resources/updated for uri. Sessions that have
+// disconnected without unsubscribing are dropped first, and the watch is
+// stopped once none remain.
+func (p *ResourceProvider) notify(ctx context.Context, uri string) {
+	live := make(map[*mcp.ServerSession]bool)
+	for session := range p.server.Sessions() {
+		live[session] = true
+	}
+
+	p.mu.Lock()
+	w, ok := p.watches[uri]
+	if ok {
+		for session := range w.sessions {
+			if !live[session] {
+				delete(w.sessions, session)
+			}
+		}
+		if len(w.sessions) == 0 {
+			w.cancel()
+			delete(p.watches, uri)
+			ok = false
+		}
+	}
+	p.mu.Unlock()
+
+	if ok {
+		_ = p.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+// waitRetry waits before a watch is retried.
+func (p *ResourceProvider) waitRetry(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(watchRetryInterval):
+	}
+}
+
+// checkRBAC checks that the caller may perform verb on gvr in a context.
+func (p *ResourceProvider) checkRBAC(ctx context.Context, contextName, verb string, gvr schema.GroupVersionResource, namespace string) error {
+	if !p.requireRBAC || p.rbacAuthorizer == nil {
+		return nil
+	}
+
+	allowed, err := p.rbacAuthorizer.Allowed(ctx, auth.IdentityFromContext(ctx), contextName, verb, gvr, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to check RBAC: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// ResourcesTestSuite tests the MCP resources backed by Kubernetes.
+type ResourcesTestSuite struct {
+	suite.Suite
+	clientSet *kubernetes.ClientSet
+	resources *mcpHelpers.ResourceProvider
+	session   *mcp.ClientSession
+	updated   chan string
+	cleanup   []func()
+}
+
+var deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+// clientSetProvider serves a single fixed client set.
+type clientSetProvider struct {
+	clientSet *kubernetes.ClientSet
+}
+
+func (p *clientSetProvider) GetClientSet(context string) (*kubernetes.ClientSet, error) {
+	return p.clientSet, nil
+}
+
+func (p *clientSetProvider) ListContexts() ([]string, error) {
+	return []string{"test"}, nil
+}
+
+func (p *clientSetProvider) GetCurrentContext() (string, error) {
+	return "test", nil
+}
+
+func (s *ResourcesTestSuite) SetupTest() {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("default")
+	deployment.SetName("web")
+	deployment.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, meta.RESTScopeRoot)
+
+	s.clientSet = &kubernetes.ClientSet{
+		Typed: fake.NewSimpleClientset(),
+		Dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{deploymentsGVR: "DeploymentList"}, deployment),
+		RESTMapper: mapper,
+	}
+
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	s.resources = mcpHelpers.NewResourceProvider(&clientSetProvider{clientSet: s.clientSet})
+	server.RegisterResources(s.resources)
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	s.updated = make(chan string, 16)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			s.updated <- req.Params.URI
+		},
+	})
+	s.session, err = client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+
+	s.cleanup = []func(){func() { s.session.Close() }, func() { serverSession.Close() }}
+}
+
+// recordingAuthorizer denies every check and records it.
+type recordingAuthorizer struct {
+	checks []string
+}
+
+func (a *recordingAuthorizer) Allowed(_ context.Context, _ *auth.Identity, _, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	a.checks = append(a.checks, verb+" "+gvr.Resource+" "+namespace)
+	return false, nil
+}
+
+func (s *ResourcesTestSuite) TearDownTest() {
+	for _, fn := range s.cleanup {
+		fn()
+	}
+}
+
+func (s *ResourcesTestSuite) read(uri string) (*mcp.ReadResourceResult, error) {
+	return s.session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: uri})
+}
+
+// TestResourceTemplates tests that the templates are advertised.
+func (s *ResourcesTestSuite) TestResourceTemplates() {
+	var templates []string
+	for tmpl, err := range s.session.ResourceTemplates(context.Background(), nil) {
+		s.Require().NoError(err)
+		templates = append(templates, tmpl.URITemplate)
+	}
+	s.ElementsMatch([]string{
+		mcpHelpers.ObjectURITemplate,
+		mcpHelpers.ClusterObjectURITemplate,
+		mcpHelpers.PodLogURITemplate,
+		mcpHelpers.HelmReleaseURITemplate,
+	}, templates)
+}
+
+// TestReadObject tests reading an object, with and without an explicit context.
+func (s *ResourcesTestSuite) TestReadObject() {
+	for _, uri := range []string{"k8s://test/apps/v1/Deployment/default/web", "k8s:///apps/v1/Deployment/default/web"} {
+		result, err := s.read(uri)
+		s.Require().NoError(err, uri)
+		s.Require().Len(result.Contents, 1)
+		s.Equal("application/json", result.Contents[0].MIMEType)
+
+		var obj map[string]any
+		s.Require().NoError(json.Unmarshal([]byte(result.Contents[0].Text), &obj))
+		metadata := obj["metadata"].(map[string]any)
+		s.Equal("web", metadata["name"])
+		s.NotContains(metadata, "managedFields")
+	}
+}
+
+// TestReadErrors tests missing objects, scope mismatches and unknown kinds.
+func (s *ResourcesTestSuite) TestReadErrors() {
+	_, err := s.read("k8s://test/apps/v1/Deployment/default/missing")
+	s.ErrorContains(err, "not found")
+
+	_, err = s.read("k8s://test/apps/v1/Deployment/web")
+	s.ErrorContains(err, "namespaced")
+
+	_, err = s.read("k8s://test/core/v1/Node/default/node-1")
+	s.ErrorContains(err, "cluster-scoped")
+
+	_, err = s.read("k8s://test/example.com/v1/Widget/default/web")
+	s.Error(err)
+}
+
+// TestReadPodLogs tests reading pod logs.
+func (s *ResourcesTestSuite) TestReadPodLogs() {
+	result, err := s.read("k8s://test/core/v1/Pod/default/web/log?container=app&tail_lines=10")
+	s.Require().NoError(err)
+	s.Equal("text/plain", result.Contents[0].MIMEType)
+	s.Equal("fake logs", result.Contents[0].Text)
+
+	_, err = s.read("k8s://test/core/v1/Pod/default/web/log?tail_lines=ten")
+	s.Error(err)
+
+	// Logs are checked as the pods/log subresource
+	authorizer := &recordingAuthorizer{}
+	s.resources.SetRBACAuthorizer(authorizer, true)
+	_, err = s.read("k8s://test/core/v1/Pod/default/web/log")
+	s.ErrorContains(err, "Forbidden")
+	s.Equal([]string{"get pods/log default"}, authorizer.checks)
+}
+
+// TestReadHelmRelease tests reading the latest revision of a Helm release.
+func (s *ResourcesTestSuite) TestReadHelmRelease() {
+	secrets := driver.NewSecrets(s.clientSet.Typed.CoreV1().Secrets("default"))
+	for version, manifest := range []string{"kind: ConfigMap", "kind: Deployment"} {
+		rel := &release.Release{
+			Name:      "web",
+			Namespace: "default",
+			Version:   version + 1,
+			Manifest:  manifest,
+			Info:      &release.Info{Status: release.StatusDeployed},
+		}
+		s.Require().NoError(secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel))
+	}
+
+	result, err := s.read("helm://test/default/web")
+	s.Require().NoError(err)
+	s.Equal("application/yaml", result.Contents[0].MIMEType)
+	s.Equal("kind: Deployment", result.Contents[0].Text)
+
+	_, err = s.read("helm://test/default/missing")
+	s.ErrorContains(err, "not found")
+}
+
+// TestSubscribe tests that changes to a subscribed object are notified until
+// the client unsubscribes.
+func (s *ResourcesTestSuite) TestSubscribe() {
+	ctx := context.Background()
+	uri := "k8s://test/apps/v1/Deployment/default/web"
+	s.Require().NoError(s.session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+
+	// The watch starts asynchronously, so keep changing the object until an
+	// update arrives.
+	deployments := s.clientSet.Dynamic.Resource(deploymentsGVR).Namespace("default")
+	received := false
+	for i := 0; i < 50 && !received; i++ {
+		obj, err := deployments.Get(ctx, "web", metav1.GetOptions{})
+		s.Require().NoError(err)
+		obj.SetLabels(map[string]string{"revision": time.Now().String()})
+		_, err = deployments.Update(ctx, obj, metav1.UpdateOptions{})
+		s.Require().NoError(err)
+
+		select {
+		case got := <-s.updated:
+			s.Equal(uri, got)
+			received = true
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	s.True(received, "no update notification received")
+
+	s.Require().NoError(s.session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}))
+
+	_, err := s.read(uri)
+	s.Require().NoError(err)
+	s.Error(s.session.Subscribe(ctx, &mcp.SubscribeParams{URI: "k8s://test/apps/v1/Deployment/web"}))
+}
+
+// TestResourcesTestSuite runs the resources test suite.
+func TestResourcesTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourcesTestSuite))
+}
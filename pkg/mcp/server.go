@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -14,6 +15,14 @@ type Server struct {
 	implementation     *mcp.Implementation
 	normalizeToolNames bool
 	nameMapping        map[string]string // normalized -> original name mapping
+
+	mu              sync.RWMutex
+	toolFilters     []ToolFilter
+	toolListFilters []ToolListFilter
+	toolMiddleware  []ToolMiddleware
+	resources       *ResourceProvider
+	completer       *Completer
+	prompts         map[string]*Prompt
 }
 
 // NewServer creates a new MCP server.
@@ -23,23 +32,33 @@ func NewServer(name, version string, normalizeToolNames bool) *Server {
 		Version: version,
 	}
 
-	sdkServer := mcp.NewServer(impl, nil)
-	registry := NewToolRegistry()
-
 	srv := &Server{
-		sdkServer:          sdkServer,
-		registry:           registry,
+		registry:           NewToolRegistry(),
 		implementation:     impl,
 		normalizeToolNames: normalizeToolNames,
 		nameMapping:        make(map[string]string),
+		prompts:            make(map[string]*Prompt),
 	}
 
+	sdkServer := mcp.NewServer(impl, &mcp.ServerOptions{
+		SubscribeHandler:   srv.subscribeResource,
+		UnsubscribeHandler: srv.unsubscribeResource,
+		CompletionHandler:  srv.complete,
+	})
+	sdkServer.AddReceivingMiddleware(srv.filterToolList)
+	srv.sdkServer = sdkServer
+
 	// Register mapping for AddTool wrapper
 	registerServerMapping(sdkServer, srv)
 
 	return srv
 }
 
+// Implementation returns the server's name and version.
+func (s *Server) Implementation() *mcp.Implementation {
+	return s.implementation
+}
+
 // normalizeToolName replaces dots with underscores in tool names for n8n compatibility.
 func (s *Server) normalizeToolName(name string) string {
 	if !s.normalizeToolNames {
@@ -70,7 +89,12 @@ func (s *Server) RegisterToolset(toolset Toolset) error {
 	// Note: Tool name normalization requires toolsets to use mcp.AddTool wrapper
 	// For now, we'll normalize tool names after registration by intercepting
 	// the tool list. This is a workaround until all toolsets are updated.
-	return toolset.RegisterTools(s.sdkServer)
+	if err := toolset.RegisterTools(s.sdkServer); err != nil {
+		return err
+	}
+
+	s.registerPrompts(toolset)
+	return nil
 }
 
 // GetSDKServer returns the underlying MCP SDK server.
@@ -78,12 +102,28 @@ func (s *Server) GetSDKServer() *mcp.Server {
 	return s.sdkServer
 }
 
+// SessionCount returns the number of open MCP sessions.
+func (s *Server) SessionCount() int {
+	count := 0
+	for range s.sdkServer.Sessions() {
+		count++
+	}
+	return count
+}
+
 // Run runs the server on the given transport.
 func (s *Server) Run(ctx context.Context, transport mcp.Transport) error {
 	return s.sdkServer.Run(ctx, transport)
 }
 
-// ListTools returns all registered tools from the registry.
+// ListTools returns all registered tools from the registry, excluding tools
+// hidden by tool filters.
 func (s *Server) ListTools() []*mcp.Tool {
-	return s.registry.ListTools()
+	tools := make([]*mcp.Tool, 0)
+	for _, tool := range s.registry.ListTools() {
+		if s.toolAllowed(tool) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
 }
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Reasons a stream stopped.
+const (
+	// StreamEnded means the source ended, e.g. the container exited
+	StreamEnded = "ended"
+	// StreamMaxDuration means the stream ran for its maximum duration
+	StreamMaxDuration = "max_duration"
+	// StreamMaxItems means the stream delivered its maximum number of items
+	StreamMaxItems = "max_items"
+)
+
+// streamBuffer is how many items a producer may run ahead of delivery before
+// it is blocked.
+const streamBuffer = 256
+
+// streamBatch is the most items delivered in one notification.
+const streamBatch = 100
+
+// StreamLimits bound a stream.
+type StreamLimits struct {
+	// How long to stream (0 for no limit)
+	Duration time.Duration
+
+	// Most items to deliver (0 for no limit)
+	MaxItems int
+}
+
+// StreamFilter rewrites an item before it is delivered, such as to mask
+// credentials in it. Filters may modify the item they are given.
+type StreamFilter func(item any) any
+
+type streamFiltersKey struct{}
+
+// ContextWithStreamFilter returns a context whose streams pass every item
+// through filter, after the filters ctx already has. Middleware that rewrites
+// tool results uses it so streamed items, which bypass the result, are
+// rewritten alike.
+func ContextWithStreamFilter(ctx context.Context, filter StreamFilter) context.Context {
+	filters, _ := ctx.Value(streamFiltersKey{}).([]StreamFilter)
+	return context.WithValue(ctx, streamFiltersKey{}, append(slices.Clone(filters), filter))
+}
+
+// StreamSummary describes a stream that stopped.
+type StreamSummary struct {
+	// Items delivered
+	Items int
+
+	// Whether items were delivered as progress notifications; otherwise they
+	// are in Collected
+	Streamed bool
+
+	// Items not delivered as notifications, for the call's result
+	Collected []any
+
+	// Why the stream stopped: StreamEnded, StreamMaxDuration or StreamMaxItems
+	Reason string
+}
+
+// Stream delivers the items produce emits to the client as they arrive, until
+// produce returns, a limit is reached or ctx is cancelled.
+//
+// Items pass through the stream filters of ctx before they are delivered.
+// Calls carrying a progress token receive the items as progress
+// notifications: each carries the items pending at the time, one per line of
+// its message (strings as they are, other items as JSON), and the number of
+// items delivered so far as progress. For other calls, items are collected
+// for the result.
+//
+// produce runs in its own goroutine and must return once its ctx is done.
+// emit blocks while the client falls behind, so producers reading from a
+// connection apply backpressure to it; it returns false when produce should
+// stop.
+func Stream(ctx context.Context, limits StreamLimits, produce func(ctx context.Context, emit func(item any) bool) error) (*StreamSummary, error) {
+	progress := ProgressFromContext(ctx)
+	filters, _ := ctx.Value(streamFiltersKey{}).([]StreamFilter)
+	summary := &StreamSummary{
+		Streamed: progress.session != nil && progress.token != nil,
+		Reason:   StreamEnded,
+	}
+
+	produceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	items := make(chan any, streamBuffer)
+	produced := make(chan error, 1)
+	go func() {
+		defer close(items)
+		produced <- produce(produceCtx, func(item any) bool {
+			select {
+			case items <- item:
+				return true
+			case <-produceCtx.Done():
+				return false
+			}
+		})
+	}()
+
+	// stop stops produce and waits for it to return
+	stop := func(reason string) (*StreamSummary, error) {
+		summary.Reason = reason
+		cancel()
+		for range items {
+		}
+		<-produced
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("stream cancelled: %w", ctx.Err())
+		}
+		return summary, nil
+	}
+
+	var deadline <-chan time.Time
+	if limits.Duration > 0 {
+		timer := time.NewTimer(limits.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return stop(StreamEnded)
+		case <-deadline:
+			return stop(StreamMaxDuration)
+		case item, ok := <-items:
+			if !ok {
+				// produce also returns when the call is cancelled, which
+				// may close items before ctx.Done is selected
+				if err := <-produced; ctx.Err() != nil {
+					return nil, fmt.Errorf("stream cancelled: %w", ctx.Err())
+				} else if err != nil {
+					return summary, err
+				}
+				return summary, nil
+			}
+			batch := pending(items, item)
+			full := limits.MaxItems > 0 && summary.Items+len(batch) >= limits.MaxItems
+			if full {
+				batch = batch[:limits.MaxItems-summary.Items]
+			}
+			summary.Items += len(batch)
+			for i := range batch {
+				for _, filter := range filters {
+					batch[i] = filter(batch[i])
+				}
+			}
+			if summary.Streamed {
+				progress.Report(ctx, float64(summary.Items), float64(limits.MaxItems), streamMessage(batch))
+			} else {
+				summary.Collected = append(summary.Collected, batch...)
+			}
+			if full {
+				return stop(StreamMaxItems)
+			}
+		}
+	}
+}
+
+// pending returns first and the items waiting in items, up to streamBatch.
+func pending(items <-chan any, first any) []any {
+	batch := []any{first}
+	for len(batch) < streamBatch {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, item)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// streamMessage formats items as the message of a notification.
+func streamMessage(items []any) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		if s, ok := item.(string); ok {
+			lines[i] = s
+			continue
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			lines[i] = fmt.Sprint(item)
+			continue
+		}
+		lines[i] = string(data)
+	}
+	return strings.Join(lines, "\n")
+}
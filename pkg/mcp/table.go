@@ -0,0 +1,383 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// FormatAge formats the time since t like kubectl's AGE column.
+func FormatAge(t time.Time) string {
+	duration := time.Since(t)
+	if duration < time.Minute {
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	}
+	if duration < time.Hour {
+		return fmt.Sprintf("%dm", int(duration.Minutes()))
+	}
+	if duration < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(duration.Hours()/24))
+}
+
+// column is a table column and how its cells are read from an object.
+type column struct {
+	name  string
+	value func(obj map[string]any) string
+}
+
+// kindColumns are the columns kubectl shows after NAME for kinds whose
+// status it summarises: pods, workloads and services.
+var kindColumns = map[string][]column{
+	"Pod": {
+		{"READY", podReady},
+		{"STATUS", podStatus},
+		{"RESTARTS", podRestarts},
+		{"AGE", age},
+	},
+	"Deployment": {
+		{"READY", replicasReady},
+		{"UP-TO-DATE", intAt("status", "updatedReplicas")},
+		{"AVAILABLE", intAt("status", "availableReplicas")},
+		{"AGE", age},
+	},
+	"ReplicaSet": {
+		{"DESIRED", intAt("spec", "replicas")},
+		{"CURRENT", intAt("status", "replicas")},
+		{"READY", intAt("status", "readyReplicas")},
+		{"AGE", age},
+	},
+	"StatefulSet": {
+		{"READY", replicasReady},
+		{"AGE", age},
+	},
+	"DaemonSet": {
+		{"DESIRED", intAt("status", "desiredNumberScheduled")},
+		{"CURRENT", intAt("status", "currentNumberScheduled")},
+		{"READY", intAt("status", "numberReady")},
+		{"UP-TO-DATE", intAt("status", "updatedNumberScheduled")},
+		{"AVAILABLE", intAt("status", "numberAvailable")},
+		{"AGE", age},
+	},
+	"Service": {
+		{"TYPE", stringAt("spec", "type")},
+		{"CLUSTER-IP", stringAt("spec", "clusterIP")},
+		{"EXTERNAL-IP", serviceExternalIP},
+		{"PORT(S)", servicePorts},
+		{"AGE", age},
+	},
+}
+
+// renderTable renders items as a table, with the container's other scalar
+// fields, such as a continue token, on lines after it.
+func (s *outputShape) renderTable(container map[string]any, key string, items []any) (string, error) {
+	columns := s.tableColumns(items)
+	lines := make([]string, 0, len(items)+1)
+	if len(items) == 0 {
+		lines = append(lines, "No resources found.")
+	} else {
+		var buf strings.Builder
+		writer := tabwriter.NewWriter(&buf, 0, 8, 3, ' ', 0)
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = c.name
+		}
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+		for _, item := range items {
+			obj, _ := item.(map[string]any)
+			cells := make([]string, len(columns))
+			for i, c := range columns {
+				cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(c.value(obj))
+			}
+			fmt.Fprintln(writer, strings.Join(cells, "\t"))
+		}
+		if err := writer.Flush(); err != nil {
+			return "", fmt.Errorf("failed to render table: %w", err)
+		}
+		lines = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	}
+
+	var trailer []string
+	for _, k := range sortedKeys(container) {
+		if k != key && scalar(container[k]) {
+			trailer = append(trailer, fmt.Sprintf("%s: %s", k, cell(container[k])))
+		}
+	}
+
+	render := func(rows int) string {
+		kept := append(append([]string(nil), lines[:rows+1]...), trailer...)
+		if rows < len(items) {
+			kept = append(kept, fmt.Sprintf("(%d more; call again with cursor %s)",
+				len(items)-rows, s.encodeCursor(s.offset+rows)))
+		}
+		return strings.Join(kept, "\n") + "\n"
+	}
+	rows := len(lines) - 1
+	table := render(rows)
+	for s.maxBytes > 0 && len(table) > s.maxBytes && rows > 0 {
+		rows--
+		table = render(rows)
+	}
+	if rows == 0 && len(items) > 0 {
+		return "", fmt.Errorf("a table row exceeds max_bytes of %d; select fewer fields with fields", s.maxBytes)
+	}
+	return table, nil
+}
+
+// tableColumns returns the columns of a table of items: the selected
+// fields, the columns of their kind, or NAME and AGE of other objects.
+// Items that are not Kubernetes objects get a column per scalar field.
+func (s *outputShape) tableColumns(items []any) []column {
+	if len(s.fields) > 0 {
+		columns := make([]column, len(s.fields))
+		for i, field := range s.fields {
+			columns[i] = column{field, fieldAt(field)}
+		}
+		return columns
+	}
+
+	kinds := map[string]bool{}
+	namespaces := map[string]bool{}
+	objects := true
+	for _, item := range items {
+		obj, _ := item.(map[string]any)
+		if _, ok := obj["metadata"].(map[string]any); !ok {
+			objects = false
+			break
+		}
+		kind, _ := obj["kind"].(string)
+		kinds[kind] = true
+		namespaces[stringAt("metadata", "namespace")(obj)] = true
+	}
+	if !objects {
+		return summaryColumns(items)
+	}
+
+	columns := []column{{"NAME", stringAt("metadata", "name")}}
+	if len(namespaces) > 1 {
+		columns = []column{{"NAMESPACE", stringAt("metadata", "namespace")}, columns[0]}
+	}
+	if len(kinds) == 1 {
+		for kind := range kinds {
+			if known, ok := kindColumns[kind]; ok {
+				return append(columns, known...)
+			}
+		}
+	} else {
+		columns = append(columns, column{"KIND", stringAt("kind")})
+	}
+	return append(columns, column{"AGE", age})
+}
+
+// summaryColumns returns a column per scalar field of items, name and
+// namespace first.
+func summaryColumns(items []any) []column {
+	seen := map[string]any{}
+	for _, item := range items {
+		obj, _ := item.(map[string]any)
+		for k, v := range obj {
+			if scalar(v) {
+				seen[k] = true
+			}
+		}
+	}
+	var columns []column
+	for _, k := range []string{"name", "namespace"} {
+		if seen[k] != nil {
+			columns = append(columns, column{strings.ToUpper(k), stringAt(k)})
+			delete(seen, k)
+		}
+	}
+	for _, k := range sortedKeys(seen) {
+		columns = append(columns, column{strings.ToUpper(strings.ReplaceAll(k, "_", "-")), stringAt(k)})
+	}
+	return columns
+}
+
+// scalar reports whether a decoded JSON value is a string, number or bool.
+func scalar(value any) bool {
+	switch value.(type) {
+	case string, json.Number, float64, bool:
+		return true
+	}
+	return false
+}
+
+// cell formats a value as a table cell.
+func cell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if v == "" {
+			return "<none>"
+		}
+		return v
+	case json.Number:
+		return v.String()
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	data, err := marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// valueAt returns the value at a path of fields in obj.
+func valueAt(obj map[string]any, path ...string) any {
+	var value any = obj
+	for _, field := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[field]
+	}
+	return value
+}
+
+// intValue returns the integer at a path of fields in obj, 0 if it is absent.
+func intValue(obj map[string]any, path ...string) int64 {
+	switch v := valueAt(obj, path...).(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+func stringAt(path ...string) func(map[string]any) string {
+	return func(obj map[string]any) string { return cell(valueAt(obj, path...)) }
+}
+
+func intAt(path ...string) func(map[string]any) string {
+	return func(obj map[string]any) string { return fmt.Sprint(intValue(obj, path...)) }
+}
+
+// fieldAt reads a projected field.
+func fieldAt(field string) func(map[string]any) string {
+	return func(obj map[string]any) string { return cell(obj[field]) }
+}
+
+func age(obj map[string]any) string {
+	created, _ := valueAt(obj, "metadata", "creationTimestamp").(string)
+	t, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return "<unknown>"
+	}
+	return FormatAge(t)
+}
+
+// containerStatuses returns the container statuses of a pod.
+func containerStatuses(pod map[string]any) []map[string]any {
+	values, _ := valueAt(pod, "status", "containerStatuses").([]any)
+	statuses := make([]map[string]any, 0, len(values))
+	for _, value := range values {
+		if status, ok := value.(map[string]any); ok {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func podReady(pod map[string]any) string {
+	containers, _ := valueAt(pod, "spec", "containers").([]any)
+	ready := 0
+	for _, status := range containerStatuses(pod) {
+		if status["ready"] == true {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(containers))
+}
+
+// podStatus returns the reason a container is waiting or terminated, such as
+// CrashLoopBackOff, or else the pod's phase, like kubectl's STATUS column.
+func podStatus(pod map[string]any) string {
+	if valueAt(pod, "metadata", "deletionTimestamp") != nil {
+		return "Terminating"
+	}
+	for _, status := range containerStatuses(pod) {
+		for _, state := range []string{"waiting", "terminated"} {
+			if reason, ok := valueAt(status, "state", state, "reason").(string); ok && reason != "" && reason != "Completed" {
+				return reason
+			}
+		}
+	}
+	if reason, ok := valueAt(pod, "status", "reason").(string); ok && reason != "" {
+		return reason
+	}
+	return cell(valueAt(pod, "status", "phase"))
+}
+
+func podRestarts(pod map[string]any) string {
+	var restarts int64
+	for _, status := range containerStatuses(pod) {
+		restarts += intValue(status, "restartCount")
+	}
+	return fmt.Sprint(restarts)
+}
+
+// replicasReady returns ready out of desired replicas of a workload.
+func replicasReady(obj map[string]any) string {
+	return fmt.Sprintf("%d/%d", intValue(obj, "status", "readyReplicas"), intValue(obj, "spec", "replicas"))
+}
+
+func serviceExternalIP(svc map[string]any) string {
+	var ips []string
+	ingress, _ := valueAt(svc, "status", "loadBalancer", "ingress").([]any)
+	for _, entry := range ingress {
+		if m, ok := entry.(map[string]any); ok {
+			for _, key := range []string{"ip", "hostname"} {
+				if ip, ok := m[key].(string); ok && ip != "" {
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+	external, _ := valueAt(svc, "spec", "externalIPs").([]any)
+	for _, ip := range external {
+		if s, ok := ip.(string); ok {
+			ips = append(ips, s)
+		}
+	}
+	if len(ips) == 0 {
+		return "<none>"
+	}
+	return strings.Join(ips, ",")
+}
+
+// servicePorts formats the ports of a service like 80/TCP or
+// 443:30443/TCP for node ports.
+func servicePorts(svc map[string]any) string {
+	ports, _ := valueAt(svc, "spec", "ports").([]any)
+	formatted := make([]string, 0, len(ports))
+	for _, value := range ports {
+		port, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		protocol, _ := port["protocol"].(string)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		text := fmt.Sprint(intValue(port, "port"))
+		if nodePort := intValue(port, "nodePort"); nodePort != 0 {
+			text += fmt.Sprintf(":%d", nodePort)
+		}
+		formatted = append(formatted, text+"/"+protocol)
+	}
+	if len(formatted) == 0 {
+		return "<none>"
+	}
+	return strings.Join(formatted, ",")
+}
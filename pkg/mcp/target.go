@@ -0,0 +1,89 @@
+package mcp
+
+import "strings"
+
+// Target is the Kubernetes object a tool call operates on, as far as it can be
+// told from the call's arguments. Fields that cannot be determined are empty.
+type Target struct {
+	Context   string `json:"context,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// impliedKinds are the kinds of tools that operate on a fixed kind and
+// therefore take no kind argument.
+var impliedKinds = map[string]Target{
+	"pods_get":            {Version: "v1", Kind: "Pod"},
+	"pods_delete":         {Version: "v1", Kind: "Pod"},
+	"pods_logs":           {Version: "v1", Kind: "Pod"},
+	"pods_exec":           {Version: "v1", Kind: "Pod"},
+	"pods_port_forward":   {Version: "v1", Kind: "Pod"},
+	"pods_logs_aggregate": {Version: "v1", Kind: "Pod"},
+	"configmaps_get_data": {Version: "v1", Kind: "ConfigMap"},
+	"configmaps_set_data": {Version: "v1", Kind: "ConfigMap"},
+	"secrets_get_data":    {Version: "v1", Kind: "Secret"},
+	"secrets_set_data":    {Version: "v1", Kind: "Secret"},
+}
+
+// targetKinds are the kinds tools such as pods_port_forward and
+// pods_logs_aggregate accept in a kind argument without a group and version.
+var targetKinds = map[string]Target{
+	"pod":         {Version: "v1", Kind: "Pod"},
+	"service":     {Version: "v1", Kind: "Service"},
+	"deployment":  {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"statefulset": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"daemonset":   {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	"job":         {Group: "batch", Version: "v1", Kind: "Job"},
+	"rollout":     {Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+}
+
+// TargetOf resolves the target of a call to the named tool (original,
+// non-normalized name) from its arguments: explicit group/version/kind
+// arguments (resources_* tools), a manifest, or the kind implied by the tool.
+// The namespace of a manifest takes precedence over a namespace argument.
+func TargetOf(toolName string, args map[string]any) Target {
+	str := func(m map[string]any, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	target := Target{
+		Context:   str(args, "context"),
+		Namespace: str(args, "namespace"),
+		Name:      str(args, "name"),
+	}
+
+	if kind := str(args, "kind"); kind != "" {
+		target.Group = str(args, "group")
+		target.Version = str(args, "version")
+		target.Kind = kind
+		if known, ok := targetKinds[strings.ToLower(kind)]; ok && target.Version == "" {
+			target.Group = known.Group
+			target.Version = known.Version
+			target.Kind = known.Kind
+		}
+	} else if manifest, ok := args["manifest"].(map[string]any); ok && str(manifest, "kind") != "" {
+		target.Kind = str(manifest, "kind")
+		apiVersion := str(manifest, "apiVersion")
+		if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+			target.Group = apiVersion[:i]
+		}
+		target.Version = apiVersion[strings.LastIndex(apiVersion, "/")+1:]
+		// Tools act on the manifest's namespace, so a namespace argument
+		// must not stand in for it
+		metadata, _ := manifest["metadata"].(map[string]any)
+		if name := str(metadata, "name"); name != "" {
+			target.Name = name
+		}
+		target.Namespace = str(metadata, "namespace")
+	} else if implied, ok := impliedKinds[toolName]; ok {
+		target.Group = implied.Group
+		target.Version = implied.Version
+		target.Kind = implied.Kind
+	}
+
+	return target
+}
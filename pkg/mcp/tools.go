@@ -22,26 +22,119 @@ func NewTool(name, description string) *ToolBuilder {
 	}
 }
 
-// WithReadOnly marks the tool as read-only using annotations.
-func (b *ToolBuilder) WithReadOnly() *ToolBuilder {
+// annotations returns the tool's annotations, creating them on first use.
+// Tools act on the Kubernetes API, a closed domain, unless marked otherwise
+// with WithOpenWorld.
+func (b *ToolBuilder) annotations() *mcp.ToolAnnotations {
 	if b.tool.Annotations == nil {
-		b.tool.Annotations = &mcp.ToolAnnotations{}
+		b.tool.Annotations = &mcp.ToolAnnotations{OpenWorldHint: boolPtr(false)}
 	}
-	// Store read-only hint in annotations or meta
+	return b.tool.Annotations
+}
+
+// WithReadOnly marks the tool as read-only using annotations.
+func (b *ToolBuilder) WithReadOnly() *ToolBuilder {
+	annotations := b.annotations()
+	annotations.ReadOnlyHint = true
+	annotations.DestructiveHint = boolPtr(false)
+	annotations.IdempotentHint = true
+	return b
+}
+
+// WithNonDestructive marks the tool as a non-destructive write, i.e. one that
+// creates or updates state without deleting or disrupting it.
+func (b *ToolBuilder) WithNonDestructive() *ToolBuilder {
+	annotations := b.annotations()
+	annotations.ReadOnlyHint = false
+	annotations.DestructiveHint = boolPtr(false)
 	return b
 }
 
 // WithDestructive marks the tool as destructive using annotations.
 func (b *ToolBuilder) WithDestructive() *ToolBuilder {
-	if b.tool.Annotations == nil {
-		b.tool.Annotations = &mcp.ToolAnnotations{}
-	}
-	// Store destructive hint in annotations or meta
+	annotations := b.annotations()
+	annotations.ReadOnlyHint = false
+	annotations.DestructiveHint = boolPtr(true)
+	return b
+}
+
+// WithIdempotent marks the tool as idempotent: repeating a call with the same
+// arguments has no additional effect.
+func (b *ToolBuilder) WithIdempotent() *ToolBuilder {
+	b.annotations().IdempotentHint = true
+	return b
+}
+
+// WithOpenWorld marks the tool as reaching systems outside the cluster, such
+// as remote chart repositories.
+func (b *ToolBuilder) WithOpenWorld() *ToolBuilder {
+	b.annotations().OpenWorldHint = boolPtr(true)
 	return b
 }
 
 // WithParameter adds a parameter to the tool's input schema.
 func (b *ToolBuilder) WithParameter(name, paramType, description string, required bool) *ToolBuilder {
+	return b.withProperty(name, map[string]any{
+		"type":        paramType,
+		"description": description,
+	}, required)
+}
+
+// WithArrayParameter adds an array parameter whose items are of itemType.
+func (b *ToolBuilder) WithArrayParameter(name, itemType, description string, required bool) *ToolBuilder {
+	return b.withProperty(name, map[string]any{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]any{"type": itemType},
+	}, required)
+}
+
+// WithMapParameter adds an object parameter whose values are of valueType,
+// such as a label set.
+func (b *ToolBuilder) WithMapParameter(name, valueType, description string, required bool) *ToolBuilder {
+	return b.withProperty(name, map[string]any{
+		"type":                 "object",
+		"description":          description,
+		"additionalProperties": map[string]any{"type": valueType},
+	}, required)
+}
+
+// WithEnumParameter adds a string parameter restricted to values.
+func (b *ToolBuilder) WithEnumParameter(name, description string, values []string, required bool) *ToolBuilder {
+	return b.withProperty(name, map[string]any{
+		"type":        "string",
+		"description": description,
+		"enum":        values,
+	}, required)
+}
+
+// WithSchemaParameter adds a parameter described by an arbitrary JSON schema,
+// for values a single type cannot express (e.g. nullable or union types).
+func (b *ToolBuilder) WithSchemaParameter(name, description string, schema map[string]any, required bool) *ToolBuilder {
+	property := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		property[k] = v
+	}
+	property["description"] = description
+	return b.withProperty(name, property, required)
+}
+
+// WithDefault sets the default value of a previously added parameter. The SDK
+// applies defaults to incoming arguments before the handler runs.
+func (b *ToolBuilder) WithDefault(name string, value any) *ToolBuilder {
+	schema, ok := b.tool.InputSchema.(map[string]any)
+	if !ok {
+		return b
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	if property, ok := properties[name].(map[string]any); ok {
+		property["default"] = value
+	}
+	return b
+}
+
+// withProperty adds a property to the tool's input schema.
+func (b *ToolBuilder) withProperty(name string, property map[string]any, required bool) *ToolBuilder {
 	// Build JSON schema for the parameter
 	if b.tool.InputSchema == nil {
 		b.tool.InputSchema = map[string]any{
@@ -68,10 +161,7 @@ func (b *ToolBuilder) WithParameter(name, paramType, description string, require
 		schema["properties"] = properties
 	}
 
-	properties[name] = map[string]any{
-		"type":        paramType,
-		"description": description,
-	}
+	properties[name] = property
 
 	if required {
 		requiredList, ok := schema["required"].([]string)
@@ -95,8 +185,31 @@ func (b *ToolBuilder) WithParameter(name, paramType, description string, require
 	return b
 }
 
-// Build returns the built tool.
+// Build returns the built tool. Tools without parameters get an empty object
+// schema, as MCP requires every tool to publish one. List and get tools get
+// the output parameters the OutputShaper reads, unless they define their own.
 func (b *ToolBuilder) Build() *mcp.Tool {
+	if b.tool.InputSchema == nil {
+		b.tool.InputSchema = map[string]any{
+			"type":       "object",
+			"properties": make(map[string]any),
+		}
+	}
+	if ShapesOutput(b.tool) {
+		if schema, ok := b.tool.InputSchema.(map[string]any); ok {
+			properties, _ := schema["properties"].(map[string]any)
+			for name, parameter := range outputParameters {
+				if _, defined := properties[name]; defined {
+					continue
+				}
+				property := make(map[string]any, len(parameter))
+				for k, v := range parameter {
+					property[k] = v
+				}
+				b.withProperty(name, property, false)
+			}
+		}
+	}
 	return b.tool
 }
 
@@ -110,6 +223,38 @@ func ParseArguments[T any](req *mcp.CallToolRequest) (T, error) {
 	return args, nil
 }
 
+// ToolClass describes the effect a tool has on the cluster.
+type ToolClass string
+
+const (
+	// ToolClassRead tools only read state.
+	ToolClassRead ToolClass = "read"
+	// ToolClassWrite tools create or update state without removing it.
+	ToolClassWrite ToolClass = "write"
+	// ToolClassDestructive tools delete, interrupt or otherwise disrupt state.
+	ToolClassDestructive ToolClass = "destructive"
+)
+
+// ClassifyTool derives a tool's class from its annotations.
+// Tools without annotations are treated as destructive, matching the MCP
+// defaults (readOnlyHint=false, destructiveHint=true).
+func ClassifyTool(tool *mcp.Tool) ToolClass {
+	if tool == nil || tool.Annotations == nil {
+		return ToolClassDestructive
+	}
+	if tool.Annotations.ReadOnlyHint {
+		return ToolClassRead
+	}
+	if tool.Annotations.DestructiveHint != nil && !*tool.Annotations.DestructiveHint {
+		return ToolClassWrite
+	}
+	return ToolClassDestructive
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 // NewTextResult creates a text content result.
 func NewTextResult(text string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
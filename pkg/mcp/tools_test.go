@@ -0,0 +1,279 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/autoscaling"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/backup"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/capi"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/certs"
+	configToolset "github.com/wrkode/kube-mcp/pkg/toolsets/config"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/core"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/gitops"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/helm"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/kiali"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/kubevirt"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/net"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/policy"
+	"github.com/wrkode/kube-mcp/pkg/toolsets/rollouts"
+	"helm.sh/helm/v3/pkg/cli"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// ToolsTestSuite tests that registered tools match the toolset definitions.
+type ToolsTestSuite struct {
+	suite.Suite
+}
+
+// crdGVKs are the optional CRDs the toolsets detect.
+var crdGVKs = []schema.GroupVersionKind{
+	autoscaling.ScaledObjectGVK,
+	autoscaling.ScaledJobGVK,
+	backup.BackupGVK,
+	backup.RestoreGVK,
+	backup.BackupStorageLocationGVK,
+	backup.ScheduleGVK,
+	capi.ClusterGVK,
+	capi.MachineGVK,
+	capi.MachineDeploymentGVK,
+	certs.CertificateGVK,
+	certs.IssuerGVK,
+	certs.ClusterIssuerGVK,
+	certs.CertificateRequestGVK,
+	certs.OrderGVK,
+	certs.ChallengeGVK,
+	gitops.KustomizationGVK,
+	gitops.HelmReleaseGVK,
+	gitops.ApplicationGVK,
+	net.CiliumNetworkPolicyGVK,
+	net.CiliumClusterwideNetworkPolicyGVK,
+	policy.KyvernoClusterPolicyGVK,
+	policy.KyvernoPolicyGVK,
+	policy.KyvernoPolicyReportGVK,
+	policy.KyvernoClusterPolicyReportGVK,
+	policy.GatekeeperConstraintTemplateGVK,
+	policy.GatekeeperConstraintGVK,
+	rollouts.RolloutGVK,
+	rollouts.CanaryGVK,
+	{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachine"},
+	{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "DataSource"},
+	{Group: "instancetypes.kubevirt.io", Version: "v1beta1", Kind: "VirtualMachineInstancetype"},
+}
+
+// crdDiscovery serves crdGVKs as the server's preferred resources.
+type crdDiscovery struct {
+	discovery.DiscoveryInterface
+}
+
+func (d *crdDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	lists := make([]*metav1.APIResourceList, 0, len(crdGVKs))
+	for _, gvk := range crdGVKs {
+		lists = append(lists, &metav1.APIResourceList{
+			GroupVersion: gvk.GroupVersion().String(),
+			APIResources: []metav1.APIResource{{Name: gvk.Kind, Kind: gvk.Kind}},
+		})
+	}
+	return lists, nil
+}
+
+type mockProvider struct{}
+
+func (m *mockProvider) GetClientSet(context string) (*kubernetes.ClientSet, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) ListContexts() ([]string, error) {
+	return []string{}, nil
+}
+
+func (m *mockProvider) GetCurrentContext() (string, error) {
+	return "", nil
+}
+
+// allToolsets returns every toolset with all optional features enabled.
+func allToolsets(s *suite.Suite) []mcpHelpers.Toolset {
+	provider := &mockProvider{}
+	crds := kubernetes.NewCRDDiscovery(&kubernetes.ClientSet{
+		Discovery: &crdDiscovery{fake.NewSimpleClientset().Discovery()},
+	}, 0)
+	s.Require().NoError(crds.DiscoverCRDs(context.Background()))
+
+	kialiToolset, err := kiali.NewToolset(&config.KialiConfig{Enabled: true, URL: "http://kiali.example.com"})
+	s.Require().NoError(err)
+
+	return []mcpHelpers.Toolset{
+		configToolset.NewToolset(provider),
+		core.NewToolset(provider),
+		helm.NewToolset(provider, cli.New()),
+		kubevirt.NewToolset(provider, crds),
+		kialiToolset,
+		gitops.NewToolset(provider, crds),
+		policy.NewToolset(provider, crds),
+		capi.NewToolset(provider, crds),
+		rollouts.NewToolset(provider, crds),
+		certs.NewToolset(provider, crds),
+		autoscaling.NewToolset(provider, crds),
+		backup.NewToolset(provider, crds),
+		net.NewToolset(provider, crds, config.NetConfig{HubbleAPIURL: "http://hubble.example.com"}),
+	}
+}
+
+// connect connects an in-memory client to the server. The returned function
+// closes both sessions.
+func connect(s *suite.Suite, server *mcpHelpers.Server) (*mcp.ClientSession, func()) {
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+
+	return session, func() {
+		session.Close()
+		serverSession.Close()
+	}
+}
+
+// listTools returns the tools a client sees.
+func (s *ToolsTestSuite) listTools(server *mcpHelpers.Server) map[string]*mcp.Tool {
+	ctx := context.Background()
+	session, closeSessions := connect(&s.Suite, server)
+	defer closeSessions()
+
+	tools := make(map[string]*mcp.Tool)
+	for tool, err := range session.Tools(ctx, nil) {
+		s.Require().NoError(err)
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+// TestRegisteredToolsMatchDefinitions tests that every tool a client lists
+// carries the name, description, schema and annotations published by Tools().
+func (s *ToolsTestSuite) TestRegisteredToolsMatchDefinitions() {
+	for _, normalize := range []bool{false, true} {
+		server := mcpHelpers.NewServer("test", "1.0.0", normalize)
+		definitions := make(map[string]*mcp.Tool)
+		for _, toolset := range allToolsets(&s.Suite) {
+			s.Require().NoError(server.RegisterToolset(toolset))
+			for _, tool := range toolset.Tools() {
+				definitions[tool.Name] = tool
+			}
+		}
+
+		registered := s.listTools(server)
+		s.Len(registered, len(definitions), "normalize=%v", normalize)
+
+		for name, tool := range registered {
+			def, ok := definitions[server.GetOriginalToolName(name)]
+			if !s.True(ok, "tool %s has no definition in Tools()", name) {
+				continue
+			}
+			s.Equal(def.Description, tool.Description, name)
+			s.JSONEq(s.toJSON(def.InputSchema), s.toJSON(tool.InputSchema), name)
+			s.Equal(def.Annotations, tool.Annotations, name)
+			s.NotNil(tool.Annotations, "tool %s has no annotations", name)
+		}
+	}
+}
+
+// TestToolSchemas tests schema details that clients rely on.
+func (s *ToolsTestSuite) TestToolSchemas() {
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	for _, toolset := range allToolsets(&s.Suite) {
+		s.Require().NoError(server.RegisterToolset(toolset))
+	}
+	tools := s.listTools(server)
+
+	var exec struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Type  string `json:"type"`
+			Items struct {
+				Type string `json:"type"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(s.toJSON(tools["pods_exec"].InputSchema)), &exec))
+	s.ElementsMatch([]string{"name", "namespace", "command"}, exec.Required)
+	s.Equal("array", exec.Properties["command"].Type)
+	s.Equal("string", exec.Properties["command"].Items.Type)
+
+	var patch struct {
+		Properties map[string]struct {
+			Enum    []string `json:"enum"`
+			Default string   `json:"default"`
+		} `json:"properties"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(s.toJSON(tools["resources_patch"].InputSchema)), &patch))
+	s.Equal([]string{"merge", "json", "strategic"}, patch.Properties["patch_type"].Enum)
+	s.Equal("merge", patch.Properties["patch_type"].Default)
+
+	// List and get tools accept the output parameters, other tools do not
+	var list, del struct {
+		Properties map[string]any `json:"properties"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(s.toJSON(tools["pods_list"].InputSchema)), &list))
+	s.Require().NoError(json.Unmarshal([]byte(s.toJSON(tools["pods_delete"].InputSchema)), &del))
+	for _, name := range []string{"fields", "output", "max_bytes", "cursor"} {
+		s.Contains(list.Properties, name)
+	}
+	s.NotContains(del.Properties, "fields")
+
+	s.True(tools["pods_list"].Annotations.ReadOnlyHint)
+	s.True(tools["pods_list"].Annotations.IdempotentHint)
+	s.Equal(false, *tools["pods_list"].Annotations.OpenWorldHint)
+	s.Equal(true, *tools["pods_delete"].Annotations.DestructiveHint)
+	s.Equal(true, *tools["secrets_set_data"].Annotations.DestructiveHint, "Replacing data removes keys")
+	s.Equal(true, *tools["configmaps_set_data"].Annotations.DestructiveHint)
+	s.Equal(true, *tools["resources_patch"].Annotations.DestructiveHint, "Patches can remove fields")
+	s.Equal(true, *tools["resources_apply"].Annotations.DestructiveHint)
+	s.True(tools["resources_apply"].Annotations.IdempotentHint)
+	s.False(tools["pods_exec"].Annotations.IdempotentHint)
+	s.Equal(true, *tools["helm_install"].Annotations.OpenWorldHint)
+}
+
+// TestArgumentsValidated tests that calls are checked against the published schema.
+func (s *ToolsTestSuite) TestArgumentsValidated() {
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	s.Require().NoError(server.RegisterToolset(core.NewToolset(&mockProvider{})))
+
+	ctx := context.Background()
+	session, closeSessions := connect(&s.Suite, server)
+	defer closeSessions()
+
+	_, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "pods_exec",
+		Arguments: map[string]any{"name": "web", "namespace": "default", "command": "ls"},
+	})
+	s.Error(err, "command must be an array")
+
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "pods_get",
+		Arguments: map[string]any{"name": "web"},
+	})
+	s.Error(err, "namespace is required")
+}
+
+func (s *ToolsTestSuite) toJSON(v any) string {
+	data, err := json.Marshal(v)
+	s.Require().NoError(err)
+	return string(data)
+}
+
+// TestToolsTestSuite runs the tools test suite.
+func TestToolsTestSuite(t *testing.T) {
+	suite.Run(t, new(ToolsTestSuite))
+}
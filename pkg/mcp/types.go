@@ -18,15 +18,17 @@ type Toolset interface {
 
 // ToolRegistry manages tool registration and dispatch.
 type ToolRegistry struct {
-	toolsets map[string]Toolset
-	tools    map[string]*mcp.Tool
+	toolsets  map[string]Toolset
+	tools     map[string]*mcp.Tool
+	toolsetOf map[string]string // tool name -> toolset name
 }
 
 // NewToolRegistry creates a new tool registry.
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
-		toolsets: make(map[string]Toolset),
-		tools:    make(map[string]*mcp.Tool),
+		toolsets:  make(map[string]Toolset),
+		tools:     make(map[string]*mcp.Tool),
+		toolsetOf: make(map[string]string),
 	}
 }
 
@@ -42,6 +44,7 @@ func (r *ToolRegistry) RegisterToolset(toolset Toolset) error {
 	// Store tools (with normalized names if needed)
 	for _, tool := range toolset.Tools() {
 		r.tools[tool.Name] = tool
+		r.toolsetOf[tool.Name] = name
 	}
 
 	return nil
@@ -53,6 +56,12 @@ func (r *ToolRegistry) GetTool(name string) (*mcp.Tool, bool) {
 	return tool, ok
 }
 
+// ToolsetOf returns the name of the toolset that publishes a tool, or "" if
+// no toolset lists it.
+func (r *ToolRegistry) ToolsetOf(toolName string) string {
+	return r.toolsetOf[toolName]
+}
+
 // ListTools returns all registered tools.
 // Note: Tool name normalization happens when tools are registered via AddTool wrapper.
 func (r *ToolRegistry) ListTools() []*mcp.Tool {
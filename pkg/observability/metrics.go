@@ -13,6 +13,9 @@ type Metrics struct {
 	toolLatency       *prometheus.HistogramVec
 	httpRequestsTotal *prometheus.CounterVec
 	httpLatency       *prometheus.HistogramVec
+	rateLimitedTotal  *prometheus.CounterVec
+	sessionsTotal     *prometheus.CounterVec
+	factory           promauto.Factory
 }
 
 // NewMetrics creates a new metrics collector.
@@ -26,6 +29,7 @@ func NewMetrics(registry prometheus.Registerer) *Metrics {
 	factory := promauto.With(registry)
 
 	return &Metrics{
+		factory: factory,
 		toolCallsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "kube_mcp_tool_calls_total",
@@ -56,6 +60,20 @@ func NewMetrics(registry prometheus.Registerer) *Metrics {
 			},
 			[]string{"method", "path"},
 		),
+		rateLimitedTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_mcp_rate_limited_total",
+				Help: "Total number of tool calls and HTTP requests rejected by rate limits",
+			},
+			[]string{"layer", "scope", "tool"},
+		),
+		sessionsTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_mcp_sessions_total",
+				Help: "Total number of MCP sessions opened over HTTP",
+			},
+			[]string{"transport"},
+		),
 	}
 }
 
@@ -75,3 +93,28 @@ func (m *Metrics) RecordHTTPRequest(method, path string, statusCode int, latency
 	m.httpRequestsTotal.WithLabelValues(method, path, statusLabel).Inc()
 	m.httpLatency.WithLabelValues(method, path).Observe(latencySeconds)
 }
+
+// RecordRateLimited records a call rejected by a rate limit. layer is "tool"
+// or "http", scope is the limit that rejected it, and tool is empty for HTTP
+// requests.
+func (m *Metrics) RecordRateLimited(layer, scope, tool string) {
+	m.rateLimitedTotal.WithLabelValues(layer, scope, tool).Inc()
+}
+
+// RecordSession records an MCP session opened over the transport,
+// "streamable" or "sse".
+func (m *Metrics) RecordSession(transport string) {
+	m.sessionsTotal.WithLabelValues(transport).Inc()
+}
+
+// RegisterActiveSessions exposes the number of open MCP sessions, as
+// returned by count, as a gauge. It must be called at most once.
+func (m *Metrics) RegisterActiveSessions(count func() int) {
+	m.factory.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "kube_mcp_sessions_active",
+			Help: "Number of open MCP sessions",
+		},
+		func() float64 { return float64(count()) },
+	)
+}
@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Middleware rejects tool calls that exceed the global, per-identity,
+// per-context or per-tool limits, and holds their concurrency slots while
+// they run.
+func (l *Limiter) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			if !l.Enabled() {
+				return next(ctx, call)
+			}
+
+			release, denial := l.acquire(l.toolChecks(ctx, call), "tool", call.Name)
+			if denial != nil {
+				return rateLimitedResult(call.Name, denial)
+			}
+			defer release()
+			return next(ctx, call)
+		}
+	}
+}
+
+// toolChecks returns the limits that apply to a tool call.
+func (l *Limiter) toolChecks(ctx context.Context, call *mcpHelpers.ToolCall) []check {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	caller := toolCaller(ctx, call)
+	checks := []check{
+		{key{ScopeGlobal, ""}, l.cfg.Global, "All tool calls"},
+		{key{ScopeIdentity, caller}, l.cfg.PerIdentity, "Tool calls per caller"},
+	}
+
+	contextName := mcpHelpers.TargetOf(call.Name, call.Arguments).Context
+	contextLimit, ok := l.cfg.Contexts[contextName]
+	if !ok {
+		contextLimit = l.cfg.PerContext
+	}
+	reason := fmt.Sprintf("Tool calls against context %s", contextName)
+	if contextName == "" {
+		reason = "Tool calls against the default context"
+	}
+	checks = append(checks, check{key{ScopeContext, contextName}, contextLimit, reason})
+
+	if toolLimit, ok := l.cfg.Tools[call.Name]; ok {
+		checks = append(checks, check{key{ScopeTool, caller + "\x00" + call.Name}, toolLimit,
+			fmt.Sprintf("Calls to %s per caller", call.Name)})
+	}
+	return checks
+}
+
+// toolCaller identifies the caller of a tool call: the authenticated user, or
+// the MCP session for unauthenticated callers.
+func toolCaller(ctx context.Context, call *mcpHelpers.ToolCall) string {
+	if identity := auth.IdentityFromContext(ctx); identity != nil && identity.Username != "" {
+		return "user:" + identity.Username
+	}
+	if call.Request != nil && call.Request.Session != nil && call.Request.Session.ID() != "" {
+		return "session:" + call.Request.Session.ID()
+	}
+	return "anonymous"
+}
+
+// rateLimitedResult builds a structured error for a rejected tool call.
+func rateLimitedResult(tool string, denial *Denial) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":                "RateLimited",
+			"message":             fmt.Sprintf("Rate limit exceeded for tool %s; retry after %ds", tool, denial.RetryAfterSeconds()),
+			"details":             denial.Reason,
+			"scope":               denial.Scope,
+			"retry_after_seconds": denial.RetryAfterSeconds(),
+			"tool":                tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
+
+// HTTPMiddleware rejects requests to the MCP endpoint that exceed the
+// per-client HTTP limit with 429 Too Many Requests and a Retry-After header.
+// Clients are identified by their authenticated user, so it must run after
+// authentication, or by their address otherwise.
+func (l *Limiter) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l.mu.Lock()
+		limit := l.cfg.HTTP
+		l.mu.Unlock()
+
+		release, denial := l.acquire([]check{
+			{key{ScopeHTTP, httpClient(r)}, limit, "HTTP requests per client"},
+		}, "http", "")
+		if denial != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(denial.RetryAfterSeconds()))
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]any{
+					"type":                "RateLimited",
+					"message":             "Rate limit exceeded",
+					"details":             denial.Reason,
+					"scope":               denial.Scope,
+					"retry_after_seconds": denial.RetryAfterSeconds(),
+				},
+			})
+			return
+		}
+		defer release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpClient identifies the client of an HTTP request.
+func httpClient(r *http.Request) string {
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil && identity.Username != "" {
+		return "user:" + identity.Username
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
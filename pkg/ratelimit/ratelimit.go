@@ -0,0 +1,257 @@
+// Package ratelimit enforces the [rate_limit] settings: token-bucket rate
+// limits and concurrency limits on tool calls and HTTP requests.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/observability"
+	"golang.org/x/time/rate"
+)
+
+// Scopes of the limits, as reported in rejections and metrics.
+const (
+	ScopeGlobal   = "global"
+	ScopeIdentity = "identity"
+	ScopeContext  = "context"
+	ScopeTool     = "tool"
+	ScopeHTTP     = "http"
+)
+
+// concurrencyRetryAfter is the retry hint given when a concurrency limit is
+// reached, since there is no way to tell when a running call will finish.
+const concurrencyRetryAfter = time.Second
+
+// idleTimeout is how long an unused bucket is kept before it is dropped.
+const idleTimeout = 10 * time.Minute
+
+// Denial describes why a call was rejected.
+type Denial struct {
+	// Scope is the limit that rejected the call.
+	Scope string
+
+	// Reason is a human-readable description of the limit.
+	Reason string
+
+	// RetryAfter is when the call would be allowed again, as far as known.
+	RetryAfter time.Duration
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to whole seconds, at least 1.
+func (d *Denial) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(d.RetryAfter.Seconds())))
+}
+
+// key identifies a bucket: the scope and the caller, tool or context it limits.
+type key struct {
+	scope string
+	name  string
+}
+
+// bucket holds the token bucket and in-flight count of one key.
+type bucket struct {
+	rate     *rate.Limiter
+	inFlight int
+	lastUsed time.Time
+}
+
+// check is a limit to apply to a call.
+type check struct {
+	key    key
+	limit  config.RateLimit
+	reason string
+}
+
+// Limiter enforces rate and concurrency limits. Buckets are created on first
+// use and dropped after they have been idle for a while.
+type Limiter struct {
+	mu        sync.Mutex
+	cfg       config.RateLimitConfig
+	buckets   map[key]*bucket
+	metrics   *observability.Metrics
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewLimiter creates a limiter from the rate limit configuration.
+func NewLimiter(cfg *config.RateLimitConfig) (*Limiter, error) {
+	l := &Limiter{now: time.Now}
+	if err := l.Update(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// SetMetrics sets the metrics throttled calls are recorded in.
+func (l *Limiter) SetMetrics(metrics *observability.Metrics) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.metrics = metrics
+}
+
+// Update replaces the configuration, e.g. on reload. Buckets start full
+// again; calls already running keep counting against the old limits.
+func (l *Limiter) Update(cfg *config.RateLimitConfig) error {
+	if err := validate(cfg); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = *cfg
+	l.buckets = make(map[key]*bucket)
+	return nil
+}
+
+// Enabled reports whether rate limiting is enabled.
+func (l *Limiter) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cfg.Enabled
+}
+
+func validate(cfg *config.RateLimitConfig) error {
+	checkLimit := func(name string, limit config.RateLimit) error {
+		if limit.RPS < 0 || limit.Burst < 0 || limit.MaxConcurrent < 0 {
+			return fmt.Errorf("invalid rate_limit.%s: rps, burst and max_concurrent must not be negative", name)
+		}
+		return nil
+	}
+
+	limits := map[string]config.RateLimit{
+		"global":       cfg.Global,
+		"per_identity": cfg.PerIdentity,
+		"per_context":  cfg.PerContext,
+		"http":         cfg.HTTP,
+	}
+	for name, limit := range cfg.Contexts {
+		limits["contexts."+name] = limit
+	}
+	for name, limit := range cfg.Tools {
+		limits["tools."+name] = limit
+	}
+	for name, limit := range limits {
+		if err := checkLimit(name, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limited reports whether a limit restricts anything.
+func limited(limit config.RateLimit) bool {
+	return limit.RPS > 0 || limit.MaxConcurrent > 0
+}
+
+// acquire applies the checks to a call. If all of them allow it, the call's
+// concurrency slots are taken and the returned release func frees them. The
+// call's tokens are only consumed if every check allows it.
+func (l *Limiter) acquire(checks []check, layer, tool string) (func(), *Denial) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	type slot struct {
+		check
+		bucket *bucket
+	}
+	slots := make([]slot, 0, len(checks))
+	for _, c := range checks {
+		if !limited(c.limit) {
+			continue
+		}
+		b := l.buckets[c.key]
+		if b == nil {
+			b = newBucket(c.limit)
+			l.buckets[c.key] = b
+		}
+		b.lastUsed = now
+
+		if c.limit.MaxConcurrent > 0 && b.inFlight >= c.limit.MaxConcurrent {
+			return nil, l.deny(layer, tool, &Denial{
+				Scope:      c.key.scope,
+				Reason:     fmt.Sprintf("%s: at most %d concurrent calls", c.reason, c.limit.MaxConcurrent),
+				RetryAfter: concurrencyRetryAfter,
+			})
+		}
+		slots = append(slots, slot{check: c, bucket: b})
+	}
+
+	// Reserve a token from every bucket; if any has none left, give the
+	// reserved tokens back so the rejected call does not use up other limits.
+	var denial *Denial
+	reservations := make([]*rate.Reservation, 0, len(slots))
+	for _, s := range slots {
+		if s.bucket.rate == nil {
+			continue
+		}
+		reservation := s.bucket.rate.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		if delay := reservation.DelayFrom(now); delay > 0 && (denial == nil || delay > denial.RetryAfter) {
+			denial = &Denial{
+				Scope:      s.key.scope,
+				Reason:     fmt.Sprintf("%s: at most %g calls per second", s.reason, s.limit.RPS),
+				RetryAfter: delay,
+			}
+		}
+	}
+	if denial != nil {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+		return nil, l.deny(layer, tool, denial)
+	}
+
+	for _, s := range slots {
+		s.bucket.inFlight++
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, s := range slots {
+				s.bucket.inFlight--
+			}
+		})
+	}, nil
+}
+
+// deny records a rejection in the metrics and returns the denial.
+func (l *Limiter) deny(layer, tool string, denial *Denial) *Denial {
+	if l.metrics != nil {
+		l.metrics.RecordRateLimited(layer, denial.Scope, tool)
+	}
+	return denial
+}
+
+// sweep drops idle buckets, at most once a minute.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		if b.inFlight == 0 && now.Sub(b.lastUsed) > idleTimeout {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func newBucket(limit config.RateLimit) *bucket {
+	b := &bucket{}
+	if limit.RPS > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = max(1, int(math.Ceil(limit.RPS)))
+		}
+		b.rate = rate.NewLimiter(rate.Limit(limit.RPS), burst)
+	}
+	return b
+}
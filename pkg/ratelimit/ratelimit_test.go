@@ -0,0 +1,218 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"github.com/wrkode/kube-mcp/pkg/observability"
+)
+
+// RateLimitTestSuite tests rate and concurrency limits on tool calls and HTTP requests.
+type RateLimitTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *RateLimitTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// limiter creates a limiter using the suite's clock.
+func (s *RateLimitTestSuite) limiter(cfg config.RateLimitConfig) *Limiter {
+	cfg.Enabled = true
+	l, err := NewLimiter(&cfg)
+	s.Require().NoError(err)
+	l.now = func() time.Time { return s.now }
+	return l
+}
+
+// call runs a tool call through the limiter's middleware as the given user.
+func (s *RateLimitTestSuite) call(l *Limiter, user, tool string, args map[string]any) *mcp.CallToolResult {
+	ctx := context.Background()
+	if user != "" {
+		ctx = auth.WithIdentity(ctx, &auth.Identity{Username: user})
+	}
+	next := func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		return mcpHelpers.NewTextResult("ok"), nil
+	}
+	result, err := l.Middleware()(next)(ctx, &mcpHelpers.ToolCall{Name: tool, Arguments: args})
+	s.Require().NoError(err)
+	return result
+}
+
+// rejection decodes the structured error of a rejected call.
+func (s *RateLimitTestSuite) rejection(result *mcp.CallToolResult) map[string]any {
+	s.Require().True(result.IsError, "Call should be rejected")
+	var body struct {
+		Error map[string]any `json:"error"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &body))
+	s.Equal("RateLimited", body.Error["type"])
+	return body.Error
+}
+
+// TestToolLimit tests a per-tool limit, which applies to each caller separately.
+func (s *RateLimitTestSuite) TestToolLimit() {
+	l := s.limiter(config.RateLimitConfig{
+		Tools: map[string]config.RateLimit{"resources_watch": {RPS: 0.5, Burst: 2}},
+	})
+
+	s.False(s.call(l, "alice", "resources_watch", nil).IsError)
+	s.False(s.call(l, "alice", "resources_watch", nil).IsError)
+	rejection := s.rejection(s.call(l, "alice", "resources_watch", nil))
+	s.Equal(ScopeTool, rejection["scope"])
+	s.Equal(float64(2), rejection["retry_after_seconds"])
+	s.Equal("resources_watch", rejection["tool"])
+
+	s.False(s.call(l, "alice", "pods_list", nil).IsError, "Other tools should not be limited")
+	s.False(s.call(l, "bob", "resources_watch", nil).IsError, "Other callers should not be limited")
+
+	s.now = s.now.Add(2 * time.Second)
+	s.False(s.call(l, "alice", "resources_watch", nil).IsError, "Tokens should be refilled")
+}
+
+// TestDeniedCallsConsumeNothing tests that a call rejected by one limit does
+// not use up tokens of the others.
+func (s *RateLimitTestSuite) TestDeniedCallsConsumeNothing() {
+	l := s.limiter(config.RateLimitConfig{
+		Global:      config.RateLimit{RPS: 1, Burst: 3},
+		PerIdentity: config.RateLimit{RPS: 1, Burst: 1},
+	})
+
+	s.False(s.call(l, "alice", "pods_list", nil).IsError)
+	for i := 0; i < 5; i++ {
+		s.Equal(ScopeIdentity, s.rejection(s.call(l, "alice", "pods_list", nil))["scope"])
+	}
+	s.False(s.call(l, "bob", "pods_list", nil).IsError)
+	s.False(s.call(l, "carol", "pods_list", nil).IsError)
+	s.Equal(ScopeGlobal, s.rejection(s.call(l, "dave", "pods_list", nil))["scope"])
+}
+
+// TestContextLimit tests per-context limits and their overrides.
+func (s *RateLimitTestSuite) TestContextLimit() {
+	l := s.limiter(config.RateLimitConfig{
+		PerContext: config.RateLimit{RPS: 1, Burst: 1},
+		Contexts:   map[string]config.RateLimit{"prod": {RPS: 1, Burst: 2}},
+	})
+
+	s.False(s.call(l, "alice", "pods_list", nil).IsError)
+	s.Equal(ScopeContext, s.rejection(s.call(l, "bob", "pods_list", nil))["scope"])
+
+	prod := map[string]any{"context": "prod"}
+	s.False(s.call(l, "alice", "pods_list", prod).IsError)
+	s.False(s.call(l, "bob", "pods_list", prod).IsError)
+	s.True(s.call(l, "carol", "pods_list", prod).IsError)
+
+	s.False(s.call(l, "alice", "pods_list", map[string]any{"context": "staging"}).IsError)
+}
+
+// TestConcurrency tests that running calls hold their slots until they finish.
+func (s *RateLimitTestSuite) TestConcurrency() {
+	l := s.limiter(config.RateLimitConfig{
+		PerIdentity: config.RateLimit{MaxConcurrent: 1},
+	})
+
+	var inner *mcp.CallToolResult
+	next := func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		inner = s.call(l, "alice", "pods_list", nil)
+		return mcpHelpers.NewTextResult("ok"), nil
+	}
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: "alice"})
+	result, err := l.Middleware()(next)(ctx, &mcpHelpers.ToolCall{Name: "resources_watch"})
+	s.Require().NoError(err)
+	s.False(result.IsError)
+	rejection := s.rejection(inner)
+	s.Equal(ScopeIdentity, rejection["scope"])
+	s.Contains(rejection["details"], "at most 1 concurrent calls")
+
+	s.False(s.call(l, "alice", "pods_list", nil).IsError, "The slot should be released")
+}
+
+// TestHTTPMiddleware tests per-client limits on HTTP requests.
+func (s *RateLimitTestSuite) TestHTTPMiddleware() {
+	l := s.limiter(config.RateLimitConfig{
+		HTTP: config.RateLimit{RPS: 0.2, Burst: 1},
+	})
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	s.Equal(http.StatusOK, request("10.0.0.1:1234").Code)
+	rec := request("10.0.0.1:5678")
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.Equal("5", rec.Header().Get("Retry-After"))
+	s.Contains(rec.Body.String(), `"type":"RateLimited"`)
+	s.Equal(http.StatusOK, request("10.0.0.2:1234").Code)
+}
+
+// TestMetrics tests that throttled calls are counted.
+func (s *RateLimitTestSuite) TestMetrics() {
+	registry := prometheus.NewRegistry()
+	l := s.limiter(config.RateLimitConfig{
+		Tools: map[string]config.RateLimit{"resources_relationships": {RPS: 1, Burst: 1}},
+	})
+	l.SetMetrics(observability.NewMetrics(registry))
+
+	for i := 0; i < 3; i++ {
+		s.call(l, "alice", "resources_relationships", nil)
+	}
+
+	families, err := registry.Gather()
+	s.Require().NoError(err)
+	var throttled float64
+	for _, family := range families {
+		if family.GetName() != "kube_mcp_rate_limited_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			s.Equal(map[string]string{"layer": "tool", "scope": ScopeTool, "tool": "resources_relationships"}, labels)
+			throttled += metric.GetCounter().GetValue()
+		}
+	}
+	s.Equal(float64(2), throttled)
+}
+
+// TestUpdate tests disabling, reloading and validating the configuration.
+func (s *RateLimitTestSuite) TestUpdate() {
+	l := s.limiter(config.RateLimitConfig{Global: config.RateLimit{RPS: 1, Burst: 1}})
+	s.False(s.call(l, "alice", "pods_list", nil).IsError)
+	s.True(s.call(l, "alice", "pods_list", nil).IsError)
+
+	s.Require().NoError(l.Update(&config.RateLimitConfig{Enabled: true, Global: config.RateLimit{RPS: 1, Burst: 2}}))
+	s.False(s.call(l, "alice", "pods_list", nil).IsError)
+	s.False(s.call(l, "alice", "pods_list", nil).IsError)
+
+	s.Require().NoError(l.Update(&config.RateLimitConfig{Global: config.RateLimit{RPS: 1, Burst: 1}}))
+	for i := 0; i < 3; i++ {
+		s.False(s.call(l, "alice", "pods_list", nil).IsError, "Limits should not apply when disabled")
+	}
+
+	s.Error(l.Update(&config.RateLimitConfig{Tools: map[string]config.RateLimit{"pods_exec": {RPS: -1}}}))
+}
+
+// TestRateLimitTestSuite runs the rate limit test suite.
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
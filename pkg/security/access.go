@@ -0,0 +1,258 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// resourceToolsets are the toolsets of the read-only tools MCP resources are
+// checked as.
+var resourceToolsets = map[string]string{
+	"resources_get":      "core",
+	"pods_logs":          "core",
+	"helm_releases_list": "helm",
+}
+
+// Access enforces the per-identity access rules: which contexts, namespaces,
+// toolsets and tools each caller may use. The rules can be replaced at runtime
+// with Update.
+type Access struct {
+	mu       sync.RWMutex
+	enabled  bool
+	rules    []config.AccessRule
+	provider kubernetes.ClientProvider
+}
+
+// NewAccess creates an access policy from the access configuration. The
+// provider resolves the default context of calls that do not name one.
+func NewAccess(cfg *config.AccessConfig, provider kubernetes.ClientProvider) (*Access, error) {
+	a := &Access{provider: provider}
+	if err := a.Update(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update replaces the access rules, e.g. on reload.
+func (a *Access) Update(cfg *config.AccessConfig) error {
+	for i, rule := range cfg.Rules {
+		for _, patterns := range [][]string{rule.Tools, rule.Contexts, rule.Namespaces} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid security.access.rules[%d] pattern %q: %w", i, pattern, err)
+				}
+			}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enabled = cfg.Enabled
+	a.rules = slices.Clone(cfg.Rules)
+	return nil
+}
+
+// callerRules returns whether the policy is enabled and the rules that apply
+// to the caller in ctx.
+func (a *Access) callerRules(ctx context.Context) (bool, []config.AccessRule) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !a.enabled {
+		return false, nil
+	}
+
+	identity := auth.IdentityFromContext(ctx)
+	var rules []config.AccessRule
+	for _, rule := range a.rules {
+		if ruleAppliesTo(rule, identity) {
+			rules = append(rules, rule)
+		}
+	}
+	return true, rules
+}
+
+// ToolListFilter lists only the tools the caller may use in some context and
+// namespace.
+func (a *Access) ToolListFilter() mcpHelpers.ToolListFilter {
+	return func(ctx context.Context, tool *mcp.Tool, toolset string) bool {
+		enabled, rules := a.callerRules(ctx)
+		if !enabled {
+			return true
+		}
+		class := mcpHelpers.ClassifyTool(tool)
+		for _, rule := range rules {
+			if ruleAllowsTool(rule, tool.Name, toolset, class) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Middleware rejects calls to tools, contexts and namespaces the caller may
+// not use, before they reach the cluster.
+func (a *Access) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			target := mcpHelpers.TargetOf(call.Name, call.Arguments)
+			if reason := a.denied(ctx, call.Name, call.Toolset, call.Class, target); reason != "" {
+				return accessDeniedResult(call.Name, fmt.Sprintf("Access to tool %s denied", call.Name), reason)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// CheckResource decides whether the caller may read a resource, checked as a
+// call to the read-only tool that returns the same data.
+func (a *Access) CheckResource(ctx context.Context, toolName string, target mcpHelpers.Target) error {
+	if reason := a.denied(ctx, toolName, resourceToolsets[toolName], mcpHelpers.ToolClassRead, target); reason != "" {
+		return fmt.Errorf("access denied: %s", reason)
+	}
+	return nil
+}
+
+// CheckContext decides whether the caller may read resources in a context,
+// in some namespace, checked as a call to the read-only tool that returns the
+// same data.
+func (a *Access) CheckContext(ctx context.Context, toolName, contextName string) error {
+	enabled, rules := a.callerRules(ctx)
+	if !enabled {
+		return nil
+	}
+	if contextName == "" && a.provider != nil {
+		contextName, _ = a.provider.GetCurrentContext()
+	}
+	for _, rule := range rules {
+		if ruleAllowsTool(rule, toolName, resourceToolsets[toolName], mcpHelpers.ToolClassRead) && matchesAny(rule.Contexts, contextName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("access denied: the caller may not use tool %s in context %s", toolName, contextName)
+}
+
+// denied returns why the caller may not use the tool against the target, or
+// "" if a rule allows it.
+func (a *Access) denied(ctx context.Context, toolName, toolset string, class mcpHelpers.ToolClass, target mcpHelpers.Target) string {
+	enabled, rules := a.callerRules(ctx)
+	if !enabled {
+		return ""
+	}
+
+	caller := "Unauthenticated callers"
+	if identity := auth.IdentityFromContext(ctx); identity != nil {
+		caller = "User " + identity.Username
+	}
+	if len(rules) == 0 {
+		return caller + " match no rule in security.access.rules"
+	}
+
+	contextName := target.Context
+	if contextName == "" && a.provider != nil {
+		contextName, _ = a.provider.GetCurrentContext()
+	}
+
+	toolAllowed := false
+	for _, rule := range rules {
+		if !ruleAllowsTool(rule, toolName, toolset, class) {
+			continue
+		}
+		toolAllowed = true
+		if matchesAny(rule.Contexts, contextName) && ruleAllowsNamespace(rule, target.Namespace) {
+			return ""
+		}
+	}
+
+	if !toolAllowed {
+		return fmt.Sprintf("%s may not use tool %s", caller, toolName)
+	}
+	if target.Namespace == "" {
+		return fmt.Sprintf("%s may not use tool %s in context %s without a namespace; pass a namespace the caller has access to", caller, toolName, contextName)
+	}
+	return fmt.Sprintf("%s may not use tool %s in namespace %s of context %s", caller, toolName, target.Namespace, contextName)
+}
+
+// ruleAppliesTo reports whether a rule applies to the identity. Rules without
+// users and groups apply to every caller, including unauthenticated ones.
+func ruleAppliesTo(rule config.AccessRule, identity *auth.Identity) bool {
+	if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+		return true
+	}
+	if identity == nil {
+		return false
+	}
+	if slices.Contains(rule.Users, identity.Username) {
+		return true
+	}
+	for _, group := range identity.Groups {
+		if slices.Contains(rule.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAllowsTool reports whether a rule allows a tool, in some context and
+// namespace.
+func ruleAllowsTool(rule config.AccessRule, toolName, toolset string, class mcpHelpers.ToolClass) bool {
+	if rule.ReadOnly && class != mcpHelpers.ToolClassRead {
+		return false
+	}
+	if len(rule.Toolsets) == 0 && len(rule.Tools) == 0 {
+		return true
+	}
+	if toolset != "" && slices.Contains(rule.Toolsets, toolset) {
+		return true
+	}
+	return len(rule.Tools) > 0 && matchesAny(rule.Tools, toolName)
+}
+
+// ruleAllowsNamespace reports whether a rule allows the namespace. Rules
+// restricted to namespaces do not allow calls without one, such as lists
+// across all namespaces and calls on cluster-scoped objects.
+func ruleAllowsNamespace(rule config.AccessRule, namespace string) bool {
+	if len(rule.Namespaces) == 0 {
+		return true
+	}
+	return namespace != "" && matchesAny(rule.Namespaces, namespace)
+}
+
+// matchesAny reports whether value matches one of the glob patterns. An empty
+// pattern list matches every value.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// accessDeniedResult builds a structured error for a call the caller may not make.
+func accessDeniedResult(tool, message, details string) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "AccessDenied",
+			"message": message,
+			"details": details,
+			"tool":    tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
@@ -0,0 +1,256 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// fakeRolloutsToolset registers dotted tool names, like the rollouts toolset.
+type fakeRolloutsToolset struct{}
+
+func (f *fakeRolloutsToolset) Name() string { return "rollouts" }
+
+func (f *fakeRolloutsToolset) Tools() []*mcp.Tool {
+	return []*mcp.Tool{
+		mcpHelpers.NewTool("rollouts.get_status", "Read").WithReadOnly().Build(),
+		mcpHelpers.NewTool("rollouts.promote", "Write").WithNonDestructive().Build(),
+	}
+}
+
+func (f *fakeRolloutsToolset) RegisterTools(server *mcp.Server) error {
+	handler := func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		return mcpHelpers.NewTextResult("ok"), nil, nil
+	}
+	for _, tool := range f.Tools() {
+		mcpHelpers.AddTool(server, &mcp.Tool{Name: tool.Name, Description: tool.Description}, handler)
+	}
+	return nil
+}
+
+// bearerTransport sends a fixed bearer token with every request.
+type bearerTransport struct {
+	token string
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// AccessTestSuite tests per-identity access rules.
+type AccessTestSuite struct {
+	suite.Suite
+	groups map[string][]string
+}
+
+func (s *AccessTestSuite) SetupTest() {
+	s.groups = map[string][]string{
+		"alice": {"platform"},
+		"bob":   {"team-a"},
+		"carol": {"team-b"},
+	}
+}
+
+// rules grants the platform team everything, and team-a the fake toolset's
+// read tools and the rollouts tools in its own namespaces.
+func (s *AccessTestSuite) rules() *config.AccessConfig {
+	return &config.AccessConfig{
+		Enabled: true,
+		Rules: []config.AccessRule{
+			{Groups: []string{"platform"}},
+			{Groups: []string{"team-a"}, Toolsets: []string{"fake"}, ReadOnly: true, Namespaces: []string{"team-a-*"}},
+			{Groups: []string{"team-a"}, Tools: []string{"rollouts.*"}, Namespaces: []string{"team-a-*"}},
+		},
+	}
+}
+
+// connect serves a server with the access policy over HTTP and connects as
+// user, whose bearer token is the username.
+func (s *AccessTestSuite) connect(access *Access, user string) *mcp.ClientSession {
+	server := mcpHelpers.NewServer("test", "0.0.0", false)
+	server.AddToolListFilter(access.ToolListFilter())
+	server.UseToolMiddleware(access.Middleware())
+	s.Require().NoError(server.RegisterToolset(&fakeToolset{}))
+	s.Require().NoError(server.RegisterToolset(&fakeRolloutsToolset{}))
+
+	verify := func(ctx context.Context, token string, _ *http.Request) (*sdkauth.TokenInfo, error) {
+		identity := &auth.Identity{Username: token, Groups: s.groups[token]}
+		return auth.NewTokenInfo(identity, token, nil, time.Now().Add(time.Hour)), nil
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server.GetSDKServer() }, nil)
+	httpServer := httptest.NewServer(sdkauth.RequireBearerToken(verify, nil)(handler))
+	s.T().Cleanup(httpServer.Close)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   httpServer.URL,
+		HTTPClient: &http.Client{Transport: &bearerTransport{token: user}},
+	}, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func (s *AccessTestSuite) access(cfg *config.AccessConfig) *Access {
+	access, err := NewAccess(cfg, &clientSetProvider{})
+	s.Require().NoError(err)
+	return access
+}
+
+func (s *AccessTestSuite) listToolNames(session *mcp.ClientSession) []string {
+	result, err := session.ListTools(context.Background(), nil)
+	s.Require().NoError(err)
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *AccessTestSuite) call(session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	s.Require().NoError(err)
+	return result
+}
+
+// TestToolsList tests that each session lists only the tools its caller may use.
+func (s *AccessTestSuite) TestToolsList() {
+	access := s.access(s.rules())
+
+	s.Equal([]string{"fake_apply", "fake_delete", "fake_get", "rollouts.get_status", "rollouts.promote"},
+		s.listToolNames(s.connect(access, "alice")))
+	s.Equal([]string{"fake_get", "rollouts.get_status", "rollouts.promote"},
+		s.listToolNames(s.connect(access, "bob")))
+	s.Empty(s.listToolNames(s.connect(access, "carol")))
+}
+
+// TestCalls tests that calls outside the caller's tools and namespaces are rejected.
+func (s *AccessTestSuite) TestCalls() {
+	access := s.access(s.rules())
+	bob := s.connect(access, "bob")
+
+	s.False(s.call(bob, "fake_get", map[string]any{"namespace": "team-a-web"}).IsError)
+	s.False(s.call(bob, "rollouts.promote", map[string]any{"namespace": "team-a-web", "name": "web"}).IsError)
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"fake_get", map[string]any{"namespace": "kube-system"}},
+		{"fake_get", map[string]any{}},
+		{"fake_delete", map[string]any{"namespace": "team-a-web"}},
+		{"rollouts.promote", map[string]any{"namespace": "team-b-web"}},
+	} {
+		result := s.call(bob, tc.tool, tc.args)
+		s.True(result.IsError, "%s %v should be denied", tc.tool, tc.args)
+		assertErrorType(&s.Suite, result, "AccessDenied")
+	}
+
+	alice := s.connect(access, "alice")
+	s.False(s.call(alice, "fake_delete", map[string]any{"namespace": "kube-system"}).IsError)
+}
+
+// TestManifestNamespace tests that calls with a manifest are checked against
+// the manifest's namespace, which the tools act on, and not a namespace
+// argument.
+func (s *AccessTestSuite) TestManifestNamespace() {
+	access := s.access(&config.AccessConfig{
+		Enabled: true,
+		Rules:   []config.AccessRule{{Groups: []string{"team-a"}, Toolsets: []string{"fake"}, Namespaces: []string{"team-a-*"}}},
+	})
+	bob := s.connect(access, "bob")
+	manifest := func(namespace string) map[string]any {
+		return map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "settings", "namespace": namespace},
+		}
+	}
+
+	result := s.call(bob, "fake_apply", map[string]any{"namespace": "team-a-web", "manifest": manifest("kube-system")})
+	s.True(result.IsError, "A namespace argument must not unlock another manifest namespace")
+	assertErrorType(&s.Suite, result, "AccessDenied")
+	s.False(s.call(bob, "fake_apply", map[string]any{"namespace": "kube-system", "manifest": manifest("team-a-web")}).IsError)
+}
+
+// TestContexts tests context patterns, with calls without a context checked
+// against the provider's current context.
+func (s *AccessTestSuite) TestContexts() {
+	access := s.access(&config.AccessConfig{
+		Enabled: true,
+		Rules:   []config.AccessRule{{Groups: []string{"team-a"}, Contexts: []string{"staging-*"}}},
+	})
+	bob := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob", Groups: []string{"team-a"}})
+
+	s.Empty(access.denied(bob, "fake_get", "fake", mcpHelpers.ToolClassRead, mcpHelpers.Target{Context: "staging-eu"}))
+	s.Contains(access.denied(bob, "fake_get", "fake", mcpHelpers.ToolClassRead, mcpHelpers.Target{Context: "prod"}),
+		"in context prod")
+	s.Contains(access.denied(bob, "fake_get", "fake", mcpHelpers.ToolClassRead, mcpHelpers.Target{}),
+		"in context test", "The current context should be checked")
+	s.Contains(access.denied(context.Background(), "fake_get", "fake", mcpHelpers.ToolClassRead, mcpHelpers.Target{Context: "staging-eu"}),
+		"Unauthenticated callers match no rule")
+}
+
+// TestCheckResource tests that resources are checked as the equivalent read tool.
+func (s *AccessTestSuite) TestCheckResource() {
+	access := s.access(&config.AccessConfig{
+		Enabled: true,
+		Rules: []config.AccessRule{
+			{Groups: []string{"team-a"}, Toolsets: []string{"core"}, ReadOnly: true, Namespaces: []string{"team-a-*"}},
+		},
+	})
+	bob := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob", Groups: []string{"team-a"}})
+
+	s.NoError(access.CheckResource(bob, "pods_logs", mcpHelpers.Target{Namespace: "team-a-web", Kind: "Pod", Name: "web"}))
+	s.Error(access.CheckResource(bob, "resources_get", mcpHelpers.Target{Namespace: "kube-system", Kind: "Secret", Name: "token"}))
+	s.Error(access.CheckResource(bob, "helm_releases_list", mcpHelpers.Target{Namespace: "team-a-web", Name: "web"}),
+		"The helm toolset is not allowed")
+}
+
+// TestCheckContext tests that contexts are allowed when a rule allows the
+// equivalent read tool in some namespace of them.
+func (s *AccessTestSuite) TestCheckContext() {
+	access := s.access(&config.AccessConfig{
+		Enabled: true,
+		Rules: []config.AccessRule{
+			{Groups: []string{"team-a"}, Toolsets: []string{"core"}, Contexts: []string{"staging-*"}, Namespaces: []string{"team-a-*"}},
+		},
+	})
+	bob := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob", Groups: []string{"team-a"}})
+
+	s.NoError(access.CheckContext(bob, "resources_get", "staging-eu"))
+	s.Error(access.CheckContext(bob, "resources_get", "prod"))
+	s.Error(access.CheckContext(bob, "helm_releases_list", "staging-eu"))
+	s.Error(access.CheckContext(context.Background(), "resources_get", "staging-eu"))
+}
+
+// TestUpdate tests disabling, reloading and validating the rules.
+func (s *AccessTestSuite) TestUpdate() {
+	access := s.access(&config.AccessConfig{})
+	carol := s.connect(access, "carol")
+	s.Len(s.listToolNames(carol), 5, "All tools should be listed when disabled")
+
+	s.Require().NoError(access.Update(s.rules()))
+	s.Empty(s.listToolNames(carol))
+	assertErrorType(&s.Suite, s.call(carol, "fake_get", map[string]any{"namespace": "team-b-web"}), "AccessDenied")
+
+	s.Error(access.Update(&config.AccessConfig{Rules: []config.AccessRule{{Namespaces: []string{"team-["}}}}))
+}
+
+// TestAccessTestSuite runs the access test suite.
+func TestAccessTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTestSuite))
+}
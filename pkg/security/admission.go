@@ -0,0 +1,337 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/audit"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Admission rule actions.
+const (
+	AdmissionDeny    = "deny"
+	AdmissionConfirm = "confirm"
+	AdmissionAudit   = "audit"
+)
+
+// admissionCostLimit bounds the work a single rule evaluation may do.
+const admissionCostLimit = 1_000_000
+
+// newAdmissionEnv declares the variables admission rules are evaluated against.
+var newAdmissionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("object", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+	)
+})
+
+// Admission evaluates CEL rules against every tool call before it runs. A
+// matching rule denies the call, requires the user to confirm it, or is
+// recorded in the call's audit entry. The rules can be replaced at runtime
+// with Update.
+type Admission struct {
+	mu       sync.RWMutex
+	enabled  bool
+	failOpen bool
+	rules    []*admissionRule
+	provider kubernetes.ClientProvider
+}
+
+// admissionRule is a rule with its compiled expressions.
+type admissionRule struct {
+	config.AdmissionRule
+	program        cel.Program
+	messageProgram cel.Program
+}
+
+// NewAdmission creates an admission policy from the admission configuration.
+// The provider is used to read the target objects and namespaces rules refer to.
+func NewAdmission(cfg *config.AdmissionConfig, provider kubernetes.ClientProvider) (*Admission, error) {
+	a := &Admission{provider: provider}
+	if err := a.Update(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update compiles and replaces the rules, e.g. on reload.
+func (a *Admission) Update(cfg *config.AdmissionConfig) error {
+	var failOpen bool
+	switch cfg.FailurePolicy {
+	case "", "deny":
+	case "ignore":
+		failOpen = true
+	default:
+		return fmt.Errorf("invalid security.admission.failure_policy %q: must be \"deny\" or \"ignore\"", cfg.FailurePolicy)
+	}
+
+	rules := make([]*admissionRule, 0, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		compiled, err := compileAdmissionRule(rule)
+		if err != nil {
+			return fmt.Errorf("invalid security.admission.rules[%d]: %w", i, err)
+		}
+		if compiled.Name == "" {
+			compiled.Name = fmt.Sprintf("rules[%d]", i)
+		}
+		rules = append(rules, compiled)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enabled = cfg.Enabled
+	a.failOpen = failOpen
+	a.rules = rules
+	return nil
+}
+
+func compileAdmissionRule(rule config.AdmissionRule) (*admissionRule, error) {
+	switch rule.Action {
+	case "":
+		rule.Action = AdmissionDeny
+	case AdmissionDeny, AdmissionConfirm, AdmissionAudit:
+	default:
+		return nil, fmt.Errorf("action %q must be \"deny\", \"confirm\" or \"audit\"", rule.Action)
+	}
+	for _, pattern := range rule.Tools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("tools pattern %q: %w", pattern, err)
+		}
+	}
+	if rule.Expression == "" {
+		return nil, fmt.Errorf("expression is required")
+	}
+
+	compiled := &admissionRule{AdmissionRule: rule}
+	var err error
+	if compiled.program, err = compileCEL(rule.Expression, cel.BoolType); err != nil {
+		return nil, fmt.Errorf("expression: %w", err)
+	}
+	if rule.MessageExpression != "" {
+		if compiled.messageProgram, err = compileCEL(rule.MessageExpression, cel.StringType); err != nil {
+			return nil, fmt.Errorf("message_expression: %w", err)
+		}
+	}
+	return compiled, nil
+}
+
+// compileCEL compiles an expression that must evaluate to the given type, or
+// to a dynamic value checked at evaluation time.
+func compileCEL(expression string, outputType *cel.Type) (cel.Program, error) {
+	env, err := newAdmissionEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if !ast.OutputType().IsExactType(outputType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("must evaluate to %s, not %s", outputType, ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(admissionCostLimit))
+}
+
+// Middleware evaluates the rules that apply to each tool call, in order. It
+// rejects the call at the first matching deny rule, asks for confirmation if
+// a confirm rule matches, and records the matching rules in the call's audit
+// entry.
+func (a *Admission) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			a.mu.RLock()
+			enabled, failOpen, rules := a.enabled, a.failOpen, a.rules
+			a.mu.RUnlock()
+			if !enabled || len(rules) == 0 {
+				return next(ctx, call)
+			}
+
+			vars := a.activation(ctx, call)
+			var decisions []audit.AdmissionDecision
+			for _, rule := range rules {
+				if !matchesAny(rule.Tools, call.Name) {
+					continue
+				}
+
+				matched, message, err := rule.evaluate(vars, call.Name)
+				if err != nil {
+					if rule.Action == AdmissionAudit {
+						decisions = append(decisions, audit.AdmissionDecision{
+							Rule:    rule.Name,
+							Action:  rule.Action,
+							Message: fmt.Sprintf("Evaluation failed: %v", err),
+						})
+						continue
+					}
+					if failOpen {
+						continue
+					}
+					message := fmt.Sprintf("Tool call %s denied: admission rule %s could not be evaluated", call.Name, rule.Name)
+					audit.RecordAdmissionDecisions(ctx, append(decisions, audit.AdmissionDecision{
+						Rule:    rule.Name,
+						Action:  AdmissionDeny,
+						Message: fmt.Sprintf("Evaluation failed: %v", err),
+					})...)
+					return admissionDeniedResult(call.Name, rule.Name, message, err.Error())
+				}
+				if !matched {
+					continue
+				}
+
+				switch rule.Action {
+				case AdmissionDeny:
+					audit.RecordAdmissionDecisions(ctx, append(decisions, audit.AdmissionDecision{Rule: rule.Name, Action: rule.Action, Message: message})...)
+					return admissionDeniedResult(call.Name, rule.Name, message,
+						fmt.Sprintf("Denied by admission rule %s", rule.Name))
+				case AdmissionConfirm:
+					call.ConfirmationReasons = append(call.ConfirmationReasons, message)
+				}
+				decisions = append(decisions, audit.AdmissionDecision{Rule: rule.Name, Action: rule.Action, Message: message})
+			}
+
+			audit.RecordAdmissionDecisions(ctx, decisions...)
+			return next(ctx, call)
+		}
+	}
+}
+
+// evaluate reports whether the rule matches, and the message for its action.
+func (r *admissionRule) evaluate(vars map[string]any, toolName string) (bool, string, error) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		return false, "", err
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, "", fmt.Errorf("expression returned %s, not bool", out.Type())
+	}
+	if !matched {
+		return false, "", nil
+	}
+
+	if r.messageProgram != nil {
+		out, _, err := r.messageProgram.Eval(vars)
+		if err != nil {
+			return true, "", fmt.Errorf("message_expression: %w", err)
+		}
+		if message, ok := out.Value().(string); ok && message != "" {
+			return true, message, nil
+		}
+	}
+	if r.Message != "" {
+		return true, r.Message, nil
+	}
+	switch r.Action {
+	case AdmissionDeny:
+		return true, fmt.Sprintf("Tool call %s denied by admission rule %s", toolName, r.Name), nil
+	default:
+		return true, fmt.Sprintf("Tool call %s matched admission rule %s", toolName, r.Name), nil
+	}
+}
+
+// activation builds the variables rules are evaluated against. object and
+// namespaceObject are read from the cluster only if a rule uses them, at most
+// once per call, and are null if they do not exist or the call has no target.
+func (a *Admission) activation(ctx context.Context, call *mcpHelpers.ToolCall) map[string]any {
+	target := mcpHelpers.TargetOf(call.Name, call.Arguments)
+	if target.Context == "" && a.provider != nil {
+		target.Context, _ = a.provider.GetCurrentContext()
+	}
+
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	user := map[string]any{"username": "", "uid": "", "groups": []string{}}
+	if identity := auth.IdentityFromContext(ctx); identity != nil {
+		groups := identity.Groups
+		if groups == nil {
+			groups = []string{}
+		}
+		user = map[string]any{"username": identity.Username, "uid": identity.UID, "groups": groups}
+	}
+
+	request := map[string]any{
+		"tool":      call.Name,
+		"toolset":   call.Toolset,
+		"class":     string(call.Class),
+		"arguments": arguments,
+		"user":      user,
+		"context":   target.Context,
+		"namespace": target.Namespace,
+		"target": map[string]any{
+			"group":     target.Group,
+			"version":   target.Version,
+			"kind":      target.Kind,
+			"name":      target.Name,
+			"namespace": target.Namespace,
+		},
+	}
+
+	object := sync.OnceValue(func() ref.Val {
+		if target.Kind == "" || target.Name == "" {
+			return types.NullValue
+		}
+		gvk := schema.GroupVersionKind{Group: target.Group, Version: target.Version, Kind: target.Kind}
+		return liveValue(getLiveObject(ctx, a.provider, call.StringArg("context"), gvk, target.Namespace, target.Name))
+	})
+	namespaceObject := sync.OnceValue(func() ref.Val {
+		if target.Namespace == "" {
+			return types.NullValue
+		}
+		gvk := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+		return liveValue(getLiveObject(ctx, a.provider, call.StringArg("context"), gvk, "", target.Namespace))
+	})
+
+	return map[string]any{
+		"request":         request,
+		"object":          object,
+		"namespaceObject": namespaceObject,
+	}
+}
+
+// liveValue converts a live object to a CEL value: null if it does not exist,
+// and an error that fails the evaluation if it could not be read.
+func liveValue(obj map[string]any, err error) ref.Val {
+	if err != nil {
+		return types.NewErr("%v", err)
+	}
+	if obj == nil {
+		return types.NullValue
+	}
+	return types.DefaultTypeAdapter.NativeToValue(obj)
+}
+
+// admissionDeniedResult builds a structured error for a call denied by an
+// admission rule.
+func admissionDeniedResult(tool, rule, message, details string) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "AdmissionDenied",
+			"message": message,
+			"details": details,
+			"rule":    rule,
+			"tool":    tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
@@ -0,0 +1,300 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/audit"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// AdmissionTestSuite tests CEL admission rules.
+type AdmissionTestSuite struct {
+	suite.Suite
+	provider *clientSetProvider
+	auditLog bytes.Buffer
+}
+
+func (s *AdmissionTestSuite) SetupTest() {
+	object := func(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(labels)
+		return obj
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	s.provider = &clientSetProvider{clientSet: &kubernetes.ClientSet{
+		Typed: fake.NewSimpleClientset(),
+		Dynamic: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+			object("v1", "Namespace", "", "shop", map[string]string{"tier": "prod"}),
+			object("v1", "Namespace", "", "shop-dev", nil),
+			object("apps/v1", "Deployment", "shop", "web", map[string]string{"protected": "true"}),
+			object("apps/v1", "Deployment", "shop", "worker", nil),
+		),
+		RESTMapper: mapper,
+	}}
+	s.auditLog.Reset()
+}
+
+func (s *AdmissionTestSuite) admission(cfg config.AdmissionConfig) *Admission {
+	cfg.Enabled = true
+	admission, err := NewAdmission(&cfg, s.provider)
+	s.Require().NoError(err)
+	return admission
+}
+
+// call runs a tool call through the audit and admission middleware as user,
+// and returns the result and the call as seen by the next handler.
+// Tools named *_get are read-only, all others write.
+func (s *AdmissionTestSuite) call(admission *Admission, user, tool string, args map[string]any) (*mcp.CallToolResult, *mcpHelpers.ToolCall) {
+	auditor, err := audit.NewAuditor(audit.NewWriterSink(&s.auditLog))
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	if user != "" {
+		ctx = auth.WithIdentity(ctx, &auth.Identity{Username: user, Groups: []string{"sre"}})
+	}
+	var seen *mcpHelpers.ToolCall
+	next := func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		seen = call
+		return mcpHelpers.NewTextResult("ok"), nil
+	}
+	class := mcpHelpers.ToolClassWrite
+	if strings.HasSuffix(tool, "_get") {
+		class = mcpHelpers.ToolClassRead
+	}
+	result, err := auditor.Middleware()(admission.Middleware()(next))(ctx, &mcpHelpers.ToolCall{Name: tool, Class: class, Arguments: args})
+	s.Require().NoError(err)
+	return result, seen
+}
+
+// denial decodes the structured error of a denied call.
+func (s *AdmissionTestSuite) denial(result *mcp.CallToolResult) map[string]any {
+	s.Require().True(result.IsError, "Call should be denied")
+	var body struct {
+		Error map[string]any `json:"error"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &body))
+	s.Equal("AdmissionDenied", body.Error["type"])
+	return body.Error
+}
+
+// TestDenyScaleToZero tests a rule on the arguments and the target's namespace.
+func (s *AdmissionTestSuite) TestDenyScaleToZero() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{{
+		Name:              "no-scale-to-zero-in-prod",
+		Tools:             []string{"resources_scale"},
+		Expression:        `has(request.arguments.replicas) && request.arguments.replicas == 0 && namespaceObject.?metadata.labels.tier.orValue("") == "prod"`,
+		Action:            AdmissionDeny,
+		MessageExpression: `"Scaling " + request.target.kind + " " + request.target.name + " to zero is not allowed in production namespace " + request.namespace`,
+	}}})
+	scale := func(namespace string, replicas any) map[string]any {
+		return map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "name": "web", "namespace": namespace, "replicas": replicas}
+	}
+
+	result, seen := s.call(admission, "alice", "resources_scale", scale("shop", float64(0)))
+	denial := s.denial(result)
+	s.Nil(seen, "Denied calls should not run")
+	s.Equal("Scaling Deployment web to zero is not allowed in production namespace shop", denial["message"])
+	s.Equal("no-scale-to-zero-in-prod", denial["rule"])
+
+	var entry audit.Entry
+	s.Require().NoError(json.Unmarshal(s.auditLog.Bytes(), &entry))
+	s.Equal(audit.OutcomeDenied, entry.Outcome)
+	s.Equal([]audit.AdmissionDecision{{
+		Rule:    "no-scale-to-zero-in-prod",
+		Action:  AdmissionDeny,
+		Message: "Scaling Deployment web to zero is not allowed in production namespace shop",
+	}}, entry.Admission)
+
+	for _, args := range []map[string]any{
+		scale("shop", float64(2)),
+		scale("shop", nil),
+		scale("shop-dev", float64(0)),
+		scale("missing", float64(0)),
+	} {
+		result, _ := s.call(admission, "alice", "resources_scale", args)
+		s.False(result.IsError, "%v should be allowed", args)
+	}
+
+	result, _ = s.call(admission, "alice", "resources_get", scale("shop", float64(0)))
+	s.False(result.IsError, "Rules should apply only to their tools")
+}
+
+// TestExecAllowlist tests a rule denying commands outside an allowlist.
+func (s *AdmissionTestSuite) TestExecAllowlist() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{{
+		Name:       "exec-allowlist",
+		Tools:      []string{"pods_exec"},
+		Expression: `!(request.arguments.command[0] in ["ls", "cat", "env"])`,
+		Message:    "Only ls, cat and env may be run in pods",
+	}}})
+	exec := func(command ...any) map[string]any {
+		return map[string]any{"namespace": "shop", "name": "web-0", "command": command}
+	}
+
+	result, _ := s.call(admission, "alice", "pods_exec", exec("cat", "/etc/hostname"))
+	s.False(result.IsError)
+	result, _ = s.call(admission, "alice", "pods_exec", exec("sh", "-c", "rm -rf /"))
+	s.Equal("Only ls, cat and env may be run in pods", s.denial(result)["message"])
+}
+
+// TestObject tests a rule on the live target object.
+func (s *AdmissionTestSuite) TestObject() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{{
+		Name:       "protected",
+		Tools:      []string{"*_delete"},
+		Expression: `object != null && object.metadata.?labels.protected.orValue("") == "true"`,
+	}}})
+	deployment := func(name string) map[string]any {
+		return map[string]any{"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "shop", "name": name}
+	}
+
+	result, _ := s.call(admission, "alice", "resources_delete", deployment("web"))
+	s.Equal("Tool call resources_delete denied by admission rule protected", s.denial(result)["message"])
+	result, _ = s.call(admission, "alice", "resources_delete", deployment("worker"))
+	s.False(result.IsError)
+	result, _ = s.call(admission, "alice", "resources_delete", deployment("gone"))
+	s.False(result.IsError, "Missing objects should be null")
+}
+
+// TestConfirmAndAudit tests that confirm rules ask for confirmation and that
+// confirm and audit rules are recorded in the audit entry.
+func (s *AdmissionTestSuite) TestConfirmAndAudit() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{
+		{
+			Name:       "prod-writes",
+			Expression: `request.class != "read" && request.namespace == "shop"`,
+			Action:     AdmissionConfirm,
+			Message:    "Writes to the shop namespace need confirmation",
+		},
+		{
+			Name:       "sre-activity",
+			Expression: `"sre" in request.user.groups`,
+			Action:     AdmissionAudit,
+		},
+	}})
+
+	result, seen := s.call(admission, "alice", "resources_apply", map[string]any{"namespace": "shop"})
+	s.False(result.IsError)
+	s.Equal([]string{"Writes to the shop namespace need confirmation"}, seen.ConfirmationReasons)
+
+	var entry audit.Entry
+	s.Require().NoError(json.Unmarshal(s.auditLog.Bytes(), &entry))
+	s.Equal([]audit.AdmissionDecision{
+		{Rule: "prod-writes", Action: AdmissionConfirm, Message: "Writes to the shop namespace need confirmation"},
+		{Rule: "sre-activity", Action: AdmissionAudit, Message: "Tool call resources_apply matched admission rule sre-activity"},
+	}, entry.Admission)
+
+	s.auditLog.Reset()
+	_, seen = s.call(admission, "", "resources_get", map[string]any{"namespace": "shop"})
+	s.Empty(seen.ConfirmationReasons)
+	s.NotContains(s.auditLog.String(), `"admission"`)
+}
+
+// TestConfirmThroughServer tests that confirm rules are confirmed by the user
+// even when security.confirmation is disabled.
+func (s *AdmissionTestSuite) TestConfirmThroughServer() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{{
+		Name:       "confirm-apply",
+		Tools:      []string{"fake_apply"},
+		Expression: `true`,
+		Action:     AdmissionConfirm,
+		Message:    "Applies need confirmation",
+	}}})
+	confirmation, err := NewConfirmation(&config.ConfirmationConfig{}, s.provider)
+	s.Require().NoError(err)
+
+	server := mcpHelpers.NewServer("test", "0.0.0", false)
+	server.UseToolMiddleware(admission.Middleware())
+	server.UseToolMiddleware(confirmation.Middleware())
+	s.Require().NoError(server.RegisterToolset(&fakeToolset{}))
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	var messages []string
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			messages = append(messages, req.Params.Message)
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		},
+	})
+	session, err := client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "fake_apply"})
+	s.Require().NoError(err)
+	assertErrorType(&s.Suite, result, "ConfirmationRequired")
+	s.Require().Len(messages, 1)
+	s.Contains(messages[0], "Reason: Applies need confirmation")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "fake_get"})
+	s.Require().NoError(err)
+	s.False(result.IsError)
+}
+
+// TestFailurePolicy tests rules that fail to evaluate.
+func (s *AdmissionTestSuite) TestFailurePolicy() {
+	rules := []config.AdmissionRule{{Name: "replicas", Expression: `request.arguments.replicas == 0`}}
+
+	result, _ := s.call(s.admission(config.AdmissionConfig{Rules: rules}), "alice", "resources_scale", map[string]any{})
+	denial := s.denial(result)
+	s.Contains(denial["message"], "could not be evaluated")
+	s.Contains(denial["details"], "no such key")
+
+	result, _ = s.call(s.admission(config.AdmissionConfig{FailurePolicy: "ignore", Rules: rules}), "alice", "resources_scale", map[string]any{})
+	s.False(result.IsError)
+}
+
+// TestUpdate tests disabling, reloading and validating the rules.
+func (s *AdmissionTestSuite) TestUpdate() {
+	admission := s.admission(config.AdmissionConfig{Rules: []config.AdmissionRule{{Expression: `true`}}})
+	result, _ := s.call(admission, "alice", "fake_get", nil)
+	s.Equal("rules[0]", s.denial(result)["rule"], "Unnamed rules should be named by index")
+
+	s.Require().NoError(admission.Update(&config.AdmissionConfig{Rules: []config.AdmissionRule{{Expression: `true`}}}))
+	result, _ = s.call(admission, "alice", "fake_get", nil)
+	s.False(result.IsError, "Rules should not apply when disabled")
+
+	for _, cfg := range []config.AdmissionConfig{
+		{FailurePolicy: "allow"},
+		{Rules: []config.AdmissionRule{{}}},
+		{Rules: []config.AdmissionRule{{Expression: `request.tool ==`}}},
+		{Rules: []config.AdmissionRule{{Expression: `1 + 1`}}},
+		{Rules: []config.AdmissionRule{{Expression: `true`, Action: "warn"}}},
+		{Rules: []config.AdmissionRule{{Expression: `true`, Tools: []string{"pods_["}}}},
+		{Rules: []config.AdmissionRule{{Expression: `true`, MessageExpression: `42`}}},
+	} {
+		s.Error(admission.Update(&cfg), "%+v should be rejected", cfg)
+	}
+}
+
+// TestAdmissionTestSuite runs the admission test suite.
+func TestAdmissionTestSuite(t *testing.T) {
+	suite.Run(t, new(AdmissionTestSuite))
+}
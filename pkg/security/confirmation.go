@@ -0,0 +1,382 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Confirmation asks the user to confirm destructive tool calls, and calls
+// matching the configured rules, with an MCP elicitation request before they
+// run. Unlike the confirm arguments some tools take, the answer comes from the
+// user and cannot be supplied by the model.
+type Confirmation struct {
+	enabled       bool
+	allowFallback bool
+	timeout       time.Duration
+	rules         []config.ConfirmationRule
+	provider      kubernetes.ClientProvider
+}
+
+// confirmationSchema is the form shown to the user: a single checkbox that
+// must be ticked for the call to run.
+var confirmationSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Run this tool call",
+			"description": "Tick to allow the call to run",
+			"default":     false,
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// NewConfirmation creates a confirmation policy. The provider is used to read
+// the live objects shown in the diff.
+func NewConfirmation(cfg *config.ConfirmationConfig, provider kubernetes.ClientProvider) (*Confirmation, error) {
+	var allowFallback bool
+	switch cfg.Fallback {
+	case "", "deny":
+	case "allow":
+		allowFallback = true
+	default:
+		return nil, fmt.Errorf("invalid security.confirmation.fallback %q: must be \"deny\" or \"allow\"", cfg.Fallback)
+	}
+
+	timeout := cfg.Timeout.Duration()
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	return &Confirmation{
+		enabled:       cfg.Enabled,
+		allowFallback: allowFallback,
+		timeout:       timeout,
+		rules:         cfg.Rules,
+		provider:      provider,
+	}, nil
+}
+
+// Requires reports whether the call needs confirmation. Calls with
+// confirmation reasons need it even if confirmation is disabled.
+func (c *Confirmation) Requires(call *mcpHelpers.ToolCall) bool {
+	if len(call.ConfirmationReasons) > 0 {
+		return true
+	}
+	if !c.enabled {
+		return false
+	}
+	if call.Class == mcpHelpers.ToolClassDestructive || revealRequested(call) {
+		return true
+	}
+	for _, rule := range c.rules {
+		if ruleMatches(rule, call) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware asks the user to confirm calls that require it, and runs them
+// only if the user accepts.
+func (c *Confirmation) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			if !c.Requires(call) {
+				return next(ctx, call)
+			}
+
+			var session *mcp.ServerSession
+			if call.Request != nil {
+				session = call.Request.Session
+			}
+			if !supportsElicitation(session) {
+				if c.allowFallback {
+					return next(ctx, call)
+				}
+				return confirmationResult(call.Name,
+					fmt.Sprintf("Tool %s requires user confirmation", call.Name),
+					"The client does not support elicitation and security.confirmation.fallback is deny")
+			}
+
+			elicitCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			answer, err := session.Elicit(elicitCtx, &mcp.ElicitParams{
+				Message:         c.describe(ctx, call),
+				RequestedSchema: confirmationSchema,
+			})
+			if err != nil {
+				return confirmationResult(call.Name,
+					fmt.Sprintf("Tool %s was not confirmed", call.Name),
+					fmt.Sprintf("Confirmation request failed: %v", err))
+			}
+			if answer.Action != "accept" || answer.Content["confirm"] != true {
+				return confirmationResult(call.Name,
+					fmt.Sprintf("Tool %s was not confirmed", call.Name),
+					fmt.Sprintf("The user did not confirm the call (action: %s)", answer.Action))
+			}
+
+			return next(ctx, call)
+		}
+	}
+}
+
+// supportsElicitation reports whether the client declared the elicitation capability.
+func supportsElicitation(session *mcp.ServerSession) bool {
+	if session == nil {
+		return false
+	}
+	params := session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+// ruleMatches reports whether every non-empty field of the rule matches the call.
+func ruleMatches(rule config.ConfirmationRule, call *mcpHelpers.ToolCall) bool {
+	if len(rule.Tools) == 0 && len(rule.Namespaces) == 0 && len(rule.Kinds) == 0 {
+		return false
+	}
+	if len(rule.Tools) > 0 && !slices.Contains(rule.Tools, call.Name) {
+		return false
+	}
+	target := mcpHelpers.TargetOf(call.Name, call.Arguments)
+	if len(rule.Namespaces) > 0 && !slices.Contains(rule.Namespaces, target.Namespace) {
+		return false
+	}
+	if len(rule.Kinds) > 0 {
+		if target.Kind == "" {
+			return false
+		}
+		matched := false
+		for _, kind := range rule.Kinds {
+			if strings.EqualFold(kind, target.Kind) || strings.EqualFold(kind, formatGroupKind(target.Group, target.Kind)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// describe builds the message shown to the user: what runs, against which
+// object, with which arguments, and a diff of the object where it can be
+// computed.
+func (c *Confirmation) describe(ctx context.Context, call *mcpHelpers.ToolCall) string {
+	var b strings.Builder
+	description := ""
+	if call.Tool != nil {
+		description = call.Tool.Description
+	}
+	fmt.Fprintf(&b, "Confirm tool call %s", call.Name)
+	if description != "" {
+		fmt.Fprintf(&b, " (%s)", description)
+	}
+	b.WriteString("\n")
+	for _, reason := range call.ConfirmationReasons {
+		fmt.Fprintf(&b, "Reason: %s\n", reason)
+	}
+
+	target := mcpHelpers.TargetOf(call.Name, call.Arguments)
+	if target.Kind != "" && target.Name != "" {
+		fmt.Fprintf(&b, "Target: %s %s", formatGroupKind(target.Group, target.Kind), target.Name)
+		if target.Namespace != "" {
+			fmt.Fprintf(&b, " in namespace %s", target.Namespace)
+		}
+		b.WriteString("\n")
+	}
+	contextName := target.Context
+	if contextName == "" {
+		contextName = "(current)"
+	}
+	fmt.Fprintf(&b, "Context: %s\n", contextName)
+
+	diff, diffErr := c.diff(ctx, call, target)
+
+	args := make(map[string]any, len(call.Arguments))
+	for key, value := range call.Arguments {
+		// The manifest is shown as a diff when one is available
+		if key == "manifest" && diff != "" {
+			continue
+		}
+		args[key] = value
+	}
+	if target.Kind == "Secret" {
+		args = redactSecret(args)
+	}
+	if len(args) > 0 {
+		if out, err := yaml.Marshal(args); err == nil {
+			b.WriteString("\nArguments:\n")
+			b.Write(out)
+		}
+	}
+
+	switch {
+	case diff != "":
+		b.WriteString("\nChanges:\n```diff\n")
+		b.WriteString(diff)
+		b.WriteString("```\n")
+	case diffErr != nil:
+		fmt.Fprintf(&b, "\nChanges could not be computed: %v\n", diffErr)
+	}
+	return b.String()
+}
+
+// diff returns a unified diff of the target object before and after the
+// call, for calls that apply a manifest or delete an object. It returns an
+// empty diff for other calls.
+func (c *Confirmation) diff(ctx context.Context, call *mcpHelpers.ToolCall, target mcpHelpers.Target) (string, error) {
+	manifest, hasManifest := call.Arguments["manifest"].(map[string]any)
+	deletes := strings.HasSuffix(call.Name, "_delete")
+	if target.Kind == "" || target.Name == "" || (!hasManifest && !deletes) {
+		return "", nil
+	}
+	gvk := schema.GroupVersionKind{Group: target.Group, Version: target.Version, Kind: target.Kind}
+
+	before, err := getLiveObject(ctx, c.provider, call.StringArg("context"), gvk, target.Namespace, target.Name)
+	if err != nil {
+		return "", err
+	}
+
+	var after map[string]any
+	if hasManifest {
+		after = manifest
+	}
+
+	beforeYAML, err := diffYAML(before, gvk.Kind)
+	if err != nil {
+		return "", err
+	}
+	afterYAML, err := diffYAML(after, gvk.Kind)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(beforeYAML),
+		B:        difflib.SplitLines(afterYAML),
+		FromFile: "live",
+		ToFile:   "after",
+		Context:  3,
+	})
+}
+
+// getLiveObject reads an object from the cluster. It returns nil if the
+// object does not exist.
+func getLiveObject(ctx context.Context, provider kubernetes.ClientProvider, contextName string, gvk schema.GroupVersionKind, namespace, name string) (map[string]any, error) {
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, provider, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client set: %w", err)
+	}
+	var versions []string
+	if gvk.Version != "" {
+		versions = append(versions, gvk.Version)
+	}
+	mapping, err := clientSet.RESTMapper.RESTMapping(gvk.GroupKind(), versions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s: %w", gvk.Kind, err)
+	}
+
+	live, err := clientSet.Dynamic.Resource(mapping.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get %s %s: %w", gvk.Kind, name, err)
+	}
+	return live.Object, nil
+}
+
+// diffYAML renders an object for diffing, without status and server-managed
+// metadata, and with Secret values redacted.
+func diffYAML(obj map[string]any, kind string) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	cleaned := make(map[string]any, len(obj))
+	for key, value := range obj {
+		switch key {
+		case "status":
+		case "metadata":
+			metadata, ok := value.(map[string]any)
+			if !ok {
+				cleaned[key] = value
+				continue
+			}
+			cleanedMetadata := make(map[string]any, len(metadata))
+			for mk, mv := range metadata {
+				switch mk {
+				case "managedFields", "resourceVersion", "uid", "creationTimestamp", "generation":
+				default:
+					cleanedMetadata[mk] = mv
+				}
+			}
+			cleaned[key] = cleanedMetadata
+		default:
+			cleaned[key] = value
+		}
+	}
+	if kind == "Secret" {
+		cleaned = redactSecret(cleaned)
+	}
+
+	out, err := yaml.Marshal(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", kind, err)
+	}
+	return string(out), nil
+}
+
+// redactSecret replaces Secret values with a fingerprint, so the user sees
+// which keys change without the values being shown.
+func redactSecret(obj map[string]any) map[string]any {
+	redacted := make(map[string]any, len(obj))
+	for key, value := range obj {
+		values, ok := value.(map[string]any)
+		if (key != "data" && key != "stringData") || !ok {
+			redacted[key] = value
+			continue
+		}
+		masked := make(map[string]any, len(values))
+		for k, v := range values {
+			raw, _ := json.Marshal(v)
+			sum := sha256.Sum256(raw)
+			masked[k] = fmt.Sprintf("<redacted sha256:%x>", sum[:6])
+		}
+		redacted[key] = masked
+	}
+	return redacted
+}
+
+// confirmationResult builds a ConfirmationRequired error result.
+func confirmationResult(tool, message, details string) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "ConfirmationRequired",
+			"message": message,
+			"details": details,
+			"tool":    tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// clientSetProvider serves a single fixed client set.
+type clientSetProvider struct {
+	clientSet *kubernetes.ClientSet
+}
+
+func (p *clientSetProvider) GetClientSet(context string) (*kubernetes.ClientSet, error) {
+	return p.clientSet, nil
+}
+
+func (p *clientSetProvider) ListContexts() ([]string, error) {
+	return []string{"test"}, nil
+}
+
+func (p *clientSetProvider) GetCurrentContext() (string, error) {
+	return "test", nil
+}
+
+// ConfirmationTestSuite tests confirmation of tool calls via elicitation.
+type ConfirmationTestSuite struct {
+	suite.Suite
+	provider *clientSetProvider
+	messages []string
+}
+
+func (s *ConfirmationTestSuite) SetupTest() {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace("default")
+	configMap.SetName("app-config")
+	configMap.SetResourceVersion("42")
+	s.Require().NoError(unstructured.SetNestedStringMap(configMap.Object, map[string]string{"mode": "fast"}, "data"))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	s.provider = &clientSetProvider{clientSet: &kubernetes.ClientSet{
+		Typed:      fake.NewSimpleClientset(),
+		Dynamic:    dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), configMap),
+		RESTMapper: mapper,
+	}}
+	s.messages = nil
+}
+
+// connect builds a server with confirmation installed. A nil answer connects
+// a client without elicitation support.
+func (s *ConfirmationTestSuite) connect(cfg *config.ConfirmationConfig, answer *mcp.ElicitResult) *mcp.ClientSession {
+	confirmation, err := NewConfirmation(cfg, s.provider)
+	s.Require().NoError(err)
+
+	server := mcpHelpers.NewServer("test", "0.0.0", false)
+	server.UseToolMiddleware(confirmation.Middleware())
+	s.Require().NoError(server.RegisterToolset(&fakeToolset{}))
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	opts := &mcp.ClientOptions{}
+	if answer != nil {
+		opts.ElicitationHandler = func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			s.messages = append(s.messages, req.Params.Message)
+			return answer, nil
+		}
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, opts)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func (s *ConfirmationTestSuite) call(session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	s.Require().NoError(err)
+	return result
+}
+
+// TestDisabled tests that calls run unconfirmed when confirmation is disabled.
+func (s *ConfirmationTestSuite) TestDisabled() {
+	session := s.connect(&config.ConfirmationConfig{}, nil)
+	s.False(s.call(session, "fake_delete", nil).IsError)
+}
+
+// TestFallback tests the decision for clients without elicitation support.
+func (s *ConfirmationTestSuite) TestFallback() {
+	session := s.connect(&config.ConfirmationConfig{Enabled: true, Fallback: "deny"}, nil)
+	result := s.call(session, "fake_delete", nil)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "ConfirmationRequired")
+	s.False(s.call(session, "fake_apply", nil).IsError, "Non-destructive tools should not need confirmation")
+	s.True(s.call(session, "fake_get", map[string]any{"reveal": true}).IsError, "Reveal calls should need confirmation")
+
+	session = s.connect(&config.ConfirmationConfig{Enabled: true, Fallback: "allow"}, nil)
+	s.False(s.call(session, "fake_delete", nil).IsError)
+}
+
+// TestAnswers tests that only an accepted, ticked confirmation runs the call.
+func (s *ConfirmationTestSuite) TestAnswers() {
+	cfg := &config.ConfirmationConfig{Enabled: true}
+
+	session := s.connect(cfg, &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": true}})
+	s.False(s.call(session, "fake_delete", nil).IsError)
+	s.Require().Len(s.messages, 1)
+	s.Contains(s.messages[0], "fake_delete (Delete)")
+
+	for _, answer := range []*mcp.ElicitResult{
+		{Action: "decline"},
+		{Action: "cancel"},
+		{Action: "accept", Content: map[string]any{"confirm": false}},
+	} {
+		session = s.connect(cfg, answer)
+		result := s.call(session, "fake_delete", nil)
+		s.True(result.IsError, "Answer %+v should not run the call", answer)
+		assertErrorType(&s.Suite, result, "ConfirmationRequired")
+	}
+}
+
+// TestRules tests that rules require confirmation for matching non-destructive calls.
+func (s *ConfirmationTestSuite) TestRules() {
+	session := s.connect(&config.ConfirmationConfig{
+		Enabled: true,
+		Rules: []config.ConfirmationRule{
+			{Namespaces: []string{"kube-system"}},
+			{Tools: []string{"fake_apply"}, Kinds: []string{"Namespace"}},
+		},
+	}, nil)
+
+	s.True(s.call(session, "fake_apply", map[string]any{"namespace": "kube-system"}).IsError)
+	s.True(s.call(session, "fake_apply", map[string]any{"manifest": map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]any{"name": "team-a"},
+	}}).IsError)
+	s.False(s.call(session, "fake_apply", map[string]any{"namespace": "default"}).IsError)
+	s.False(s.call(session, "fake_get", map[string]any{"kind": "Namespace"}).IsError, "Rules only match their tools")
+}
+
+// TestDiff tests that the confirmation message shows a diff against the live object.
+func (s *ConfirmationTestSuite) TestDiff() {
+	session := s.connect(&config.ConfirmationConfig{
+		Enabled: true,
+		Rules:   []config.ConfirmationRule{{Tools: []string{"fake_apply"}}},
+	}, &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": true}})
+
+	s.False(s.call(session, "fake_apply", map[string]any{"manifest": map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "app-config", "namespace": "default"},
+		"data":       map[string]any{"mode": "safe"},
+	}}).IsError)
+	s.False(s.call(session, "fake_delete", map[string]any{
+		"version": "v1", "kind": "ConfigMap", "namespace": "default", "name": "app-config",
+	}).IsError)
+
+	s.Require().Len(s.messages, 2)
+	s.Contains(s.messages[0], "Target: ConfigMap app-config in namespace default")
+	s.Contains(s.messages[0], "-  mode: fast")
+	s.Contains(s.messages[0], "+  mode: safe")
+	s.NotContains(s.messages[0], "resourceVersion")
+	s.Contains(s.messages[1], "-  mode: fast")
+	s.NotContains(s.messages[1], "+  mode")
+}
+
+// TestInvalidFallback tests that unknown fallback values are rejected.
+func (s *ConfirmationTestSuite) TestInvalidFallback() {
+	_, err := NewConfirmation(&config.ConfirmationConfig{Fallback: "ask"}, s.provider)
+	s.Error(err)
+
+	confirmation, err := NewConfirmation(&config.ConfirmationConfig{Timeout: config.Duration(time.Second)}, s.provider)
+	s.Require().NoError(err)
+	s.Equal(time.Second, confirmation.timeout)
+}
+
+// TestConfirmationTestSuite runs the confirmation test suite.
+func TestConfirmationTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfirmationTestSuite))
+}
@@ -0,0 +1,137 @@
+// Package security enforces the server-wide [security] settings on MCP tools.
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Policy enforces read-only, non-destructive and denied GVK settings.
+type Policy struct {
+	readOnly       bool
+	nonDestructive bool
+	deniedGVKs     *kubernetes.DeniedGVKs
+}
+
+// NewPolicy creates a policy from the security configuration.
+func NewPolicy(cfg *config.SecurityConfig) (*Policy, error) {
+	denied, err := kubernetes.NewDeniedGVKs(cfg.DeniedGVKs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security.denied_gvks: %w", err)
+	}
+
+	return &Policy{
+		readOnly:       cfg.ReadOnly,
+		nonDestructive: cfg.NonDestructive,
+		deniedGVKs:     denied,
+	}, nil
+}
+
+// DeniedGVKs returns the parsed denied GVK set, for use by the client factory.
+func (p *Policy) DeniedGVKs() *kubernetes.DeniedGVKs {
+	return p.deniedGVKs
+}
+
+// AllowsClass reports whether tools of the given class may be used.
+func (p *Policy) AllowsClass(class mcpHelpers.ToolClass) bool {
+	switch class {
+	case mcpHelpers.ToolClassRead:
+		return true
+	case mcpHelpers.ToolClassWrite:
+		return !p.readOnly
+	default:
+		return !p.readOnly && !p.nonDestructive
+	}
+}
+
+// ToolFilter hides tools whose class is not allowed by the policy.
+func (p *Policy) ToolFilter() mcpHelpers.ToolFilter {
+	return func(tool *mcp.Tool) bool {
+		return p.AllowsClass(mcpHelpers.ClassifyTool(tool))
+	}
+}
+
+// Middleware rejects calls to disallowed tools and calls that target a denied GVK.
+// Tools are normally hidden by ToolFilter already; the class check here guards
+// against tools registered before the policy was installed.
+func (p *Policy) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			if !p.AllowsClass(call.Class) {
+				return featureDisabledResult(call.Name,
+					fmt.Sprintf("Tool %s is disabled", call.Name),
+					p.modeReason())
+			}
+
+			if group, kind, ok := targetKind(call.Arguments); ok && p.deniedGVKs.Denies(group, kind) {
+				return featureDisabledResult(call.Name,
+					fmt.Sprintf("Access to %s is denied", formatGroupKind(group, kind)),
+					"The kind is listed in security.denied_gvks")
+			}
+
+			return next(ctx, call)
+		}
+	}
+}
+
+// modeReason describes which mode disabled a tool.
+func (p *Policy) modeReason() string {
+	if p.readOnly {
+		return "Read-only mode is enabled"
+	}
+	return "Non-destructive mode is enabled"
+}
+
+// targetKind extracts the group and kind a call operates on, either from
+// explicit group/kind arguments (resources_* tools) or from a manifest.
+func targetKind(args map[string]any) (string, string, bool) {
+	if kind, _ := args["kind"].(string); kind != "" {
+		group, _ := args["group"].(string)
+		return group, kind, true
+	}
+
+	manifest, ok := args["manifest"].(map[string]any)
+	if !ok {
+		return "", "", false
+	}
+	kind, _ := manifest["kind"].(string)
+	if kind == "" {
+		return "", "", false
+	}
+	apiVersion, _ := manifest["apiVersion"].(string)
+	group := ""
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		group = apiVersion[:i]
+	}
+	return group, kind, true
+}
+
+func formatGroupKind(group, kind string) string {
+	if group == "" {
+		return kind
+	}
+	return kind + "." + group
+}
+
+// featureDisabledResult builds a FeatureDisabled error result.
+func featureDisabledResult(tool, message, details string) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "FeatureDisabled",
+			"message": message,
+			"details": details,
+			"tool":    tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// fakeToolset registers one tool per class.
+type fakeToolset struct{}
+
+func (f *fakeToolset) Name() string { return "fake" }
+
+func (f *fakeToolset) Tools() []*mcp.Tool {
+	return []*mcp.Tool{
+		mcpHelpers.NewTool("fake_get", "Read").WithReadOnly().Build(),
+		mcpHelpers.NewTool("fake_apply", "Write").WithNonDestructive().Build(),
+		mcpHelpers.NewTool("fake_delete", "Delete").WithDestructive().Build(),
+	}
+}
+
+func (f *fakeToolset) RegisterTools(server *mcp.Server) error {
+	handler := func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		return mcpHelpers.NewTextResult("ok"), nil, nil
+	}
+	for _, tool := range f.Tools() {
+		mcpHelpers.AddTool(server, &mcp.Tool{Name: tool.Name, Description: tool.Description}, handler)
+	}
+	return nil
+}
+
+// PolicyTestSuite tests security policy enforcement.
+type PolicyTestSuite struct {
+	suite.Suite
+}
+
+// connect builds a server with the policy installed and returns a connected client session.
+func (s *PolicyTestSuite) connect(cfg *config.SecurityConfig) *mcp.ClientSession {
+	policy, err := NewPolicy(cfg)
+	s.Require().NoError(err)
+
+	server := mcpHelpers.NewServer("test", "0.0.0", false)
+	server.AddToolFilter(policy.ToolFilter())
+	server.UseToolMiddleware(policy.Middleware())
+	s.Require().NoError(server.RegisterToolset(&fakeToolset{}))
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func (s *PolicyTestSuite) listToolNames(session *mcp.ClientSession) []string {
+	result, err := session.ListTools(context.Background(), nil)
+	s.Require().NoError(err)
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// TestAllowsClass tests class decisions for each mode.
+func (s *PolicyTestSuite) TestAllowsClass() {
+	cases := []struct {
+		cfg         config.SecurityConfig
+		write       bool
+		destructive bool
+	}{
+		{config.SecurityConfig{}, true, true},
+		{config.SecurityConfig{NonDestructive: true}, true, false},
+		{config.SecurityConfig{ReadOnly: true}, false, false},
+		{config.SecurityConfig{ReadOnly: true, NonDestructive: true}, false, false},
+	}
+	for _, tc := range cases {
+		policy, err := NewPolicy(&tc.cfg)
+		s.Require().NoError(err)
+		s.True(policy.AllowsClass(mcpHelpers.ToolClassRead), "Reads should always be allowed")
+		s.Equal(tc.write, policy.AllowsClass(mcpHelpers.ToolClassWrite), "Write decision for %+v", tc.cfg)
+		s.Equal(tc.destructive, policy.AllowsClass(mcpHelpers.ToolClassDestructive), "Destructive decision for %+v", tc.cfg)
+	}
+}
+
+// TestUnannotatedToolIsDestructive tests that tools without annotations fail closed.
+func (s *PolicyTestSuite) TestUnannotatedToolIsDestructive() {
+	s.Equal(mcpHelpers.ToolClassDestructive, mcpHelpers.ClassifyTool(&mcp.Tool{Name: "x"}))
+	s.Equal(mcpHelpers.ToolClassDestructive, mcpHelpers.ClassifyTool(nil))
+}
+
+// TestInvalidDeniedGVK tests that malformed denied_gvks entries are rejected.
+func (s *PolicyTestSuite) TestInvalidDeniedGVK() {
+	_, err := NewPolicy(&config.SecurityConfig{DeniedGVKs: []string{"Secret"}})
+	s.Error(err)
+}
+
+// TestReadOnlyHidesTools tests that read-only mode hides write and destructive tools.
+func (s *PolicyTestSuite) TestReadOnlyHidesTools() {
+	session := s.connect(&config.SecurityConfig{ReadOnly: true})
+	s.ElementsMatch([]string{"fake_get"}, s.listToolNames(session))
+
+	_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "fake_delete"})
+	s.Error(err, "Hidden tools should not be callable")
+}
+
+// TestNonDestructiveHidesTools tests that non-destructive mode keeps writes.
+func (s *PolicyTestSuite) TestNonDestructiveHidesTools() {
+	session := s.connect(&config.SecurityConfig{NonDestructive: true})
+	s.ElementsMatch([]string{"fake_get", "fake_apply"}, s.listToolNames(session))
+}
+
+// TestDeniedGVKRejected tests that calls targeting a denied kind are rejected.
+func (s *PolicyTestSuite) TestDeniedGVKRejected() {
+	session := s.connect(&config.SecurityConfig{
+		DeniedGVKs: []string{"rbac.authorization.k8s.io/v1/ClusterRole", "v1/Secret"},
+	})
+	ctx := context.Background()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "fake_get",
+		Arguments: map[string]any{"group": "rbac.authorization.k8s.io", "version": "v1", "kind": "ClusterRole"},
+	})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "FeatureDisabled")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name: "fake_apply",
+		Arguments: map[string]any{"manifest": map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+		}},
+	})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "FeatureDisabled")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "fake_get",
+		Arguments: map[string]any{"version": "v1", "kind": "ConfigMap"},
+	})
+	s.Require().NoError(err)
+	s.False(result.IsError, "Kinds not in the deny list should pass")
+}
+
+// assertErrorType asserts that a result carries a structured error of the given type.
+func assertErrorType(s *suite.Suite, result *mcp.CallToolResult, errorType string) {
+	s.Require().Len(result.Content, 1)
+	text, ok := result.Content[0].(*mcp.TextContent)
+	s.Require().True(ok)
+	var payload struct {
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(text.Text), &payload))
+	s.Equal(errorType, payload.Error.Type)
+}
+
+// TestPolicyTestSuite runs the policy test suite.
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
@@ -0,0 +1,163 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Scopes restricts callers to the tools their credential allows: tools whose
+// required OAuth scopes (e.g. kube:write for mutating tools) the token
+// grants, and the tool allowlist of API keys that have one. Scopes are only
+// checked for tokens that carry them; STDIO, client certificate and
+// ServiceAccount token callers have none. The rules can be replaced at
+// runtime with Update.
+type Scopes struct {
+	mu    sync.RWMutex
+	rules []config.ToolScopeRule
+}
+
+// NewScopes creates a scope policy from the tool scope rules.
+func NewScopes(rules []config.ToolScopeRule) (*Scopes, error) {
+	s := &Scopes{}
+	if err := s.Update(rules); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the tool scope rules, e.g. on reload.
+func (s *Scopes) Update(rules []config.ToolScopeRule) error {
+	for i, rule := range rules {
+		if rule.Scope == "" {
+			return fmt.Errorf("invalid server.http.oauth.tool_scopes[%d]: scope is required", i)
+		}
+		for _, pattern := range rule.Tools {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid server.http.oauth.tool_scopes[%d] pattern %q: %w", i, pattern, err)
+			}
+		}
+		for _, class := range rule.Classes {
+			switch mcpHelpers.ToolClass(class) {
+			case mcpHelpers.ToolClassRead, mcpHelpers.ToolClassWrite, mcpHelpers.ToolClassDestructive:
+			default:
+				return fmt.Errorf("invalid server.http.oauth.tool_scopes[%d] class %q: must be \"read\", \"write\" or \"destructive\"", i, class)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = slices.Clone(rules)
+	return nil
+}
+
+// ToolListFilter lists only the tools the caller's credential allows.
+func (s *Scopes) ToolListFilter() mcpHelpers.ToolListFilter {
+	return func(ctx context.Context, tool *mcp.Tool, toolset string) bool {
+		return credentialAllowsTool(ctx, tool.Name) &&
+			len(s.missing(ctx, tool.Name, toolset, mcpHelpers.ClassifyTool(tool))) == 0
+	}
+}
+
+// Middleware rejects calls to tools outside the caller's tool allowlist or
+// that the caller's token lacks a required scope for.
+func (s *Scopes) Middleware() mcpHelpers.ToolMiddleware {
+	return func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			if !credentialAllowsTool(ctx, call.Name) {
+				tools, _ := auth.AllowedToolsFromContext(ctx)
+				return accessDeniedResult(call.Name, fmt.Sprintf("Access to tool %s denied", call.Name),
+					fmt.Sprintf("The caller's credential is restricted to tools %s", strings.Join(tools, ", ")))
+			}
+			if missing := s.missing(ctx, call.Name, call.Toolset, call.Class); len(missing) > 0 {
+				return insufficientScopeResult(call.Name, missing)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// CheckResource decides whether the caller's credential allows reading a
+// resource or completing its names, checked as a call to the read-only tool
+// that returns the same data.
+func (s *Scopes) CheckResource(ctx context.Context, toolName string, _ mcpHelpers.Target) error {
+	if !credentialAllowsTool(ctx, toolName) {
+		tools, _ := auth.AllowedToolsFromContext(ctx)
+		return fmt.Errorf("access denied: the caller's credential is restricted to tools %s", strings.Join(tools, ", "))
+	}
+	if missing := s.missing(ctx, toolName, resourceToolsets[toolName], mcpHelpers.ToolClassRead); len(missing) > 0 {
+		return fmt.Errorf("insufficient scope: tool %s requires scope %s", toolName, strings.Join(missing, " "))
+	}
+	return nil
+}
+
+// credentialAllowsTool reports whether the tool is in the caller's tool
+// allowlist, if it has one.
+func credentialAllowsTool(ctx context.Context, toolName string) bool {
+	tools, restricted := auth.AllowedToolsFromContext(ctx)
+	return !restricted || matchesAny(tools, toolName)
+}
+
+// missing returns the scopes the tool requires that the caller's token does
+// not grant.
+func (s *Scopes) missing(ctx context.Context, toolName, toolset string, class mcpHelpers.ToolClass) []string {
+	granted, ok := auth.ScopesFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var missing []string
+	for _, rule := range s.rules {
+		if !scopeRuleApplies(rule, toolName, toolset, class) {
+			continue
+		}
+		if !slices.Contains(granted, rule.Scope) && !slices.Contains(missing, rule.Scope) {
+			missing = append(missing, rule.Scope)
+		}
+	}
+	return missing
+}
+
+// scopeRuleApplies reports whether a tool scope rule matches the tool.
+func scopeRuleApplies(rule config.ToolScopeRule, toolName, toolset string, class mcpHelpers.ToolClass) bool {
+	if len(rule.Classes) > 0 && !slices.Contains(rule.Classes, string(class)) {
+		return false
+	}
+	if len(rule.Toolsets) == 0 && len(rule.Tools) == 0 {
+		return true
+	}
+	if toolset != "" && slices.Contains(rule.Toolsets, toolset) {
+		return true
+	}
+	return len(rule.Tools) > 0 && matchesAny(rule.Tools, toolName)
+}
+
+// insufficientScopeResult builds a structured error for a call whose token
+// lacks required scopes.
+func insufficientScopeResult(tool string, missing []string) (*mcp.CallToolResult, error) {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "InsufficientScope",
+			"message": fmt.Sprintf("Tool %s requires scope %s", tool, strings.Join(missing, " ")),
+			"details": "Request a token that includes the missing scopes from the authorization server",
+			"scopes":  missing,
+			"tool":    tool,
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	result.IsError = true
+	return result, nil
+}
@@ -0,0 +1,190 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// ScopesTestSuite tests OAuth scope requirements for tools.
+type ScopesTestSuite struct {
+	suite.Suite
+	scopes map[string][]string
+}
+
+func (s *ScopesTestSuite) SetupTest() {
+	s.scopes = map[string][]string{
+		"reader": {"kube:read"},
+		"writer": {"kube:read", "kube:write"},
+		"none":   {},
+	}
+}
+
+// rules require kube:read for every tool and kube:write for mutating tools.
+func (s *ScopesTestSuite) rules() []config.ToolScopeRule {
+	return []config.ToolScopeRule{
+		{Scope: "kube:read"},
+		{Scope: "kube:write", Classes: []string{"write", "destructive"}},
+	}
+}
+
+// connect serves a server with the scope policy over HTTP and connects with
+// token, whose scopes are looked up in s.scopes.
+func (s *ScopesTestSuite) connect(scopes *Scopes, token string) *mcp.ClientSession {
+	server := mcpHelpers.NewServer("test", "0.0.0", false)
+	server.AddToolListFilter(scopes.ToolListFilter())
+	server.UseToolMiddleware(scopes.Middleware())
+	s.Require().NoError(server.RegisterToolset(&fakeToolset{}))
+
+	verify := func(ctx context.Context, token string, _ *http.Request) (*sdkauth.TokenInfo, error) {
+		identity := &auth.Identity{Username: token}
+		return auth.NewTokenInfo(identity, token, s.scopes[token], time.Now().Add(time.Hour)), nil
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server.GetSDKServer() }, nil)
+	httpServer := httptest.NewServer(sdkauth.RequireBearerToken(verify, nil)(handler))
+	s.T().Cleanup(httpServer.Close)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   httpServer.URL,
+		HTTPClient: &http.Client{Transport: &bearerTransport{token: token}},
+	}, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func (s *ScopesTestSuite) policy(rules []config.ToolScopeRule) *Scopes {
+	scopes, err := NewScopes(rules)
+	s.Require().NoError(err)
+	return scopes
+}
+
+func (s *ScopesTestSuite) listToolNames(session *mcp.ClientSession) []string {
+	result, err := session.ListTools(context.Background(), nil)
+	s.Require().NoError(err)
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestToolsList tests that each token lists only the tools it has the scopes for.
+func (s *ScopesTestSuite) TestToolsList() {
+	scopes := s.policy(s.rules())
+
+	s.Equal([]string{"fake_apply", "fake_delete", "fake_get"}, s.listToolNames(s.connect(scopes, "writer")))
+	s.Equal([]string{"fake_get"}, s.listToolNames(s.connect(scopes, "reader")))
+	s.Empty(s.listToolNames(s.connect(scopes, "none")))
+}
+
+// TestCalls tests that calls without the required scopes are rejected.
+func (s *ScopesTestSuite) TestCalls() {
+	scopes := s.policy(s.rules())
+	reader := s.connect(scopes, "reader")
+
+	result, err := reader.CallTool(context.Background(), &mcp.CallToolParams{Name: "fake_get"})
+	s.Require().NoError(err)
+	s.False(result.IsError)
+
+	result, err = reader.CallTool(context.Background(), &mcp.CallToolParams{Name: "fake_delete"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "InsufficientScope")
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "kube:write")
+
+	// A token without a scope claim grants no scopes rather than skipping
+	// the rules
+	scopeless := s.connect(scopes, "scopeless")
+	result, err = scopeless.CallTool(context.Background(), &mcp.CallToolParams{Name: "fake_delete"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "InsufficientScope")
+
+	writer := s.connect(scopes, "writer")
+	result, err = writer.CallTool(context.Background(), &mcp.CallToolParams{Name: "fake_delete"})
+	s.Require().NoError(err)
+	s.False(result.IsError)
+}
+
+// TestMatching tests tool and toolset patterns, and that callers without a
+// bearer token are not checked.
+func (s *ScopesTestSuite) TestMatching() {
+	scopes := s.policy([]config.ToolScopeRule{
+		{Scope: "kube:helm", Toolsets: []string{"helm"}},
+		{Scope: "kube:rollouts", Tools: []string{"rollouts.*"}},
+	})
+	ctx := auth.ContextWithTokenInfo(context.Background(), auth.NewTokenInfo(nil, "token", []string{"kube:helm"}, time.Now()))
+
+	s.Empty(scopes.missing(ctx, "helm_install", "helm", mcpHelpers.ToolClassWrite))
+	s.Equal([]string{"kube:rollouts"}, scopes.missing(ctx, "rollouts.promote", "rollouts", mcpHelpers.ToolClassWrite))
+	s.Empty(scopes.missing(ctx, "pods_list", "core", mcpHelpers.ToolClassRead))
+	s.Empty(scopes.missing(context.Background(), "rollouts.promote", "rollouts", mcpHelpers.ToolClassWrite),
+		"Callers without a bearer token should not be checked")
+}
+
+// TestAllowedTools tests that callers restricted to tools, e.g. by an API
+// key, can only list and call those tools.
+func (s *ScopesTestSuite) TestAllowedTools() {
+	scopes := s.policy(nil)
+	ctx := auth.WithAllowedTools(context.Background(), []string{"fake_get", "fake_a*"})
+	filter := scopes.ToolListFilter()
+	s.True(filter(ctx, &mcp.Tool{Name: "fake_get"}, "fake"))
+	s.True(filter(ctx, &mcp.Tool{Name: "fake_apply"}, "fake"))
+	s.False(filter(ctx, &mcp.Tool{Name: "fake_delete"}, "fake"))
+	s.True(filter(context.Background(), &mcp.Tool{Name: "fake_delete"}, "fake"))
+
+	next := func(context.Context, *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	result, err := scopes.Middleware()(next)(ctx, &mcpHelpers.ToolCall{Name: "fake_delete", Toolset: "fake"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	assertErrorType(&s.Suite, result, "AccessDenied")
+}
+
+// TestCheckResource tests that resources are checked as the equivalent read
+// tool against the allowlist and scopes of the credential.
+func (s *ScopesTestSuite) TestCheckResource() {
+	scopes := s.policy([]config.ToolScopeRule{{Scope: "helm:read", Toolsets: []string{"helm"}}})
+	target := mcpHelpers.Target{Namespace: "shop", Name: "web"}
+
+	s.NoError(scopes.CheckResource(context.Background(), "helm_releases_list", target))
+	ctx := auth.WithScopes(context.Background(), []string{"kube:read"})
+	s.NoError(scopes.CheckResource(ctx, "pods_logs", target))
+	s.ErrorContains(scopes.CheckResource(ctx, "helm_releases_list", target), "requires scope helm:read")
+
+	ctx = auth.WithAllowedTools(context.Background(), []string{"pods_*"})
+	s.NoError(scopes.CheckResource(ctx, "pods_logs", target))
+	s.ErrorContains(scopes.CheckResource(ctx, "resources_get", target), "restricted to tools pods_*")
+}
+
+// TestUpdate tests replacing and validating the rules.
+func (s *ScopesTestSuite) TestUpdate() {
+	scopes := s.policy(nil)
+	none := s.connect(scopes, "none")
+	s.Len(s.listToolNames(none), 3, "All tools should be listed without rules")
+
+	s.Require().NoError(scopes.Update(s.rules()))
+	s.Empty(s.listToolNames(none))
+
+	s.Error(scopes.Update([]config.ToolScopeRule{{Scope: "kube:write", Classes: []string{"mutating"}}}))
+	s.Error(scopes.Update([]config.ToolScopeRule{{Tools: []string{"*"}}}))
+}
+
+// TestScopesTestSuite runs the scopes test suite.
+func TestScopesTestSuite(t *testing.T) {
+	suite.Run(t, new(ScopesTestSuite))
+}
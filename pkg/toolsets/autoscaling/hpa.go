@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -120,7 +121,7 @@ func (t *Toolset) handleHPAList(ctx context.Context, args struct {
 	Limit         int    `json:"limit"`
 	Continue      string `json:"continue"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -172,7 +173,7 @@ func (t *Toolset) handleHPAExplain(ctx context.Context, args struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -96,7 +97,7 @@ func (t *Toolset) handleKEDAScaledObjectsList(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -169,7 +170,7 @@ func (t *Toolset) handleKEDAScaledObjectGet(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -216,7 +217,7 @@ func (t *Toolset) handleKEDATriggersExplain(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -254,7 +255,6 @@ func (t *Toolset) handleKEDAPause(ctx context.Context, args struct {
 	Context   string `json:"context"`
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
-	Confirm   bool   `json:"confirm"`
 }) (*mcp.CallToolResult, error) {
 	// Refresh discovery in case CRDs were installed after startup
 	if t.discovery != nil {
@@ -277,18 +277,14 @@ func (t *Toolset) handleKEDAPause(ctx context.Context, args struct {
 		return result, err
 	}
 
-	if !args.Confirm {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to pause")), nil
-	}
-
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", t.scaledObjectGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", t.scaledObjectGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
@@ -326,7 +322,6 @@ func (t *Toolset) handleKEDAResume(ctx context.Context, args struct {
 	Context   string `json:"context"`
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
-	Confirm   bool   `json:"confirm"`
 }) (*mcp.CallToolResult, error) {
 	// Refresh discovery in case CRDs were installed after startup
 	if t.discovery != nil {
@@ -349,18 +344,14 @@ func (t *Toolset) handleKEDAResume(ctx context.Context, args struct {
 		return result, err
 	}
 
-	if !args.Confirm {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to resume")), nil
-	}
-
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", t.scaledObjectGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", t.scaledObjectGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
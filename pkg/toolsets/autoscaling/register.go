@@ -60,16 +60,16 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithParameter("name", "string", "ScaledObject name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("confirm", "boolean", "Must be true to pause", true).
 			WithDestructive().
+			WithIdempotent().
 			Build())
 
 		tools = append(tools, mcpHelpers.NewTool("autoscaling.keda_resume", "Resume KEDA autoscaling").
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithParameter("name", "string", "ScaledObject name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("confirm", "boolean", "Must be true to resume", true).
 			WithDestructive().
+			WithIdempotent().
 			Build())
 	}
 
@@ -220,7 +220,6 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 			Context   string `json:"context"`
 			Name      string `json:"name"`
 			Namespace string `json:"namespace"`
-			Confirm   bool   `json:"confirm"`
 		}
 		handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 			typedArgs, err := unmarshalArgs[KEDAPauseArgs](args)
@@ -247,7 +246,6 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 			Context   string `json:"context"`
 			Name      string `json:"name"`
 			Namespace string `json:"namespace"`
-			Confirm   bool   `json:"confirm"`
 		}
 		handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 			typedArgs, err := unmarshalArgs[KEDAResumeArgs](args)
@@ -3,24 +3,22 @@ package autoscaling
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
-	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Toolset implements the Autoscaling toolset for HPA and KEDA.
 type Toolset struct {
+	toolsets.RBAC
 	provider        kubernetes.ClientProvider
 	discovery       *kubernetes.CRDDiscovery
 	logger          *observability.Logger
 	metrics         *observability.Metrics
-	rbacAuthorizer  kubernetes.RBACAuthorizer
-	requireRBAC     bool
 	enabled         bool // Always true (HPA is native)
 	hasKEDA         bool
 	scaledObjectGVR schema.GroupVersionResource
@@ -61,12 +59,6 @@ func (t *Toolset) SetObservability(logger *observability.Logger, metrics *observ
 	t.metrics = metrics
 }
 
-// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
-func (t *Toolset) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
-	t.rbacAuthorizer = authorizer
-	t.requireRBAC = requireRBAC
-}
-
 // IsEnabled returns whether the Autoscaling toolset is enabled.
 func (t *Toolset) IsEnabled() bool {
 	return t.enabled
@@ -142,35 +134,3 @@ func (t *Toolset) wrapToolHandler(
 		return result, out, err
 	}
 }
-
-// checkRBAC performs an RBAC check before an operation.
-func (t *Toolset) checkRBAC(ctx context.Context, clientSet *kubernetes.ClientSet, verb string, gvr schema.GroupVersionResource, namespace string) (*mcp.CallToolResult, error) {
-	if !t.requireRBAC || t.rbacAuthorizer == nil {
-		return nil, nil
-	}
-
-	user := ""
-	allowed, err := t.rbacAuthorizer.Allowed(ctx, user, verb, gvr, namespace)
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to check RBAC: %w", err)), nil
-	}
-
-	if !allowed {
-		result, err := mcpHelpers.NewJSONResult(map[string]any{
-			"error": map[string]any{
-				"code":    "KubernetesError",
-				"message": fmt.Sprintf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace),
-				"details": map[string]any{
-					"verb":      verb,
-					"group":     gvr.Group,
-					"resource":  gvr.Resource,
-					"namespace": namespace,
-					"reason":    "Forbidden",
-				},
-			},
-		})
-		return result, err
-	}
-
-	return nil, nil
-}
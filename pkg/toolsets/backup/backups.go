@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -78,7 +79,7 @@ func (t *Toolset) handleBackupsList(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -134,7 +135,7 @@ func (t *Toolset) handleBackupGet(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -219,6 +220,8 @@ func (t *Toolset) handleBackupCreate(ctx context.Context, args struct {
 	SnapshotVolumes         *bool             `json:"snapshot_volumes,omitempty"`
 	IncludeClusterResources *bool             `json:"include_cluster_resources,omitempty"`
 	Confirm                 bool              `json:"confirm"`
+	Wait                    bool              `json:"wait"`
+	Timeout                 int               `json:"timeout"`
 }) (*mcp.CallToolResult, error) {
 	if errResult, err := t.checkFeatureEnabled(); errResult != nil || err != nil {
 		return errResult, err
@@ -228,14 +231,14 @@ func (t *Toolset) handleBackupCreate(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to create backup")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "create", t.backupGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "create", t.backupGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Generate name if not provided
@@ -284,6 +287,13 @@ func (t *Toolset) handleBackupCreate(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create Backup: %w", err)), nil
 	}
 
+	if args.Wait {
+		created, err = t.waitForCompletion(ctx, clientSet.Dynamic.Resource(t.backupGVR).Namespace(args.Namespace), "Backup", name, args.Timeout)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("Backup %s/%s was created but did not complete: %w", args.Namespace, name, err)), nil
+		}
+	}
+
 	summary := t.normalizeBackupSummary(created)
 	result, err := mcpHelpers.NewJSONResult(map[string]any{
 		"result": map[string]any{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -76,7 +77,7 @@ func (t *Toolset) handleLocationsList(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"fmt"
+
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Prompts returns the Velero investigation prompts (only if enabled).
+func (t *Toolset) Prompts() []*mcpHelpers.Prompt {
+	if !t.enabled || !t.hasRestore {
+		return []*mcpHelpers.Prompt{}
+	}
+
+	return []*mcpHelpers.Prompt{
+		mcpHelpers.NewPrompt("investigate_failed_restore", "Investigate a failed Velero restore",
+			"Find out why a Velero restore failed or partially failed").
+			WithContextArgument().
+			WithArgument("namespace", "Velero namespace (usually velero)", true).
+			WithObjectArgument("name", "Restore name", RestoreGVK, true).
+			WithTools("backup.restores_list", "resources_describe", "backup.backup_get", "events_list").
+			WithRender(func(args map[string]string) []string {
+				return []string{
+					fmt.Sprintf("Velero restore %s/%s%s failed. Find out why and what to do next.",
+						args["namespace"], args["name"], mcpHelpers.ContextClause(args)),
+					"Work through these steps, calling the tools with the same context:\n" +
+						fmt.Sprintf("1. Call `backup.restores_list` with namespace=%s to find the restore, its phase, warnings, errors and the backup it was created from.\n", args["namespace"]) +
+						fmt.Sprintf("2. Call `resources_describe` with group=%s, version=%s, kind=%s to read the failure reason and validation errors.\n",
+							RestoreGVK.Group, RestoreGVK.Version, RestoreGVK.Kind) +
+						"3. Call `backup.backup_get` for the source backup and check that it completed, has not expired and covers the restored namespaces.\n" +
+						"4. Call `events_list` for the Velero namespace and the target namespaces to find conflicts, admission denials or volume errors.\n" +
+						"5. Explain the cause and whether a new restore (e.g. with different included namespaces) would succeed. Do not create a restore without asking.",
+				}
+			}).
+			Build(),
+	}
+}
@@ -41,12 +41,14 @@ func (t *Toolset) Tools() []*mcp.Tool {
 		WithParameter("name", "string", "Backup name (optional, auto-generated if not provided)", false).
 		WithParameter("namespace", "string", "Namespace name", true).
 		WithParameter("ttl", "string", "Time to live (e.g., '720h0m0s')", false).
-		WithParameter("included_namespaces", "array", "Namespaces to include", false).
-		WithParameter("excluded_namespaces", "array", "Namespaces to exclude", false).
-		WithParameter("label_selector", "object", "Label selector map", false).
+		WithArrayParameter("included_namespaces", "string", "Namespaces to include", false).
+		WithArrayParameter("excluded_namespaces", "string", "Namespaces to exclude", false).
+		WithMapParameter("label_selector", "string", "Label selector map", false).
 		WithParameter("snapshot_volumes", "boolean", "Snapshot volumes", false).
 		WithParameter("include_cluster_resources", "boolean", "Include cluster resources", false).
 		WithParameter("confirm", "boolean", "Must be true to create", true).
+		WithParameter("wait", "boolean", "Wait until the backup completes or fails (default: false)", false).
+		WithParameter("timeout", "integer", "Maximum seconds to wait (default: 300)", false).
 		WithDestructive().
 		Build())
 
@@ -66,9 +68,11 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("name", "string", "Restore name (optional, auto-generated if not provided)", false).
 			WithParameter("namespace", "string", "Namespace name", true).
 			WithParameter("backup_name", "string", "Backup name to restore from", true).
-			WithParameter("included_namespaces", "array", "Namespaces to include", false).
-			WithParameter("excluded_namespaces", "array", "Namespaces to exclude", false).
+			WithArrayParameter("included_namespaces", "string", "Namespaces to include", false).
+			WithArrayParameter("excluded_namespaces", "string", "Namespaces to exclude", false).
 			WithParameter("confirm", "boolean", "Must be true to create", true).
+			WithParameter("wait", "boolean", "Wait until the restore completes or fails (default: false)", false).
+			WithParameter("timeout", "integer", "Maximum seconds to wait (default: 300)", false).
 			WithDestructive().
 			Build())
 	}
@@ -159,6 +163,8 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 		SnapshotVolumes         *bool             `json:"snapshot_volumes,omitempty"`
 		IncludeClusterResources *bool             `json:"include_cluster_resources,omitempty"`
 		Confirm                 bool              `json:"confirm"`
+		Wait                    bool              `json:"wait"`
+		Timeout                 int               `json:"timeout"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 		typedArgs, err := unmarshalArgs[BackupCreateArgs](args)
@@ -218,6 +224,8 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 			IncludedNamespaces []string `json:"included_namespaces,omitempty"`
 			ExcludedNamespaces []string `json:"excluded_namespaces,omitempty"`
 			Confirm            bool     `json:"confirm"`
+			Wait               bool     `json:"wait"`
+			Timeout            int      `json:"timeout"`
 		}
 		handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 			typedArgs, err := unmarshalArgs[RestoreCreateArgs](args)
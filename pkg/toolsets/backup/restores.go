@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -94,7 +95,7 @@ func (t *Toolset) handleRestoresList(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -148,6 +149,8 @@ func (t *Toolset) handleRestoreCreate(ctx context.Context, args struct {
 	IncludedNamespaces []string `json:"included_namespaces,omitempty"`
 	ExcludedNamespaces []string `json:"excluded_namespaces,omitempty"`
 	Confirm            bool     `json:"confirm"`
+	Wait               bool     `json:"wait"`
+	Timeout            int      `json:"timeout"`
 }) (*mcp.CallToolResult, error) {
 	if errResult, err := t.checkFeatureEnabled(); errResult != nil || err != nil {
 		return errResult, err
@@ -178,14 +181,14 @@ func (t *Toolset) handleRestoreCreate(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to create restore")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "create", t.restoreGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "create", t.restoreGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Generate name if not provided
@@ -222,6 +225,13 @@ func (t *Toolset) handleRestoreCreate(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create Restore: %w", err)), nil
 	}
 
+	if args.Wait {
+		created, err = t.waitForCompletion(ctx, clientSet.Dynamic.Resource(t.restoreGVR).Namespace(args.Namespace), "Restore", name, args.Timeout)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("Restore %s/%s was created but did not complete: %w", args.Namespace, name, err)), nil
+		}
+	}
+
 	summary := t.normalizeRestoreSummary(created)
 	result, err := mcpHelpers.NewJSONResult(map[string]any{
 		"result": map[string]any{
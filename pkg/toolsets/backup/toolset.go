@@ -10,17 +10,20 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
 // Toolset implements the Backup/Restore toolset for Velero.
 type Toolset struct {
+	toolsets.RBAC
 	provider                 kubernetes.ClientProvider
 	discovery                *kubernetes.CRDDiscovery
 	logger                   *observability.Logger
 	metrics                  *observability.Metrics
-	rbacAuthorizer           kubernetes.RBACAuthorizer
-	requireRBAC              bool
 	enabled                  bool
 	backupGVR                schema.GroupVersionResource
 	restoreGVR               schema.GroupVersionResource
@@ -82,12 +85,6 @@ func (t *Toolset) SetObservability(logger *observability.Logger, metrics *observ
 	t.metrics = metrics
 }
 
-// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
-func (t *Toolset) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
-	t.rbacAuthorizer = authorizer
-	t.requireRBAC = requireRBAC
-}
-
 // IsEnabled returns whether the Backup/Restore toolset is enabled.
 func (t *Toolset) IsEnabled() bool {
 	return t.enabled
@@ -179,34 +176,28 @@ func (t *Toolset) checkFeatureEnabled() (*mcp.CallToolResult, error) {
 	return nil, nil
 }
 
-// checkRBAC performs an RBAC check before an operation.
-func (t *Toolset) checkRBAC(ctx context.Context, clientSet *kubernetes.ClientSet, verb string, gvr schema.GroupVersionResource, namespace string) (*mcp.CallToolResult, error) {
-	if !t.requireRBAC || t.rbacAuthorizer == nil {
-		return nil, nil
-	}
+// waitInterval is how often waitForCompletion polls.
+var waitInterval = 5 * time.Second
 
-	user := ""
-	allowed, err := t.rbacAuthorizer.Allowed(ctx, user, verb, gvr, namespace)
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to check RBAC: %w", err)), nil
-	}
-
-	if !allowed {
-		result, err := mcpHelpers.NewJSONResult(map[string]any{
-			"error": map[string]any{
-				"code":    "KubernetesError",
-				"message": fmt.Sprintf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace),
-				"details": map[string]any{
-					"verb":      verb,
-					"group":     gvr.Group,
-					"resource":  gvr.Resource,
-					"namespace": namespace,
-					"reason":    "Forbidden",
-				},
-			},
-		})
-		return result, err
-	}
-
-	return nil, nil
+// waitForCompletion polls a Backup or Restore until Velero reports a final
+// phase, and returns the final object.
+func (t *Toolset) waitForCompletion(ctx context.Context, client dynamic.ResourceInterface, kind, name string, timeoutSeconds int) (*unstructured.Unstructured, error) {
+	var current *unstructured.Unstructured
+	err := mcpHelpers.WaitFor(ctx, waitInterval, time.Duration(timeoutSeconds)*time.Second, func(ctx context.Context) (bool, string, error) {
+		obj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get %s: %w", kind, err)
+		}
+		current = obj
+
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		switch phase {
+		case "Completed", "PartiallyFailed", "Failed", "FailedValidation":
+			return true, "", nil
+		case "":
+			phase = "New"
+		}
+		return false, fmt.Sprintf("%s %s is %s", kind, name, phase), nil
+	})
+	return current, err
 }
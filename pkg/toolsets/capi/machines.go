@@ -3,8 +3,10 @@ package capi
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -155,7 +157,7 @@ func (t *Toolset) handleMachinesList(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -220,7 +222,7 @@ func (t *Toolset) handleMachineDeploymentsList(ctx context.Context, args struct
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -253,7 +255,7 @@ func (t *Toolset) handleRolloutStatus(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -325,6 +327,8 @@ func (t *Toolset) handleScaleMachineDeployment(ctx context.Context, args struct
 	Name      string `json:"name"`
 	Replicas  int    `json:"replicas"`
 	Confirm   bool   `json:"confirm"`
+	Wait      bool   `json:"wait"`
+	Timeout   int    `json:"timeout"`
 }) (*mcp.CallToolResult, error) {
 	if errResult, err := t.checkFeatureEnabled(); errResult != nil || err != nil {
 		return errResult, err
@@ -349,14 +353,14 @@ func (t *Toolset) handleScaleMachineDeployment(ctx context.Context, args struct
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to scale")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", t.machineDeploymentGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", t.machineDeploymentGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
@@ -376,7 +380,38 @@ func (t *Toolset) handleScaleMachineDeployment(ctx context.Context, args struct
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to scale MachineDeployment: %w", err)), nil
 	}
 
+	if args.Wait {
+		updated, err = t.waitForMachineDeployment(ctx, clientSet, args.Namespace, args.Name, int64(args.Replicas), args.Timeout)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("MachineDeployment %s/%s was scaled but did not become ready: %w", args.Namespace, args.Name, err)), nil
+		}
+	}
+
 	summary := t.normalizeMachineDeploymentSummary(updated)
 
 	return mcpHelpers.NewJSONResult(map[string]any{"summary": summary})
 }
+
+// waitInterval is how often waitForMachineDeployment polls.
+var waitInterval = 5 * time.Second
+
+// waitForMachineDeployment polls a MachineDeployment until its controller has
+// observed the latest spec and all replicas are updated and ready.
+func (t *Toolset) waitForMachineDeployment(ctx context.Context, clientSet *kubernetes.ClientSet, namespace, name string, replicas int64, timeoutSeconds int) (*unstructured.Unstructured, error) {
+	var current *unstructured.Unstructured
+	err := mcpHelpers.WaitFor(ctx, waitInterval, time.Duration(timeoutSeconds)*time.Second, func(ctx context.Context) (bool, string, error) {
+		obj, err := clientSet.Dynamic.Resource(t.machineDeploymentGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get MachineDeployment: %w", err)
+		}
+		current = obj
+
+		observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		total, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		done := observed >= obj.GetGeneration() && total == replicas && updated == replicas && ready == replicas
+		return done, fmt.Sprintf("%d/%d replicas ready", ready, replicas), nil
+	})
+	return current, err
+}
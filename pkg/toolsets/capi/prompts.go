@@ -0,0 +1,42 @@
+package capi
+
+import (
+	"fmt"
+
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Prompts returns the Cluster API planning prompts (only if enabled).
+func (t *Toolset) Prompts() []*mcpHelpers.Prompt {
+	if !t.enabled || !t.hasMachineDeployment {
+		return []*mcpHelpers.Prompt{}
+	}
+
+	return []*mcpHelpers.Prompt{
+		mcpHelpers.NewPrompt("plan_machinedeployment_scale", "Plan a MachineDeployment scale",
+			"Check that a Cluster API MachineDeployment can be scaled safely and plan the change").
+			WithContextArgument().
+			WithArgument("namespace", "Namespace of the MachineDeployment", true).
+			WithObjectArgument("name", "MachineDeployment name", MachineDeploymentGVK, true).
+			WithArgument("replicas", "Target number of replicas", false).
+			WithTools("resources_get", "capi.cluster_get", "capi.rollout_status", "capi.machinedeployments_list", "capi.machines_list").
+			WithRender(func(args map[string]string) []string {
+				target := "a suitable number of replicas"
+				if args["replicas"] != "" {
+					target = args["replicas"] + " replicas"
+				}
+				return []string{
+					fmt.Sprintf("Plan scaling MachineDeployment %s/%s%s to %s.",
+						args["namespace"], args["name"], mcpHelpers.ContextClause(args), target),
+					"Work through these steps, calling the tools with the same context:\n" +
+						fmt.Sprintf("1. Call `resources_get` with group=%s, version=%s, kind=%s to read the current replicas, the cluster name label (cluster.x-k8s.io/cluster-name) and any autoscaler min/max annotations.\n",
+							MachineDeploymentGVK.Group, MachineDeploymentGVK.Version, MachineDeploymentGVK.Kind) +
+						"2. Call `capi.cluster_get` and `capi.rollout_status` for the owning cluster and check that it is provisioned and no rollout is in progress.\n" +
+						"3. Call `capi.machinedeployments_list` and `capi.machines_list` for the cluster and look for machines that are not running or are being deleted.\n" +
+						"4. Present a plan: current and target replicas, risks (autoscaler conflicts, capacity, in-flight rollouts, workloads on machines that would be removed) and how to roll back.\n" +
+						"5. Only after the plan is approved, call `capi.scale_machinedeployment` with confirm=true, then follow up with `capi.rollout_status`.",
+				}
+			}).
+			Build(),
+	}
+}
@@ -71,7 +71,10 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("name", "string", "MachineDeployment name", true).
 			WithParameter("replicas", "integer", "Number of replicas", true).
 			WithParameter("confirm", "boolean", "Must be true to scale", true).
+			WithParameter("wait", "boolean", "Wait until all replicas are ready (default: false)", false).
+			WithParameter("timeout", "integer", "Maximum seconds to wait (default: 300)", false).
 			WithDestructive().
+			WithIdempotent().
 			Build())
 	}
 
@@ -229,6 +232,8 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 			Name      string `json:"name"`
 			Replicas  int    `json:"replicas"`
 			Confirm   bool   `json:"confirm"`
+			Wait      bool   `json:"wait"`
+			Timeout   int    `json:"timeout"`
 		}
 		handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 			typedArgs, err := unmarshalArgs[ScaleMachineDeploymentArgs](args)
@@ -53,5 +53,19 @@ func (t *Toolset) TestHandleScaleMachineDeployment(ctx context.Context, args str
 	Replicas  int    `json:"replicas"`
 	Confirm   bool   `json:"confirm"`
 }) (*mcp.CallToolResult, error) {
-	return t.handleScaleMachineDeployment(ctx, args)
+	return t.handleScaleMachineDeployment(ctx, struct {
+		Context   string `json:"context"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		Replicas  int    `json:"replicas"`
+		Confirm   bool   `json:"confirm"`
+		Wait      bool   `json:"wait"`
+		Timeout   int    `json:"timeout"`
+	}{
+		Context:   args.Context,
+		Namespace: args.Namespace,
+		Name:      args.Name,
+		Replicas:  args.Replicas,
+		Confirm:   args.Confirm,
+	})
 }
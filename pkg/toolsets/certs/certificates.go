@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -87,7 +88,7 @@ func (t *Toolset) handleCertificatesList(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -143,7 +144,7 @@ func (t *Toolset) handleCertificateGet(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -197,14 +198,14 @@ func (t *Toolset) handleCertificateRenew(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to renew")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", t.certificateGVR, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", t.certificateGVR, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
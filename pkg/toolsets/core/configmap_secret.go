@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,11 +19,20 @@ func (t *Toolset) handleConfigMapsGetData(ctx context.Context, args struct {
 	Keys      []string `json:"keys"` // Optional: specific keys to retrieve
 	Context   string   `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "configmaps",
+	}, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	configMap, err := clientSet.Typed.CoreV1().ConfigMaps(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get ConfigMap: %w", err)), nil
@@ -64,21 +74,18 @@ func (t *Toolset) handleConfigMapsSetData(ctx context.Context, args struct {
 	Merge     bool              `json:"merge"` // If true, merge with existing data; if false, replace
 	Context   string            `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// Check RBAC
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "update", schema.GroupVersionResource{
+	if result := t.CheckRBAC(ctx, args.Context, "update", schema.GroupVersionResource{
 		Group:    "",
 		Version:  "v1",
 		Resource: "configmaps",
-	}, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	}, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get existing ConfigMap
@@ -127,11 +134,20 @@ func (t *Toolset) handleSecretsGetData(ctx context.Context, args struct {
 	Decode    bool     `json:"decode"` // If true, base64 decode the values
 	Context   string   `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "secrets",
+	}, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	secret, err := clientSet.Typed.CoreV1().Secrets(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get Secret: %w", err)), nil
@@ -188,21 +204,18 @@ func (t *Toolset) handleSecretsSetData(ctx context.Context, args struct {
 	Encode    bool              `json:"encode"` // If true, base64 encode the provided values; if false, assume already encoded
 	Context   string            `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// Check RBAC
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "update", schema.GroupVersionResource{
+	if result := t.CheckRBAC(ctx, args.Context, "update", schema.GroupVersionResource{
 		Group:    "",
 		Version:  "v1",
 		Resource: "secrets",
-	}, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	}, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get existing Secret
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"sort"
 	"strings"
@@ -25,7 +26,7 @@ func (t *Toolset) handleResourcesDescribe(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -43,13 +44,23 @@ func (t *Toolset) handleResourcesDescribe(ctx context.Context, args struct {
 	}
 
 	gvr := mapping.Resource
+
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+
 	resource, err := clientSet.Dynamic.Resource(gvr).Namespace(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get resource: %w", err)), nil
 	}
 
-	// Get events for this resource
-	events, err := t.getResourceEvents(ctx, clientSet, args.Namespace, gvk.Kind, args.Name)
+	// Get events for this resource, if the caller may list them
+	eventsGVR := schema.GroupVersionResource{Version: "v1", Resource: "events"}
+	events := []corev1.Event{}
+	if t.CheckRBAC(ctx, args.Context, "list", eventsGVR, args.Namespace) == nil {
+		events, err = t.getResourceEvents(ctx, clientSet, args.Namespace, gvk.Kind, args.Name)
+	}
 	if err != nil {
 		// Don't fail if events can't be fetched, just continue without them
 		events = []corev1.Event{}
@@ -122,6 +133,8 @@ func (t *Toolset) formatDescribeOutput(resource *unstructured.Unstructured, even
 		t.formatWorkloadDescribe(&buf, resource)
 	case "Service":
 		t.formatServiceDescribe(&buf, resource)
+	case "Secret":
+		t.formatSecretDescribe(&buf, resource)
 	default:
 		t.formatGenericDescribe(&buf, resource)
 	}
@@ -132,7 +145,7 @@ func (t *Toolset) formatDescribeOutput(resource *unstructured.Unstructured, even
 		buf.WriteString(fmt.Sprintf("  %-8s %-12s %-8s %-12s %s\n", "Type", "Reason", "Age", "From", "Message"))
 		buf.WriteString("  " + strings.Repeat("-", 80) + "\n")
 		for _, event := range events {
-			age := formatAge(event.LastTimestamp.Time)
+			age := mcpHelpers.FormatAge(event.LastTimestamp.Time)
 			buf.WriteString(fmt.Sprintf("  %-8s %-12s %-8s %-12s %s\n",
 				event.Type,
 				event.Reason,
@@ -261,6 +274,30 @@ func (t *Toolset) formatWorkloadDescribe(buf *strings.Builder, resource *unstruc
 	}
 }
 
+// formatSecretDescribe formats secret-specific describe output. Like kubectl,
+// it shows the size of each value but never the value itself.
+func (t *Toolset) formatSecretDescribe(buf *strings.Builder, secret *unstructured.Unstructured) {
+	if secretType, found, _ := unstructured.NestedString(secret.Object, "type"); found {
+		buf.WriteString(fmt.Sprintf("Type:         %s\n", secretType))
+	}
+
+	data, _, _ := unstructured.NestedMap(secret.Object, "data")
+	buf.WriteString("\nData\n====\n")
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		encoded, _ := data[key].(string)
+		size := base64.StdEncoding.DecodedLen(len(encoded))
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			size = len(decoded)
+		}
+		buf.WriteString(fmt.Sprintf("%s:  %d bytes\n", key, size))
+	}
+}
+
 // formatServiceDescribe formats service-specific describe output.
 func (t *Toolset) formatServiceDescribe(buf *strings.Builder, svc *unstructured.Unstructured) {
 	// Type
@@ -336,8 +373,15 @@ func formatAnnotations(annotations map[string]string) string {
 	}
 	pairs := make([]string, 0, len(annotations))
 	for k, v := range annotations {
+		// Like kubectl, skip the applied manifest; it may hold Secret data
+		if k == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
 		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
 	}
+	if len(pairs) == 0 {
+		return "<none>"
+	}
 	sort.Strings(pairs)
 	return strings.Join(pairs, ", ")
 }
@@ -346,20 +390,6 @@ func formatTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05 -0700 MST")
 }
 
-func formatAge(t time.Time) string {
-	duration := time.Since(t)
-	if duration < time.Minute {
-		return fmt.Sprintf("%ds", int(duration.Seconds()))
-	}
-	if duration < time.Hour {
-		return fmt.Sprintf("%dm", int(duration.Minutes()))
-	}
-	if duration < 24*time.Hour {
-		return fmt.Sprintf("%dh", int(duration.Hours()))
-	}
-	return fmt.Sprintf("%dd", int(duration.Hours()/24))
-}
-
 func convertToStringMap(m map[string]interface{}) map[string]string {
 	result := make(map[string]string)
 	for k, v := range m {
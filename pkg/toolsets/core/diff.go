@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -26,7 +27,7 @@ func (t *Toolset) handleResourcesDiff(ctx context.Context, args struct {
 	DiffFormat string                 `json:"diff_format"` // "unified" (default), "json", or "yaml"
 	Context    string                 `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -45,6 +46,16 @@ func (t *Toolset) handleResourcesDiff(ctx context.Context, args struct {
 
 	gvr := mapping.Resource
 
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+
+	// The desired object must be the one compared against
+	if namespace, _, _ := unstructured.NestedString(args.Manifest, "metadata", "namespace"); namespace != "" && namespace != args.Namespace {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("manifest namespace %q does not match namespace %q", namespace, args.Namespace)), nil
+	}
+
 	// Get current resource
 	current, err := clientSet.Dynamic.Resource(gvr).Namespace(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
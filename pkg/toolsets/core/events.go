@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // handleEventsList handles the events_list tool.
@@ -14,11 +16,17 @@ func (t *Toolset) handleEventsList(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	eventsGVR := schema.GroupVersionResource{Version: "v1", Resource: "events"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", eventsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	events, err := clientSet.Typed.CoreV1().Events(args.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to list events: %w", err)), nil
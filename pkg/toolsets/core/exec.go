@@ -0,0 +1,321 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// defaultExecConfig applies when SetExecConfig was not called.
+var defaultExecConfig = config.ExecConfig{
+	Timeout:        config.Duration(30 * time.Second),
+	MaxTimeout:     config.Duration(5 * time.Minute),
+	MaxOutputBytes: 1 << 20,
+	MaxStdinBytes:  1 << 20,
+}
+
+// executorFunc creates an executor for a URL of the exec subresource.
+type executorFunc func(config *rest.Config, u *url.URL) (remotecommand.Executor, error)
+
+// newExecutor creates an executor speaking the WebSocket exec protocol, and
+// SPDY to API servers that do not support it, as kubectl does.
+func newExecutor(config *rest.Config, u *url.URL) (remotecommand.Executor, error) {
+	websocket, err := remotecommand.NewWebSocketExecutor(config, http.MethodGet, u.String())
+	if err != nil {
+		return nil, err
+	}
+	spdy, err := remotecommand.NewSPDYExecutor(config, http.MethodPost, u)
+	if err != nil {
+		return nil, err
+	}
+	return remotecommand.NewFallbackExecutor(websocket, spdy, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+}
+
+// SetExecConfig sets the limits of pods_exec: timeouts, output and stdin
+// sizes, and the commands allowed in each namespace.
+func (t *Toolset) SetExecConfig(cfg *config.ExecConfig) error {
+	for i, rule := range cfg.Rules {
+		for _, patterns := range [][]string{rule.Namespaces, rule.Allow, rule.Deny} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("exec rule %d: invalid pattern %q: %w", i, pattern, err)
+				}
+			}
+		}
+	}
+	t.exec = *cfg
+	return nil
+}
+
+// execRule returns the rule for commands in namespace, or nil if there is none.
+func (t *Toolset) execRule(namespace string) *config.ExecRule {
+	for i, rule := range t.exec.Rules {
+		if len(rule.Namespaces) == 0 || matchesAny(rule.Namespaces, namespace) {
+			return &t.exec.Rules[i]
+		}
+	}
+	return nil
+}
+
+// commandWrappers are commands that run the command given in their
+// arguments, such as env sh or busybox sh, and their options that take a
+// separate value.
+var commandWrappers = map[string][]string{
+	"busybox": nil,
+	"env":     {"-u", "--unset", "-C", "--chdir", "-S", "--split-string"},
+	"ionice":  {"-c", "--class", "-n", "--classdata"},
+	"nice":    {"-n", "--adjustment"},
+	"nohup":   nil,
+	"setsid":  nil,
+	"stdbuf":  {"-i", "--input", "-o", "--output", "-e", "--error"},
+	"timeout": {"-s", "--signal", "-k", "--kill-after"},
+}
+
+// executables returns the executable of command, and if it is a wrapper, the
+// executables it runs. Options and variable assignments of wrappers are
+// skipped, as is the duration of timeout. The string env -S splits, whether
+// given as a separate value, as -S<string> or as --split-string=<string>, is
+// read as further arguments of env.
+func executables(command []string) []string {
+	var names []string
+	for len(command) > 0 {
+		names = append(names, command[0])
+		wrapper := path.Base(command[0])
+		options, ok := commandWrappers[wrapper]
+		if !ok {
+			break
+		}
+		command = command[1:]
+		for len(command) > 0 && (strings.HasPrefix(command[0], "-") || strings.Contains(command[0], "=")) {
+			option := command[0]
+			command = command[1:]
+			if wrapper == "env" {
+				if split, ok := splitString(option); ok {
+					command = append(strings.Fields(split), command...)
+					continue
+				}
+			}
+			if !slices.Contains(options, option) || len(command) == 0 {
+				continue
+			}
+			if option == "-S" || option == "--split-string" {
+				command = append(strings.Fields(command[0]), command[1:]...)
+				continue
+			}
+			command = command[1:]
+		}
+		if wrapper == "timeout" && len(command) > 0 {
+			command = command[1:]
+		}
+	}
+	return names
+}
+
+// splitString returns the string of an env -S<string> or
+// --split-string=<string> option.
+func splitString(option string) (string, bool) {
+	if value, ok := strings.CutPrefix(option, "--split-string="); ok {
+		return value, true
+	}
+	if len(option) > 2 && strings.HasPrefix(option, "-S") {
+		return option[2:], true
+	}
+	return "", false
+}
+
+// commandAllowed reports whether command may run in namespace, and if not,
+// why. Commands run through wrappers must be allowed as well.
+func (t *Toolset) commandAllowed(namespace string, command []string) (bool, string) {
+	rule := t.execRule(namespace)
+	if rule == nil {
+		return true, ""
+	}
+	for _, executable := range executables(command) {
+		names := []string{executable, path.Base(executable)}
+		for _, name := range names {
+			if matchesAny(rule.Deny, name) {
+				return false, fmt.Sprintf("%q is denied in namespace %s", executable, namespace)
+			}
+		}
+		if len(rule.Allow) > 0 && !matchesAny(rule.Allow, names[0]) && !matchesAny(rule.Allow, names[1]) {
+			return false, fmt.Sprintf("%q is not in the allowed commands of namespace %s", executable, namespace)
+		}
+	}
+	return true, ""
+}
+
+// matchesAny reports whether value matches one of the glob patterns.
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest,
+// so a chatty command cannot exhaust memory or stall the stream.
+type cappedBuffer struct {
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+// Write implements io.Writer.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - len(b.buf); room < len(p) {
+		b.buf = append(b.buf, p[:max(room, 0)]...)
+		b.truncated = true
+	} else {
+		b.buf = append(b.buf, p...)
+	}
+	return len(p), nil
+}
+
+// execResult is the outcome of a command.
+type execResult struct {
+	Stdout          string `json:"stdout"`
+	Stderr          string `json:"stderr"`
+	ExitCode        int    `json:"exit_code"`
+	StdoutTruncated bool   `json:"stdout_truncated,omitempty"`
+	StderrTruncated bool   `json:"stderr_truncated,omitempty"`
+	TimedOut        bool   `json:"timed_out,omitempty"`
+}
+
+// execDeniedResult builds a structured error for a command the exec rules
+// do not allow.
+func execDeniedResult(reason string) *mcp.CallToolResult {
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"type":    "AccessDenied",
+			"message": "Command not allowed",
+			"details": reason,
+			"tool":    "pods_exec",
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err)
+	}
+	result.IsError = true
+	return result
+}
+
+// handlePodsExec handles the pods_exec tool.
+func (t *Toolset) handlePodsExec(ctx context.Context, args struct {
+	Name           string   `json:"name"`
+	Namespace      string   `json:"namespace"`
+	Container      string   `json:"container"`
+	Command        []string `json:"command"`
+	Stdin          string   `json:"stdin"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+	Context        string   `json:"context"`
+}) (*mcp.CallToolResult, error) {
+	if len(args.Command) == 0 || args.Command[0] == "" {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("command is required")), nil
+	}
+	if len(args.Stdin) > t.exec.MaxStdinBytes {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("stdin is %d bytes, more than the limit of %d", len(args.Stdin), t.exec.MaxStdinBytes)), nil
+	}
+	timeout := t.exec.Timeout.Duration()
+	if args.TimeoutSeconds > 0 {
+		timeout = time.Duration(args.TimeoutSeconds) * time.Second
+	}
+	if maxTimeout := t.exec.MaxTimeout.Duration(); timeout > maxTimeout {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("timeout of %s exceeds the limit of %s", timeout, maxTimeout)), nil
+	}
+	if allowed, reason := t.commandAllowed(args.Namespace, args.Command); !allowed {
+		return execDeniedResult(reason), nil
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
+	}
+
+	// Check RBAC before running the command
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods/exec"}
+	if result := t.CheckRBAC(ctx, args.Context, "create", gvr, args.Namespace); result != nil {
+		return result, nil
+	}
+
+	opts := &corev1.PodExecOptions{
+		Container: args.Container,
+		Command:   args.Command,
+		Stdin:     args.Stdin != "",
+		Stdout:    true,
+		Stderr:    true,
+	}
+	execURL := clientSet.Typed.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(args.Namespace).
+		Name(args.Name).
+		SubResource("exec").
+		VersionedParams(opts, scheme.ParameterCodec).
+		URL()
+
+	newExec := t.newExecutor
+	if newExec == nil {
+		newExec = newExecutor
+	}
+	executor, err := newExec(clientSet.Config, execURL)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create executor: %w", err)), nil
+	}
+
+	stdout := &cappedBuffer{max: t.exec.MaxOutputBytes}
+	stderr := &cappedBuffer{max: t.exec.MaxOutputBytes}
+	streamOpts := remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr}
+	if args.Stdin != "" {
+		streamOpts.Stdin = strings.NewReader(args.Stdin)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err = executor.StreamWithContext(execCtx, streamOpts)
+
+	result := execResult{
+		Stdout:          string(stdout.buf),
+		Stderr:          string(stderr.buf),
+		StdoutTruncated: stdout.truncated,
+		StderrTruncated: stderr.truncated,
+	}
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr) && exitErr.Exited():
+		result.ExitCode = exitErr.ExitStatus()
+	case execCtx.Err() != nil && ctx.Err() == nil:
+		result.ExitCode = -1
+		result.TimedOut = true
+	default:
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to exec: %w", err)), nil
+	}
+
+	callResult, err := mcpHelpers.NewJSONResult(result)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	callResult.IsError = result.TimedOut
+	return callResult, nil
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor runs a command by calling run with the streams.
+type fakeExecutor struct {
+	run func(ctx context.Context, opts remotecommand.StreamOptions) error
+}
+
+func (e *fakeExecutor) Stream(opts remotecommand.StreamOptions) error {
+	return e.run(context.Background(), opts)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, opts remotecommand.StreamOptions) error {
+	return e.run(ctx, opts)
+}
+
+// execProvider serves a client set for an API server that is never called.
+type execProvider struct {
+	clientSet *kubernetes.ClientSet
+}
+
+func (p *execProvider) GetClientSet(string) (*kubernetes.ClientSet, error) { return p.clientSet, nil }
+func (p *execProvider) ListContexts() ([]string, error)                    { return nil, nil }
+func (p *execProvider) GetCurrentContext() (string, error)                 { return "", nil }
+
+// execArgs are the arguments of pods_exec.
+type execArgs = struct {
+	Name           string   `json:"name"`
+	Namespace      string   `json:"namespace"`
+	Container      string   `json:"container"`
+	Command        []string `json:"command"`
+	Stdin          string   `json:"stdin"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+	Context        string   `json:"context"`
+}
+
+// ExecTestSuite tests pods_exec.
+type ExecTestSuite struct {
+	suite.Suite
+	toolset *Toolset
+	url     *url.URL
+	run     func(ctx context.Context, opts remotecommand.StreamOptions) error
+}
+
+func (s *ExecTestSuite) SetupTest() {
+	restConfig := &rest.Config{Host: "https://k8s.example.com"}
+	typed, err := k8sclient.NewForConfig(restConfig)
+	s.Require().NoError(err)
+	s.toolset = NewToolset(&execProvider{clientSet: &kubernetes.ClientSet{Typed: typed, Config: restConfig}})
+	s.toolset.newExecutor = func(_ *rest.Config, u *url.URL) (remotecommand.Executor, error) {
+		s.url = u
+		return &fakeExecutor{run: s.run}, nil
+	}
+}
+
+// exec runs pods_exec and decodes the result.
+func (s *ExecTestSuite) exec(args execArgs) (*mcp.CallToolResult, map[string]any) {
+	if args.Name == "" {
+		args.Name, args.Namespace = "web", "default"
+	}
+	result, err := s.toolset.handlePodsExec(context.Background(), args)
+	s.Require().NoError(err)
+	var body map[string]any
+	_ = json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &body)
+	return result, body
+}
+
+// TestExec tests separate stdout and stderr, stdin, the exit code and the
+// exec request.
+func (s *ExecTestSuite) TestExec() {
+	s.run = func(_ context.Context, opts remotecommand.StreamOptions) error {
+		input, _ := io.ReadAll(opts.Stdin)
+		_, _ = io.WriteString(opts.Stdout, "read: "+string(input))
+		_, _ = io.WriteString(opts.Stderr, "warning")
+		return utilexec.CodeExitError{Err: io.EOF, Code: 3}
+	}
+
+	result, body := s.exec(execArgs{Name: "web", Namespace: "shop", Container: "app", Command: []string{"cat"}, Stdin: "hello"})
+	s.False(result.IsError)
+	s.Equal("read: hello", body["stdout"])
+	s.Equal("warning", body["stderr"])
+	s.Equal(3.0, body["exit_code"])
+
+	s.Equal("/api/v1/namespaces/shop/pods/web/exec", s.url.Path)
+	query := s.url.Query()
+	s.Equal([]string{"cat"}, query["command"])
+	s.Equal("app", query.Get("container"))
+	s.Equal("true", query.Get("stdin"))
+	s.Empty(query.Get("tty"))
+}
+
+// TestLimits tests the output cap, the timeout and the stdin limit.
+func (s *ExecTestSuite) TestLimits() {
+	s.Require().NoError(s.toolset.SetExecConfig(&config.ExecConfig{
+		Timeout:        config.Duration(50 * time.Millisecond),
+		MaxTimeout:     config.Duration(time.Minute),
+		MaxOutputBytes: 4,
+		MaxStdinBytes:  4,
+	}))
+	s.run = func(ctx context.Context, opts remotecommand.StreamOptions) error {
+		_, _ = io.WriteString(opts.Stdout, "0123456789")
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	result, body := s.exec(execArgs{Command: []string{"yes"}})
+	s.True(result.IsError)
+	s.Equal("0123", body["stdout"])
+	s.Equal(true, body["stdout_truncated"])
+	s.Equal(true, body["timed_out"])
+	s.Equal(-1.0, body["exit_code"])
+
+	result, _ = s.exec(execArgs{Command: []string{"cat"}, Stdin: "too long"})
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "stdin")
+
+	result, _ = s.exec(execArgs{Command: []string{"sleep", "600"}, TimeoutSeconds: 600})
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "exceeds the limit")
+}
+
+// TestRules tests allowed and denied commands per namespace.
+func (s *ExecTestSuite) TestRules() {
+	cfg := defaultExecConfig
+	cfg.Rules = []config.ExecRule{
+		{Namespaces: []string{"prod-*"}, Allow: []string{"ls", "cat"}},
+		{Deny: []string{"sh", "bash"}},
+	}
+	s.Require().NoError(s.toolset.SetExecConfig(&cfg))
+	s.run = func(context.Context, remotecommand.StreamOptions) error { return nil }
+
+	for _, tc := range []struct {
+		namespace string
+		command   []string
+		allowed   bool
+	}{
+		{"prod-eu", []string{"ls"}, true},
+		{"prod-eu", []string{"/bin/cat"}, true},
+		{"prod-eu", []string{"env"}, false},
+		{"dev", []string{"env"}, true},
+		{"dev", []string{"/bin/sh"}, false},
+		{"dev", []string{"env", "-i", "PATH=/bin", "sh"}, false},
+		{"dev", []string{"timeout", "5", "bash", "-c", "id"}, false},
+		{"dev", []string{"busybox", "ls"}, true},
+		{"dev", []string{"nice", "-n", "10", "ls"}, true},
+		{"dev", []string{"nice", "-n", "10", "sh"}, false},
+		{"dev", []string{"env", "-S", "sh -c id"}, false},
+		{"dev", []string{"env", "-Ssh -c id"}, false},
+		{"dev", []string{"env", "--split-string=sh -c id"}, false},
+		{"dev", []string{"env", "-S", "-i PATH=/bin sh"}, false},
+		{"dev", []string{"env", "-Sls -l"}, true},
+	} {
+		result, body := s.exec(execArgs{Name: "web", Namespace: tc.namespace, Command: tc.command})
+		s.Equal(!tc.allowed, result.IsError, "%v in %s", tc.command, tc.namespace)
+		if !tc.allowed {
+			s.Equal("AccessDenied", body["error"].(map[string]any)["type"])
+		}
+	}
+
+	// Wrappers must be allowed, and so must the commands they run
+	cfg.Rules = []config.ExecRule{{Allow: []string{"env", "ls"}}}
+	s.Require().NoError(s.toolset.SetExecConfig(&cfg))
+	for _, tc := range []struct {
+		command []string
+		allowed bool
+	}{
+		{[]string{"env"}, true},
+		{[]string{"env", "ls", "/"}, true},
+		{[]string{"env", "sh"}, false},
+		{[]string{"env", "-u", "HOME", "ls"}, true},
+		{[]string{"timeout", "5", "ls"}, false},
+	} {
+		result, _ := s.exec(execArgs{Name: "web", Namespace: "prod-eu", Command: tc.command})
+		s.Equal(!tc.allowed, result.IsError, "%v", tc.command)
+	}
+
+	cfg.Rules = []config.ExecRule{{Allow: []string{"[ls"}}}
+	s.Error(s.toolset.SetExecConfig(&cfg))
+}
+
+// TestExecTestSuite runs the exec test suite.
+func TestExecTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecTestSuite))
+}
@@ -1,13 +1,17 @@
 package core
 
 import (
+	"context"
+
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/api/meta"
 )
 
-// getClusterClient gets a cluster client for the given context.
-// If context is empty, uses the provider's default context.
-func (t *Toolset) getClusterClient(context string) (*kubernetes.ClientSet, error) {
-	return t.provider.GetClientSet(context)
+// getClusterClient gets a cluster client for the given context, scoped to the
+// request's caller when per-caller credentials are enabled.
+// If name is empty, uses the provider's default context.
+func (t *Toolset) getClusterClient(ctx context.Context, name string) (*kubernetes.ClientSet, error) {
+	return kubernetes.ClientSetForRequest(ctx, t.provider, name)
 }
 
 // getContextOrDefault returns the context or the default if empty.
@@ -21,3 +25,12 @@ func (t *Toolset) getContextOrDefault(context string) string {
 	}
 	return context
 }
+
+// resourceNamespace returns the namespace RBAC is checked in for a call on
+// the resource of mapping in namespace: none for cluster-scoped resources.
+func resourceNamespace(mapping *meta.RESTMapping, namespace string) string {
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return ""
+	}
+	return namespace
+}
@@ -0,0 +1,447 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// defaultLogsConfig applies when SetLogsConfig was not called.
+var defaultLogsConfig = config.LogsConfig{
+	Concurrency: 5,
+	MaxBytes:    1 << 20,
+}
+
+// SetLogsConfig sets how many containers pods_logs_aggregate reads at once
+// and how many bytes of logs it returns.
+func (t *Toolset) SetLogsConfig(cfg *config.LogsConfig) {
+	t.logs = *cfg
+}
+
+// rolloutGVR is the resource of Argo Rollouts, which own ReplicaSets like
+// Deployments do.
+var rolloutGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+// logSource is a container whose logs are aggregated.
+type logSource struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Previous  bool   `json:"previous,omitempty"`
+}
+
+// prefix returns the prefix of the source's lines in the merged logs.
+func (s logSource) prefix() string {
+	if s.Previous {
+		return fmt.Sprintf("[%s/%s previous]", s.Pod, s.Container)
+	}
+	return fmt.Sprintf("[%s/%s]", s.Pod, s.Container)
+}
+
+// logFailure is a source whose logs could not be read.
+type logFailure struct {
+	logSource
+	Error string `json:"error"`
+}
+
+// logLine is a prefixed log line and the time it was written.
+type logLine struct {
+	time time.Time
+	text string
+}
+
+// logFilter selects log lines by regular expressions.
+type logFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// newLogFilter returns a filter passing lines that match include, if set,
+// and do not match exclude, if set.
+func newLogFilter(include, exclude string) (logFilter, error) {
+	var filter logFilter
+	var err error
+	if include != "" {
+		if filter.include, err = regexp.Compile(include); err != nil {
+			return filter, fmt.Errorf("invalid include pattern: %w", err)
+		}
+	}
+	if exclude != "" {
+		if filter.exclude, err = regexp.Compile(exclude); err != nil {
+			return filter, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func (f logFilter) match(message string) bool {
+	if f.include != nil && !f.include.MatchString(message) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.MatchString(message)
+}
+
+// readLogLines reads the timestamped lines of a log that pass filter, keeping
+// the newest maxBytes of them, and reports whether older lines were dropped.
+// Lines without a timestamp take the time of the line before.
+func readLogLines(r io.Reader, source logSource, filter logFilter, maxBytes int) ([]logLine, bool, error) {
+	prefix := source.prefix()
+	var lines []logLine
+	var last time.Time
+	size, first, dropped := 0, 0, false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		timestamp, message, _ := strings.Cut(line, " ")
+		written, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			timestamp, message, written = "", line, last
+		}
+		last = written
+		if !filter.match(message) {
+			continue
+		}
+
+		text := prefix + " " + message
+		if timestamp != "" {
+			text = timestamp + " " + text
+		}
+		lines = append(lines, logLine{time: written, text: text})
+		size += len(text) + 1
+		for size > maxBytes {
+			size -= len(lines[first].text) + 1
+			first++
+			dropped = true
+		}
+		// Move the kept lines to the front once most of the slice is
+		// dropped, so a long log does not keep its dropped lines reachable
+		if first > len(lines)/2 {
+			n := copy(lines, lines[first:])
+			clear(lines[n:])
+			lines, first = lines[:n], 0
+		}
+	}
+	return lines[first:], dropped, scanner.Err()
+}
+
+// mergeLogLines merges the lines of all sources in the order they were
+// written, keeping the newest maxBytes of them. It returns the merged logs,
+// the number of lines kept and whether lines were dropped.
+func mergeLogLines(sources [][]logLine, maxBytes int) (string, int, bool) {
+	var merged []logLine
+	for _, lines := range sources {
+		merged = append(merged, lines...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].time.Before(merged[j].time) })
+
+	first, size := len(merged), 0
+	for first > 0 && size+len(merged[first-1].text)+1 <= maxBytes {
+		first--
+		size += len(merged[first].text) + 1
+	}
+
+	var logs strings.Builder
+	logs.Grow(size)
+	for _, line := range merged[first:] {
+		logs.WriteString(line.text)
+		logs.WriteByte('\n')
+	}
+	return logs.String(), len(merged) - first, first > 0
+}
+
+// logPods returns the pods of the workload of kind named name, following
+// owner references through ReplicaSets for Deployments and Rollouts, or the
+// pods matching labelSelector. It also returns the label selector used.
+func logPods(ctx context.Context, clientSet *kubernetes.ClientSet, namespace, kind, name, labelSelector string) ([]corev1.Pod, string, error) {
+	client := clientSet.Typed
+	if kind == "" {
+		if labelSelector == "" {
+			return nil, "", fmt.Errorf("label_selector, or kind and name, are required")
+		}
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid label selector: %w", err)
+		}
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list pods: %w", err)
+		}
+		return pods.Items, selector.String(), nil
+	}
+	if labelSelector != "" {
+		return nil, "", fmt.Errorf("set either label_selector or kind and name, not both")
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("name is required with kind")
+	}
+
+	var labelSel *metav1.LabelSelector
+	var uid types.UID
+	viaReplicaSets := false
+	switch strings.ToLower(kind) {
+	case "deployment":
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get deployment: %w", err)
+		}
+		labelSel, uid, viaReplicaSets = deployment.Spec.Selector, deployment.UID, true
+	case "statefulset":
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		labelSel, uid = statefulSet.Spec.Selector, statefulSet.UID
+	case "daemonset":
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		labelSel, uid = daemonSet.Spec.Selector, daemonSet.UID
+	case "job":
+		job, err := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get job: %w", err)
+		}
+		labelSel, uid = job.Spec.Selector, job.UID
+	case "rollout":
+		rollout, err := clientSet.Dynamic.Resource(rolloutGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get rollout: %w", err)
+		}
+		if spec, ok := rollout.Object["spec"].(map[string]any); ok {
+			if selector, ok := spec["selector"].(map[string]any); ok {
+				labelSel = &metav1.LabelSelector{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, labelSel); err != nil {
+					return nil, "", fmt.Errorf("invalid selector of rollout %s: %w", name, err)
+				}
+			}
+		}
+		uid, viaReplicaSets = rollout.GetUID(), true
+	default:
+		return nil, "", fmt.Errorf("unsupported kind %q: must be Deployment, StatefulSet, DaemonSet, Job or Rollout", kind)
+	}
+	if labelSel == nil {
+		return nil, "", fmt.Errorf("%s %s has no selector", kind, name)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSel)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid selector of %s %s: %w", kind, name, err)
+	}
+	listOptions := metav1.ListOptions{LabelSelector: selector.String()}
+
+	// Selectors of different workloads may overlap, so only pods the
+	// workload controls count
+	owners := map[types.UID]bool{uid: true}
+	if viaReplicaSets {
+		replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list replicasets: %w", err)
+		}
+		for i := range replicaSets.Items {
+			if owner := metav1.GetControllerOf(&replicaSets.Items[i]); owner != nil && owner.UID == uid {
+				owners[replicaSets.Items[i].UID] = true
+			}
+		}
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	var owned []corev1.Pod
+	for _, pod := range pods.Items {
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owners[owner.UID] {
+			owned = append(owned, pod)
+		}
+	}
+	return owned, selector.String(), nil
+}
+
+// logSources returns the containers of pods to read logs of: container, or
+// all containers, and with previous also the previous instance of restarted
+// containers. Pods without container are returned as failures.
+func logSources(pods []corev1.Pod, container string, previous bool) ([]logSource, []logFailure) {
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	var sources []logSource
+	var failures []logFailure
+	for _, pod := range pods {
+		restarted := map[string]bool{}
+		for _, status := range pod.Status.ContainerStatuses {
+			restarted[status.Name] = status.RestartCount > 0
+		}
+		found := false
+		for _, c := range pod.Spec.Containers {
+			if container != "" && c.Name != container {
+				continue
+			}
+			found = true
+			if previous && restarted[c.Name] {
+				sources = append(sources, logSource{Pod: pod.Name, Container: c.Name, Previous: true})
+			}
+			sources = append(sources, logSource{Pod: pod.Name, Container: c.Name})
+		}
+		if !found {
+			failures = append(failures, logFailure{
+				logSource: logSource{Pod: pod.Name, Container: container},
+				Error:     fmt.Sprintf("pod has no container %s", container),
+			})
+		}
+	}
+	return sources, failures
+}
+
+// logResult is what reading the logs of a source returned.
+type logResult struct {
+	lines     []logLine
+	truncated bool
+	err       error
+}
+
+// readLogs reads the logs of sources, at most concurrency at a time.
+func readLogs(ctx context.Context, pods corev1client.PodInterface, sources []logSource, opts *corev1.PodLogOptions, filter logFilter, maxBytes, concurrency int) []logResult {
+	results := make([]logResult, len(sources))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(sources)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = readLog(ctx, pods, sources[i], opts, filter, maxBytes)
+			}
+		}()
+	}
+	for i := range sources {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// readLog reads the timestamped logs of source.
+func readLog(ctx context.Context, pods corev1client.PodInterface, source logSource, opts *corev1.PodLogOptions, filter logFilter, maxBytes int) logResult {
+	sourceOpts := *opts
+	sourceOpts.Container = source.Container
+	sourceOpts.Previous = source.Previous
+	sourceOpts.Timestamps = true
+	logs, err := pods.GetLogs(source.Pod, &sourceOpts).Stream(ctx)
+	if err != nil {
+		return logResult{err: fmt.Errorf("failed to get logs: %w", err)}
+	}
+	defer logs.Close()
+
+	lines, truncated, err := readLogLines(logs, source, filter, maxBytes)
+	if err != nil {
+		return logResult{err: fmt.Errorf("failed to read logs: %w", err)}
+	}
+	return logResult{lines: lines, truncated: truncated}
+}
+
+// handlePodsLogsAggregate handles the pods_logs_aggregate tool.
+func (t *Toolset) handlePodsLogsAggregate(ctx context.Context, args struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"label_selector"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Container     string `json:"container"`
+	TailLines     *int   `json:"tail_lines"`
+	Since         string `json:"since"`
+	SinceTime     string `json:"since_time"`
+	Previous      bool   `json:"previous"`
+	Include       string `json:"include"`
+	Exclude       string `json:"exclude"`
+	MaxBytes      int    `json:"max_bytes"`
+	Context       string `json:"context"`
+}) (*mcp.CallToolResult, error) {
+	maxBytes := t.logs.MaxBytes
+	switch {
+	case args.MaxBytes < 0:
+		return mcpHelpers.NewErrorResult(fmt.Errorf("max_bytes must not be negative")), nil
+	case args.MaxBytes > t.logs.MaxBytes:
+		return mcpHelpers.NewErrorResult(fmt.Errorf("max_bytes of %d exceeds the limit of %d", args.MaxBytes, t.logs.MaxBytes)), nil
+	case args.MaxBytes > 0:
+		maxBytes = args.MaxBytes
+	}
+	filter, err := newLogFilter(args.Include, args.Exclude)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	opts, err := podLogOptions(args.TailLines, args.Since, args.SinceTime)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
+	}
+
+	// Check RBAC for finding the pods and reading their logs
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", podsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+	if result := t.CheckRBAC(ctx, args.Context, "get", podLogsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
+	pods, selector, err := logPods(ctx, clientSet, args.Namespace, args.Kind, args.Name, args.LabelSelector)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+
+	sources, failures := logSources(pods, args.Container, args.Previous)
+	results := readLogs(ctx, clientSet.Typed.CoreV1().Pods(args.Namespace), sources, opts, filter, maxBytes, t.logs.Concurrency)
+	if ctx.Err() != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("log aggregation cancelled: %w", ctx.Err())), nil
+	}
+
+	var lines [][]logLine
+	truncated := false
+	for i, result := range results {
+		if result.err != nil {
+			failures = append(failures, logFailure{logSource: sources[i], Error: result.err.Error()})
+			continue
+		}
+		lines = append(lines, result.lines)
+		truncated = truncated || result.truncated
+	}
+	logs, count, dropped := mergeLogLines(lines, maxBytes)
+	if failures == nil {
+		failures = []logFailure{}
+	}
+
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"namespace":  args.Namespace,
+		"selector":   selector,
+		"pods":       len(pods),
+		"containers": len(sources),
+		"lines":      count,
+		"bytes":      len(logs),
+		"truncated":  truncated || dropped,
+		"failed":     failures,
+		"logs":       logs,
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// logsAggregateArgs are the arguments of pods_logs_aggregate.
+type logsAggregateArgs = struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"label_selector"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Container     string `json:"container"`
+	TailLines     *int   `json:"tail_lines"`
+	Since         string `json:"since"`
+	SinceTime     string `json:"since_time"`
+	Previous      bool   `json:"previous"`
+	Include       string `json:"include"`
+	Exclude       string `json:"exclude"`
+	MaxBytes      int    `json:"max_bytes"`
+	Context       string `json:"context"`
+}
+
+// LogsTestSuite tests log aggregation across pods.
+type LogsTestSuite struct {
+	suite.Suite
+	clientSet *kubernetes.ClientSet
+	toolset   *Toolset
+}
+
+func (s *LogsTestSuite) SetupTest() {
+	labels := map[string]string{"app": "web"}
+	controlledBy := func(kind, name string) []metav1.OwnerReference {
+		controller := true
+		return []metav1.OwnerReference{{Kind: kind, Name: name, UID: "uid-" + k8stypes.UID(name), Controller: &controller}}
+	}
+	pod := func(name, owner string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels, OwnerReferences: controlledBy("ReplicaSet", owner)},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "proxy"}}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}}},
+		}
+	}
+	replicaSet := func(name, owner, kind string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "shop", UID: "uid-" + k8stypes.UID(name), Labels: labels, OwnerReferences: controlledBy(kind, owner),
+		}}
+	}
+	client := fake.NewClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "uid-web"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		replicaSet("web-1", "web", "Deployment"),
+		replicaSet("canary-1", "canary", "Rollout"),
+		pod("web-1-b", "web-1", 2),
+		pod("web-1-a", "web-1", 0),
+		pod("canary-1-a", "canary-1", 0),
+	)
+	rollout := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]any{"name": "canary", "namespace": "shop", "uid": "uid-canary"},
+		"spec":       map[string]any{"selector": map[string]any{"matchLabels": map[string]any{"app": "web"}}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rolloutGVR: "RolloutList"}, rollout)
+	s.clientSet = &kubernetes.ClientSet{Typed: client, Dynamic: dynamicClient}
+	s.toolset = NewToolset(&execProvider{clientSet: s.clientSet})
+}
+
+// TestPods tests that workloads resolve to the pods they control, through
+// ReplicaSets for Deployments and Rollouts, and selectors to all matching pods.
+func (s *LogsTestSuite) TestPods() {
+	names := func(pods []corev1.Pod) []string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+	ctx := context.Background()
+
+	pods, selector, err := logPods(ctx, s.clientSet, "shop", "Deployment", "web", "")
+	s.Require().NoError(err)
+	s.Equal("app=web", selector)
+	s.ElementsMatch([]string{"web-1-a", "web-1-b"}, names(pods))
+
+	pods, _, err = logPods(ctx, s.clientSet, "shop", "rollout", "canary", "")
+	s.Require().NoError(err)
+	s.Equal([]string{"canary-1-a"}, names(pods))
+
+	pods, _, err = logPods(ctx, s.clientSet, "shop", "", "", "app=web")
+	s.Require().NoError(err)
+	s.Len(pods, 3)
+
+	_, _, err = logPods(ctx, s.clientSet, "shop", "", "", "")
+	s.ErrorContains(err, "required")
+	_, _, err = logPods(ctx, s.clientSet, "shop", "Deployment", "web", "app=web")
+	s.ErrorContains(err, "not both")
+	_, _, err = logPods(ctx, s.clientSet, "shop", "CronJob", "web", "")
+	s.ErrorContains(err, "unsupported kind")
+}
+
+// TestMerge tests that lines of all containers are merged in time order,
+// filtered, and cut to the newest lines within the byte budget.
+func (s *LogsTestSuite) TestMerge() {
+	filter, err := newLogFilter("GET|POST", "healthz")
+	s.Require().NoError(err)
+	a, truncated, err := readLogLines(strings.NewReader(
+		"2025-01-01T10:00:01Z GET /\n2025-01-01T10:00:03Z GET /healthz\n2025-01-01T10:00:05Z POST /cart\n",
+	), logSource{Pod: "web-a", Container: "app"}, filter, 1000)
+	s.Require().NoError(err)
+	s.False(truncated)
+	b, _, err := readLogLines(strings.NewReader(
+		"2025-01-01T10:00:02.5Z GET /shop\n2025-01-01T10:00:04Z starting\n",
+	), logSource{Pod: "web-b", Container: "app", Previous: true}, filter, 1000)
+	s.Require().NoError(err)
+
+	logs, count, dropped := mergeLogLines([][]logLine{a, b}, 1000)
+	s.Equal(3, count)
+	s.False(dropped)
+	s.Equal("2025-01-01T10:00:01Z [web-a/app] GET /\n"+
+		"2025-01-01T10:00:02.5Z [web-b/app previous] GET /shop\n"+
+		"2025-01-01T10:00:05Z [web-a/app] POST /cart\n", logs)
+
+	logs, count, dropped = mergeLogLines([][]logLine{a, b}, 100)
+	s.Equal(2, count)
+	s.True(dropped)
+	s.True(strings.HasPrefix(logs, "2025-01-01T10:00:02.5Z"))
+
+	_, truncated, err = readLogLines(strings.NewReader(
+		"2025-01-01T10:00:01Z GET /\n2025-01-01T10:00:05Z POST /cart\n",
+	), logSource{Pod: "web-a", Container: "app"}, logFilter{}, 50)
+	s.Require().NoError(err)
+	s.True(truncated)
+
+	// A long log keeps only its newest lines, in a slice bounded by them
+	var long strings.Builder
+	for i := range 1000 {
+		fmt.Fprintf(&long, "2025-01-01T10:00:00Z line %d\n", i)
+	}
+	kept, truncated, err := readLogLines(strings.NewReader(long.String()), logSource{Pod: "web-a", Container: "app"}, logFilter{}, 200)
+	s.Require().NoError(err)
+	s.True(truncated)
+	s.Require().NotEmpty(kept)
+	s.True(strings.HasSuffix(kept[len(kept)-1].text, "line 999"))
+	s.LessOrEqual(cap(kept), 4*len(kept))
+
+	_, err = newLogFilter("(", "")
+	s.ErrorContains(err, "invalid include pattern")
+}
+
+// TestAggregate tests the tool's sources, failures and limits.
+func (s *LogsTestSuite) TestAggregate() {
+	result, err := s.toolset.handlePodsLogsAggregate(context.Background(), logsAggregateArgs{
+		Namespace: "shop", Kind: "Deployment", Name: "web", Previous: true,
+	})
+	s.Require().NoError(err)
+	s.Require().False(result.IsError, text(result))
+	body := decode(result)
+	s.Equal(2.0, body["pods"])
+	// Both containers of both pods, and the previous app container of web-1-b
+	s.Equal(5.0, body["containers"])
+	s.Equal(5.0, body["lines"])
+	s.Empty(body["failed"])
+	s.Contains(body["logs"], "[web-1-b/app previous] fake logs\n")
+
+	result, err = s.toolset.handlePodsLogsAggregate(context.Background(), logsAggregateArgs{
+		Namespace: "shop", LabelSelector: "app=web", Container: "proxy", Include: "nothing",
+	})
+	s.Require().NoError(err)
+	body = decode(result)
+	s.Equal(3.0, body["containers"])
+	s.Equal(0.0, body["lines"])
+	s.Equal("", body["logs"])
+
+	pod, err := s.clientSet.Typed.CoreV1().Pods("shop").Get(context.Background(), "web-1-a", metav1.GetOptions{})
+	s.Require().NoError(err)
+	_, failures := logSources([]corev1.Pod{*pod}, "sidecar", false)
+	s.Require().Len(failures, 1)
+	s.Equal("pod has no container sidecar", failures[0].Error)
+
+	result, err = s.toolset.handlePodsLogsAggregate(context.Background(), logsAggregateArgs{
+		Namespace: "shop", LabelSelector: "app=web", MaxBytes: 10 << 20,
+	})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Contains(text(result), "exceeds the limit")
+}
+
+func TestLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(LogsTestSuite))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // metricsAvailable checks if metrics API is available.
@@ -20,7 +21,7 @@ func (t *Toolset) handlePodsTop(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -37,6 +38,12 @@ func (t *Toolset) handlePodsTop(ctx context.Context, args struct {
 		})
 	}
 
+	// Check RBAC
+	podMetricsGVR := schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", podMetricsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	// Get pod metrics
 	metrics, err := clientSet.Metrics.MetricsV1beta1().PodMetricses(args.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -92,7 +99,7 @@ func (t *Toolset) handlePodsTop(ctx context.Context, args struct {
 func (t *Toolset) handleNodesTop(ctx context.Context, args struct {
 	Context string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -109,6 +116,15 @@ func (t *Toolset) handleNodesTop(ctx context.Context, args struct {
 		})
 	}
 
+	// Check RBAC
+	nodeMetricsGVR := schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", nodeMetricsGVR, ""); result != nil {
+		return result, nil
+	}
+	if result := t.CheckRBAC(ctx, args.Context, "list", nodesGVR, ""); result != nil {
+		return result, nil
+	}
+
 	// Get node metrics
 	metrics, err := clientSet.Metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
 	if err != nil {
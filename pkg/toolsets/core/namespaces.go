@@ -5,19 +5,27 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // handleNamespacesList handles the namespaces_list tool.
 func (t *Toolset) handleNamespacesList(ctx context.Context, args struct {
 	Context string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	namespacesGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", namespacesGVR, ""); result != nil {
+		return result, nil
+	}
+
 	namespaces, err := clientSet.Typed.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to list namespaces: %w", err)), nil
@@ -5,24 +5,34 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // handleNodesTop is now implemented in metrics.go
 // This function is kept for backward compatibility but delegates to the metrics implementation
 
+// nodesGVR is the resource of nodes.
+var nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
 // handleNodesSummary handles the nodes_summary tool.
 func (t *Toolset) handleNodesSummary(ctx context.Context, args struct {
 	Name    string `json:"name"`
 	Context string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "list", nodesGVR, ""); result != nil {
+		return result, nil
+	}
+
 	nodes, err := clientSet.Typed.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to list nodes: %w", err)), nil
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -25,7 +26,7 @@ func (t *Toolset) handleResourcesPatch(ctx context.Context, args struct {
 	DryRun       bool        `json:"dry_run"`
 	Context      string      `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -45,11 +46,8 @@ func (t *Toolset) handleResourcesPatch(ctx context.Context, args struct {
 	gvr := mapping.Resource
 
 	// Check RBAC before patch (even in dry-run mode to validate permissions)
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "patch", gvr, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	if result := t.CheckRBAC(ctx, args.Context, "patch", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Determine patch type
@@ -1,14 +1,18 @@
 package core
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -21,11 +25,17 @@ func (t *Toolset) handlePodsList(ctx context.Context, args struct {
 	Continue      string `json:"continue"`
 	Context       string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	if result := t.CheckRBAC(ctx, args.Context, "list", podsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	// Build list options with selectors and pagination
 	listOptions := metav1.ListOptions{}
 	if args.LabelSelector != "" {
@@ -48,6 +58,14 @@ func (t *Toolset) handlePodsList(ctx context.Context, args struct {
 
 	podList := make([]map[string]any, 0, len(pods.Items))
 	for _, pod := range pods.Items {
+		if mcpHelpers.WantsObjects(ctx) {
+			obj, err := podObject(&pod)
+			if err != nil {
+				return mcpHelpers.NewErrorResult(err), nil
+			}
+			podList = append(podList, obj)
+			continue
+		}
 		podList = append(podList, map[string]any{
 			"name":       pod.Name,
 			"namespace":  pod.Namespace,
@@ -72,15 +90,28 @@ func (t *Toolset) handlePodsGet(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	// Check RBAC
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	if result := t.CheckRBAC(ctx, args.Context, "get", podsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	pod, err := clientSet.Typed.CoreV1().Pods(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get pod: %w", err)), nil
 	}
+	if mcpHelpers.WantsObjects(ctx) {
+		obj, err := podObject(pod)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil
+		}
+		return mcpHelpers.NewJSONResult(obj)
+	}
 
 	podData := map[string]any{
 		"name":       pod.Name,
@@ -102,13 +133,25 @@ func (t *Toolset) handlePodsGet(ctx context.Context, args struct {
 	return mcpHelpers.NewJSONResult(podData)
 }
 
+// podObject returns a pod as the object the API serves, for the output layer
+// to project fields of or tabulate.
+func podObject(pod *corev1.Pod) (map[string]any, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pod: %w", err)
+	}
+	obj["apiVersion"] = "v1"
+	obj["kind"] = "Pod"
+	return obj, nil
+}
+
 // handlePodsDelete handles the pods_delete tool.
 func (t *Toolset) handlePodsDelete(ctx context.Context, args struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -119,11 +162,8 @@ func (t *Toolset) handlePodsDelete(ctx context.Context, args struct {
 		Version:  "v1",
 		Resource: "pods",
 	}
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "delete", gvr, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	if result := t.CheckRBAC(ctx, args.Context, "delete", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	err = clientSet.Typed.CoreV1().Pods(args.Namespace).Delete(ctx, args.Name, metav1.DeleteOptions{})
@@ -134,51 +174,50 @@ func (t *Toolset) handlePodsDelete(ctx context.Context, args struct {
 	return mcpHelpers.NewTextResult(fmt.Sprintf("Pod %s/%s deleted successfully", args.Namespace, args.Name)), nil
 }
 
+// podLogsGVR is the subresource pod logs are read through.
+var podLogsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods/log"}
+
+// maxLogLineBytes is the longest log line pods_logs follows.
+const maxLogLineBytes = 1 << 20
+
 // handlePodsLogs handles the pods_logs tool.
 func (t *Toolset) handlePodsLogs(ctx context.Context, args struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Container string `json:"container"`
-	TailLines *int   `json:"tail_lines"`
-	Since     string `json:"since"`
-	SinceTime string `json:"since_time"`
-	Previous  bool   `json:"previous"`
-	Follow    bool   `json:"follow"`
-	Context   string `json:"context"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Container   string `json:"container"`
+	TailLines   *int   `json:"tail_lines"`
+	Since       string `json:"since"`
+	SinceTime   string `json:"since_time"`
+	Previous    bool   `json:"previous"`
+	Follow      bool   `json:"follow"`
+	MaxDuration int    `json:"max_duration"`
+	MaxLines    int    `json:"max_lines"`
+	Context     string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
-	opts := &corev1.PodLogOptions{}
-	if args.Container != "" {
-		opts.Container = args.Container
-	}
-	if args.TailLines != nil {
-		tailLines := int64(*args.TailLines)
-		opts.TailLines = &tailLines
-	}
-	if args.Since != "" {
-		duration, err := time.ParseDuration(args.Since)
-		if err != nil {
-			return mcpHelpers.NewErrorResult(fmt.Errorf("invalid since duration: %w", err)), nil
-		}
-		sinceTime := metav1.NewTime(time.Now().Add(-duration))
-		opts.SinceTime = &sinceTime
-	}
-	if args.SinceTime != "" {
-		sinceTime, err := time.Parse(time.RFC3339, args.SinceTime)
-		if err != nil {
-			return mcpHelpers.NewErrorResult(fmt.Errorf("invalid since_time format (expected RFC3339): %w", err)), nil
-		}
-		opts.SinceTime = &metav1.Time{Time: sinceTime}
+	// Logs are read through the pods/log subresource
+	if result := t.CheckRBAC(ctx, args.Context, "get", podLogsGVR, args.Namespace); result != nil {
+		return result, nil
 	}
-	if args.Previous {
-		opts.Previous = true
+
+	opts, err := podLogOptions(args.TailLines, args.Since, args.SinceTime)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
 	}
+	opts.Container = args.Container
+	opts.Previous = args.Previous
+
 	if args.Follow {
 		opts.Follow = true
+		limits, err := t.streamLimits(args.MaxDuration, args.MaxLines)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil
+		}
+		return t.followPodLogs(ctx, clientSet, args.Namespace, args.Name, opts, limits)
 	}
 
 	req_logs := clientSet.Typed.CoreV1().Pods(args.Namespace).GetLogs(args.Name, opts)
@@ -188,51 +227,6 @@ func (t *Toolset) handlePodsLogs(ctx context.Context, args struct {
 	}
 	defer logs.Close()
 
-	// If follow is enabled, we'll read available logs and note that streaming is available via HTTP
-	// Note: Full streaming requires HTTP transport (Streamable HTTP) integration
-	if args.Follow {
-		// Read initial logs (non-blocking read with timeout context)
-		logBytes := make([]byte, 0)
-		buf := make([]byte, 4096)
-
-		// Use a timeout context to limit initial read
-		readCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-		defer cancel()
-
-		done := make(chan bool)
-		go func() {
-			for {
-				select {
-				case <-readCtx.Done():
-					done <- true
-					return
-				default:
-					n, err := logs.Read(buf)
-					if n > 0 {
-						logBytes = append(logBytes, buf[:n]...)
-					}
-					if err != nil {
-						done <- true
-						return
-					}
-				}
-			}
-		}()
-		<-done
-
-		// Return initial logs with note about streaming
-		result, err := mcpHelpers.NewJSONResult(map[string]any{
-			"logs":           string(logBytes),
-			"follow":         true,
-			"streaming_note": "Log following is enabled. For real-time streaming, use HTTP transport endpoint.",
-		})
-		if err != nil {
-			return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
-		}
-		return result, nil
-	}
-
-	// Non-follow mode: read all logs
 	logBytes := make([]byte, 0)
 	buf := make([]byte, 4096)
 	for {
@@ -248,55 +242,77 @@ func (t *Toolset) handlePodsLogs(ctx context.Context, args struct {
 	return mcpHelpers.NewTextResult(string(logBytes)), nil
 }
 
-// handlePodsExec handles the pods_exec tool.
-func (t *Toolset) handlePodsExec(ctx context.Context, args struct {
-	Name      string   `json:"name"`
-	Namespace string   `json:"namespace"`
-	Container string   `json:"container"`
-	Command   []string `json:"command"`
-	Context   string   `json:"context"`
-}) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
+// podLogOptions returns the log options for the tail_lines, since and
+// since_time arguments of pods_logs and pods_logs_aggregate.
+func podLogOptions(tailLines *int, since, sinceTime string) (*corev1.PodLogOptions, error) {
+	opts := &corev1.PodLogOptions{}
+	if tailLines != nil {
+		lines := int64(*tailLines)
+		opts.TailLines = &lines
 	}
-
-	req_exec := clientSet.Typed.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Namespace(args.Namespace).
-		Name(args.Name).
-		SubResource("exec")
-
-	opts := &corev1.PodExecOptions{
-		Command: args.Command,
-		Stdout:  true,
-		Stderr:  true,
+	if since != "" {
+		duration, err := time.ParseDuration(since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since duration: %w", err)
+		}
+		start := metav1.NewTime(time.Now().Add(-duration))
+		opts.SinceTime = &start
 	}
-	if args.Container != "" {
-		opts.Container = args.Container
+	if sinceTime != "" {
+		start, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since_time format (expected RFC3339): %w", err)
+		}
+		opts.SinceTime = &metav1.Time{Time: start}
 	}
+	return opts, nil
+}
 
-	req_exec = req_exec.VersionedParams(opts, metav1.ParameterCodec)
+// followPodLogs streams log lines of a pod as they are written, within
+// limits.
+func (t *Toolset) followPodLogs(ctx context.Context, clientSet *kubernetes.ClientSet, namespace, name string, opts *corev1.PodLogOptions, limits mcpHelpers.StreamLimits) (*mcp.CallToolResult, error) {
+	summary, err := mcpHelpers.Stream(ctx, limits, func(ctx context.Context, emit func(item any) bool) error {
+		logs, err := clientSet.Typed.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+		defer logs.Close()
 
-	exec, err := req_exec.Stream(ctx)
+		scanner := bufio.NewScanner(logs)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+		for scanner.Scan() {
+			if !emit(scanner.Text()) {
+				return nil
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to exec: %w", err)), nil
+		return mcpHelpers.NewErrorResult(err), nil
 	}
-	defer exec.Close()
 
-	output := make([]byte, 0)
-	buf := make([]byte, 4096)
-	for {
-		n, err := exec.Read(buf)
-		if n > 0 {
-			output = append(output, buf[:n]...)
-		}
-		if err != nil {
-			break
+	body := map[string]any{
+		"follow":   true,
+		"lines":    summary.Items,
+		"streamed": summary.Streamed,
+		"stopped":  summary.Reason,
+	}
+	if !summary.Streamed {
+		var logs strings.Builder
+		for _, line := range summary.Collected {
+			logs.WriteString(line.(string))
+			logs.WriteByte('\n')
 		}
+		body["logs"] = logs.String()
 	}
-
-	return mcpHelpers.NewTextResult(string(output)), nil
+	result, err := mcpHelpers.NewJSONResult(body)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
 }
 
 // handlePodsTop is now implemented in metrics.go
@@ -0,0 +1,674 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sclient "k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// defaultPortForwardConfig applies when SetPortForwardConfig was not called.
+var defaultPortForwardConfig = config.PortForwardConfig{
+	Addresses:   []string{"localhost"},
+	IdleTimeout: config.Duration(30 * time.Minute),
+	MaxSessions: 10,
+}
+
+// podWatchRetry is how long to wait before watching a forwarded pod again
+// after the API server failed.
+const podWatchRetry = 5 * time.Second
+
+// SetPortForwardConfig sets the listen addresses, idle timeout and session
+// limit of port-forward sessions. With statelessHTTP, every HTTP request is
+// its own MCP session, so sessions opened over HTTP are not closed when the
+// MCP session ends.
+func (t *Toolset) SetPortForwardConfig(cfg *config.PortForwardConfig, statelessHTTP bool) {
+	t.portForward = *cfg
+	t.statelessHTTP = statelessHTTP
+}
+
+// forwardStats counts the traffic of a port-forward session.
+type forwardStats struct {
+	sent         atomic.Int64
+	received     atomic.Int64
+	lastActivity atomic.Int64 // Unix nanoseconds
+}
+
+// touch records traffic now.
+func (s *forwardStats) touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idleFor returns the time since the last traffic.
+func (s *forwardStats) idleFor() time.Duration {
+	return time.Since(time.Unix(0, s.lastActivity.Load()))
+}
+
+// countingDialer counts the traffic on the data streams of its connections.
+type countingDialer struct {
+	httpstream.Dialer
+	stats *forwardStats
+}
+
+// Dial implements httpstream.Dialer.
+func (d *countingDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	conn, protocol, err := d.Dialer.Dial(protocols...)
+	if err != nil {
+		return nil, "", err
+	}
+	return &countingConnection{Connection: conn, stats: d.stats}, protocol, nil
+}
+
+// countingConnection wraps the data streams it creates in countingStreams.
+type countingConnection struct {
+	httpstream.Connection
+	stats *forwardStats
+}
+
+// CreateStream implements httpstream.Connection.
+func (c *countingConnection) CreateStream(headers http.Header) (httpstream.Stream, error) {
+	stream, err := c.Connection.CreateStream(headers)
+	if err != nil || headers.Get(corev1.StreamType) != corev1.StreamTypeData {
+		return stream, err
+	}
+	c.stats.touch()
+	return &countingStream{Stream: stream, stats: c.stats}, nil
+}
+
+// countingStream counts the bytes read from and written to the pod.
+type countingStream struct {
+	httpstream.Stream
+	stats *forwardStats
+}
+
+// Read implements io.Reader.
+func (s *countingStream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	if n > 0 {
+		s.stats.received.Add(int64(n))
+		s.stats.touch()
+	}
+	return n, err
+}
+
+// Write implements io.Writer.
+func (s *countingStream) Write(p []byte) (int, error) {
+	n, err := s.Stream.Write(p)
+	if n > 0 {
+		s.stats.sent.Add(int64(n))
+		s.stats.touch()
+	}
+	return n, err
+}
+
+// portForwarder forwards local ports to a pod until it is stopped or the
+// connection is lost.
+type portForwarder interface {
+	ForwardPorts() error
+	GetPorts() ([]portforward.ForwardedPort, error)
+}
+
+// forwarderFunc creates a forwarder for a URL of the portforward
+// subresource, counting its traffic in stats.
+type forwarderFunc func(config *rest.Config, u *url.URL, stats *forwardStats, addresses, ports []string, stop <-chan struct{}, ready chan struct{}) (portForwarder, error)
+
+// newForwarder creates a forwarder tunneling SPDY over WebSocket, and
+// speaking SPDY to API servers that do not support it, as kubectl does.
+func newForwarder(config *rest.Config, u *url.URL, stats *forwardStats, addresses, ports []string, stop <-chan struct{}, ready chan struct{}) (portForwarder, error) {
+	websocket, err := portforward.NewSPDYOverWebsocketDialer(u, config)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	dialer := portforward.NewFallbackDialer(websocket,
+		spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, u),
+		func(err error) bool {
+			return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+		})
+	return portforward.NewOnAddresses(&countingDialer{Dialer: dialer, stats: stats}, addresses, ports, stop, ready, io.Discard, io.Discard)
+}
+
+// portForward is an open port-forward session.
+type portForward struct {
+	id        string
+	owner     string
+	context   string
+	namespace string
+	pod       string
+	target    string
+	addresses []string
+	localPort int
+	podPort   int
+	startedAt time.Time
+	stats     *forwardStats
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// close stops forwarding.
+func (f *portForward) close() {
+	f.stopOnce.Do(func() { close(f.stop) })
+}
+
+// portForwardInfo describes a port-forward session.
+type portForwardInfo struct {
+	ID            string    `json:"id"`
+	Context       string    `json:"context,omitempty"`
+	Namespace     string    `json:"namespace"`
+	Pod           string    `json:"pod"`
+	Target        string    `json:"target"`
+	Addresses     []string  `json:"addresses"`
+	LocalPort     int       `json:"local_port"`
+	PodPort       int       `json:"pod_port"`
+	StartedAt     time.Time `json:"started_at"`
+	LastActivity  time.Time `json:"last_activity"`
+	BytesSent     int64     `json:"bytes_sent"`
+	BytesReceived int64     `json:"bytes_received"`
+}
+
+// info describes the session.
+func (f *portForward) info() portForwardInfo {
+	return portForwardInfo{
+		ID:            f.id,
+		Context:       f.context,
+		Namespace:     f.namespace,
+		Pod:           f.pod,
+		Target:        f.target,
+		Addresses:     f.addresses,
+		LocalPort:     f.localPort,
+		PodPort:       f.podPort,
+		StartedAt:     f.startedAt,
+		LastActivity:  time.Unix(0, f.stats.lastActivity.Load()).UTC(),
+		BytesSent:     f.stats.sent.Load(),
+		BytesReceived: f.stats.received.Load(),
+	}
+}
+
+// portForwards is the registry of open port-forward sessions.
+type portForwards struct {
+	mu       sync.Mutex
+	sessions map[string]*portForward
+}
+
+// newPortForwards creates an empty registry.
+func newPortForwards() *portForwards {
+	return &portForwards{sessions: make(map[string]*portForward)}
+}
+
+// errTooManyPortForwards reports that max sessions are open.
+func errTooManyPortForwards(max int) error {
+	return fmt.Errorf("%d port-forward sessions are open, the maximum; stop one with pods_port_forward_stop", max)
+}
+
+// full reports whether max sessions are open.
+func (r *portForwards) full(max int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sessions) >= max
+}
+
+// add registers a session unless max sessions are open.
+func (r *portForwards) add(f *portForward, max int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.sessions) >= max {
+		return errTooManyPortForwards(max)
+	}
+	r.sessions[f.id] = f
+	return nil
+}
+
+// remove unregisters a session.
+func (r *portForwards) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// get returns the session with id if owner opened it.
+func (r *portForwards) get(id, owner string) *portForward {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.sessions[id]; ok && f.owner == owner {
+		return f
+	}
+	return nil
+}
+
+// list returns the sessions owner opened, oldest first.
+func (r *portForwards) list(owner string) []*portForward {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []*portForward
+	for _, f := range r.sessions {
+		if f.owner == owner {
+			sessions = append(sessions, f)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].startedAt.Before(sessions[j].startedAt) })
+	return sessions
+}
+
+// forwardOwner returns who port-forward sessions opened in ctx belong to:
+// the authenticated caller, or for anonymous callers the MCP session, so
+// they cannot list or stop each other's sessions. session may be nil; STDIO
+// sessions have no ID, and a single client.
+func forwardOwner(ctx context.Context, session *mcp.ServerSession) string {
+	if identity := auth.IdentityFromContext(ctx); identity != nil {
+		return "user:" + identity.Username
+	}
+	if session != nil && session.ID() != "" {
+		return "session:" + session.ID()
+	}
+	return ""
+}
+
+// resolveForwardTarget returns the pod a port-forward to the named Pod,
+// Service or Deployment connects to, and the pod port that port maps to.
+// For a Service, port is a service port; otherwise it is a pod port.
+func resolveForwardTarget(ctx context.Context, client k8sclient.Interface, kind, namespace, name string, port int) (*corev1.Pod, int, error) {
+	switch strings.ToLower(kind) {
+	case "", "pod":
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get pod: %w", err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return nil, 0, fmt.Errorf("pod is not running (current phase: %s)", pod.Status.Phase)
+		}
+		return pod, port, nil
+	case "service":
+		service, err := client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get service: %w", err)
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, 0, fmt.Errorf("service %s has no selector, so no pods to forward to", name)
+		}
+		var servicePort *corev1.ServicePort
+		for i := range service.Spec.Ports {
+			if int(service.Spec.Ports[i].Port) == port {
+				servicePort = &service.Spec.Ports[i]
+			}
+		}
+		if servicePort == nil {
+			return nil, 0, fmt.Errorf("service %s has no port %d", name, port)
+		}
+		pod, err := readyPod(ctx, client, namespace, labels.SelectorFromSet(service.Spec.Selector))
+		if err != nil {
+			return nil, 0, err
+		}
+		podPort, err := targetPort(pod, servicePort)
+		return pod, podPort, err
+	case "deployment":
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid selector of deployment %s: %w", name, err)
+		}
+		pod, err := readyPod(ctx, client, namespace, selector)
+		return pod, port, err
+	default:
+		return nil, 0, fmt.Errorf("unsupported kind %q: must be Pod, Service or Deployment", kind)
+	}
+}
+
+// readyPod returns a ready pod matching selector, the first by name.
+func readyPod(ctx context.Context, client k8sclient.Interface, namespace string, selector labels.Selector) (*corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	var ready []*corev1.Pod
+	for i := range pods.Items {
+		if podReady(&pods.Items[i]) {
+			ready = append(ready, &pods.Items[i])
+		}
+	}
+	if len(ready) == 0 {
+		return nil, fmt.Errorf("no ready pod matches %s", selector)
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Name < ready[j].Name })
+	return ready[0], nil
+}
+
+// podReady reports whether pod is running, not terminating and ready.
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// targetPort returns the port of pod that servicePort sends traffic to.
+func targetPort(pod *corev1.Pod, servicePort *corev1.ServicePort) (int, error) {
+	switch {
+	case servicePort.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == servicePort.TargetPort.StrVal {
+					return int(port.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, servicePort.TargetPort.StrVal)
+	case servicePort.TargetPort.IntVal != 0:
+		return int(servicePort.TargetPort.IntVal), nil
+	default:
+		return int(servicePort.Port), nil
+	}
+}
+
+// handlePodsPortForward handles the pods_port_forward tool. The session is
+// closed when the MCP session that opened it ends; session may be nil.
+func (t *Toolset) handlePodsPortForward(ctx context.Context, session *mcp.ServerSession, args struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	LocalPort int    `json:"local_port"`
+	PodPort   int    `json:"pod_port"`
+	Container string `json:"container"`
+	Context   string `json:"context"`
+}) (*mcp.CallToolResult, error) {
+	// Validate ports
+	if args.LocalPort < 0 || args.LocalPort > 65535 {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid local_port: must be between 0 and 65535")), nil
+	}
+	if args.PodPort <= 0 || args.PodPort > 65535 {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid pod_port: must be between 1 and 65535")), nil
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
+	}
+
+	// Check RBAC
+	gvr := schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods/portforward",
+	}
+	if result := t.CheckRBAC(ctx, args.Context, "create", gvr, args.Namespace); result != nil {
+		return result, nil
+	}
+
+	pod, podPort, err := resolveForwardTarget(ctx, clientSet.Typed, args.Kind, args.Namespace, args.Name, args.PodPort)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+	kind := strings.ToLower(args.Kind)
+	if kind == "" {
+		kind = "pod"
+	}
+
+	forwardURL := clientSet.Typed.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+
+	f := &portForward{
+		id:        "pf-" + strings.ToLower(rand.Text()[:10]),
+		owner:     forwardOwner(ctx, session),
+		context:   args.Context,
+		namespace: pod.Namespace,
+		pod:       pod.Name,
+		target:    kind + "/" + args.Name,
+		addresses: t.portForward.Addresses,
+		podPort:   podPort,
+		startedAt: time.Now().UTC(),
+		stats:     &forwardStats{},
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	f.stats.touch()
+	if t.forwards.full(t.portForward.MaxSessions) {
+		return mcpHelpers.NewErrorResult(errTooManyPortForwards(t.portForward.MaxSessions)), nil
+	}
+
+	newFwd := t.newForwarder
+	if newFwd == nil {
+		newFwd = newForwarder
+	}
+	ready := make(chan struct{})
+	forwarder, err := newFwd(clientSet.Config, forwardURL, f.stats, f.addresses, []string{fmt.Sprintf("%d:%d", args.LocalPort, podPort)}, f.stop, ready)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create port forwarder: %w", err)), nil
+	}
+
+	forwarded := make(chan error, 1)
+	go func() { forwarded <- forwarder.ForwardPorts() }()
+	select {
+	case <-ready:
+	case err := <-forwarded:
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to forward ports: %w", err)), nil
+	case <-ctx.Done():
+		f.close()
+		return mcpHelpers.NewErrorResult(fmt.Errorf("port forward cancelled: %w", ctx.Err())), nil
+	}
+	if ports, err := forwarder.GetPorts(); err == nil && len(ports) > 0 {
+		f.localPort = int(ports[0].Local)
+	}
+	if err := t.forwards.add(f, t.portForward.MaxSessions); err != nil {
+		f.close()
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+
+	// HTTP sessions of a stateless server end with the request; STDIO
+	// sessions have no ID
+	var sessionDone chan struct{}
+	if session != nil && (!t.statelessHTTP || session.ID() == "") {
+		sessionDone = make(chan struct{})
+		go func() {
+			_ = session.Wait()
+			close(sessionDone)
+		}()
+	}
+	go t.supervisePortForward(f, clientSet.Typed.CoreV1().Pods(pod.Namespace), pod.UID, forwarded, sessionDone)
+
+	result, err := mcpHelpers.NewJSONResult(f.info())
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
+}
+
+// supervisePortForward closes a session when it is idle for the idle
+// timeout, when the MCP session that opened it ends or when its pod is
+// deleted or replaced, and unregisters it once forwarding stopped.
+func (t *Toolset) supervisePortForward(f *portForward, pods corev1client.PodInterface, uid types.UID, forwarded <-chan error, sessionDone <-chan struct{}) {
+	defer close(f.done)
+	defer t.forwards.remove(f.id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	podGone := watchPod(ctx, pods, f.pod, uid)
+
+	var idle <-chan time.Time
+	idleTimeout := t.portForward.IdleTimeout.Duration()
+	var timer *time.Timer
+	if idleTimeout > 0 {
+		timer = time.NewTimer(idleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+
+	for {
+		select {
+		case <-forwarded:
+			return
+		case <-sessionDone:
+			f.close()
+			sessionDone = nil
+		case <-podGone:
+			f.close()
+			podGone = nil
+		case <-idle:
+			if remaining := idleTimeout - f.stats.idleFor(); remaining > 0 {
+				timer.Reset(remaining)
+			} else {
+				f.close()
+			}
+		}
+	}
+}
+
+// watchPod returns a channel that is closed when the pod named name with uid
+// is deleted, replaced by a pod of the same name, terminating or no longer
+// running, until ctx is done.
+func watchPod(ctx context.Context, pods corev1client.PodInterface, name string, uid types.UID) <-chan struct{} {
+	gone := make(chan struct{})
+	serving := func(pod *corev1.Pod) bool {
+		return pod.UID == uid && pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning
+	}
+	retry := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(podWatchRetry):
+			return true
+		}
+	}
+
+	go func() {
+		for ctx.Err() == nil {
+			pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) || (err == nil && !serving(pod)) {
+				close(gone)
+				return
+			}
+			if err != nil {
+				if !retry() {
+					return
+				}
+				continue
+			}
+
+			watcher, err := pods.Watch(ctx, metav1.ListOptions{
+				FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+				ResourceVersion: pod.ResourceVersion,
+			})
+			if err != nil {
+				if !retry() {
+					return
+				}
+				continue
+			}
+			if podWatchEnded(ctx, watcher, name, serving) {
+				close(gone)
+				return
+			}
+		}
+	}()
+	return gone
+}
+
+// podWatchEnded consumes pod events until the pod named name is deleted or
+// stops serving, in which case it returns true, or until the watch or ctx
+// ends.
+func podWatchEnded(ctx context.Context, watcher watch.Interface, name string, serving func(*corev1.Pod) bool) bool {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false
+			}
+			pod, isPod := event.Object.(*corev1.Pod)
+			if !isPod || pod.Name != name {
+				continue
+			}
+			if event.Type == watch.Deleted || !serving(pod) {
+				return true
+			}
+		}
+	}
+}
+
+// handlePodsPortForwardList handles the pods_port_forward_list tool.
+func (t *Toolset) handlePodsPortForwardList(ctx context.Context, session *mcp.ServerSession) (*mcp.CallToolResult, error) {
+	sessions := make([]portForwardInfo, 0)
+	for _, f := range t.forwards.list(forwardOwner(ctx, session)) {
+		sessions = append(sessions, f.info())
+	}
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"port_forwards": sessions,
+		"count":         len(sessions),
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
+}
+
+// handlePodsPortForwardStop handles the pods_port_forward_stop tool.
+func (t *Toolset) handlePodsPortForwardStop(ctx context.Context, session *mcp.ServerSession, args struct {
+	ID string `json:"id"`
+}) (*mcp.CallToolResult, error) {
+	// Sessions of other callers are reported as missing, so their IDs cannot
+	// be probed
+	f := t.forwards.get(args.ID, forwardOwner(ctx, session))
+	if f == nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("port-forward session %q not found", args.ID)), nil
+	}
+	f.close()
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return mcpHelpers.NewErrorResult(fmt.Errorf("stopping port-forward session cancelled: %w", ctx.Err())), nil
+	}
+
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"stopped":      true,
+		"port_forward": f.info(),
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
+}
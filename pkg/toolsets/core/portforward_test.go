@@ -0,0 +1,344 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// forwardClient is a fake client set whose core REST client builds real
+// URLs, which the fake's does not.
+type forwardClient struct {
+	*fake.Clientset
+	rest rest.Interface
+}
+
+func (c *forwardClient) CoreV1() corev1client.CoreV1Interface {
+	return &forwardCoreV1{CoreV1Interface: c.Clientset.CoreV1(), rest: c.rest}
+}
+
+type forwardCoreV1 struct {
+	corev1client.CoreV1Interface
+	rest rest.Interface
+}
+
+func (c *forwardCoreV1) RESTClient() rest.Interface { return c.rest }
+
+// fakeForwarder forwards nothing until it is stopped.
+type fakeForwarder struct {
+	stop  <-chan struct{}
+	ready chan struct{}
+}
+
+func (f *fakeForwarder) ForwardPorts() error {
+	close(f.ready)
+	<-f.stop
+	return nil
+}
+
+func (f *fakeForwarder) GetPorts() ([]portforward.ForwardedPort, error) {
+	return []portforward.ForwardedPort{{Local: 40000}}, nil
+}
+
+// portForwardArgs are the arguments of pods_port_forward.
+type portForwardArgs = struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	LocalPort int    `json:"local_port"`
+	PodPort   int    `json:"pod_port"`
+	Container string `json:"container"`
+	Context   string `json:"context"`
+}
+
+// PortForwardTestSuite tests port-forward sessions.
+type PortForwardTestSuite struct {
+	suite.Suite
+	client   *fake.Clientset
+	toolset  *Toolset
+	url      *url.URL
+	ports    []string
+	stats    *forwardStats
+	watching chan struct{}
+}
+
+func (s *PortForwardTestSuite) SetupTest() {
+	labels := map[string]string{"app": "web"}
+	container := corev1.Container{Name: "app", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: "uid-" + k8stypes.UID(name), Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{container}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	s.client = fake.NewClientset(
+		pod("web-a", corev1.ConditionFalse),
+		pod("web-b", corev1.ConditionTrue),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+	)
+	watching := make(chan struct{}, 10)
+	s.watching = watching
+	s.client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		watching <- struct{}{}
+		return false, nil, nil
+	})
+
+	restConfig := &rest.Config{Host: "https://k8s.example.com"}
+	typed, err := k8sclient.NewForConfig(restConfig)
+	s.Require().NoError(err)
+	client := &forwardClient{Clientset: s.client, rest: typed.CoreV1().RESTClient()}
+	s.toolset = NewToolset(&execProvider{clientSet: &kubernetes.ClientSet{Typed: client, Config: restConfig}})
+	s.toolset.newForwarder = func(_ *rest.Config, u *url.URL, stats *forwardStats, _, ports []string, stop <-chan struct{}, ready chan struct{}) (portForwarder, error) {
+		s.url, s.ports, s.stats = u, ports, stats
+		return &fakeForwarder{stop: stop, ready: ready}, nil
+	}
+}
+
+// TearDownTest stops the sessions a test left open.
+func (s *PortForwardTestSuite) TearDownTest() {
+	s.toolset.forwards.mu.Lock()
+	var sessions []*portForward
+	for _, f := range s.toolset.forwards.sessions {
+		sessions = append(sessions, f)
+	}
+	s.toolset.forwards.mu.Unlock()
+	for _, f := range sessions {
+		f.close()
+		<-f.done
+	}
+}
+
+// forward runs pods_port_forward and decodes the result.
+func (s *PortForwardTestSuite) forward(ctx context.Context, session *mcp.ServerSession, args portForwardArgs) (*mcp.CallToolResult, map[string]any) {
+	if args.Namespace == "" {
+		args.Namespace = "shop"
+	}
+	result, err := s.toolset.handlePodsPortForward(ctx, session, args)
+	s.Require().NoError(err)
+	return result, decode(result)
+}
+
+// decode decodes the JSON content of a result, or nil if it is an error
+// message.
+func decode(result *mcp.CallToolResult) map[string]any {
+	var body map[string]any
+	_ = json.Unmarshal([]byte(text(result)), &body)
+	return body
+}
+
+// text returns the text content of a result.
+func text(result *mcp.CallToolResult) string {
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+// open returns the number of open sessions.
+func (s *PortForwardTestSuite) open() int {
+	s.toolset.forwards.mu.Lock()
+	defer s.toolset.forwards.mu.Unlock()
+	return len(s.toolset.forwards.sessions)
+}
+
+// TestTargets tests that services and deployments resolve to a ready pod and
+// service ports to its container port.
+func (s *PortForwardTestSuite) TestTargets() {
+	for _, tc := range []struct {
+		kind    string
+		name    string
+		port    int
+		pod     string
+		podPort int
+	}{
+		{"", "web-a", 9000, "web-a", 9000},
+		{"Service", "web", 80, "web-b", 8080},
+		{"deployment", "web", 8080, "web-b", 8080},
+	} {
+		result, body := s.forward(context.Background(), nil, portForwardArgs{Kind: tc.kind, Name: tc.name, PodPort: tc.port})
+		s.Require().False(result.IsError, "%s %s: %s", tc.kind, tc.name, text(result))
+		s.Equal(tc.pod, body["pod"])
+		s.Equal(float64(tc.podPort), body["pod_port"])
+		s.Equal(40000.0, body["local_port"])
+		s.Equal("/api/v1/namespaces/shop/pods/"+tc.pod+"/portforward", s.url.Path)
+		s.Equal([]string{fmt.Sprintf("0:%d", tc.podPort)}, s.ports)
+	}
+
+	result, _ := s.forward(context.Background(), nil, portForwardArgs{Kind: "Service", Name: "web", PodPort: 443})
+	s.True(result.IsError)
+	s.Contains(text(result), "no port 443")
+}
+
+// TestListAndStop tests that callers list and stop only their own sessions.
+func (s *PortForwardTestSuite) TestListAndStop() {
+	alice := auth.WithIdentity(context.Background(), &auth.Identity{Username: "alice"})
+	bob := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob"})
+	_, started := s.forward(alice, nil, portForwardArgs{Name: "web-b", PodPort: 8080})
+	id := started["id"].(string)
+	s.stats.sent.Add(5)
+
+	result, err := s.toolset.handlePodsPortForwardList(alice, nil)
+	s.Require().NoError(err)
+	list := decode(result)
+	s.Equal(1.0, list["count"])
+	session := list["port_forwards"].([]any)[0].(map[string]any)
+	s.Equal(id, session["id"])
+	s.Equal("pod/web-b", session["target"])
+	s.Equal(5.0, session["bytes_sent"])
+
+	result, err = s.toolset.handlePodsPortForwardList(bob, nil)
+	s.Require().NoError(err)
+	s.Equal(0.0, decode(result)["count"])
+
+	result, err = s.toolset.handlePodsPortForwardStop(bob, nil, struct {
+		ID string `json:"id"`
+	}{ID: id})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Equal(1, s.open())
+
+	result, err = s.toolset.handlePodsPortForwardStop(alice, nil, struct {
+		ID string `json:"id"`
+	}{ID: id})
+	s.Require().NoError(err)
+	s.False(result.IsError, text(result))
+	s.Equal(true, decode(result)["stopped"])
+	s.Equal(0, s.open())
+}
+
+// idTransport gives the connections of a transport a session ID, as HTTP
+// transports have.
+type idTransport struct {
+	mcp.Transport
+	id string
+}
+
+func (t idTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	return idConnection{Connection: conn, id: t.id}, err
+}
+
+type idConnection struct {
+	mcp.Connection
+	id string
+}
+
+func (c idConnection) SessionID() string { return c.id }
+
+// connect connects a client to a new server session with id.
+func (s *PortForwardTestSuite) connect(id string) *mcp.ServerSession {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := server.Connect(context.Background(), idTransport{Transport: serverTransport, id: id}, nil)
+	s.Require().NoError(err)
+	client, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(context.Background(), clientTransport, nil)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = client.Close() })
+	return session
+}
+
+// TestAnonymousSessions tests that anonymous callers list and stop only the
+// sessions their MCP session opened.
+func (s *PortForwardTestSuite) TestAnonymousSessions() {
+	ctx := context.Background()
+	first, second := s.connect("first"), s.connect("second")
+	s.Require().Equal("first", first.ID())
+	_, started := s.forward(ctx, first, portForwardArgs{Name: "web-b", PodPort: 8080})
+	id := started["id"].(string)
+
+	result, err := s.toolset.handlePodsPortForwardList(ctx, second)
+	s.Require().NoError(err)
+	s.Equal(0.0, decode(result)["count"])
+	result, err = s.toolset.handlePodsPortForwardStop(ctx, second, struct {
+		ID string `json:"id"`
+	}{ID: id})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+
+	result, err = s.toolset.handlePodsPortForwardList(ctx, first)
+	s.Require().NoError(err)
+	s.Equal(1.0, decode(result)["count"])
+	result, err = s.toolset.handlePodsPortForwardStop(ctx, first, struct {
+		ID string `json:"id"`
+	}{ID: id})
+	s.Require().NoError(err)
+	s.False(result.IsError, text(result))
+}
+
+// TestAutomaticClose tests that sessions close when the pod is deleted, when
+// they are idle and when the MCP session ends.
+func (s *PortForwardTestSuite) TestAutomaticClose() {
+	ctx := context.Background()
+	s.forward(ctx, nil, portForwardArgs{Name: "web-b", PodPort: 8080})
+	<-s.watching
+	s.Require().NoError(s.client.CoreV1().Pods("shop").Delete(ctx, "web-b", metav1.DeleteOptions{}))
+	s.Eventually(func() bool { return s.open() == 0 }, time.Second, 10*time.Millisecond)
+
+	cfg := defaultPortForwardConfig
+	cfg.IdleTimeout = config.Duration(50 * time.Millisecond)
+	s.toolset.SetPortForwardConfig(&cfg, false)
+	s.forward(ctx, nil, portForwardArgs{Name: "web-a", PodPort: 8080})
+	s.Equal(1, s.open())
+	s.Eventually(func() bool { return s.open() == 0 }, time.Second, 10*time.Millisecond)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := server.Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+	client, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+	cfg.IdleTimeout = -1
+	s.toolset.SetPortForwardConfig(&cfg, false)
+	s.forward(ctx, session, portForwardArgs{Name: "web-a", PodPort: 8080})
+	s.Equal(1, s.open())
+	s.Require().NoError(client.Close())
+	s.Eventually(func() bool { return s.open() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+// TestMaxSessions tests the limit of open sessions.
+func (s *PortForwardTestSuite) TestMaxSessions() {
+	cfg := defaultPortForwardConfig
+	cfg.MaxSessions = 1
+	s.toolset.SetPortForwardConfig(&cfg, false)
+	result, _ := s.forward(context.Background(), nil, portForwardArgs{Name: "web-b", PodPort: 8080})
+	s.False(result.IsError)
+	result, _ = s.forward(context.Background(), nil, portForwardArgs{Name: "web-b", PodPort: 9090})
+	s.True(result.IsError)
+	s.Contains(text(result), "maximum")
+}
+
+func TestPortForwardTestSuite(t *testing.T) {
+	suite.Run(t, new(PortForwardTestSuite))
+}
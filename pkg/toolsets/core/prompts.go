@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Prompts returns the troubleshooting prompts for core workloads.
+func (t *Toolset) Prompts() []*mcpHelpers.Prompt {
+	return []*mcpHelpers.Prompt{
+		mcpHelpers.NewPrompt("troubleshoot_crashlooping_pod", "Troubleshoot a crashlooping pod",
+			"Find out why a pod keeps restarting and suggest a fix").
+			WithContextArgument().
+			WithArgument("namespace", "Namespace of the pod", true).
+			WithObjectArgument("name", "Pod name", schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, true).
+			WithTools("pods_get", "pods_logs", "events_list", "resources_relationships").
+			WithRender(func(args map[string]string) []string {
+				return []string{
+					fmt.Sprintf("Pod %s/%s%s is in CrashLoopBackOff. Find the root cause and suggest a fix.",
+						args["namespace"], args["name"], mcpHelpers.ContextClause(args)),
+					"Work through these steps, calling the tools with the same context and namespace:\n" +
+						"1. Call `pods_get` and note each container's state, last termination reason, exit code and restart count.\n" +
+						"2. Call `pods_logs` with previous=true and tail_lines=100 for the crashing container to see why the last instance exited.\n" +
+						"3. Call `events_list` for the namespace and look for events about the pod (BackOff, OOMKilled, failed probes, image pull or mount errors).\n" +
+						"4. Call `resources_relationships` with direction=owners to find the workload that manages the pod, since fixes belong there.\n" +
+						"5. Summarize the most likely cause with the evidence for it, and propose a concrete change (e.g. memory limits, probe settings, configuration or image). Do not change anything without asking.",
+				}
+			}).
+			Build(),
+
+		mcpHelpers.NewPrompt("explain_deployment_not_progressing", "Explain why a deployment is not progressing",
+			"Explain why a Deployment rollout is stuck and what would unblock it").
+			WithContextArgument().
+			WithArgument("namespace", "Namespace of the Deployment", true).
+			WithObjectArgument("name", "Deployment name", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, true).
+			WithTools("resources_describe", "resources_relationships", "pods_get", "events_list").
+			WithRender(func(args map[string]string) []string {
+				return []string{
+					fmt.Sprintf("Deployment %s/%s%s is not progressing. Explain why and what would unblock the rollout.",
+						args["namespace"], args["name"], mcpHelpers.ContextClause(args)),
+					"Work through these steps, calling the tools with the same context and namespace:\n" +
+						"1. Call `resources_describe` with group=apps, version=v1, kind=Deployment and check the Progressing and Available conditions, replica counts and rollout strategy.\n" +
+						"2. Call `resources_relationships` with direction=dependents to find the ReplicaSets, and compare the newest ReplicaSet with the previous one.\n" +
+						"3. Call `pods_get` on pods of the newest ReplicaSet that are not ready, and check container states and readiness probes.\n" +
+						"4. Call `events_list` for the namespace and look for scheduling failures, quota or admission denials and image pull errors.\n" +
+						"5. Explain which of these blocks the rollout and propose the smallest change that unblocks it. Do not change anything without asking.",
+				}
+			}).
+			Build(),
+	}
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/audit"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// RBACTestSuite tests the RBAC checks of core tools.
+type RBACTestSuite struct {
+	suite.Suite
+	typed    *fake.Clientset
+	reviews  []authorizationv1.ResourceAttributes
+	toolset  *Toolset
+	auditLog bytes.Buffer
+	auditor  *audit.Auditor
+}
+
+func (s *RBACTestSuite) SetupTest() {
+	s.typed = fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+	s.typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		s.reviews = append(s.reviews, *review.Spec.ResourceAttributes)
+		review.Status.Allowed = review.Spec.User == "alice"
+		return true, review, nil
+	})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	provider := &execProvider{clientSet: &kubernetes.ClientSet{Typed: s.typed, RESTMapper: mapper}}
+	s.toolset = NewToolset(provider)
+	s.toolset.SetRBACAuthorizer(kubernetes.NewRBACAuthorizer(provider, 60), true)
+
+	s.reviews = nil
+	s.auditLog.Reset()
+	var err error
+	s.auditor, err = audit.NewAuditor(audit.NewWriterSink(&s.auditLog))
+	s.Require().NoError(err)
+}
+
+// deletePod deletes the pod as user through the audit middleware, and returns
+// the result and the audit entry.
+func (s *RBACTestSuite) deletePod(user string) (*mcp.CallToolResult, audit.Entry) {
+	s.auditLog.Reset()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: user})
+	handler := s.auditor.Middleware()(func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+		return s.toolset.TestHandlePodsDelete(ctx, struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+			Context   string `json:"context"`
+		}{Name: "web", Namespace: "default"})
+	})
+	result, err := handler(ctx, &mcpHelpers.ToolCall{Name: "pods_delete", Arguments: map[string]any{"name": "web", "namespace": "default"}})
+	s.Require().NoError(err)
+
+	var entry audit.Entry
+	s.Require().NoError(json.Unmarshal(s.auditLog.Bytes(), &entry))
+	return result, entry
+}
+
+// TestDenied tests that a denied RBAC check fails the call and is audited as
+// denied.
+func (s *RBACTestSuite) TestDenied() {
+	result, entry := s.deletePod("bob")
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "Forbidden")
+	s.Equal(audit.OutcomeDenied, entry.Outcome)
+	s.Equal([]kubernetes.RBACDecision{{Verb: "delete", Resource: "pods", Namespace: "default", Allowed: false}}, entry.RBAC)
+	_, err := s.typed.CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{})
+	s.NoError(err, "Denied calls should not delete the pod")
+
+	result, entry = s.deletePod("alice")
+	s.False(result.IsError)
+	s.Equal(audit.OutcomeSuccess, entry.Outcome)
+}
+
+// TestExec tests that pods_exec checks create on the pods/exec subresource
+// before running a command.
+func (s *RBACTestSuite) TestExec() {
+	s.toolset.newExecutor = func(*rest.Config, *url.URL) (remotecommand.Executor, error) {
+		s.Fail("Denied commands should not run")
+		return nil, nil
+	}
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob"})
+	result, err := s.toolset.handlePodsExec(ctx, execArgs{Name: "web", Namespace: "default", Command: []string{"ls"}})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "Forbidden")
+	s.Equal([]authorizationv1.ResourceAttributes{{Namespace: "default", Verb: "create", Resource: "pods", Subresource: "exec"}}, s.reviews)
+}
+
+// TestPortForward tests that pods_port_forward checks create on the
+// pods/portforward subresource.
+func (s *RBACTestSuite) TestPortForward() {
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Username: "bob"})
+	result, err := s.toolset.TestHandlePodsPortForward(ctx, portForwardArgs{Name: "web", Namespace: "default", PodPort: 8080})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "Forbidden")
+	s.Equal([]authorizationv1.ResourceAttributes{{Namespace: "default", Verb: "create", Resource: "pods", Subresource: "portforward"}}, s.reviews)
+	s.Equal(0, len(s.toolset.forwards.sessions))
+}
+
+// callAs calls a tool through an MCP server as user.
+func (s *RBACTestSuite) callAs(user, name string, args map[string]any) *mcp.CallToolResult {
+	server := mcpHelpers.NewServer("test", "1.0.0", false)
+	server.UseToolMiddleware(func(next mcpHelpers.ToolHandlerFunc) mcpHelpers.ToolHandlerFunc {
+		return func(ctx context.Context, call *mcpHelpers.ToolCall) (*mcp.CallToolResult, error) {
+			return next(auth.WithIdentity(ctx, &auth.Identity{Username: user}), call)
+		}
+	})
+	s.Require().NoError(server.RegisterToolset(s.toolset))
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.GetSDKServer().Connect(ctx, serverTransport, nil)
+	s.Require().NoError(err)
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	s.Require().NoError(err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	s.Require().NoError(err)
+	return result
+}
+
+// TestReadsDenied tests that tools reading the cluster check the verb and
+// resource they read.
+func (s *RBACTestSuite) TestReadsDenied() {
+	pod := map[string]any{"version": "v1", "kind": "Pod", "name": "web", "namespace": "default"}
+	with := func(args map[string]any, extra map[string]any) map[string]any {
+		merged := map[string]any{}
+		for k, v := range args {
+			merged[k] = v
+		}
+		for k, v := range extra {
+			merged[k] = v
+		}
+		return merged
+	}
+	for _, tc := range []struct {
+		tool      string
+		args      map[string]any
+		forbidden string
+	}{
+		{"pods_list", map[string]any{"namespace": "default"}, "list /pods"},
+		{"pods_get", map[string]any{"name": "web", "namespace": "default"}, "get /pods"},
+		{"pods_logs", map[string]any{"name": "web", "namespace": "default"}, "get /pods/log"},
+		{"pods_logs_aggregate", map[string]any{"namespace": "default", "label_selector": "app=web"}, "list /pods"},
+		{"configmaps_get_data", map[string]any{"name": "settings", "namespace": "default"}, "get /configmaps"},
+		{"secrets_get_data", map[string]any{"name": "credentials", "namespace": "default"}, "get /secrets"},
+		{"resources_list", map[string]any{"version": "v1", "kind": "Pod", "namespace": "default"}, "list /pods"},
+		{"resources_get", pod, "get /pods"},
+		{"resources_describe", pod, "get /pods"},
+		{"resources_diff", with(pod, map[string]any{"manifest": map[string]any{}}), "get /pods"},
+		{"resources_relationships", pod, "get /pods"},
+		{"resources_watch", map[string]any{"version": "v1", "kind": "Pod", "namespace": "default"}, "watch /pods"},
+		{"events_list", map[string]any{"namespace": "default"}, "list /events"},
+		{"namespaces_list", map[string]any{}, "list /namespaces"},
+		{"nodes_summary", map[string]any{}, "list /nodes"},
+	} {
+		result := s.callAs("bob", tc.tool, tc.args)
+		s.True(result.IsError, tc.tool)
+		s.Contains(result.Content[0].(*mcp.TextContent).Text, "Forbidden: user does not have permission to "+tc.forbidden, tc.tool)
+	}
+}
+
+// TestScale tests that resources_scale checks get on the scale subresource
+// before reading it and patch before changing it.
+func (s *RBACTestSuite) TestScale() {
+	dynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamic.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		scale := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "autoscaling/v1",
+			"kind":       "Scale",
+			"metadata":   map[string]any{"name": "web", "namespace": "default"},
+			"spec":       map[string]any{"replicas": int64(3)},
+		}}
+		return action.GetSubresource() == "scale", scale, nil
+	})
+	s.toolset.provider.(*execProvider).clientSet.Dynamic = dynamic
+	s.typed.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		s.reviews = append(s.reviews, *review.Spec.ResourceAttributes)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb == "get"
+		return true, review, nil
+	})
+
+	result := s.callAs("bob", "resources_scale", map[string]any{
+		"group": "apps", "version": "v1", "kind": "Deployment", "name": "web", "namespace": "default", "replicas": 0,
+	})
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "Forbidden")
+	s.Equal([]authorizationv1.ResourceAttributes{
+		{Namespace: "default", Verb: "get", Group: "apps", Resource: "deployments", Subresource: "scale"},
+		{Namespace: "default", Verb: "patch", Group: "apps", Resource: "deployments", Subresource: "scale"},
+	}, s.reviews)
+	for _, action := range dynamic.Actions() {
+		s.NotEqual("patch", action.GetVerb(), "Denied calls should not scale")
+	}
+}
+
+func TestRBACTestSuite(t *testing.T) {
+	suite.Run(t, new(RBACTestSuite))
+}
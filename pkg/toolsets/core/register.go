@@ -93,15 +93,17 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 
 	// pods_logs
 	type PodsLogsArgs struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace"`
-		Container string `json:"container"`
-		TailLines *int   `json:"tail_lines"`
-		Since     string `json:"since"`
-		SinceTime string `json:"since_time"`
-		Previous  bool   `json:"previous"`
-		Follow    bool   `json:"follow"`
-		Context   string `json:"context"`
+		Name        string `json:"name"`
+		Namespace   string `json:"namespace"`
+		Container   string `json:"container"`
+		TailLines   *int   `json:"tail_lines"`
+		Since       string `json:"since"`
+		SinceTime   string `json:"since_time"`
+		Previous    bool   `json:"previous"`
+		Follow      bool   `json:"follow"`
+		MaxDuration int    `json:"max_duration"`
+		MaxLines    int    `json:"max_lines"`
+		Context     string `json:"context"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 		typedArgs, err := unmarshalArgs[PodsLogsArgs](args)
@@ -123,13 +125,51 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 		Description: "Fetch pod logs",
 	}, wrappedHandler)
 
+	// pods_logs_aggregate
+	type PodsLogsAggregateArgs struct {
+		Namespace     string `json:"namespace"`
+		LabelSelector string `json:"label_selector"`
+		Kind          string `json:"kind"`
+		Name          string `json:"name"`
+		Container     string `json:"container"`
+		TailLines     *int   `json:"tail_lines"`
+		Since         string `json:"since"`
+		SinceTime     string `json:"since_time"`
+		Previous      bool   `json:"previous"`
+		Include       string `json:"include"`
+		Exclude       string `json:"exclude"`
+		MaxBytes      int    `json:"max_bytes"`
+		Context       string `json:"context"`
+	}
+	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		typedArgs, err := unmarshalArgs[PodsLogsAggregateArgs](args)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("failed to parse arguments: %w", err)), nil, nil
+		}
+		result, err := t.handlePodsLogsAggregate(ctx, typedArgs)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil, nil
+		}
+		return result, nil, nil
+	}
+	wrappedHandler = t.wrapToolHandler("pods_logs_aggregate", handler, func(args any) string {
+		typedArgs, _ := unmarshalArgs[PodsLogsAggregateArgs](args)
+		return typedArgs.Context
+	})
+	mcpHelpers.AddTool(server, &mcp.Tool{
+		Name:        "pods_logs_aggregate",
+		Description: "Fetch the logs of all pods of a workload or label selector, merged in time order",
+	}, wrappedHandler)
+
 	// pods_exec
 	type PodsExecArgs struct {
-		Name      string   `json:"name"`
-		Namespace string   `json:"namespace"`
-		Container string   `json:"container"`
-		Command   []string `json:"command"`
-		Context   string   `json:"context"`
+		Name           string   `json:"name"`
+		Namespace      string   `json:"namespace"`
+		Container      string   `json:"container"`
+		Command        []string `json:"command"`
+		Stdin          string   `json:"stdin"`
+		TimeoutSeconds int      `json:"timeout_seconds"`
+		Context        string   `json:"context"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 		typedArgs, err := unmarshalArgs[PodsExecArgs](args)
@@ -148,7 +188,7 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 	})
 	mcpHelpers.AddTool(server, &mcp.Tool{
 		Name:        "pods_exec",
-		Description: "Execute command in pod",
+		Description: "Execute a command in a pod container and return its stdout, stderr and exit code",
 	}, wrappedHandler)
 
 	// pods_top
@@ -180,6 +220,7 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 	type PodsPortForwardArgs struct {
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
+		Kind      string `json:"kind"`
 		LocalPort int    `json:"local_port"`
 		PodPort   int    `json:"pod_port"`
 		Container string `json:"container"`
@@ -190,7 +231,11 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 		if err != nil {
 			return mcpHelpers.NewErrorResult(fmt.Errorf("failed to parse arguments: %w", err)), nil, nil
 		}
-		result, err := t.handlePodsPortForward(ctx, typedArgs)
+		var session *mcp.ServerSession
+		if req != nil {
+			session = req.Session
+		}
+		result, err := t.handlePodsPortForward(ctx, session, typedArgs)
 		if err != nil {
 			return mcpHelpers.NewErrorResult(err), nil, nil
 		}
@@ -202,7 +247,54 @@ func (t *Toolset) registerPodTools(server *mcp.Server) {
 	})
 	mcpHelpers.AddTool(server, &mcp.Tool{
 		Name:        "pods_port_forward",
-		Description: "Set up port forwarding from local port to pod port",
+		Description: "Forward a local port to a pod, or to a ready pod of a service or deployment, until stopped",
+	}, wrappedHandler)
+
+	// pods_port_forward_list
+	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		var session *mcp.ServerSession
+		if req != nil {
+			session = req.Session
+		}
+		result, err := t.handlePodsPortForwardList(ctx, session)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil, nil
+		}
+		return result, nil, nil
+	}
+	wrappedHandler = t.wrapToolHandler("pods_port_forward_list", handler, func(args any) string {
+		return ""
+	})
+	mcpHelpers.AddTool(server, &mcp.Tool{
+		Name:        "pods_port_forward_list",
+		Description: "List the caller's open port-forward sessions with their traffic",
+	}, wrappedHandler)
+
+	// pods_port_forward_stop
+	type PodsPortForwardStopArgs struct {
+		ID string `json:"id"`
+	}
+	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
+		typedArgs, err := unmarshalArgs[PodsPortForwardStopArgs](args)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("failed to parse arguments: %w", err)), nil, nil
+		}
+		var session *mcp.ServerSession
+		if req != nil {
+			session = req.Session
+		}
+		result, err := t.handlePodsPortForwardStop(ctx, session, typedArgs)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil, nil
+		}
+		return result, nil, nil
+	}
+	wrappedHandler = t.wrapToolHandler("pods_port_forward_stop", handler, func(args any) string {
+		return ""
+	})
+	mcpHelpers.AddTool(server, &mcp.Tool{
+		Name:        "pods_port_forward_stop",
+		Description: "Stop a port-forward session",
 	}, wrappedHandler)
 }
 
@@ -627,6 +719,7 @@ func (t *Toolset) registerResourceTools(server *mcp.Server) {
 		LabelSelector string `json:"label_selector"`
 		FieldSelector string `json:"field_selector"`
 		Timeout       int    `json:"timeout"`
+		MaxEvents     int    `json:"max_events"`
 		Context       string `json:"context"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
@@ -646,7 +739,7 @@ func (t *Toolset) registerResourceTools(server *mcp.Server) {
 	})
 	mcpHelpers.AddTool(server, &mcp.Tool{
 		Name:        "resources_watch",
-		Description: "Watch resources for changes (returns events within timeout)",
+		Description: "Watch resources for changes, delivering events as progress notifications when the call has a progress token",
 	}, wrappedHandler)
 }
 
@@ -24,7 +24,7 @@ func (t *Toolset) handleResourcesRelationships(ctx context.Context, args struct
 	Direction string `json:"direction"` // "owners", "dependents", or "both" (default: "both")
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -43,6 +43,11 @@ func (t *Toolset) handleResourcesRelationships(ctx context.Context, args struct
 
 	gvr := mapping.Resource
 
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+
 	// Get the resource
 	resource, err := clientSet.Dynamic.Resource(gvr).Namespace(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
@@ -63,13 +68,16 @@ func (t *Toolset) handleResourcesRelationships(ctx context.Context, args struct
 
 	// Find owners (resources that own this resource)
 	if direction == "owners" || direction == "both" {
-		owners := t.findOwners(ctx, clientSet, resource)
+		owners := t.findOwners(ctx, args.Context, clientSet, resource)
 		result["owners"] = owners
 	}
 
 	// Find dependents (resources owned by this resource)
 	if direction == "dependents" || direction == "both" {
-		dependents := t.findDependents(ctx, clientSet, resource, gvk)
+		dependents := t.findDependents(ctx, args.Context, clientSet, resource, gvk)
+		if ctx.Err() != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("relationship search cancelled: %w", ctx.Err())), nil
+		}
 		result["dependents"] = dependents
 	}
 
@@ -80,8 +88,9 @@ func (t *Toolset) handleResourcesRelationships(ctx context.Context, args struct
 	return resultJSON, nil
 }
 
-// findOwners finds all resources that own the given resource.
-func (t *Toolset) findOwners(ctx context.Context, clientSet *kubernetes.ClientSet, resource *unstructured.Unstructured) []map[string]interface{} {
+// findOwners finds all resources that own the given resource. Owners the
+// caller may not get are reported as the resource references them.
+func (t *Toolset) findOwners(ctx context.Context, contextName string, clientSet *kubernetes.ClientSet, resource *unstructured.Unstructured) []map[string]interface{} {
 	owners := []map[string]interface{}{}
 
 	ownerRefs := resource.GetOwnerReferences()
@@ -98,6 +107,17 @@ func (t *Toolset) findOwners(ctx context.Context, clientSet *kubernetes.ClientSe
 			ownerNamespace = ""
 		}
 
+		if t.CheckRBAC(ctx, contextName, "get", ownerGVR, ownerNamespace) != nil {
+			owners = append(owners, map[string]interface{}{
+				"name":        ownerRef.Name,
+				"kind":        ownerRef.Kind,
+				"api_version": ownerRef.APIVersion,
+				"uid":         ownerRef.UID,
+				"namespace":   ownerNamespace,
+			})
+			continue
+		}
+
 		owner, err := clientSet.Dynamic.Resource(ownerGVR).Namespace(ownerNamespace).Get(ctx, ownerRef.Name, metav1.GetOptions{})
 		if err != nil {
 			// Owner might not exist anymore
@@ -125,8 +145,9 @@ func (t *Toolset) findOwners(ctx context.Context, clientSet *kubernetes.ClientSe
 	return owners
 }
 
-// findDependents finds all resources owned by the given resource.
-func (t *Toolset) findDependents(ctx context.Context, clientSet *kubernetes.ClientSet, resource *unstructured.Unstructured, ownerGVK schema.GroupVersionKind) []map[string]interface{} {
+// findDependents finds all resources owned by the given resource, among the
+// resources the caller may list.
+func (t *Toolset) findDependents(ctx context.Context, contextName string, clientSet *kubernetes.ClientSet, resource *unstructured.Unstructured, ownerGVK schema.GroupVersionKind) []map[string]interface{} {
 	dependents := []map[string]interface{}{}
 	resourceUID := resource.GetUID()
 
@@ -136,9 +157,24 @@ func (t *Toolset) findDependents(ctx context.Context, clientSet *kubernetes.Clie
 		return dependents
 	}
 
+	// Every resource type is listed, which can take a while on clusters with
+	// many CRDs, so report progress and stop early when the call is cancelled
+	progress := mcpHelpers.ProgressFromContext(ctx)
+	total := 0
+	for _, apiResourceList := range apiResources {
+		total += len(apiResourceList.APIResources)
+	}
+	searched := 0
+
 	// Search through all resource types for dependents
 	for _, apiResourceList := range apiResources {
 		for _, apiResource := range apiResourceList.APIResources {
+			if ctx.Err() != nil {
+				return dependents
+			}
+			searched++
+			progress.Report(ctx, float64(searched), float64(total), fmt.Sprintf("Searching %s in %s", apiResource.Name, apiResourceList.GroupVersion))
+
 			// Skip subresources
 			if strings.Contains(apiResource.Name, "/") {
 				continue
@@ -158,6 +194,9 @@ func (t *Toolset) findDependents(ctx context.Context, clientSet *kubernetes.Clie
 
 			gvr := mapping.Resource
 			namespace := resource.GetNamespace()
+			if t.CheckRBAC(ctx, contextName, "list", gvr, resourceNamespace(mapping, namespace)) != nil {
+				continue
+			}
 
 			// List resources in the namespace (or cluster-wide)
 			var list *unstructured.UnstructuredList
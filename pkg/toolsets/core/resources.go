@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -24,7 +25,7 @@ func (t *Toolset) handleResourcesList(ctx context.Context, args struct {
 	Context       string `json:"context"`
 }) (*mcp.CallToolResult, error) {
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -43,6 +44,12 @@ func (t *Toolset) handleResourcesList(ctx context.Context, args struct {
 
 	gvr := mapping.Resource
 
+	// Check RBAC; listing a namespaced resource in every namespace needs
+	// cluster-wide list
+	if result := t.CheckRBAC(ctx, args.Context, "list", gvr, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+
 	// Build list options with selectors and pagination
 	listOptions := metav1.ListOptions{}
 	if args.LabelSelector != "" {
@@ -88,6 +95,10 @@ func (t *Toolset) handleResourcesList(ctx context.Context, args struct {
 
 	resources := make([]map[string]any, 0, len(list.Items))
 	for _, item := range list.Items {
+		if mcpHelpers.WantsObjects(ctx) {
+			resources = append(resources, item.Object)
+			continue
+		}
 		resources = append(resources, map[string]any{
 			"name":       item.GetName(),
 			"namespace":  item.GetNamespace(),
@@ -115,7 +126,7 @@ func (t *Toolset) handleResourcesGet(ctx context.Context, args struct {
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -132,6 +143,12 @@ func (t *Toolset) handleResourcesGet(ctx context.Context, args struct {
 	}
 
 	gvr := mapping.Resource
+
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+
 	resource, err := clientSet.Dynamic.Resource(gvr).Namespace(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get resource: %w", err)), nil
@@ -148,7 +165,7 @@ func (t *Toolset) handleResourcesApply(ctx context.Context, args struct {
 	Context      string         `json:"context"`
 }) (*mcp.CallToolResult, error) {
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -176,11 +193,8 @@ func (t *Toolset) handleResourcesApply(ctx context.Context, args struct {
 	}
 
 	// Check RBAC before apply (even in dry-run mode to validate permissions)
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, verb, gvr, namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	if result := t.CheckRBAC(ctx, args.Context, verb, gvr, namespace); result != nil {
+		return result, nil
 	}
 
 	fieldManager := args.FieldManager
@@ -228,7 +242,7 @@ func (t *Toolset) handleResourcesDelete(ctx context.Context, args struct {
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -247,11 +261,8 @@ func (t *Toolset) handleResourcesDelete(ctx context.Context, args struct {
 	gvr := mapping.Resource
 
 	// Check RBAC before deletion (even in dry-run mode to validate permissions)
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "delete", gvr, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+	if result := t.CheckRBAC(ctx, args.Context, "delete", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Build delete options with dry-run support
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,7 +28,7 @@ func (t *Toolset) handleResourcesScale(ctx context.Context, args struct {
 	DryRun    bool   `json:"dry_run"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -53,6 +54,13 @@ func (t *Toolset) handleResourcesScale(ctx context.Context, args struct {
 		Resource: gvr.Resource,
 	}
 
+	// Check RBAC on the scale subresource
+	scaleResource := schema.GroupVersionResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource + "/scale"}
+	namespace := resourceNamespace(mapping, args.Namespace)
+	if result := t.CheckRBAC(ctx, args.Context, "get", scaleResource, namespace); result != nil {
+		return result, nil
+	}
+
 	// Get current scale
 	scaleObj, err := clientSet.Dynamic.Resource(scaleGVR).Namespace(args.Namespace).
 		Get(ctx, args.Name, metav1.GetOptions{}, "scale")
@@ -93,6 +101,10 @@ func (t *Toolset) handleResourcesScale(ctx context.Context, args struct {
 		return result, nil
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "patch", scaleResource, namespace); result != nil {
+		return result, nil
+	}
+
 	// Apply the scale update using PATCH (replicas can be 0 or >0)
 	desiredReplicas := *args.Replicas
 	scaleBytes, err := json.Marshal(map[string]any{
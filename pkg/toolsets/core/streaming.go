@@ -7,76 +7,50 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
-// handlePodsPortForward handles the pods_port_forward tool.
-// Note: Full port forwarding requires HTTP transport (Streamable HTTP) for long-lived connections.
-// This implementation validates the request and returns connection setup information.
-func (t *Toolset) handlePodsPortForward(ctx context.Context, args struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	LocalPort int    `json:"local_port"`
-	PodPort   int    `json:"pod_port"`
-	Container string `json:"container"`
-	Context   string `json:"context"`
-}) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
-	}
+// defaultStreamConfig applies when SetStreamConfig was not called.
+var defaultStreamConfig = config.StreamConfig{
+	DefaultDuration: config.Duration(30 * time.Second),
+	MaxDuration:     config.Duration(30 * time.Minute),
+	MaxItems:        10000,
+}
 
-	// Validate ports
-	if args.LocalPort <= 0 || args.LocalPort > 65535 {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid local_port: must be between 1 and 65535")), nil
-	}
-	if args.PodPort <= 0 || args.PodPort > 65535 {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid pod_port: must be between 1 and 65535")), nil
-	}
+// SetStreamConfig sets the default and maximum duration and the item limit
+// of log follows and watches.
+func (t *Toolset) SetStreamConfig(cfg *config.StreamConfig) {
+	t.stream = *cfg
+}
 
-	// Check RBAC
-	gvr := schema.GroupVersionResource{
-		Group:    "",
-		Version:  "v1",
-		Resource: "pods",
-	}
-	if rbacResult, rbacErr := t.checkRBAC(ctx, clientSet, "get", gvr, args.Namespace); rbacErr != nil || rbacResult != nil {
-		if rbacResult != nil {
-			return rbacResult, nil
-		}
-		return mcpHelpers.NewErrorResult(rbacErr), nil
+// streamLimits returns the limits of a stream a call asked to run for
+// seconds and deliver items, where 0 selects the server's defaults. A zero
+// server maximum sets no limit.
+func (t *Toolset) streamLimits(seconds, items int) (mcpHelpers.StreamLimits, error) {
+	limits := mcpHelpers.StreamLimits{
+		Duration: t.stream.DefaultDuration.Duration(),
+		MaxItems: t.stream.MaxItems,
 	}
-
-	// Verify pod exists and get pod details
-	pod, err := clientSet.Typed.CoreV1().Pods(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get pod: %w", err)), nil
+	if seconds > 0 {
+		limits.Duration = time.Duration(seconds) * time.Second
 	}
-
-	// Check if pod is ready for port forwarding
-	if pod.Status.Phase != corev1.PodRunning {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("pod is not running (current phase: %s)", pod.Status.Phase)), nil
+	if maxDuration := t.stream.MaxDuration.Duration(); maxDuration > 0 && limits.Duration > maxDuration {
+		return limits, fmt.Errorf("duration of %s exceeds the limit of %s", limits.Duration, maxDuration)
 	}
-
-	// Return port forward setup information
-	result, err := mcpHelpers.NewJSONResult(map[string]any{
-		"status":          "configured",
-		"local_port":      args.LocalPort,
-		"pod_port":        args.PodPort,
-		"pod":             args.Name,
-		"namespace":       args.Namespace,
-		"connection":      fmt.Sprintf("localhost:%d -> %s/%s:%d", args.LocalPort, args.Namespace, args.Name, args.PodPort),
-		"note":            "Port forwarding setup validated. For active port forwarding, use HTTP transport endpoint or kubectl port-forward directly.",
-		"kubectl_command": fmt.Sprintf("kubectl port-forward %s/%s %d:%d", args.Namespace, args.Name, args.LocalPort, args.PodPort),
-	})
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	if t.stream.MaxItems > 0 && items > t.stream.MaxItems {
+		return limits, fmt.Errorf("%d items exceed the limit of %d", items, t.stream.MaxItems)
 	}
-	return result, nil
+	if items > 0 {
+		limits.MaxItems = items
+	}
+	return limits, nil
 }
 
 // handleResourcesWatch handles the resources_watch tool.
@@ -87,10 +61,16 @@ func (t *Toolset) handleResourcesWatch(ctx context.Context, args struct {
 	Namespace     string `json:"namespace"`
 	LabelSelector string `json:"label_selector"`
 	FieldSelector string `json:"field_selector"`
-	Timeout       int    `json:"timeout"` // Timeout in seconds (0 = no timeout, use context)
+	Timeout       int    `json:"timeout"` // Seconds to watch (0 = server default)
+	MaxEvents     int    `json:"max_events"`
 	Context       string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	limits, err := t.streamLimits(args.Timeout, args.MaxEvents)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
+	}
+
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -107,10 +87,14 @@ func (t *Toolset) handleResourcesWatch(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to map GVK to GVR: %w", err)), nil
 	}
 
-	gvr := mapping.Resource
+	// Check RBAC
+	if result := t.CheckRBAC(ctx, args.Context, "watch", mapping.Resource, resourceNamespace(mapping, args.Namespace)); result != nil {
+		return result, nil
+	}
+	client := clientSet.Dynamic.Resource(mapping.Resource).Namespace(args.Namespace)
 
 	// Build watch options
-	watchOptions := metav1.ListOptions{}
+	watchOptions := metav1.ListOptions{AllowWatchBookmarks: true}
 	if args.LabelSelector != "" {
 		watchOptions.LabelSelector = args.LabelSelector
 	}
@@ -118,115 +102,120 @@ func (t *Toolset) handleResourcesWatch(ctx context.Context, args struct {
 		watchOptions.FieldSelector = args.FieldSelector
 	}
 
-	// Set timeout if specified
-	if args.Timeout > 0 {
-		watchOptions.TimeoutSeconds = int64Ptr(int64(args.Timeout))
-	}
-
-	// Create watch
-	watcher, err := clientSet.Dynamic.Resource(gvr).Namespace(args.Namespace).Watch(ctx, watchOptions)
+	// Create the first watch here so a failure fails the call; the watch
+	// returns the initial state as ADDED events
+	watcher, err := client.Watch(ctx, watchOptions)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create watch: %w", err)), nil
 	}
-	defer watcher.Stop()
 
-	// Collect events (with timeout)
-	events := make([]map[string]any, 0)
-	timeout := time.Duration(args.Timeout) * time.Second
-	if args.Timeout == 0 {
-		timeout = 30 * time.Second // Default 30 second watch
+	summary, err := mcpHelpers.Stream(ctx, limits, func(ctx context.Context, emit func(item any) bool) error {
+		for {
+			resourceVersion, err := consumeWatch(ctx, watcher, emit)
+			if err != nil || ctx.Err() != nil {
+				return err
+			}
+
+			// The API server ended the watch; resume after the last event
+			if resourceVersion != "" {
+				watchOptions.ResourceVersion = resourceVersion
+			}
+			if watcher, err = client.Watch(ctx, watchOptions); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to resume watch: %w", err)
+			}
+		}
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err), nil
 	}
 
-	watchCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	body := map[string]any{
+		"event_count": summary.Items,
+		"gvk":         gvk.String(),
+		"namespace":   args.Namespace,
+		"streamed":    summary.Streamed,
+		"stopped":     summary.Reason,
+	}
+	if !summary.Streamed {
+		events := summary.Collected
+		if events == nil {
+			events = make([]any, 0)
+		}
+		body["events"] = events
+	}
+	result, err := mcpHelpers.NewJSONResult(body)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
+	}
+	return result, nil
+}
 
-	// Watch for changes (watch API returns initial state as ADDED events, so we don't need to list separately)
+// consumeWatch emits the events of watcher until it ends, ctx is done or emit
+// asks to stop, and returns the resource version to resume from. Bookmarks
+// only advance the resource version.
+func consumeWatch(ctx context.Context, watcher watch.Interface, emit func(item any) bool) (string, error) {
+	defer watcher.Stop()
+	resourceVersion := ""
 	for {
 		select {
-		case <-watchCtx.Done():
-			// Timeout or context cancelled
-			result, err := mcpHelpers.NewJSONResult(map[string]any{
-				"events":      events,
-				"event_count": len(events),
-				"gvk":         gvk.String(),
-				"namespace":   args.Namespace,
-				"note":        "Watch completed. For continuous streaming, use HTTP transport endpoint.",
-			})
-			if err != nil {
-				return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
-			}
-			return result, nil
+		case <-ctx.Done():
+			return resourceVersion, nil
 		case event, ok := <-watcher.ResultChan():
 			if !ok {
-				// Channel closed
-				result, err := mcpHelpers.NewJSONResult(map[string]any{
-					"events":      events,
-					"event_count": len(events),
-					"gvk":         gvk.String(),
-					"namespace":   args.Namespace,
-					"note":        "Watch channel closed.",
-				})
-				if err != nil {
-					return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
-				}
-				return result, nil
+				return resourceVersion, nil
 			}
-
-			// Process event
-			eventData := map[string]any{
-				"type": string(event.Type),
+			if event.Type == watch.Error {
+				return resourceVersion, fmt.Errorf("watch failed: %w", apierrors.FromObject(event.Object))
 			}
-
-			// Convert object to map for JSON serialization
 			if obj, ok := event.Object.(metav1.Object); ok {
-				eventData["name"] = obj.GetName()
-				eventData["namespace"] = obj.GetNamespace()
-				eventData["uid"] = string(obj.GetUID())
+				resourceVersion = obj.GetResourceVersion()
+			}
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			if !emit(watchEventData(event)) {
+				return resourceVersion, nil
 			}
+		}
+	}
+}
 
-			// Try to get object as unstructured for full data
-			if unstructuredObj, ok := event.Object.(*unstructured.Unstructured); ok {
-				eventData["object"] = unstructuredObj.Object
+// watchEventData converts a watch event for the client.
+func watchEventData(event watch.Event) map[string]any {
+	eventData := map[string]any{
+		"type": string(event.Type),
+	}
+
+	// Convert object to map for JSON serialization
+	if obj, ok := event.Object.(metav1.Object); ok {
+		eventData["name"] = obj.GetName()
+		eventData["namespace"] = obj.GetNamespace()
+		eventData["uid"] = string(obj.GetUID())
+	}
+
+	// Try to get object as unstructured for full data
+	if unstructuredObj, ok := event.Object.(*unstructured.Unstructured); ok {
+		eventData["object"] = unstructuredObj.Object
+	} else {
+		// Fallback: marshal to JSON and unmarshal to map
+		jsonBytes, err := json.Marshal(event.Object)
+		if err == nil {
+			var objMap map[string]any
+			if err := json.Unmarshal(jsonBytes, &objMap); err == nil {
+				eventData["object"] = objMap
 			} else {
-				// Fallback: marshal to JSON and unmarshal to map
-				jsonBytes, err := json.Marshal(event.Object)
-				if err == nil {
-					var objMap map[string]any
-					if err := json.Unmarshal(jsonBytes, &objMap); err == nil {
-						eventData["object"] = objMap
-					} else {
-						eventData["object"] = map[string]any{
-							"kind": event.Object.GetObjectKind().GroupVersionKind().Kind,
-						}
-					}
-				} else {
-					eventData["object"] = map[string]any{
-						"kind": event.Object.GetObjectKind().GroupVersionKind().Kind,
-					}
+				eventData["object"] = map[string]any{
+					"kind": event.Object.GetObjectKind().GroupVersionKind().Kind,
 				}
 			}
-
-			events = append(events, eventData)
-
-			// Limit events to prevent memory issues
-			if len(events) >= 1000 {
-				result, err := mcpHelpers.NewJSONResult(map[string]any{
-					"events":      events,
-					"event_count": len(events),
-					"gvk":         gvk.String(),
-					"namespace":   args.Namespace,
-					"note":        "Event limit reached (1000 events). Watch stopped.",
-				})
-				if err != nil {
-					return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create result: %w", err)), nil
-				}
-				return result, nil
+		} else {
+			eventData["object"] = map[string]any{
+				"kind": event.Object.GetObjectKind().GroupVersionKind().Kind,
 			}
 		}
 	}
-}
-
-// int64Ptr returns a pointer to an int64.
-func int64Ptr(i int64) *int64 {
-	return &i
+	return eventData
 }
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/config"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// logsArgs are the arguments of pods_logs.
+type logsArgs = struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Container   string `json:"container"`
+	TailLines   *int   `json:"tail_lines"`
+	Since       string `json:"since"`
+	SinceTime   string `json:"since_time"`
+	Previous    bool   `json:"previous"`
+	Follow      bool   `json:"follow"`
+	MaxDuration int    `json:"max_duration"`
+	MaxLines    int    `json:"max_lines"`
+	Context     string `json:"context"`
+}
+
+// StreamingTestSuite tests log following and stream limits.
+type StreamingTestSuite struct {
+	suite.Suite
+	toolset *Toolset
+}
+
+func (s *StreamingTestSuite) SetupTest() {
+	client := fake.NewClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}})
+	s.toolset = NewToolset(&execProvider{clientSet: &kubernetes.ClientSet{Typed: client}})
+}
+
+// TestStreamLimits tests defaults, per-call limits and the server's maximums.
+func (s *StreamingTestSuite) TestStreamLimits() {
+	s.toolset.SetStreamConfig(&config.StreamConfig{
+		DefaultDuration: config.Duration(30 * time.Second),
+		MaxDuration:     config.Duration(time.Minute),
+		MaxItems:        100,
+	})
+
+	limits, err := s.toolset.streamLimits(0, 0)
+	s.Require().NoError(err)
+	s.Equal(mcpHelpers.StreamLimits{Duration: 30 * time.Second, MaxItems: 100}, limits)
+
+	limits, err = s.toolset.streamLimits(45, 10)
+	s.Require().NoError(err)
+	s.Equal(mcpHelpers.StreamLimits{Duration: 45 * time.Second, MaxItems: 10}, limits)
+
+	_, err = s.toolset.streamLimits(120, 0)
+	s.ErrorContains(err, "exceeds the limit")
+	_, err = s.toolset.streamLimits(0, 1000)
+	s.ErrorContains(err, "exceed the limit")
+
+	// Zero maximums set no limit
+	s.toolset.SetStreamConfig(&config.StreamConfig{DefaultDuration: config.Duration(30 * time.Second)})
+	limits, err = s.toolset.streamLimits(120, 1000)
+	s.Require().NoError(err)
+	s.Equal(mcpHelpers.StreamLimits{Duration: 120 * time.Second, MaxItems: 1000}, limits)
+}
+
+// TestFollowLogs tests that followed lines are returned to calls that cannot
+// receive notifications, with why the stream stopped.
+func (s *StreamingTestSuite) TestFollowLogs() {
+	result, err := s.toolset.handlePodsLogs(context.Background(), logsArgs{Name: "web", Namespace: "shop", Follow: true})
+	s.Require().NoError(err)
+	s.Require().False(result.IsError, text(result))
+	body := decode(result)
+	s.Equal("fake logs\n", body["logs"])
+	s.Equal(1.0, body["lines"])
+	s.Equal(false, body["streamed"])
+	s.Equal(mcpHelpers.StreamEnded, body["stopped"])
+
+	result, err = s.toolset.handlePodsLogs(context.Background(), logsArgs{Name: "web", Namespace: "shop", Follow: true, MaxDuration: 3600})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+}
+
+func TestStreamingTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamingTestSuite))
+}
@@ -38,15 +38,17 @@ func (t *Toolset) TestHandlePodsDelete(ctx context.Context, args struct {
 
 // TestHandlePodsLogs is a test helper that exposes handlePodsLogs for testing.
 func (t *Toolset) TestHandlePodsLogs(ctx context.Context, args struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Container string `json:"container"`
-	TailLines *int   `json:"tail_lines"`
-	Since     string `json:"since"`
-	SinceTime string `json:"since_time"`
-	Previous  bool   `json:"previous"`
-	Follow    bool   `json:"follow"`
-	Context   string `json:"context"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Container   string `json:"container"`
+	TailLines   *int   `json:"tail_lines"`
+	Since       string `json:"since"`
+	SinceTime   string `json:"since_time"`
+	Previous    bool   `json:"previous"`
+	Follow      bool   `json:"follow"`
+	MaxDuration int    `json:"max_duration"`
+	MaxLines    int    `json:"max_lines"`
+	Context     string `json:"context"`
 }) (*mcp.CallToolResult, error) {
 	return t.handlePodsLogs(ctx, args)
 }
@@ -182,12 +184,13 @@ func (t *Toolset) TestHandleResourcesScale(ctx context.Context, args struct {
 func (t *Toolset) TestHandlePodsPortForward(ctx context.Context, args struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
 	LocalPort int    `json:"local_port"`
 	PodPort   int    `json:"pod_port"`
 	Container string `json:"container"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	return t.handlePodsPortForward(ctx, args)
+	return t.handlePodsPortForward(ctx, nil, args)
 }
 
 // TestHandleResourcesWatch is a test helper that exposes handleResourcesWatch for testing.
@@ -199,6 +202,7 @@ func (t *Toolset) TestHandleResourcesWatch(ctx context.Context, args struct {
 	LabelSelector string `json:"label_selector"`
 	FieldSelector string `json:"field_selector"`
 	Timeout       int    `json:"timeout"`
+	MaxEvents     int    `json:"max_events"`
 	Context       string `json:"context"`
 }) (*mcp.CallToolResult, error) {
 	return t.handleResourcesWatch(ctx, args)
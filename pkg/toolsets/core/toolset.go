@@ -2,24 +2,38 @@ package core
 
 import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/config"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 )
 
 // Toolset implements the Core toolset for Kubernetes operations.
 type Toolset struct {
-	provider       kubernetes.ClientProvider
-	logger         *observability.Logger
-	metrics        *observability.Metrics
-	rbacAuthorizer kubernetes.RBACAuthorizer
-	requireRBAC    bool
+	toolsets.RBAC
+	provider      kubernetes.ClientProvider
+	logger        *observability.Logger
+	metrics       *observability.Metrics
+	exec          config.ExecConfig
+	newExecutor   executorFunc
+	portForward   config.PortForwardConfig
+	statelessHTTP bool
+	forwards      *portForwards
+	newForwarder  forwarderFunc
+	stream        config.StreamConfig
+	logs          config.LogsConfig
 }
 
 // NewToolset creates a new Core toolset.
 func NewToolset(provider kubernetes.ClientProvider) *Toolset {
 	return &Toolset{
-		provider: provider,
+		provider:    provider,
+		exec:        defaultExecConfig,
+		portForward: defaultPortForwardConfig,
+		forwards:    newPortForwards(),
+		stream:      defaultStreamConfig,
+		logs:        defaultLogsConfig,
 	}
 }
 
@@ -29,12 +43,6 @@ func (t *Toolset) SetObservability(logger *observability.Logger, metrics *observ
 	t.metrics = metrics
 }
 
-// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
-func (t *Toolset) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
-	t.rbacAuthorizer = authorizer
-	t.requireRBAC = requireRBAC
-}
-
 // Name returns the toolset name.
 func (t *Toolset) Name() string {
 	return "core"
@@ -64,6 +72,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("namespace", "string", "Namespace name", true).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("pods_logs", "Fetch pod logs").
 			WithParameter("name", "string", "Pod name", true).
@@ -73,29 +82,59 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("since", "string", "Duration string (e.g., '5m', '1h') to fetch logs since", false).
 			WithParameter("since_time", "string", "RFC3339 timestamp to fetch logs since", false).
 			WithParameter("previous", "boolean", "Fetch logs from previous container instance", false).
-			WithParameter("follow", "boolean", "Follow log stream (for real-time streaming, use HTTP transport)", false).
+			WithParameter("follow", "boolean", "Follow new log lines, delivered as progress notifications when the call has a progress token", false).
+			WithParameter("max_duration", "integer", "Seconds to follow (default: server setting)", false).
+			WithParameter("max_lines", "integer", "Most lines to follow (default: server setting)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
-		mcpHelpers.NewTool("pods_exec", "Execute command in pod").
+		mcpHelpers.NewTool("pods_logs_aggregate", "Fetch the logs of all pods of a workload or label selector, merged in time order").
+			WithParameter("namespace", "string", "Namespace name", true).
+			WithParameter("label_selector", "string", "Label selector of the pods (instead of kind and name)", false).
+			WithEnumParameter("kind", "Kind of the workload whose pods to read", []string{"Deployment", "StatefulSet", "DaemonSet", "Job", "Rollout"}, false).
+			WithParameter("name", "string", "Name of the workload", false).
+			WithParameter("container", "string", "Container name (default: all containers)", false).
+			WithParameter("tail_lines", "integer", "Number of lines to tail of each container", false).
+			WithParameter("since", "string", "Duration string (e.g., '5m', '1h') to fetch logs since", false).
+			WithParameter("since_time", "string", "RFC3339 timestamp to fetch logs since", false).
+			WithParameter("previous", "boolean", "Also fetch logs of the previous instance of restarted containers", false).
+			WithParameter("include", "string", "Regular expression lines must match", false).
+			WithParameter("exclude", "string", "Regular expression of lines to drop", false).
+			WithParameter("max_bytes", "integer", "Most bytes of logs to return, keeping the newest lines (default: server setting)", false).
+			WithParameter("context", "string", "Kubernetes context name", false).
+			WithReadOnly().
+			Build(),
+		mcpHelpers.NewTool("pods_exec", "Execute a command in a pod container and return its stdout, stderr and exit code").
 			WithParameter("name", "string", "Pod name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
 			WithParameter("container", "string", "Container name (optional)", false).
-			WithParameter("command", "array", "Command to execute", true).
+			WithArrayParameter("command", "string", "Command to execute, without a shell unless one is given (e.g. [\"sh\", \"-c\", \"...\"])", true).
+			WithParameter("stdin", "string", "Content sent to the command's standard input", false).
+			WithParameter("timeout_seconds", "integer", "Seconds the command may run (default: server setting)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
+			WithDestructive().
 			Build(),
 		mcpHelpers.NewTool("pods_top", "Get pod resource usage metrics").
 			WithParameter("namespace", "string", "Namespace name (empty for all namespaces)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
-		mcpHelpers.NewTool("pods_port_forward", "Set up port forwarding from local port to pod port").
-			WithParameter("name", "string", "Pod name", true).
+		mcpHelpers.NewTool("pods_port_forward", "Forward a local port to a pod, or to a ready pod of a service or deployment, until stopped").
+			WithParameter("name", "string", "Name of the pod, service or deployment", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("local_port", "integer", "Local port to forward from", true).
-			WithParameter("pod_port", "integer", "Pod port to forward to", true).
+			WithEnumParameter("kind", "Kind of the target (default: Pod)", []string{"Pod", "Service", "Deployment"}, false).
+			WithParameter("local_port", "integer", "Local port to listen on (0 or omitted picks a free port)", false).
+			WithParameter("pod_port", "integer", "Port to forward to: a container port, or a service port for a Service", true).
 			WithParameter("container", "string", "Container name (optional)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
+			WithNonDestructive().
+			Build(),
+		mcpHelpers.NewTool("pods_port_forward_list", "List the caller's open port-forward sessions with their traffic").
+			WithReadOnly().
+			Build(),
+		mcpHelpers.NewTool("pods_port_forward_stop", "Stop a port-forward session").
+			WithParameter("id", "string", "Session ID returned by pods_port_forward", true).
+			WithNonDestructive().
 			Build(),
 		// Resource tools
 		mcpHelpers.NewTool("resources_list", "List resources by GroupVersionKind").
@@ -116,6 +155,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("kind", "string", "Resource kind", true).
 			WithParameter("name", "string", "Resource name", true).
 			WithParameter("namespace", "string", "Namespace name (empty for cluster-scoped)", false).
+			WithParameter("reveal", "boolean", "Return Secret data unmasked; only if allowed by security.redaction.reveal (default: false)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
@@ -135,7 +175,8 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("name", "string", "Resource name", true).
 			WithParameter("namespace", "string", "Namespace name (empty for cluster-scoped)", false).
 			WithParameter("manifest", "object", "Desired resource manifest (YAML or JSON)", true).
-			WithParameter("diff_format", "string", "Diff format: 'unified' (default), 'json', or 'yaml'", false).
+			WithEnumParameter("diff_format", "Diff format: 'unified' (default), 'json', or 'yaml'", []string{"unified", "json", "yaml"}, false).
+			WithDefault("diff_format", "unified").
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
@@ -151,41 +192,48 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("kind", "string", "Resource kind", true).
 			WithParameter("name", "string", "Resource name", true).
 			WithParameter("namespace", "string", "Namespace name (empty for cluster-scoped)", false).
-			WithParameter("direction", "string", "Direction: 'owners', 'dependents', or 'both' (default: 'both')", false).
+			WithEnumParameter("direction", "Direction: 'owners', 'dependents', or 'both' (default: 'both')", []string{"owners", "dependents", "both"}, false).
+			WithDefault("direction", "both").
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("configmaps_get_data", "Get ConfigMap data").
 			WithParameter("name", "string", "ConfigMap name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("keys", "array", "Specific keys to retrieve (optional, returns all if omitted)", false).
+			WithArrayParameter("keys", "string", "Specific keys to retrieve (optional, returns all if omitted)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("configmaps_set_data", "Update ConfigMap data").
 			WithParameter("name", "string", "ConfigMap name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("data", "object", "Data to set (map of string keys to string values)", true).
+			WithMapParameter("data", "string", "Data to set (map of string keys to string values)", true).
 			WithParameter("merge", "boolean", "If true, merge with existing data; if false, replace (default: false)", false).
+			WithDefault("merge", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("secrets_get_data", "Get Secret data").
 			WithParameter("name", "string", "Secret name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("keys", "array", "Specific keys to retrieve (optional, returns all if omitted)", false).
+			WithArrayParameter("keys", "string", "Specific keys to retrieve (optional, returns all if omitted)", false).
 			WithParameter("decode", "boolean", "If true, base64 decode values; if false, return base64 encoded (default: false)", false).
+			WithParameter("reveal", "boolean", "Return values unmasked; only if allowed by security.redaction.reveal (default: false)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("secrets_set_data", "Update Secret data").
 			WithParameter("name", "string", "Secret name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("data", "object", "Data to set (map of string keys to string values)", true).
+			WithMapParameter("data", "string", "Data to set (map of string keys to string values)", true).
 			WithParameter("merge", "boolean", "If true, merge with existing data; if false, replace (default: false)", false).
-			WithParameter("encode", "boolean", "If true, base64 encode provided values; if false, assume already encoded (default: true)", false).
+			WithDefault("merge", false).
+			WithParameter("encode", "boolean", "If true, base64 encode provided values; if false, assume already encoded (default: false)", false).
+			WithDefault("encode", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("resources_apply", "Create or update a resource using server-side apply").
 			WithParameter("manifest", "object", "Resource manifest (YAML or JSON)", true).
@@ -193,6 +241,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("dry_run", "boolean", "If true, validate without applying changes", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("resources_patch", "Partially update a resource using JSON Patch, Merge Patch, or Strategic Merge Patch").
 			WithParameter("group", "string", "API group", false).
@@ -200,8 +249,9 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("kind", "string", "Resource kind", true).
 			WithParameter("name", "string", "Resource name", true).
 			WithParameter("namespace", "string", "Namespace name (empty for cluster-scoped)", false).
-			WithParameter("patch_type", "string", "Patch type: 'merge' (default), 'json', or 'strategic'", false).
-			WithParameter("patch_data", "object", "Patch data (object for merge/strategic, array or object with 'op' field for json patch)", true).
+			WithEnumParameter("patch_type", "Patch type: 'merge' (default), 'json', or 'strategic'", []string{"merge", "json", "strategic"}, false).
+			WithDefault("patch_type", "merge").
+			WithSchemaParameter("patch_data", "Patch data (object for merge/strategic, array or object with 'op' field for json patch)", map[string]any{"type": []string{"object", "array"}}, true).
 			WithParameter("field_manager", "string", "Field manager name", false).
 			WithParameter("dry_run", "boolean", "If true, validate without applying changes", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
@@ -216,6 +266,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("dry_run", "boolean", "If true, validate without deleting", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("resources_scale", "Scale a resource. Omit replicas or set to null for get-only operation").
 			WithParameter("group", "string", "API group", false).
@@ -223,19 +274,21 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("kind", "string", "Resource kind", true).
 			WithParameter("name", "string", "Resource name", true).
 			WithParameter("namespace", "string", "Namespace name", true).
-			WithParameter("replicas", "integer", "Number of replicas (omit or null for get-only, 0 to scale to zero, >0 to scale to that number)", false).
+			WithSchemaParameter("replicas", "Number of replicas (omit or null for get-only, 0 to scale to zero, >0 to scale to that number)", map[string]any{"type": []string{"integer", "null"}, "minimum": 0}, false).
 			WithParameter("dry_run", "boolean", "If true, validate without scaling", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
-		mcpHelpers.NewTool("resources_watch", "Watch resources for changes (returns events within timeout)").
+		mcpHelpers.NewTool("resources_watch", "Watch resources for changes, delivering events as progress notifications when the call has a progress token").
 			WithParameter("group", "string", "API group", false).
 			WithParameter("version", "string", "API version", true).
 			WithParameter("kind", "string", "Resource kind", true).
 			WithParameter("namespace", "string", "Namespace name (empty for cluster-scoped)", false).
 			WithParameter("label_selector", "string", "Label selector", false).
 			WithParameter("field_selector", "string", "Field selector", false).
-			WithParameter("timeout", "integer", "Timeout in seconds (0 = default 30 seconds)", false).
+			WithParameter("timeout", "integer", "Seconds to watch (default: server setting)", false).
+			WithParameter("max_events", "integer", "Most events to deliver (default: server setting)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithReadOnly().
 			Build(),
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -17,17 +18,12 @@ func (t *Toolset) handleAppReconcile(ctx context.Context, args struct {
 	Kind      string `json:"kind"`
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
-	Confirm   bool   `json:"confirm"`
 }) (*mcp.CallToolResult, error) {
 	if errResult, err := t.checkFeatureEnabled(); errResult != nil || err != nil {
 		return errResult, err
 	}
 
-	if !args.Confirm {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to reconcile")), nil
-	}
-
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -105,8 +101,8 @@ func (t *Toolset) handleAppReconcile(ctx context.Context, args struct {
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", gvr, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
@@ -21,7 +21,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 		WithParameter("context", "string", "Kubernetes context name", false).
 		WithParameter("namespace", "string", "Namespace name (empty for all namespaces)", false).
 		WithParameter("label_selector", "string", "Label selector", false).
-		WithParameter("kinds", "array", "Array of kinds to filter: 'Kustomization', 'HelmRelease', 'Application' (default: all available)", false).
+		WithSchemaParameter("kinds", "Array of kinds to filter: 'Kustomization', 'HelmRelease', 'Application' (default: all available)", map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": []string{"Kustomization", "HelmRelease", "Application"}}}, false).
 		WithParameter("limit", "integer", "Maximum number of items to return", false).
 		WithParameter("continue", "string", "Token from previous paginated request", false).
 		WithReadOnly().
@@ -30,7 +30,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// gitops.app_get
 	tools = append(tools, mcpHelpers.NewTool("gitops.app_get", "Get GitOps application details").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Application kind: 'Kustomization', 'HelmRelease', or 'Application'", true).
+		WithEnumParameter("kind", "Application kind: 'Kustomization', 'HelmRelease', or 'Application'", []string{"Kustomization", "HelmRelease", "Application"}, true).
 		WithParameter("name", "string", "Application name", true).
 		WithParameter("namespace", "string", "Namespace name (required for namespaced kinds)", true).
 		WithParameter("raw", "boolean", "Return raw object if true", false).
@@ -40,10 +40,9 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// gitops.app_reconcile (only for Flux)
 	tools = append(tools, mcpHelpers.NewTool("gitops.app_reconcile", "Trigger reconciliation for a Flux Kustomization or HelmRelease").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Application kind: 'Kustomization' or 'HelmRelease'", true).
+		WithEnumParameter("kind", "Application kind: 'Kustomization' or 'HelmRelease'", []string{"Kustomization", "HelmRelease"}, true).
 		WithParameter("name", "string", "Application name", true).
 		WithParameter("namespace", "string", "Namespace name", true).
-		WithParameter("confirm", "boolean", "Must be true to reconcile", true).
 		WithDestructive().
 		Build())
 
@@ -119,7 +118,6 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 		Kind      string `json:"kind"`
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
-		Confirm   bool   `json:"confirm"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 		typedArgs, err := unmarshalArgs[AppReconcileArgs](args)
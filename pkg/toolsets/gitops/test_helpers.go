@@ -35,7 +35,6 @@ func (t *Toolset) TestHandleAppReconcile(ctx context.Context, args struct {
 	Kind      string `json:"kind"`
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
-	Confirm   bool   `json:"confirm"`
 }) (*mcp.CallToolResult, error) {
 	return t.handleAppReconcile(ctx, args)
 }
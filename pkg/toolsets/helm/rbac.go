@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// resourceRef is a resource a release operation acts on, in the namespace it
+// acts in ("" for cluster-scoped resources).
+type resourceRef struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// manifestResources returns the resources of the objects in manifests, which
+// are applied to namespace unless they name their own. Kinds the cluster does
+// not serve yet, such as those of CRDs the chart installs, are guessed.
+func manifestResources(mapper meta.RESTMapper, namespace string, manifests ...string) ([]resourceRef, error) {
+	seen := map[resourceRef]bool{}
+	var refs []resourceRef
+	for _, manifest := range manifests {
+		docs := releaseutil.SplitManifests(manifest)
+		names := make([]string, 0, len(docs))
+		for name := range docs {
+			names = append(names, name)
+		}
+		sort.Sort(releaseutil.BySplitManifestsOrder(names))
+
+		for _, name := range names {
+			var obj unstructured.Unstructured
+			if err := yaml.Unmarshal([]byte(docs[name]), &obj.Object); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			gvk := obj.GroupVersionKind()
+			if gvk.Kind == "" {
+				continue
+			}
+
+			ref := resourceRef{namespace: obj.GetNamespace()}
+			if ref.namespace == "" {
+				ref.namespace = namespace
+			}
+			mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			switch {
+			case err == nil:
+				ref.gvr = mapping.Resource
+				if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+					ref.namespace = ""
+				}
+			case meta.IsNoMatchError(err):
+				ref.gvr, _ = meta.UnsafeGuessKindToResource(gvk)
+			default:
+				return nil, fmt.Errorf("failed to map %s: %w", gvk, err)
+			}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// renderInstall renders the manifests install would apply for chrt with
+// values: its CRDs, objects and hooks. It does not contact the cluster.
+func renderInstall(install *action.Install, chrt *chart.Chart, values map[string]interface{}) ([]string, error) {
+	render := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	render.ReleaseName = install.ReleaseName
+	render.Namespace = install.Namespace
+	render.DryRun = true
+	render.ClientOnly = true
+	rel, err := render.Run(chrt, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	var manifests []string
+	for _, crd := range chrt.CRDObjects() {
+		manifests = append(manifests, string(crd.File.Data))
+	}
+	return append(manifests, releaseManifests(rel)...), nil
+}
+
+// releaseManifests returns the manifests of a release's objects and hooks.
+func releaseManifests(rel *release.Release) []string {
+	manifests := []string{rel.Manifest}
+	for _, hook := range rel.Hooks {
+		manifests = append(manifests, hook.Manifest)
+	}
+	return manifests
+}
+
+// checkManifestRBAC checks that the caller may perform verb on every object
+// of manifests, and on the release storage in namespace.
+func (t *Toolset) checkManifestRBAC(ctx context.Context, contextName, verb, namespace string, manifests []string) *mcp.CallToolResult {
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, contextName)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err))
+	}
+	refs, err := manifestResources(clientSet.RESTMapper, namespace, manifests...)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(err)
+	}
+	refs = append(refs, resourceRef{gvr: releaseStorageGVR, namespace: namespace})
+	for _, ref := range refs {
+		if result := t.CheckRBAC(ctx, contextName, verb, ref.gvr, ref.namespace); result != nil {
+			return result
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RBACTestSuite tests finding the resources a release acts on.
+type RBACTestSuite struct {
+	suite.Suite
+	mapper meta.RESTMapper
+}
+
+func (s *RBACTestSuite) SetupTest() {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	s.mapper = mapper
+}
+
+// TestManifestResources tests namespaces, cluster-scoped kinds, kinds the
+// cluster does not serve yet and deduplication.
+func (s *RBACTestSuite) TestManifestResources() {
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: other
+---
+# Source: empty.yaml
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+`
+	refs, err := manifestResources(s.mapper, "shop", manifest, manifest)
+	s.Require().NoError(err)
+	s.Equal([]resourceRef{
+		{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespace: "shop"},
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespace: "other"},
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}},
+		{gvr: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, namespace: "shop"},
+	}, refs)
+
+	_, err = manifestResources(s.mapper, "shop", "kind: [")
+	s.Error(err)
+}
+
+// TestRenderInstall tests that the CRDs, objects and hooks of a chart are
+// rendered with the release's values.
+func (s *RBACTestSuite) TestRenderInstall() {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "web", Version: "1.0.0"},
+		Templates: []*chart.File{
+			{Name: "templates/deployment.yaml", Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Values.name }}\n")},
+			{Name: "templates/hook.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: hook\n  annotations:\n    helm.sh/hook: pre-install\n")},
+		},
+		Files: []*chart.File{
+			{Name: "crds/widget.yaml", Data: []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: widgets.example.com\n")},
+		},
+	}
+	install := action.NewInstall(new(action.Configuration))
+	install.ReleaseName = "web"
+	install.Namespace = "shop"
+
+	manifests, err := renderInstall(install, chrt, map[string]interface{}{"name": "frontend"})
+	s.Require().NoError(err)
+	s.Require().Len(manifests, 3)
+	s.Contains(manifests[1], "name: frontend")
+
+	refs, err := manifestResources(s.mapper, "shop", manifests...)
+	s.Require().NoError(err)
+	s.Equal([]resourceRef{
+		{gvr: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}},
+		{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, namespace: "shop"},
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespace: "shop"},
+	}, refs)
+}
+
+func TestRBACTestSuite(t *testing.T) {
+	suite.Run(t, new(RBACTestSuite))
+}
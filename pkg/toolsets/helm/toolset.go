@@ -10,10 +10,12 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
@@ -22,8 +24,16 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+// releaseStorageGVR is the resource Helm stores releases in. Listing releases
+// reads it, and installing and uninstalling also write it.
+var releaseStorageGVR = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "secrets",
+}
+
 // Toolset implements the Helm toolset for chart and release management.
 type Toolset struct {
+	toolsets.RBAC
 	provider kubernetes.ClientProvider
 	settings *cli.EnvSettings
 	logger   *observability.Logger
@@ -127,8 +137,11 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("namespace", "string", "Namespace", true).
 			WithParameter("values", "object", "Chart values", false).
 			WithParameter("version", "string", "Chart version", false).
+			WithParameter("wait", "boolean", "Wait until the release's resources are ready (default: false)", false).
+			WithParameter("timeout", "integer", "Maximum seconds to wait (default: 300)", false).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithOpenWorld().
 			Build(),
 		mcpHelpers.NewTool("helm_releases_list", "List Helm releases").
 			WithParameter("namespace", "string", "Namespace (empty for all)", false).
@@ -140,6 +153,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("namespace", "string", "Namespace", true).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 	}
 }
@@ -153,6 +167,8 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 		Namespace string                 `json:"namespace"`
 		Values    map[string]interface{} `json:"values"`
 		Version   string                 `json:"version"`
+		Wait      bool                   `json:"wait"`
+		Timeout   int                    `json:"timeout"`
 		Context   string                 `json:"context"`
 	}
 	handler := func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
@@ -230,8 +246,8 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 }
 
 // getActionConfig creates an action configuration for the given context.
-func (t *Toolset) getActionConfig(ctxName, namespace string) (*action.Configuration, error) {
-	clientSet, err := t.provider.GetClientSet(ctxName)
+func (t *Toolset) getActionConfig(ctx context.Context, ctxName, namespace string) (*action.Configuration, error) {
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, ctxName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get client set: %w", err)
 	}
@@ -310,10 +326,15 @@ func (t *Toolset) handleInstall(ctx context.Context, args struct {
 	Namespace string                 `json:"namespace"`
 	Values    map[string]interface{} `json:"values"`
 	Version   string                 `json:"version"`
+	Wait      bool                   `json:"wait"`
+	Timeout   int                    `json:"timeout"`
 	Context   string                 `json:"context"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, args.Context, "create", releaseStorageGVR, args.Namespace); result != nil {
+		return result, nil
+	}
 
-	actionConfig, err := t.getActionConfig(args.Context, args.Namespace)
+	actionConfig, err := t.getActionConfig(ctx, args.Context, args.Namespace)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(err), nil
 	}
@@ -322,19 +343,49 @@ func (t *Toolset) handleInstall(ctx context.Context, args struct {
 	installAction.ReleaseName = args.Name
 	installAction.Namespace = args.Namespace
 	installAction.Version = args.Version
+	installAction.Wait = args.Wait
+	installAction.Timeout = mcpHelpers.DefaultWaitTimeout
+	if args.Timeout > 0 {
+		installAction.Timeout = time.Duration(args.Timeout) * time.Second
+	}
+
+	// Installing is a fixed sequence of steps, so report them as progress
+	progress := mcpHelpers.ProgressFromContext(ctx)
+	const installSteps = 3
 
+	progress.Report(ctx, 1, installSteps, fmt.Sprintf("Locating chart %s", args.Chart))
 	cp, err := installAction.ChartPathOptions.LocateChart(args.Chart, t.settings)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to locate chart: %w", err)), nil
 	}
 
+	progress.Report(ctx, 2, installSteps, "Loading chart")
 	chrt, err := loader.Load(cp)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to load chart: %w", err)), nil
 	}
 
+	// Check the objects the chart creates, which are only known once rendered
+	if t.RequiresRBAC() {
+		manifests, err := renderInstall(installAction, chrt, args.Values)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(err), nil
+		}
+		if result := t.checkManifestRBAC(ctx, args.Context, "create", args.Namespace, manifests); result != nil {
+			return result, nil
+		}
+	}
+
+	message := "Installing release"
+	if args.Wait {
+		message = "Installing release and waiting for its resources to be ready"
+	}
+	progress.Report(ctx, 3, installSteps, message)
 	release, err := installAction.RunWithContext(ctx, chrt, args.Values)
 	if err != nil {
+		if ctx.Err() != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("install cancelled: %w", ctx.Err())), nil
+		}
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to install chart: %w", err)), nil
 	}
 
@@ -351,8 +402,11 @@ func (t *Toolset) handleList(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, args.Context, "list", releaseStorageGVR, args.Namespace); result != nil {
+		return result, nil
+	}
 
-	actionConfig, err := t.getActionConfig(args.Context, args.Namespace)
+	actionConfig, err := t.getActionConfig(ctx, args.Context, args.Namespace)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(err), nil
 	}
@@ -385,12 +439,26 @@ func (t *Toolset) handleUninstall(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, args.Context, "delete", releaseStorageGVR, args.Namespace); result != nil {
+		return result, nil
+	}
 
-	actionConfig, err := t.getActionConfig(args.Context, args.Namespace)
+	actionConfig, err := t.getActionConfig(ctx, args.Context, args.Namespace)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(err), nil
 	}
 
+	// Check the objects of the release, which uninstalling deletes
+	if t.RequiresRBAC() {
+		rel, err := action.NewGet(actionConfig).Run(args.Name)
+		if err != nil {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get release: %w", err)), nil
+		}
+		if result := t.checkManifestRBAC(ctx, args.Context, "delete", args.Namespace, releaseManifests(rel)); result != nil {
+			return result, nil
+		}
+	}
+
 	uninstallAction := action.NewUninstall(actionConfig)
 	release, err := uninstallAction.Run(args.Name)
 	if err != nil {
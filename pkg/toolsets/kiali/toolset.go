@@ -6,18 +6,53 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/wrkode/kube-mcp/pkg/config"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// GVRs checked by RBAC before querying Kiali on the caller's behalf
+var (
+	servicesGVR = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	podLogsGVR  = schema.GroupVersionResource{Version: "v1", Resource: "pods/log"}
+)
+
+// istioObjectTypes maps the object types of Kiali's Istio config API to the
+// resources they are stored as, for RBAC checks.
+var istioObjectTypes = map[string]schema.GroupVersionResource{
+	"authorizationpolicies":  {Group: "security.istio.io", Version: "v1", Resource: "authorizationpolicies"},
+	"destinationrules":       {Group: "networking.istio.io", Version: "v1", Resource: "destinationrules"},
+	"envoyfilters":           {Group: "networking.istio.io", Version: "v1alpha3", Resource: "envoyfilters"},
+	"gateways":               {Group: "networking.istio.io", Version: "v1", Resource: "gateways"},
+	"k8sgateways":            {Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"},
+	"k8sgrpcroutes":          {Group: "gateway.networking.k8s.io", Version: "v1", Resource: "grpcroutes"},
+	"k8shttproutes":          {Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"},
+	"k8sreferencegrants":     {Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "referencegrants"},
+	"k8stcproutes":           {Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tcproutes"},
+	"k8stlsroutes":           {Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tlsroutes"},
+	"peerauthentications":    {Group: "security.istio.io", Version: "v1", Resource: "peerauthentications"},
+	"requestauthentications": {Group: "security.istio.io", Version: "v1", Resource: "requestauthentications"},
+	"serviceentries":         {Group: "networking.istio.io", Version: "v1", Resource: "serviceentries"},
+	"sidecars":               {Group: "networking.istio.io", Version: "v1", Resource: "sidecars"},
+	"telemetries":            {Group: "telemetry.istio.io", Version: "v1", Resource: "telemetries"},
+	"virtualservices":        {Group: "networking.istio.io", Version: "v1", Resource: "virtualservices"},
+	"wasmplugins":            {Group: "extensions.istio.io", Version: "v1alpha1", Resource: "wasmplugins"},
+	"workloadentries":        {Group: "networking.istio.io", Version: "v1", Resource: "workloadentries"},
+	"workloadgroups":         {Group: "networking.istio.io", Version: "v1", Resource: "workloadgroups"},
+}
+
 // Toolset implements the Kiali toolset for service mesh observability.
 type Toolset struct {
+	toolsets.RBAC
 	client  *KialiClient
 	enabled bool
 	logger  *observability.Logger
@@ -101,7 +136,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			Build(),
 		mcpHelpers.NewTool("kiali_istio_config_get", "Get Istio configuration").
 			WithParameter("namespace", "string", "Namespace", false).
-			WithParameter("object_type", "string", "Object type", false).
+			WithEnumParameter("object_type", "Object type (all types if omitted)", slices.Sorted(maps.Keys(istioObjectTypes)), false).
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("kiali_metrics", "Get metrics").
@@ -112,6 +147,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 		mcpHelpers.NewTool("kiali_logs", "Get logs").
 			WithParameter("namespace", "string", "Namespace", true).
 			WithParameter("workload", "string", "Workload name", false).
+			WithParameter("context", "string", "Kubernetes context of the cluster Kiali monitors, whose permissions are checked (default: current context)", false).
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("kiali_traces", "Get traces").
@@ -206,6 +242,7 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 	type LogsArgs struct {
 		Namespace string `json:"namespace"`
 		Workload  string `json:"workload"`
+		Context   string `json:"context"`
 	}
 	handler = func(ctx context.Context, req *mcp.CallToolRequest, args any) (*mcp.CallToolResult, any, error) {
 		typedArgs, err := unmarshalArgs[LogsArgs](args)
@@ -257,6 +294,10 @@ func (t *Toolset) RegisterTools(server *mcp.Server) error {
 func (t *Toolset) handleMeshGraph(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, "", "list", servicesGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	graph, err := t.client.GetMeshGraph(ctx, args.Namespace)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get mesh graph: %w", err)), nil
@@ -270,6 +311,21 @@ func (t *Toolset) handleIstioConfigGet(ctx context.Context, args struct {
 	Namespace  string `json:"namespace"`
 	ObjectType string `json:"object_type"`
 }) (*mcp.CallToolResult, error) {
+	// Without an object type, Kiali returns objects of every type
+	gvrs := istioObjectTypes
+	if args.ObjectType != "" {
+		gvr, ok := istioObjectTypes[args.ObjectType]
+		if !ok {
+			return mcpHelpers.NewErrorResult(fmt.Errorf("unknown object type %q", args.ObjectType)), nil
+		}
+		gvrs = map[string]schema.GroupVersionResource{args.ObjectType: gvr}
+	}
+	for _, objectType := range slices.Sorted(maps.Keys(gvrs)) {
+		if result := t.CheckRBAC(ctx, "", "list", gvrs[objectType], args.Namespace); result != nil {
+			return result, nil
+		}
+	}
+
 	config, err := t.client.GetIstioConfig(ctx, args.Namespace, args.ObjectType)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get Istio config: %w", err)), nil
@@ -283,6 +339,10 @@ func (t *Toolset) handleMetrics(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Service   string `json:"service"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, "", "get", servicesGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	metrics, err := t.client.GetMetrics(ctx, args.Namespace, args.Service)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get metrics: %w", err)), nil
@@ -295,7 +355,12 @@ func (t *Toolset) handleMetrics(ctx context.Context, args struct {
 func (t *Toolset) handleLogs(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Workload  string `json:"workload"`
+	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, args.Context, "get", podLogsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	logs, err := t.client.GetLogs(ctx, args.Namespace, args.Workload)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get logs: %w", err)), nil
@@ -309,6 +374,10 @@ func (t *Toolset) handleTraces(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Service   string `json:"service"`
 }) (*mcp.CallToolResult, error) {
+	if result := t.CheckRBAC(ctx, "", "get", servicesGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	traces, err := t.client.GetTraces(ctx, args.Namespace, args.Service)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get traces: %w", err)), nil
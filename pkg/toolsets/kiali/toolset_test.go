@@ -8,8 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/suite"
+	"github.com/wrkode/kube-mcp/pkg/auth"
 	"github.com/wrkode/kube-mcp/pkg/config"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // KialiClientTestSuite tests Kiali client functionality with HTTP mocks.
@@ -134,6 +137,71 @@ func (s *KialiClientTestSuite) TestGetMetrics() {
 }
 
 // TestKialiClientTestSuite runs the Kiali client test suite.
+// deniedAuthorizer denies every check and records the resources checked.
+type deniedAuthorizer struct {
+	checked  []schema.GroupVersionResource
+	contexts []string
+}
+
+func (a *deniedAuthorizer) Allowed(ctx context.Context, identity *auth.Identity, contextName, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	a.checked = append(a.checked, gvr)
+	a.contexts = append(a.contexts, contextName)
+	return false, nil
+}
+
+// TestIstioConfigRBAC tests that Istio config is checked against the resources
+// of its object type, and of every type when none is given.
+func (s *KialiClientTestSuite) TestIstioConfigRBAC() {
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("Denied calls should not reach Kiali")
+	})
+	toolset := &Toolset{client: s.client, enabled: true}
+	authorizer := &deniedAuthorizer{}
+	toolset.SetRBACAuthorizer(authorizer, true)
+	type args = struct {
+		Namespace  string `json:"namespace"`
+		ObjectType string `json:"object_type"`
+	}
+
+	result, err := toolset.handleIstioConfigGet(context.Background(), args{Namespace: "shop", ObjectType: "peerauthentications"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Equal([]schema.GroupVersionResource{{Group: "security.istio.io", Version: "v1", Resource: "peerauthentications"}}, authorizer.checked)
+
+	authorizer.checked = nil
+	result, err = toolset.handleIstioConfigGet(context.Background(), args{Namespace: "shop"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Len(authorizer.checked, 1, "The first denied type should fail the call")
+	s.Equal(istioObjectTypes["authorizationpolicies"], authorizer.checked[0])
+
+	result, err = toolset.handleIstioConfigGet(context.Background(), args{Namespace: "shop", ObjectType: "secrets"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Contains(result.Content[0].(*mcp.TextContent).Text, "unknown object type")
+}
+
+// TestLogsRBAC tests that logs are checked as the pods/log subresource in the
+// call's context.
+func (s *KialiClientTestSuite) TestLogsRBAC() {
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("Denied calls should not reach Kiali")
+	})
+	toolset := &Toolset{client: s.client, enabled: true}
+	authorizer := &deniedAuthorizer{}
+	toolset.SetRBACAuthorizer(authorizer, true)
+
+	result, err := toolset.handleLogs(context.Background(), struct {
+		Namespace string `json:"namespace"`
+		Workload  string `json:"workload"`
+		Context   string `json:"context"`
+	}{Namespace: "shop", Workload: "web", Context: "prod"})
+	s.Require().NoError(err)
+	s.True(result.IsError)
+	s.Equal([]schema.GroupVersionResource{{Version: "v1", Resource: "pods/log"}}, authorizer.checked)
+	s.Equal([]string{"prod"}, authorizer.contexts)
+}
+
 func TestKialiClientTestSuite(t *testing.T) {
 	suite.Run(t, new(KialiClientTestSuite))
 }
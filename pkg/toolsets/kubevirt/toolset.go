@@ -9,6 +9,7 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,6 +17,7 @@ import (
 
 // Toolset implements the KubeVirt toolset for VM lifecycle management.
 type Toolset struct {
+	toolsets.RBAC
 	provider        kubernetes.ClientProvider
 	discovery       *kubernetes.CRDDiscovery
 	vmGVR           schema.GroupVersionResource
@@ -26,15 +28,15 @@ type Toolset struct {
 	metrics         *observability.Metrics
 }
 
+// VirtualMachineGVK is the KubeVirt VirtualMachine CRD the toolset requires.
+var VirtualMachineGVK = schema.GroupVersionKind{
+	Group:   "kubevirt.io",
+	Version: "v1",
+	Kind:    "VirtualMachine",
+}
+
 // NewToolset creates a new KubeVirt toolset with improved CRD detection.
 func NewToolset(provider kubernetes.ClientProvider, discovery *kubernetes.CRDDiscovery) *Toolset {
-	// Check for VirtualMachine CRD (kubevirt.io/v1)
-	vmGVK := schema.GroupVersionKind{
-		Group:   "kubevirt.io",
-		Version: "v1",
-		Kind:    "VirtualMachine",
-	}
-
 	enabled := false
 	var vmGVR schema.GroupVersionResource
 	hasDataSource := false
@@ -49,7 +51,7 @@ func NewToolset(provider kubernetes.ClientProvider, discovery *kubernetes.CRDDis
 		}
 
 		// Check for VirtualMachine CRD
-		if gvr, ok := discovery.GetGVR(vmGVK); ok {
+		if gvr, ok := discovery.GetGVR(VirtualMachineGVK); ok {
 			enabled = true
 			vmGVR = gvr
 		}
@@ -156,12 +158,14 @@ func (t *Toolset) Tools() []*mcp.Tool {
 			WithParameter("namespace", "string", "Namespace", true).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("kubevirt_vm_stop", "Stop a VirtualMachine").
 			WithParameter("name", "string", "VM name", true).
 			WithParameter("namespace", "string", "Namespace", true).
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithDestructive().
+			WithIdempotent().
 			Build(),
 		mcpHelpers.NewTool("kubevirt_vm_restart", "Restart a VirtualMachine").
 			WithParameter("name", "string", "VM name", true).
@@ -352,7 +356,7 @@ func (t *Toolset) handleVMCreate(ctx context.Context, args struct {
 	Manifest map[string]any `json:"manifest"`
 	Context  string         `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -364,6 +368,10 @@ func (t *Toolset) handleVMCreate(ctx context.Context, args struct {
 		Kind:    "VirtualMachine",
 	})
 
+	if result := t.CheckRBAC(ctx, args.Context, "create", t.vmGVR, obj.GetNamespace()); result != nil {
+		return result, nil
+	}
+
 	created, err := clientSet.Dynamic.Resource(t.vmGVR).Namespace(obj.GetNamespace()).
 		Create(ctx, obj, metav1.CreateOptions{})
 	if err != nil {
@@ -410,11 +418,15 @@ func (t *Toolset) handleVMAction(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }, action string) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "patch", t.vmGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	running := action == "start" || action == "restart"
 	patchData := fmt.Sprintf(`{"spec":{"running":%v}}`, running)
 
@@ -436,7 +448,7 @@ func (t *Toolset) handleDataSourcesList(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -466,6 +478,10 @@ func (t *Toolset) handleDataSourcesList(ctx context.Context, args struct {
 		}
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "list", dsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	list, err := clientSet.Dynamic.Resource(dsGVR).Namespace(args.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to list DataSources: %w", err)), nil
@@ -487,7 +503,7 @@ func (t *Toolset) handleInstanceTypesList(ctx context.Context, args struct {
 	Namespace string `json:"namespace"`
 	Context   string `json:"context"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -517,6 +533,10 @@ func (t *Toolset) handleInstanceTypesList(ctx context.Context, args struct {
 		}
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "list", itGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	list, err := clientSet.Dynamic.Resource(itGVR).Namespace(args.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to list InstanceTypes: %w", err)), nil
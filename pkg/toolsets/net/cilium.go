@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -68,7 +69,7 @@ func (t *Toolset) handleCiliumPoliciesList(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -87,6 +88,10 @@ func (t *Toolset) handleCiliumPoliciesList(ctx context.Context, args struct {
 			listOptions.Continue = args.Continue
 		}
 
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.ciliumNetworkPolicyGVR, args.Namespace); result != nil {
+			return result, nil
+		}
+
 		var list *unstructured.UnstructuredList
 		if args.Namespace != "" {
 			list, err = clientSet.Dynamic.Resource(t.ciliumNetworkPolicyGVR).Namespace(args.Namespace).List(ctx, listOptions)
@@ -119,6 +124,9 @@ func (t *Toolset) handleCiliumPoliciesList(ctx context.Context, args struct {
 			listOptions.Limit = int64(args.Limit - len(policies))
 		}
 
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.ciliumClusterwideNetworkPolicyGVR, ""); result != nil {
+			return result, nil
+		}
 		list, err := clientSet.Dynamic.Resource(t.ciliumClusterwideNetworkPolicyGVR).List(ctx, listOptions)
 		if err == nil {
 			for _, item := range list.Items {
@@ -166,7 +174,7 @@ func (t *Toolset) handleCiliumPolicyGet(ctx context.Context, args struct {
 		return result, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -180,6 +188,10 @@ func (t *Toolset) handleCiliumPolicyGet(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid kind: %s", args.Kind)), nil
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	var obj *unstructured.Unstructured
 	if args.Kind == "CiliumClusterwideNetworkPolicy" {
 		obj, err = clientSet.Dynamic.Resource(gvr).Get(ctx, args.Name, metav1.GetOptions{})
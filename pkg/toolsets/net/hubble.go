@@ -43,6 +43,11 @@ func (t *Toolset) handleHubbleFlowsQuery(ctx context.Context, args struct {
 		return result, err
 	}
 
+	// Flows expose pod traffic, so require permission to list pods
+	if result := t.CheckRBAC(ctx, args.Context, "list", podsGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	// Build query URL
 	queryURL := fmt.Sprintf("%s/api/v1/flows", t.hubbleAPIURL)
 	u, err := url.Parse(queryURL)
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -115,7 +116,7 @@ func (t *Toolset) handleNetworkPoliciesList(ctx context.Context, args struct {
 	Limit         int    `json:"limit"`
 	Continue      string `json:"continue"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -130,6 +131,10 @@ func (t *Toolset) handleNetworkPoliciesList(ctx context.Context, args struct {
 		listOptions.Continue = args.Continue
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "list", networkPolicyGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	var list *networkingv1.NetworkPolicyList
 	if args.Namespace != "" {
 		list, err = clientSet.Typed.NetworkingV1().NetworkPolicies(args.Namespace).List(ctx, listOptions)
@@ -170,11 +175,15 @@ func (t *Toolset) handleNetworkPolicyExplain(ctx context.Context, args struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "get", networkPolicyGVR, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	np, err := clientSet.Typed.NetworkingV1().NetworkPolicies(args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get NetworkPolicy: %w", err)), nil
@@ -195,12 +204,18 @@ func (t *Toolset) handleConnectivityHint(ctx context.Context, args struct {
 	Port         string            `json:"port"`
 	Protocol     string            `json:"protocol"`
 }) (*mcp.CallToolResult, error) {
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
 
 	// List NetworkPolicies in both namespaces
+	for _, namespace := range []string{args.SrcNamespace, args.DstNamespace} {
+		if result := t.CheckRBAC(ctx, args.Context, "list", networkPolicyGVR, namespace); result != nil {
+			return result, nil
+		}
+	}
+
 	hint := ConnectivityHint{
 		LikelyAllowed:     "unknown",
 		Reasons:           []string{},
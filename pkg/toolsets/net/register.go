@@ -34,11 +34,11 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	tools = append(tools, mcpHelpers.NewTool("net.connectivity_hint", "Analyze connectivity between pods").
 		WithParameter("context", "string", "Kubernetes context name", false).
 		WithParameter("src_namespace", "string", "Source namespace", true).
-		WithParameter("src_labels", "object", "Source pod labels", true).
+		WithMapParameter("src_labels", "string", "Source pod labels", true).
 		WithParameter("dst_namespace", "string", "Destination namespace", true).
-		WithParameter("dst_labels", "object", "Destination pod labels", true).
+		WithMapParameter("dst_labels", "string", "Destination pod labels", true).
 		WithParameter("port", "string", "Port number", true).
-		WithParameter("protocol", "string", "Protocol (TCP, UDP, SCTP)", true).
+		WithEnumParameter("protocol", "Protocol (TCP, UDP, SCTP)", []string{"TCP", "UDP", "SCTP"}, true).
 		WithReadOnly().
 		Build())
 
@@ -55,7 +55,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 
 		tools = append(tools, mcpHelpers.NewTool("net.cilium_policy_get", "Get Cilium policy details").
 			WithParameter("context", "string", "Kubernetes context name", false).
-			WithParameter("kind", "string", "Policy kind: 'CiliumNetworkPolicy' or 'CiliumClusterwideNetworkPolicy'", true).
+			WithEnumParameter("kind", "Policy kind: 'CiliumNetworkPolicy' or 'CiliumClusterwideNetworkPolicy'", []string{"CiliumNetworkPolicy", "CiliumClusterwideNetworkPolicy"}, true).
 			WithParameter("name", "string", "Policy name", true).
 			WithParameter("namespace", "string", "Namespace name (ignored for Clusterwide)", false).
 			WithParameter("raw", "boolean", "Return raw object if true", false).
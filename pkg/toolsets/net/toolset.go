@@ -13,17 +13,17 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/config"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Toolset implements the Network toolset for NetworkPolicy, Cilium, and Hubble.
 type Toolset struct {
+	toolsets.RBAC
 	provider                          kubernetes.ClientProvider
 	discovery                         *kubernetes.CRDDiscovery
 	logger                            *observability.Logger
 	metrics                           *observability.Metrics
-	rbacAuthorizer                    kubernetes.RBACAuthorizer
-	requireRBAC                       bool
 	enabled                           bool // Always true (NetworkPolicy is native)
 	hasCilium                         bool
 	hasHubble                         bool
@@ -102,12 +102,6 @@ func (t *Toolset) SetObservability(logger *observability.Logger, metrics *observ
 	t.metrics = metrics
 }
 
-// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
-func (t *Toolset) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
-	t.rbacAuthorizer = authorizer
-	t.requireRBAC = requireRBAC
-}
-
 // IsEnabled returns whether the Network toolset is enabled.
 func (t *Toolset) IsEnabled() bool {
 	return t.enabled
@@ -84,3 +84,16 @@ var (
 		Kind:    "CiliumClusterwideNetworkPolicy",
 	}
 )
+
+// GVRs for built-in resources checked by RBAC
+var (
+	networkPolicyGVR = schema.GroupVersionResource{
+		Group:    "networking.k8s.io",
+		Version:  "v1",
+		Resource: "networkpolicies",
+	}
+	podsGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "pods",
+	}
+)
@@ -9,6 +9,7 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // handleExplainDenial handles the policy.explain_denial tool.
@@ -20,7 +21,7 @@ func (t *Toolset) handleExplainDenial(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -32,11 +33,22 @@ func (t *Toolset) handleExplainDenial(ctx context.Context, args struct {
 
 	// Try to match Kyverno policies
 	if t.hasKyverno {
+		for _, gvr := range []schema.GroupVersionResource{t.clusterPolicyGVR, t.policyGVR} {
+			if gvr.Resource == "" {
+				continue
+			}
+			if result := t.CheckRBAC(ctx, args.Context, "list", gvr, ""); result != nil {
+				return result, nil
+			}
+		}
 		matches = append(matches, t.matchKyvernoPolicies(ctx, clientSet, args.Message)...)
 	}
 
 	// Try to match Gatekeeper policies
-	if t.hasGatekeeper {
+	if t.hasGatekeeper && t.constraintTemplateGVR.Resource != "" {
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.constraintTemplateGVR, ""); result != nil {
+			return result, nil
+		}
 		matches = append(matches, t.matchGatekeeperPolicies(ctx, clientSet, args.Message)...)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -112,7 +113,7 @@ func (t *Toolset) handlePoliciesList(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -134,6 +135,9 @@ func (t *Toolset) handlePoliciesList(ctx context.Context, args struct {
 
 	// Query Kyverno ClusterPolicy
 	if queryKyverno && t.clusterPolicyGVR.Resource != "" {
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.clusterPolicyGVR, ""); result != nil {
+			return result, nil
+		}
 		list, err := clientSet.Dynamic.Resource(t.clusterPolicyGVR).List(ctx, metav1.ListOptions{})
 		if err == nil {
 			for _, item := range list.Items {
@@ -144,6 +148,9 @@ func (t *Toolset) handlePoliciesList(ctx context.Context, args struct {
 
 	// Query Kyverno Policy (namespaced)
 	if queryKyverno && t.policyGVR.Resource != "" {
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.policyGVR, args.Namespace); result != nil {
+			return result, nil
+		}
 		list, err := clientSet.Dynamic.Resource(t.policyGVR).Namespace(args.Namespace).List(ctx, metav1.ListOptions{})
 		if err == nil {
 			for _, item := range list.Items {
@@ -154,6 +161,9 @@ func (t *Toolset) handlePoliciesList(ctx context.Context, args struct {
 
 	// Query Gatekeeper ConstraintTemplate
 	if queryGatekeeper && t.constraintTemplateGVR.Resource != "" {
+		if result := t.CheckRBAC(ctx, args.Context, "list", t.constraintTemplateGVR, ""); result != nil {
+			return result, nil
+		}
 		list, err := clientSet.Dynamic.Resource(t.constraintTemplateGVR).List(ctx, metav1.ListOptions{})
 		if err == nil {
 			for _, item := range list.Items {
@@ -178,7 +188,7 @@ func (t *Toolset) handlePolicyGet(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -233,6 +243,10 @@ func (t *Toolset) handlePolicyGet(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("invalid engine: %s (must be kyverno or gatekeeper)", args.Engine)), nil
 	}
 
+	if result := t.CheckRBAC(ctx, args.Context, "get", gvr, args.Namespace); result != nil {
+		return result, nil
+	}
+
 	var obj *unstructured.Unstructured
 	if isClusterScoped {
 		obj, err = clientSet.Dynamic.Resource(gvr).Get(ctx, args.Name, metav1.GetOptions{})
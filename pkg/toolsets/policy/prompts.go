@@ -0,0 +1,35 @@
+package policy
+
+import (
+	"fmt"
+
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+)
+
+// Prompts returns the policy audit prompts (only if enabled).
+func (t *Toolset) Prompts() []*mcpHelpers.Prompt {
+	if !t.enabled {
+		return []*mcpHelpers.Prompt{}
+	}
+
+	return []*mcpHelpers.Prompt{
+		mcpHelpers.NewPrompt("audit_namespace_policies", "Audit a namespace for policy violations",
+			"Summarize the Kyverno and Gatekeeper violations in a namespace and how to fix them").
+			WithContextArgument().
+			WithArgument("namespace", "Namespace to audit", true).
+			WithTools("policy.violations_list", "policy.policies_list", "policy.policy_get", "resources_get").
+			WithRender(func(args map[string]string) []string {
+				return []string{
+					fmt.Sprintf("Audit namespace %s%s for policy violations and explain how to fix them.",
+						args["namespace"], mcpHelpers.ContextClause(args)),
+					"Work through these steps, calling the tools with the same context:\n" +
+						fmt.Sprintf("1. Call `policy.violations_list` with namespace=%s and engine=all, following the continue token until all violations are listed.\n", args["namespace"]) +
+						fmt.Sprintf("2. Call `policy.policies_list` with namespace=%s to see which policies apply and whether they enforce or only audit.\n", args["namespace"]) +
+						"3. For each policy with violations, call `policy.policy_get` to read the rule and its message.\n" +
+						"4. For the most affected resources, call `resources_get` to confirm the offending fields.\n" +
+						"5. Report the violations grouped by policy, ordered by severity and whether the policy is enforced, with the change each resource needs. Do not change anything without asking.",
+				}
+			}).
+			Build(),
+	}
+}
@@ -18,12 +18,13 @@ func (t *Toolset) Tools() []*mcp.Tool {
 		mcpHelpers.NewTool("policy.policies_list", "List policy policies (Kyverno or Gatekeeper)").
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithParameter("namespace", "string", "Namespace name (for Kyverno namespaced Policy)", false).
-			WithParameter("engine", "string", "Policy engine: 'kyverno', 'gatekeeper', or 'all' (default: all available)", false).
+			WithEnumParameter("engine", "Policy engine: 'kyverno', 'gatekeeper', or 'all' (default: all available)", []string{"kyverno", "gatekeeper", "all"}, false).
+			WithDefault("engine", "all").
 			WithReadOnly().
 			Build(),
 		mcpHelpers.NewTool("policy.policy_get", "Get policy details").
 			WithParameter("context", "string", "Kubernetes context name", false).
-			WithParameter("engine", "string", "Policy engine: 'kyverno' or 'gatekeeper'", true).
+			WithEnumParameter("engine", "Policy engine: 'kyverno' or 'gatekeeper'", []string{"kyverno", "gatekeeper"}, true).
 			WithParameter("kind", "string", "Policy kind (e.g., 'ClusterPolicy', 'Policy', 'ConstraintTemplate')", true).
 			WithParameter("name", "string", "Policy name", true).
 			WithParameter("namespace", "string", "Namespace name (required for namespaced policies)", false).
@@ -33,7 +34,8 @@ func (t *Toolset) Tools() []*mcp.Tool {
 		mcpHelpers.NewTool("policy.violations_list", "List policy violations").
 			WithParameter("context", "string", "Kubernetes context name", false).
 			WithParameter("namespace", "string", "Namespace name (empty for all namespaces)", false).
-			WithParameter("engine", "string", "Policy engine: 'kyverno', 'gatekeeper', or 'all' (default: all available)", false).
+			WithEnumParameter("engine", "Policy engine: 'kyverno', 'gatekeeper', or 'all' (default: all available)", []string{"kyverno", "gatekeeper", "all"}, false).
+			WithDefault("engine", "all").
 			WithParameter("limit", "integer", "Maximum number of items to return", false).
 			WithParameter("continue", "string", "Token from previous paginated request", false).
 			WithReadOnly().
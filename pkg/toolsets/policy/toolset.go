@@ -9,11 +9,13 @@ import (
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Toolset implements the Policy toolset for Kyverno and Gatekeeper.
 type Toolset struct {
+	toolsets.RBAC
 	provider               kubernetes.ClientProvider
 	discovery              *kubernetes.CRDDiscovery
 	logger                 *observability.Logger
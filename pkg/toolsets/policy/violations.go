@@ -23,7 +23,7 @@ func (t *Toolset) handleViolationsList(ctx context.Context, args struct {
 		return errResult, err
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -58,6 +58,9 @@ func (t *Toolset) handleViolationsList(ctx context.Context, args struct {
 	if queryKyverno {
 		// ClusterPolicyReport
 		if t.clusterPolicyReportGVR.Resource != "" {
+			if result := t.CheckRBAC(ctx, args.Context, "list", t.clusterPolicyReportGVR, ""); result != nil {
+				return result, nil
+			}
 			list, err := clientSet.Dynamic.Resource(t.clusterPolicyReportGVR).List(ctx, listOpts)
 			if err == nil {
 				for _, item := range list.Items {
@@ -71,6 +74,9 @@ func (t *Toolset) handleViolationsList(ctx context.Context, args struct {
 
 		// PolicyReport (namespaced)
 		if t.policyReportGVR.Resource != "" {
+			if result := t.CheckRBAC(ctx, args.Context, "list", t.policyReportGVR, args.Namespace); result != nil {
+				return result, nil
+			}
 			list, err := clientSet.Dynamic.Resource(t.policyReportGVR).Namespace(args.Namespace).List(ctx, listOpts)
 			if err == nil {
 				for _, item := range list.Items {
@@ -0,0 +1,70 @@
+// Package toolsets holds what the toolsets share.
+package toolsets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/auth"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
+	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RBAC checks the caller's permissions before a toolset acts on the cluster.
+// Toolsets embed it; until SetRBACAuthorizer is called, nothing is checked.
+type RBAC struct {
+	authorizer  kubernetes.RBACAuthorizer
+	requireRBAC bool
+}
+
+// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
+func (r *RBAC) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
+	r.authorizer = authorizer
+	r.requireRBAC = requireRBAC
+}
+
+// RequiresRBAC reports whether CheckRBAC checks anything, for toolsets that
+// must do work to find out what to check.
+func (r *RBAC) RequiresRBAC() bool {
+	return r.requireRBAC && r.authorizer != nil
+}
+
+// CheckRBAC checks that the caller may perform verb on gvr in namespace, in
+// the cluster of the Kubernetes context contextName. It returns nil if the
+// caller may, or the error result of the call if not.
+func (r *RBAC) CheckRBAC(ctx context.Context, contextName, verb string, gvr schema.GroupVersionResource, namespace string) *mcp.CallToolResult {
+	// If RBAC is not required or authorizer is not set, skip check
+	if !r.RequiresRBAC() {
+		return nil
+	}
+
+	// Check the authenticated caller; without one, the server's own identity is checked
+	allowed, err := r.authorizer.Allowed(ctx, auth.IdentityFromContext(ctx), contextName, verb, gvr, namespace)
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to check RBAC: %w", err))
+	}
+	if allowed {
+		return nil
+	}
+
+	result, err := mcpHelpers.NewJSONResult(map[string]any{
+		"error": map[string]any{
+			"code":    "KubernetesError",
+			"message": fmt.Sprintf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace),
+			"details": map[string]any{
+				"verb":      verb,
+				"group":     gvr.Group,
+				"resource":  gvr.Resource,
+				"namespace": namespace,
+				"reason":    "Forbidden",
+			},
+		},
+	})
+	if err != nil {
+		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to create RBAC error result: %w", err))
+	}
+	result.IsError = true
+	return result
+}
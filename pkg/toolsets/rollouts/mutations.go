@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -27,7 +28,7 @@ func (t *Toolset) handleRolloutPromote(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to promote")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -78,8 +79,8 @@ func (t *Toolset) handleRolloutPromote(ctx context.Context, args struct {
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", gvr, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get current object
@@ -130,7 +131,7 @@ func (t *Toolset) handleRolloutAbort(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to abort")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -164,8 +165,8 @@ func (t *Toolset) handleRolloutAbort(ctx context.Context, args struct {
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", gvr, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get and update object
@@ -213,7 +214,7 @@ func (t *Toolset) handleRolloutRetry(ctx context.Context, args struct {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("confirm must be true to retry")), nil
 	}
 
-	clientSet, err := t.provider.GetClientSet(args.Context)
+	clientSet, err := kubernetes.ClientSetForRequest(ctx, t.provider, args.Context)
 	if err != nil {
 		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to get client set: %w", err)), nil
 	}
@@ -247,8 +248,8 @@ func (t *Toolset) handleRolloutRetry(ctx context.Context, args struct {
 	}
 
 	// RBAC check
-	if errResult, err := t.checkRBAC(ctx, clientSet, "update", gvr, args.Namespace); errResult != nil || err != nil {
-		return errResult, err
+	if result := t.CheckRBAC(ctx, args.Context, "update", gvr, args.Namespace); result != nil {
+		return result, nil
 	}
 
 	// Get and update object
@@ -29,7 +29,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// rollouts.get_status
 	tools = append(tools, mcpHelpers.NewTool("rollouts.get_status", "Get detailed status of a progressive delivery resource").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Resource kind: 'Rollout' or 'Canary'", true).
+		WithEnumParameter("kind", "Resource kind: 'Rollout' or 'Canary'", []string{"Rollout", "Canary"}, true).
 		WithParameter("name", "string", "Resource name", true).
 		WithParameter("namespace", "string", "Namespace name", true).
 		WithParameter("raw", "boolean", "Return raw object if true", false).
@@ -39,7 +39,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// rollouts.promote
 	tools = append(tools, mcpHelpers.NewTool("rollouts.promote", "Promote a rollout to the next step (Argo Rollouts only)").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Resource kind: 'Rollout'", true).
+		WithEnumParameter("kind", "Resource kind: 'Rollout'", []string{"Rollout"}, true).
 		WithParameter("name", "string", "Resource name", true).
 		WithParameter("namespace", "string", "Namespace name", true).
 		WithParameter("confirm", "boolean", "Must be true to promote", true).
@@ -49,7 +49,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// rollouts.abort
 	tools = append(tools, mcpHelpers.NewTool("rollouts.abort", "Abort a rollout (Argo Rollouts only)").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Resource kind: 'Rollout'", true).
+		WithEnumParameter("kind", "Resource kind: 'Rollout'", []string{"Rollout"}, true).
 		WithParameter("name", "string", "Resource name", true).
 		WithParameter("namespace", "string", "Namespace name", true).
 		WithParameter("confirm", "boolean", "Must be true to abort", true).
@@ -59,7 +59,7 @@ func (t *Toolset) Tools() []*mcp.Tool {
 	// rollouts.retry
 	tools = append(tools, mcpHelpers.NewTool("rollouts.retry", "Retry a rollout analysis or progression (Argo Rollouts only)").
 		WithParameter("context", "string", "Kubernetes context name", false).
-		WithParameter("kind", "string", "Resource kind: 'Rollout'", true).
+		WithEnumParameter("kind", "Resource kind: 'Rollout'", []string{"Rollout"}, true).
 		WithParameter("name", "string", "Resource name", true).
 		WithParameter("namespace", "string", "Namespace name", true).
 		WithParameter("confirm", "boolean", "Must be true to retry", true).
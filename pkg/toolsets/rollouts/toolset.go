@@ -3,29 +3,28 @@ package rollouts
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/wrkode/kube-mcp/pkg/kubernetes"
 	mcpHelpers "github.com/wrkode/kube-mcp/pkg/mcp"
 	"github.com/wrkode/kube-mcp/pkg/observability"
+	"github.com/wrkode/kube-mcp/pkg/toolsets"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Toolset implements the Progressive Delivery toolset for Argo Rollouts and Flagger.
 type Toolset struct {
-	provider       kubernetes.ClientProvider
-	discovery      *kubernetes.CRDDiscovery
-	logger         *observability.Logger
-	metrics        *observability.Metrics
-	rbacAuthorizer kubernetes.RBACAuthorizer
-	requireRBAC    bool
-	enabled        bool
-	rolloutGVR     schema.GroupVersionResource
-	canaryGVR      schema.GroupVersionResource
-	hasRollout     bool
-	hasCanary      bool
+	toolsets.RBAC
+	provider   kubernetes.ClientProvider
+	discovery  *kubernetes.CRDDiscovery
+	logger     *observability.Logger
+	metrics    *observability.Metrics
+	enabled    bool
+	rolloutGVR schema.GroupVersionResource
+	canaryGVR  schema.GroupVersionResource
+	hasRollout bool
+	hasCanary  bool
 }
 
 // NewToolset creates a new Progressive Delivery toolset with CRD detection.
@@ -67,12 +66,6 @@ func (t *Toolset) SetObservability(logger *observability.Logger, metrics *observ
 	t.metrics = metrics
 }
 
-// SetRBACAuthorizer sets the RBAC authorizer for the toolset.
-func (t *Toolset) SetRBACAuthorizer(authorizer kubernetes.RBACAuthorizer, requireRBAC bool) {
-	t.rbacAuthorizer = authorizer
-	t.requireRBAC = requireRBAC
-}
-
 // IsEnabled returns whether the Progressive Delivery toolset is enabled.
 func (t *Toolset) IsEnabled() bool {
 	return t.enabled
@@ -166,35 +159,3 @@ func (t *Toolset) checkFeatureEnabled() (*mcp.CallToolResult, error) {
 	}
 	return nil, nil
 }
-
-// checkRBAC performs an RBAC check before an operation.
-func (t *Toolset) checkRBAC(ctx context.Context, clientSet *kubernetes.ClientSet, verb string, gvr schema.GroupVersionResource, namespace string) (*mcp.CallToolResult, error) {
-	if !t.requireRBAC || t.rbacAuthorizer == nil {
-		return nil, nil
-	}
-
-	user := ""
-	allowed, err := t.rbacAuthorizer.Allowed(ctx, user, verb, gvr, namespace)
-	if err != nil {
-		return mcpHelpers.NewErrorResult(fmt.Errorf("failed to check RBAC: %w", err)), nil
-	}
-
-	if !allowed {
-		result, err := mcpHelpers.NewJSONResult(map[string]any{
-			"error": map[string]any{
-				"code":    "KubernetesError",
-				"message": fmt.Sprintf("Forbidden: user does not have permission to %s %s/%s in namespace %s", verb, gvr.Group, gvr.Resource, namespace),
-				"details": map[string]any{
-					"verb":      verb,
-					"group":     gvr.Group,
-					"resource":  gvr.Resource,
-					"namespace": namespace,
-					"reason":    "Forbidden",
-				},
-			},
-		})
-		return result, err
-	}
-
-	return nil, nil
-}
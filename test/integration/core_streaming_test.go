@@ -64,15 +64,17 @@ func (s *CoreStreamingTestSuite) TestPodsLogsFollow() {
 
 	// Test pods_logs with follow (may fail if pod isn't running, but tests the parameter)
 	args := struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace"`
-		Container string `json:"container"`
-		TailLines *int   `json:"tail_lines"`
-		Since     string `json:"since"`
-		SinceTime string `json:"since_time"`
-		Previous  bool   `json:"previous"`
-		Follow    bool   `json:"follow"`
-		Context   string `json:"context"`
+		Name        string `json:"name"`
+		Namespace   string `json:"namespace"`
+		Container   string `json:"container"`
+		TailLines   *int   `json:"tail_lines"`
+		Since       string `json:"since"`
+		SinceTime   string `json:"since_time"`
+		Previous    bool   `json:"previous"`
+		Follow      bool   `json:"follow"`
+		MaxDuration int    `json:"max_duration"`
+		MaxLines    int    `json:"max_lines"`
+		Context     string `json:"context"`
 	}{
 		Name:      "test-pod-logs-follow",
 		Namespace: namespace,
@@ -91,8 +93,8 @@ func (s *CoreStreamingTestSuite) TestPodsLogsFollow() {
 }
 
 // TestPodsPortForward tests pods_port_forward tool.
-// Note: This test validates port forward setup, but actual port forwarding requires
-// a running pod and may not work in test environment.
+// Note: envtest runs no kubelet, so the pod never runs and the call is
+// expected to fail; this test validates parameter handling.
 func (s *CoreStreamingTestSuite) TestPodsPortForward() {
 	ctx := context.Background()
 	namespace := "test-ns-port-forward"
@@ -135,6 +137,7 @@ func (s *CoreStreamingTestSuite) TestPodsPortForward() {
 	args := struct {
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
+		Kind      string `json:"kind"`
 		LocalPort int    `json:"local_port"`
 		PodPort   int    `json:"pod_port"`
 		Container string `json:"container"`
@@ -211,6 +214,7 @@ func (s *CoreStreamingTestSuite) TestResourcesWatch() {
 		LabelSelector string `json:"label_selector"`
 		FieldSelector string `json:"field_selector"`
 		Timeout       int    `json:"timeout"`
+		MaxEvents     int    `json:"max_events"`
 		Context       string `json:"context"`
 	}{
 		Group:     "apps",
@@ -254,6 +258,7 @@ func (s *CoreStreamingTestSuite) TestResourcesWatchWithSelector() {
 		LabelSelector string `json:"label_selector"`
 		FieldSelector string `json:"field_selector"`
 		Timeout       int    `json:"timeout"`
+		MaxEvents     int    `json:"max_events"`
 		Context       string `json:"context"`
 	}{
 		Group:         "apps",
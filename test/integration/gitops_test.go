@@ -316,13 +316,11 @@ func (s *GitOpsTestSuite) TestAppReconcile() {
 		Kind      string `json:"kind"`
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
-		Confirm   bool   `json:"confirm"`
 	}{
 		Context:   "",
 		Kind:      "Kustomization",
 		Name:      "test-kustomization-reconcile",
 		Namespace: namespace,
-		Confirm:   true,
 	}
 
 	result, err := s.toolset.TestHandleAppReconcile(ctx, args)